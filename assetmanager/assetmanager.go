@@ -0,0 +1,234 @@
+package assetmanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/stembuild/assets"
+)
+
+const (
+	AutomationBundleName = "StemcellAutomation.zip"
+	LGPOName             = "LGPO.zip"
+	depsName             = "deps.json"
+)
+
+// embeddedAsset reads the assets compiled into stembuild
+var embeddedAsset = assets.Asset
+
+// executable is where LGPO.zip is looked for when it is not in the working directory
+var executable = os.Executable
+
+// Manager serves the StemcellAutomation.zip and LGPO.zip that construct uploads to the VM. The automation
+// bundle compiled into stembuild is served from memory, and only written to a private temporary
+// directory for as long as an upload needs a file. Manager never writes to the working directory.
+type Manager struct {
+	automationBundlePath string
+	lgpoPath             string
+}
+
+// New returns a Manager for the automation bundle at automationBundlePath and the LGPO.zip at lgpoPath.
+// An empty automationBundlePath selects the bundle compiled into stembuild, and an empty lgpoPath has
+// LGPO.zip looked for in the working directory, then next to the stembuild executable.
+func New(automationBundlePath, lgpoPath string) *Manager {
+	return &Manager{automationBundlePath: automationBundlePath, lgpoPath: lgpoPath}
+}
+
+// AutomationBundle returns the contents of StemcellAutomation.zip
+func (m *Manager) AutomationBundle() ([]byte, error) {
+	if m.automationBundlePath == "" {
+		data, err := embeddedAsset(AutomationBundleName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the embedded %s: %s", AutomationBundleName, err)
+		}
+		return data, nil
+	}
+
+	data, err := ioutil.ReadFile(m.automationBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read automation bundle: %s", err)
+	}
+	return data, nil
+}
+
+// AutomationBundleSource describes where StemcellAutomation.zip is read from
+func (m *Manager) AutomationBundleSource() string {
+	if m.automationBundlePath == "" {
+		return "embedded " + AutomationBundleName
+	}
+	return m.automationBundlePath
+}
+
+// AutomationBundleFile returns the path of a file holding StemcellAutomation.zip, and a function that
+// removes it once it is no longer needed. The embedded bundle is written to a temporary directory only
+// the current user can read.
+func (m *Manager) AutomationBundleFile() (string, func(), error) {
+	if m.automationBundlePath != "" {
+		return m.automationBundlePath, func() {}, nil
+	}
+
+	data, err := m.AutomationBundle()
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "stembuild-assets-")
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to create a directory for %s: %s", AutomationBundleName, err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	file := filepath.Join(dir, AutomationBundleName)
+	err = ioutil.WriteFile(file, data, 0600)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("unable to write %s: %s", AutomationBundleName, err)
+	}
+	return file, cleanup, nil
+}
+
+// LGPOFile returns the path of LGPO.zip
+func (m *Manager) LGPOFile() (string, error) {
+	if m.lgpoPath != "" {
+		_, err := os.Stat(m.lgpoPath)
+		if err != nil {
+			return "", fmt.Errorf("unable to read LGPO: %s", err)
+		}
+		return m.lgpoPath, nil
+	}
+
+	var dirs []string
+	if wd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, wd)
+	}
+	if exe, err := executable(); err == nil {
+		dirs = append(dirs, filepath.Dir(exe))
+	}
+	for _, dir := range dirs {
+		file := filepath.Join(dir, LGPOName)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("could not find %s in the working directory or next to stembuild, give its path with -lgpo", LGPOName)
+}
+
+// Verify checks that LGPO.zip can be found and, when the automation bundle lists the SHA-256 of its
+// dependencies in deps.json, that the files in the bundle and LGPO.zip match them. It runs on the host,
+// so a corrupt or mismatched bundle is reported before anything is uploaded.
+func (m *Manager) Verify() error {
+	lgpo, err := m.LGPOFile()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := m.AutomationBundle()
+	if err != nil {
+		return err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		return fmt.Errorf("%s is not a valid zip archive: %s", m.AutomationBundleSource(), err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[strings.ToLower(f.Name)] = f
+	}
+
+	depsFile, ok := files[depsName]
+	if !ok {
+		return nil
+	}
+	deps, err := readDeps(depsFile)
+	if err != nil {
+		return fmt.Errorf("%s of %s is not valid: %s", depsName, m.AutomationBundleSource(), err)
+	}
+	if len(deps) == 0 {
+		return fmt.Errorf("%s of %s lists no dependencies", depsName, m.AutomationBundleSource())
+	}
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var actual string
+		if strings.EqualFold(name, LGPOName) {
+			actual, err = fileSHA(lgpo)
+		} else {
+			f, ok := files[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("%s lists %s, which %s does not contain", depsName, name, m.AutomationBundleSource())
+			}
+			actual, err = zipFileSHA(f)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to verify %s: %s", name, err)
+		}
+
+		expected := deps[name].SHA
+		if !strings.EqualFold(actual, expected) {
+			return fmt.Errorf("%s does not match %s of %s: expected SHA-256 %s, got %s", name, depsName, m.AutomationBundleSource(), expected, actual)
+		}
+	}
+	return nil
+}
+
+type dependency struct {
+	SHA string `json:"sha"`
+}
+
+func readDeps(f *zip.File) (map[string]dependency, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var deps map[string]dependency
+	err = json.NewDecoder(r).Decode(&deps)
+	if err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+func zipFileSHA(f *zip.File) (string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	return sha(r)
+}
+
+func fileSHA(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return sha(f)
+}
+
+func sha(r io.Reader) (string, error) {
+	hash := sha256.New()
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,13 @@
+package assetmanager_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAssetmanager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Assetmanager Suite")
+}
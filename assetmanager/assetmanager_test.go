@@ -0,0 +1,202 @@
+package assetmanager_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry-incubator/stembuild/assetmanager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manager", func() {
+	var (
+		dir          string
+		wd           string
+		lgpo         []byte
+		lgpoPath     string
+		psModules    []byte
+		restoreAsset func()
+		restoreExe   func()
+	)
+
+	shaOf := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	bundle := func(files map[string][]byte) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for name, contents := range files {
+			f, err := w.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = f.Write(contents)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(w.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	embed := func(data []byte) {
+		restoreAsset()
+		restoreAsset = SetEmbeddedAsset(func(name string) ([]byte, error) {
+			Expect(name).To(Equal("StemcellAutomation.zip"))
+			return data, nil
+		})
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "assetmanager")
+		Expect(err).NotTo(HaveOccurred())
+
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Mkdir(filepath.Join(dir, "cwd"), 0700)).To(Succeed())
+		Expect(os.Chdir(filepath.Join(dir, "cwd"))).To(Succeed())
+		restoreExe = SetExecutable(filepath.Join(dir, "bin", "stembuild"))
+
+		lgpo = []byte("lgpo")
+		lgpoPath = filepath.Join(dir, "LGPO.zip")
+		Expect(ioutil.WriteFile(lgpoPath, lgpo, 0600)).To(Succeed())
+
+		psModules = []byte("bosh-psmodules")
+		restoreAsset = func() {}
+		embed(bundle(map[string][]byte{
+			"bosh-psmodules.zip": psModules,
+			"deps.json":          []byte(fmt.Sprintf(`{"bosh-psmodules.zip":{"sha":"%s"},"LGPO.zip":{"sha":"%s"}}`, shaOf(psModules), shaOf(lgpo))),
+		}))
+	})
+
+	AfterEach(func() {
+		restoreAsset()
+		restoreExe()
+		Expect(os.Chdir(wd)).To(Succeed())
+		_ = os.RemoveAll(dir)
+	})
+
+	Describe("AutomationBundleFile", func() {
+		It("writes the embedded bundle to a private temporary directory and removes it on cleanup", func() {
+			file, cleanup, err := New("", "").AutomationBundleFile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Base(file)).To(Equal("StemcellAutomation.zip"))
+			Expect(filepath.Dir(file)).NotTo(Equal(filepath.Join(dir, "cwd")))
+
+			info, err := os.Stat(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			cleanup()
+			_, err = os.Stat(filepath.Dir(file))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+
+			entries, err := ioutil.ReadDir(filepath.Join(dir, "cwd"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+
+		It("returns the path of an overriding bundle as it is", func() {
+			file, cleanup, err := New("/bundles/StemcellAutomation.zip", "").AutomationBundleFile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file).To(Equal("/bundles/StemcellAutomation.zip"))
+			cleanup()
+		})
+	})
+
+	Describe("LGPOFile", func() {
+		It("returns the overriding path", func() {
+			file, err := New("", lgpoPath).LGPOFile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file).To(Equal(lgpoPath))
+		})
+
+		It("returns an error when the overriding path does not exist", func() {
+			_, err := New("", filepath.Join(dir, "missing.zip")).LGPOFile()
+			Expect(err).To(MatchError(ContainSubstring("unable to read LGPO")))
+		})
+
+		It("finds LGPO.zip in the working directory", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "cwd", "LGPO.zip"), lgpo, 0600)).To(Succeed())
+
+			file, err := New("", "").LGPOFile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Base(file)).To(Equal("LGPO.zip"))
+			Expect(filepath.Base(filepath.Dir(file))).To(Equal("cwd"))
+		})
+
+		It("finds LGPO.zip next to the stembuild executable", func() {
+			Expect(os.Mkdir(filepath.Join(dir, "bin"), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "bin", "LGPO.zip"), lgpo, 0600)).To(Succeed())
+
+			file, err := New("", "").LGPOFile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file).To(Equal(filepath.Join(dir, "bin", "LGPO.zip")))
+		})
+
+		It("says where to give LGPO.zip when it cannot be found", func() {
+			_, err := New("", "").LGPOFile()
+			Expect(err).To(MatchError("could not find LGPO.zip in the working directory or next to stembuild, give its path with -lgpo"))
+		})
+	})
+
+	Describe("Verify", func() {
+		It("succeeds when the bundle and LGPO match deps.json", func() {
+			Expect(New("", lgpoPath).Verify()).To(Succeed())
+		})
+
+		It("verifies an overriding bundle", func() {
+			bundlePath := filepath.Join(dir, "StemcellAutomation.zip")
+			Expect(ioutil.WriteFile(bundlePath, bundle(map[string][]byte{
+				"bosh-psmodules.zip": []byte("tampered"),
+				"deps.json":          []byte(fmt.Sprintf(`{"bosh-psmodules.zip":{"sha":"%s"}}`, shaOf(psModules))),
+			}), 0600)).To(Succeed())
+
+			err := New(bundlePath, lgpoPath).Verify()
+			Expect(err).To(MatchError(fmt.Sprintf("bosh-psmodules.zip does not match deps.json of %s: expected SHA-256 %s, got %s", bundlePath, shaOf(psModules), shaOf([]byte("tampered")))))
+		})
+
+		It("returns an error when LGPO does not match deps.json", func() {
+			Expect(ioutil.WriteFile(lgpoPath, []byte("another lgpo"), 0600)).To(Succeed())
+
+			err := New("", lgpoPath).Verify()
+			Expect(err).To(MatchError(ContainSubstring("LGPO.zip does not match deps.json of embedded StemcellAutomation.zip")))
+		})
+
+		It("returns an error when a dependency is missing from the bundle", func() {
+			embed(bundle(map[string][]byte{
+				"deps.json": []byte(`{"agent.zip":{"sha":"abc"}}`),
+			}))
+
+			err := New("", lgpoPath).Verify()
+			Expect(err).To(MatchError("deps.json lists agent.zip, which embedded StemcellAutomation.zip does not contain"))
+		})
+
+		It("returns an error when deps.json is not valid", func() {
+			embed(bundle(map[string][]byte{"deps.json": []byte("{")}))
+
+			err := New("", lgpoPath).Verify()
+			Expect(err).To(MatchError(ContainSubstring("deps.json of embedded StemcellAutomation.zip is not valid")))
+		})
+
+		It("only requires LGPO when the bundle has no deps.json", func() {
+			embed(bundle(map[string][]byte{"Setup.ps1": []byte("")}))
+
+			Expect(New("", lgpoPath).Verify()).To(Succeed())
+			Expect(New("", "").Verify()).To(MatchError(ContainSubstring("could not find LGPO.zip")))
+		})
+
+		It("returns an error when the bundle is not a zip archive", func() {
+			embed([]byte("not a zip"))
+
+			err := New("", lgpoPath).Verify()
+			Expect(err).To(MatchError(ContainSubstring("embedded StemcellAutomation.zip is not a valid zip archive")))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package assetmanager
+
+func SetEmbeddedAsset(asset func(name string) ([]byte, error)) func() {
+	original := embeddedAsset
+	embeddedAsset = asset
+	return func() { embeddedAsset = original }
+}
+
+func SetExecutable(path string) func() {
+	original := executable
+	executable = func() (string, error) { return path, nil }
+	return func() { executable = original }
+}
@@ -0,0 +1,188 @@
+package buildconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/package_parameters"
+	"gopkg.in/yaml.v2"
+)
+
+const envPrefix = "STEMBUILD_"
+
+// Setting binds a key of the build configuration file, such as vcenter.password, to the field it populates
+type Setting struct {
+	Key string
+	set func(value string) error
+}
+
+func String(key string, field *string) Setting {
+	return Setting{key, func(value string) error {
+		*field = value
+		return nil
+	}}
+}
+
+func Bool(key string, field *bool) Setting {
+	return Setting{key, func(value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+		*field = b
+		return nil
+	}}
+}
+
+func Int(key string, field *int) Setting {
+	return Setting{key, func(value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("expected a whole number, got %q", value)
+		}
+		*field = i
+		return nil
+	}}
+}
+
+func Duration(key string, field *time.Duration) Setting {
+	return Setting{key, func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("expected a duration such as 90s, 5m or 36h, got %q", value)
+		}
+		*field = d
+		return nil
+	}}
+}
+
+// EnvName returns the environment variable that overrides key, e.g. STEMBUILD_VCENTER_PASSWORD for vcenter.password
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+// Apply populates the fields bound by settings from the YAML file at path, unless path is empty,
+// and then from STEMBUILD_* environment variables, which take precedence over the file.
+// Keys of the file that belong to another command are ignored, keys no command knows are an error.
+func Apply(path string, settings []Setting) error {
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return err
+		}
+
+		known := knownKeys()
+		var unknown []string
+		for key := range values {
+			if !known[key] {
+				unknown = append(unknown, key)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("build configuration %s: unknown key %s", path, strings.Join(unknown, ", "))
+		}
+
+		err = set(values, settings)
+		if err != nil {
+			return fmt.Errorf("build configuration %s: %s", path, err)
+		}
+	}
+
+	for _, setting := range settings {
+		value, ok := os.LookupEnv(EnvName(setting.Key))
+		if !ok {
+			continue
+		}
+		err := setting.set(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", EnvName(setting.Key), err)
+		}
+	}
+	return nil
+}
+
+// VmdkPackageParameters reads the package section of the YAML file at path, unless path is empty, through the yaml tags
+// of the VMDK package parameters, and then overrides them from STEMBUILD_* environment variables.
+// Apply is expected to have validated the file already.
+func VmdkPackageParameters(path string) (package_parameters.VmdkPackageParameters, error) {
+	var document struct {
+		Package package_parameters.VmdkPackageParameters `yaml:"package"`
+	}
+	if path != "" {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return package_parameters.VmdkPackageParameters{}, fmt.Errorf("unable to read build configuration: %s", err)
+		}
+		err = yaml.Unmarshal(contents, &document)
+		if err != nil {
+			return package_parameters.VmdkPackageParameters{}, fmt.Errorf("build configuration %s: %s", path, err)
+		}
+	}
+
+	parameters := document.Package
+	err := Apply("", vmdkPackageSettings(&parameters))
+	return parameters, err
+}
+
+// set populates the fields bound by settings from the values of their keys
+func set(values map[string]string, settings []Setting) error {
+	for _, setting := range settings {
+		value, ok := values[setting.Key]
+		if !ok {
+			continue
+		}
+		err := setting.set(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", setting.Key, err)
+		}
+	}
+	return nil
+}
+
+// readFile flattens the YAML file at path into its dotted keys and their scalar values
+func readFile(path string) (map[string]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read build configuration: %s", err)
+	}
+
+	var document map[string]interface{}
+	err = yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, fmt.Errorf("build configuration %s is not valid YAML: %s", path, err)
+	}
+
+	values := map[string]string{}
+	for key, value := range document {
+		err = flatten(key, value, values)
+		if err != nil {
+			return nil, fmt.Errorf("build configuration %s: %s", path, err)
+		}
+	}
+	return values, nil
+}
+
+func flatten(key string, value interface{}, values map[string]string) error {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for child, childValue := range v {
+			err := flatten(fmt.Sprintf("%s.%v", key, child), childValue, values)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		return fmt.Errorf("%s must be a single value, not a list", key)
+	case nil:
+		// a key without a value leaves the setting as it is
+	default:
+		values[key] = fmt.Sprint(v)
+	}
+	return nil
+}
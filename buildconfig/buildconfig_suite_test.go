@@ -0,0 +1,13 @@
+package buildconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBuildconfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Buildconfig Suite")
+}
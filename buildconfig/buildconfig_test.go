@@ -0,0 +1,247 @@
+package buildconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/stembuild/buildconfig"
+	constructconfig "github.com/cloudfoundry-incubator/stembuild/construct/config"
+	packageconfig "github.com/cloudfoundry-incubator/stembuild/package_stemcell/config"
+	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/package_parameters"
+	verifyconfig "github.com/cloudfoundry-incubator/stembuild/verify/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Apply", func() {
+	var (
+		dir        string
+		configFile string
+	)
+
+	writeConfig := func(contents string) {
+		Expect(ioutil.WriteFile(configFile, []byte(contents), 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "buildconfig")
+		Expect(err).NotTo(HaveOccurred())
+		configFile = filepath.Join(dir, "build.yml")
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+		_ = os.Unsetenv("STEMBUILD_VCENTER_PASSWORD")
+		_ = os.Unsetenv("STEMBUILD_CONSTRUCT_TIMEOUTS_REBOOT")
+	})
+
+	It("populates the construct configuration", func() {
+		writeConfig(`
+vcenter:
+  url: vcenter.example.com
+  username: root
+  password: secret
+vm:
+  inventory_path: /dc/vm/base
+  ip: 10.0.0.5
+  username: Admin
+  password: vm-secret
+construct:
+  snapshot: pre-construct
+  delete_snapshot: true
+  winrm:
+    https: true
+    port: 15986
+    auth: kerberos
+    kerberos:
+      realm: EXAMPLE.COM
+      keytab: admin.keytab
+  timeouts:
+    construct: 6h
+    post_reboot_script: 36h
+package:
+  patch_version: 3
+`)
+		c := constructconfig.SourceConfig{}
+		err := Apply(configFile, ConstructSettings(&c))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.VCenterUrl).To(Equal("vcenter.example.com"))
+		Expect(c.VCenterPassword).To(Equal("secret"))
+		Expect(c.VmInventoryPath).To(Equal("/dc/vm/base"))
+		Expect(c.GuestVmIp).To(Equal("10.0.0.5"))
+		Expect(c.GuestVMPassword).To(Equal("vm-secret"))
+		Expect(c.SnapshotName).To(Equal("pre-construct"))
+		Expect(c.DeleteSnapshot).To(BeTrue())
+		Expect(c.Timeouts.Construct).To(Equal(6 * time.Hour))
+		Expect(c.Timeouts.PostRebootScript).To(Equal(36 * time.Hour))
+		Expect(c.WinRM.HTTPS).To(BeTrue())
+		Expect(c.WinRM.Port).To(Equal(15986))
+		Expect(c.WinRM.Auth).To(Equal("kerberos"))
+		Expect(c.WinRM.Kerberos).To(Equal(constructconfig.Kerberos{Realm: "EXAMPLE.COM", Keytab: "admin.keytab"}))
+	})
+
+	It("populates the package configuration", func() {
+		writeConfig(`
+vcenter:
+  url: vcenter.example.com
+vm:
+  inventory_path: /dc/vm/base
+  password: ignored-by-package
+package:
+  output_dir: /stemcells
+  patch_version: 3
+`)
+		s := packageconfig.SourceConfig{}
+		o := packageconfig.OutputConfig{}
+		var patchVersion string
+		err := Apply(configFile, PackageSettings(&s, &o, &patchVersion))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(s.URL).To(Equal("vcenter.example.com"))
+		Expect(s.VmInventoryPath).To(Equal("/dc/vm/base"))
+		Expect(o.OutputDir).To(Equal("/stemcells"))
+		Expect(patchVersion).To(Equal("3"))
+	})
+
+	It("populates the verify configuration", func() {
+		writeConfig(`
+vm:
+  inventory_path: /dc/vm/base
+  username: Administrator
+  ip: ignored-by-verify
+verify:
+  clone_to: /dc/vm/checks/base
+  keep_clone: true
+  timeout: 45m
+`)
+		c := verifyconfig.SourceConfig{}
+		err := Apply(configFile, VerifySettings(&c))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.VmInventoryPath).To(Equal("/dc/vm/base"))
+		Expect(c.GuestVMUsername).To(Equal("Administrator"))
+		Expect(c.CloneTo).To(Equal("/dc/vm/checks/base"))
+		Expect(c.KeepClone).To(BeTrue())
+		Expect(c.Timeout).To(Equal(45 * time.Minute))
+	})
+
+	It("lets STEMBUILD_* environment variables override the file", func() {
+		writeConfig("vcenter:\n  password: from-file\n")
+		Expect(os.Setenv("STEMBUILD_VCENTER_PASSWORD", "from-env")).To(Succeed())
+
+		c := constructconfig.SourceConfig{}
+		err := Apply(configFile, ConstructSettings(&c))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.VCenterPassword).To(Equal("from-env"))
+	})
+
+	It("applies environment variables without a file", func() {
+		Expect(os.Setenv("STEMBUILD_VCENTER_PASSWORD", "from-env")).To(Succeed())
+
+		c := constructconfig.SourceConfig{}
+		err := Apply("", ConstructSettings(&c))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.VCenterPassword).To(Equal("from-env"))
+	})
+
+	It("leaves settings alone that have no value", func() {
+		writeConfig("construct:\nvm:\n  username:\n")
+
+		c := constructconfig.SourceConfig{GuestVMUsername: "Admin"}
+		err := Apply(configFile, ConstructSettings(&c))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.GuestVMUsername).To(Equal("Admin"))
+	})
+
+	It("names unknown keys", func() {
+		writeConfig("vcenter:\n  uri: vcenter.example.com\n")
+
+		err := Apply(configFile, ConstructSettings(&constructconfig.SourceConfig{}))
+		Expect(err).To(MatchError("build configuration " + configFile + ": unknown key vcenter.uri"))
+	})
+
+	It("names the key with an invalid value", func() {
+		writeConfig("construct:\n  timeouts:\n    reboot: soon\n")
+
+		err := Apply(configFile, ConstructSettings(&constructconfig.SourceConfig{}))
+		Expect(err).To(MatchError("build configuration " + configFile + `: invalid value for construct.timeouts.reboot: expected a duration such as 90s, 5m or 36h, got "soon"`))
+	})
+
+	It("names the environment variable with an invalid value", func() {
+		Expect(os.Setenv("STEMBUILD_CONSTRUCT_TIMEOUTS_REBOOT", "soon")).To(Succeed())
+
+		err := Apply("", ConstructSettings(&constructconfig.SourceConfig{}))
+		Expect(err).To(MatchError(`invalid value for STEMBUILD_CONSTRUCT_TIMEOUTS_REBOOT: expected a duration such as 90s, 5m or 36h, got "soon"`))
+	})
+
+	It("rejects lists", func() {
+		writeConfig("construct:\n  hooks_dir: [a, b]\n")
+
+		err := Apply(configFile, ConstructSettings(&constructconfig.SourceConfig{}))
+		Expect(err).To(MatchError(ContainSubstring("construct.hooks_dir must be a single value, not a list")))
+	})
+
+	It("returns an error when the file cannot be read", func() {
+		err := Apply(filepath.Join(dir, "missing.yml"), ConstructSettings(&constructconfig.SourceConfig{}))
+		Expect(err).To(MatchError(ContainSubstring("unable to read build configuration")))
+	})
+
+	It("returns an error when the file is not YAML", func() {
+		writeConfig("vcenter: [")
+
+		err := Apply(configFile, ConstructSettings(&constructconfig.SourceConfig{}))
+		Expect(err).To(MatchError(ContainSubstring("is not valid YAML")))
+	})
+})
+
+var _ = Describe("VmdkPackageParameters", func() {
+	var (
+		dir        string
+		configFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "buildconfig")
+		Expect(err).NotTo(HaveOccurred())
+		configFile = filepath.Join(dir, "build.yml")
+		Expect(ioutil.WriteFile(configFile, []byte("package:\n  vmdk_file: from-file.vmdk\n  output_dir: /stemcells\n  patch_version: 3\n"), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+		_ = os.Unsetenv("STEMBUILD_PACKAGE_VMDK_FILE")
+	})
+
+	It("reads the package section through the yaml tags", func() {
+		parameters, err := VmdkPackageParameters(configFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parameters).To(Equal(package_parameters.VmdkPackageParameters{VMDKFile: "from-file.vmdk", OutputDir: "/stemcells"}))
+	})
+
+	It("lets STEMBUILD_* environment variables override the file", func() {
+		Expect(os.Setenv("STEMBUILD_PACKAGE_VMDK_FILE", "from-env.vmdk")).To(Succeed())
+
+		parameters, err := VmdkPackageParameters(configFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parameters.VMDKFile).To(Equal("from-env.vmdk"))
+	})
+
+	It("applies environment variables without a file", func() {
+		Expect(os.Setenv("STEMBUILD_PACKAGE_VMDK_FILE", "from-env.vmdk")).To(Succeed())
+
+		parameters, err := VmdkPackageParameters("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parameters.VMDKFile).To(Equal("from-env.vmdk"))
+	})
+})
+
+var _ = Describe("EnvName", func() {
+	It("derives the variable from the key", func() {
+		Expect(EnvName("construct.timeouts.post_reboot_script")).To(Equal("STEMBUILD_CONSTRUCT_TIMEOUTS_POST_REBOOT_SCRIPT"))
+	})
+})
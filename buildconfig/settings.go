@@ -0,0 +1,123 @@
+package buildconfig
+
+import (
+	constructconfig "github.com/cloudfoundry-incubator/stembuild/construct/config"
+	packageconfig "github.com/cloudfoundry-incubator/stembuild/package_stemcell/config"
+	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/package_parameters"
+	verifyconfig "github.com/cloudfoundry-incubator/stembuild/verify/config"
+)
+
+// ConstructSettings binds the vcenter, vm and construct keys to the configuration of stembuild construct
+func ConstructSettings(c *constructconfig.SourceConfig) []Setting {
+	return []Setting{
+		String("vcenter.url", &c.VCenterUrl),
+		String("vcenter.username", &c.VCenterUsername),
+		String("vcenter.password", &c.VCenterPassword),
+		String("vcenter.ca_certs", &c.CaCertFile),
+		String("vm.inventory_path", &c.VmInventoryPath),
+		String("vm.ip", &c.GuestVmIp),
+		String("vm.username", &c.GuestVMUsername),
+		String("vm.password", &c.GuestVMPassword),
+		Bool("construct.resume", &c.Resume),
+		String("construct.checkpoint_file", &c.CheckpointFile),
+		String("construct.organization", &c.Organization),
+		String("construct.owner", &c.Owner),
+		Bool("construct.skip_random_password", &c.SkipRandomPassword),
+		String("construct.diagnostics_dir", &c.DiagnosticsDir),
+		String("construct.clone_to", &c.CloneTo),
+		String("construct.clone_folder", &c.CloneFolder),
+		String("construct.clone_resource_pool", &c.CloneResourcePool),
+		String("construct.clone_datastore", &c.CloneDatastore),
+		String("construct.snapshot", &c.SnapshotName),
+		Bool("construct.delete_snapshot", &c.DeleteSnapshot),
+		String("construct.hooks_dir", &c.HooksDir),
+		Bool("construct.dry_run", &c.DryRun),
+		String("construct.transport", &c.Transport),
+		Bool("construct.guest_ops_only", &c.GuestOpsOnly),
+		String("construct.targets", &c.TargetsFile),
+		Int("construct.max_parallel", &c.MaxParallel),
+		String("construct.automation_bundle", &c.AutomationBundle),
+		String("construct.lgpo", &c.LGPO),
+		Bool("construct.windows_updates", &c.WindowsUpdates),
+		Int("construct.windows_updates_max_iterations", &c.WindowsUpdatesMaxIterations),
+		String("construct.proxy.http", &c.Proxy.HTTP),
+		String("construct.proxy.https", &c.Proxy.HTTPS),
+		String("construct.proxy.bypass_list", &c.Proxy.BypassList),
+		String("construct.hardening.profile", &c.Hardening.Profile),
+		String("construct.hardening.gpo_dir", &c.Hardening.GPODir),
+		Int("construct.winrm.port", &c.WinRM.Port),
+		Bool("construct.winrm.https", &c.WinRM.HTTPS),
+		String("construct.winrm.ca_certs", &c.WinRM.CACertFile),
+		Bool("construct.winrm.insecure", &c.WinRM.Insecure),
+		String("construct.winrm.auth", &c.WinRM.Auth),
+		String("construct.winrm.kerberos.realm", &c.WinRM.Kerberos.Realm),
+		String("construct.winrm.kerberos.keytab", &c.WinRM.Kerberos.Keytab),
+		String("construct.winrm.kerberos.ccache", &c.WinRM.Kerberos.CCache),
+		String("construct.winrm.kerberos.config", &c.WinRM.Kerberos.Config),
+		String("construct.winrm.kerberos.spn", &c.WinRM.Kerberos.SPN),
+		Duration("construct.timeouts.construct", &c.Timeouts.Construct),
+		Duration("construct.timeouts.reboot_delay", &c.Timeouts.RebootDelay),
+		Duration("construct.timeouts.reboot_poll_interval", &c.Timeouts.RebootPollInterval),
+		Duration("construct.timeouts.reboot", &c.Timeouts.Reboot),
+		Duration("construct.timeouts.post_reboot_script", &c.Timeouts.PostRebootScript),
+		Duration("construct.timeouts.shutdown_poll_interval", &c.Timeouts.ShutdownPollInterval),
+		Duration("construct.timeouts.shutdown", &c.Timeouts.Shutdown),
+		Duration("construct.timeouts.winrm_operation", &c.Timeouts.WinRMOperation),
+		Duration("construct.timeouts.winrm_connect", &c.Timeouts.WinRMConnect),
+		Duration("construct.timeouts.hook", &c.Timeouts.Hook),
+		Duration("construct.timeouts.windows_updates", &c.Timeouts.WindowsUpdates),
+	}
+}
+
+// PackageSettings binds the vcenter, vm.inventory_path and package keys to the configuration of stembuild package
+func PackageSettings(s *packageconfig.SourceConfig, o *packageconfig.OutputConfig, patchVersion *string) []Setting {
+	return []Setting{
+		String("vcenter.url", &s.URL),
+		String("vcenter.username", &s.Username),
+		String("vcenter.password", &s.Password),
+		String("vcenter.ca_certs", &s.CaCertFile),
+		String("vm.inventory_path", &s.VmInventoryPath),
+		String("package.output_dir", &o.OutputDir),
+		String("package.patch_version", patchVersion),
+		Bool("package.dry_run", &s.DryRun),
+	}
+}
+
+// vmdkPackageSettings binds the package keys read through the yaml tags of VmdkPackageParameters
+func vmdkPackageSettings(p *package_parameters.VmdkPackageParameters) []Setting {
+	return []Setting{
+		String("package.vmdk_file", &p.VMDKFile),
+	}
+}
+
+// VerifySettings binds the vcenter, vm and verify keys to the configuration of stembuild verify
+func VerifySettings(c *verifyconfig.SourceConfig) []Setting {
+	return []Setting{
+		String("vcenter.url", &c.VCenterUrl),
+		String("vcenter.username", &c.VCenterUsername),
+		String("vcenter.password", &c.VCenterPassword),
+		String("vcenter.ca_certs", &c.CaCertFile),
+		String("vm.inventory_path", &c.VmInventoryPath),
+		String("vm.username", &c.GuestVMUsername),
+		String("vm.password", &c.GuestVMPassword),
+		String("verify.clone_to", &c.CloneTo),
+		Bool("verify.keep_clone", &c.KeepClone),
+		Duration("verify.timeout", &c.Timeout),
+	}
+}
+
+func knownKeys() map[string]bool {
+	var patchVersion string
+	settings := append(
+		ConstructSettings(&constructconfig.SourceConfig{}),
+		PackageSettings(&packageconfig.SourceConfig{}, &packageconfig.OutputConfig{}, &patchVersion)...,
+	)
+	settings = append(settings, vmdkPackageSettings(&package_parameters.VmdkPackageParameters{})...)
+	settings = append(settings, VerifySettings(&verifyconfig.SourceConfig{})...)
+
+	known := map[string]bool{}
+	for _, setting := range settings {
+		known[setting.Key] = true
+	}
+	return known
+}
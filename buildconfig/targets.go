@@ -0,0 +1,104 @@
+package buildconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"gopkg.in/yaml.v2"
+)
+
+// batchKeys configure a batch construct as a whole, so a target cannot set them
+var batchKeys = map[string]bool{
+	"construct.targets":      true,
+	"construct.max_parallel": true,
+}
+
+// Target is one VM of a batch construct. It holds the keys of the build configuration that are
+// specific to the VM, such as vm.inventory_path, vm.ip or vcenter.url, and the name it is reported under.
+type Target struct {
+	Name   string
+	values map[string]string
+}
+
+// LoadTargets reads the targets list of the YAML file at path. Each target takes the same keys as the
+// build configuration file and is named after the last element of its construct.clone_to or
+// vm.inventory_path, unless it has a name key.
+func LoadTargets(path string) ([]Target, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read targets file: %s", err)
+	}
+
+	var document struct {
+		Targets []map[string]interface{} `yaml:"targets"`
+	}
+	err = yaml.UnmarshalStrict(contents, &document)
+	if err != nil {
+		return nil, fmt.Errorf("targets file %s is not valid: %s", path, err)
+	}
+	if len(document.Targets) == 0 {
+		return nil, fmt.Errorf("targets file %s lists no targets", path)
+	}
+
+	known := knownKeys()
+	names := map[string]bool{}
+	targets := make([]Target, 0, len(document.Targets))
+	for i, entry := range document.Targets {
+		target, err := newTarget(entry, known)
+		if err != nil {
+			return nil, fmt.Errorf("targets file %s: target %d: %s", path, i+1, err)
+		}
+		if names[target.Name] {
+			return nil, fmt.Errorf("targets file %s: more than one target is named %s, give them distinct names", path, target.Name)
+		}
+		names[target.Name] = true
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func newTarget(entry map[string]interface{}, known map[string]bool) (Target, error) {
+	target := Target{values: map[string]string{}}
+	for key, value := range entry {
+		if key == "name" {
+			target.Name = fmt.Sprint(value)
+			continue
+		}
+		err := flatten(key, value, target.values)
+		if err != nil {
+			return Target{}, err
+		}
+	}
+
+	for key := range target.values {
+		if batchKeys[key] {
+			return Target{}, fmt.Errorf("%s applies to the whole batch and cannot be set per target", key)
+		}
+		if !known[key] {
+			return Target{}, fmt.Errorf("unknown key %s", key)
+		}
+	}
+
+	inventoryPath := target.values["vm.inventory_path"]
+	if inventoryPath == "" {
+		return Target{}, fmt.Errorf("vm.inventory_path is required")
+	}
+	if target.Name == "" {
+		target.Name = path.Base(inventoryPath)
+		if cloneTo := target.values["construct.clone_to"]; cloneTo != "" {
+			target.Name = path.Base(cloneTo)
+		}
+	}
+	return target, nil
+}
+
+// Apply populates the fields bound by settings from the keys of the target, which take precedence
+// over the build configuration file, STEMBUILD_* environment variables and flags
+func (t Target) Apply(settings []Setting) error {
+	err := set(t.values, settings)
+	if err != nil {
+		return fmt.Errorf("target %s: %s", t.Name, err)
+	}
+	return nil
+}
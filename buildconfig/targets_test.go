@@ -0,0 +1,127 @@
+package buildconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry-incubator/stembuild/buildconfig"
+	constructconfig "github.com/cloudfoundry-incubator/stembuild/construct/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadTargets", func() {
+	var (
+		dir         string
+		targetsFile string
+	)
+
+	writeTargets := func(contents string) {
+		Expect(ioutil.WriteFile(targetsFile, []byte(contents), 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "targets")
+		Expect(err).NotTo(HaveOccurred())
+		targetsFile = filepath.Join(dir, "targets.yml")
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	It("overrides the shared configuration with the keys of each target", func() {
+		writeTargets(`
+targets:
+- vm:
+    inventory_path: /dc1/vm/windows2019
+    ip: 10.0.0.5
+    password: env://WIN2019_PASSWORD
+- name: vc2-1803
+  vcenter:
+    url: vcenter2.example.com
+  vm:
+    inventory_path: /dc2/vm/windows1803
+  construct:
+    clone_to: /dc2/vm/windows1803-build
+`)
+		targets, err := LoadTargets(targetsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(2))
+
+		first := constructconfig.SourceConfig{VCenterUrl: "vcenter.example.com", GuestVMUsername: "Admin"}
+		Expect(targets[0].Apply(ConstructSettings(&first))).To(Succeed())
+		Expect(targets[0].Name).To(Equal("windows2019"))
+		Expect(first.VmInventoryPath).To(Equal("/dc1/vm/windows2019"))
+		Expect(first.GuestVmIp).To(Equal("10.0.0.5"))
+		Expect(first.GuestVMPassword).To(Equal("env://WIN2019_PASSWORD"))
+		Expect(first.VCenterUrl).To(Equal("vcenter.example.com"))
+		Expect(first.GuestVMUsername).To(Equal("Admin"))
+
+		second := constructconfig.SourceConfig{VCenterUrl: "vcenter.example.com"}
+		Expect(targets[1].Apply(ConstructSettings(&second))).To(Succeed())
+		Expect(targets[1].Name).To(Equal("vc2-1803"))
+		Expect(second.VCenterUrl).To(Equal("vcenter2.example.com"))
+		Expect(second.CloneTo).To(Equal("/dc2/vm/windows1803-build"))
+	})
+
+	It("names a clone after the clone", func() {
+		writeTargets("targets:\n- vm:\n    inventory_path: /dc/vm/base\n  construct:\n    clone_to: /dc/vm/base-build\n")
+
+		targets, err := LoadTargets(targetsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets[0].Name).To(Equal("base-build"))
+	})
+
+	It("requires the inventory path of every target", func() {
+		writeTargets("targets:\n- vm:\n    ip: 10.0.0.5\n")
+
+		_, err := LoadTargets(targetsFile)
+		Expect(err).To(MatchError("targets file " + targetsFile + ": target 1: vm.inventory_path is required"))
+	})
+
+	It("rejects targets with the same name", func() {
+		writeTargets("targets:\n- vm:\n    inventory_path: /dc1/vm/base\n- vm:\n    inventory_path: /dc2/vm/base\n")
+
+		_, err := LoadTargets(targetsFile)
+		Expect(err).To(MatchError(ContainSubstring("more than one target is named base")))
+	})
+
+	It("names unknown keys", func() {
+		writeTargets("targets:\n- vm:\n    inventory_path: /dc/vm/base\n    address: 10.0.0.5\n")
+
+		_, err := LoadTargets(targetsFile)
+		Expect(err).To(MatchError("targets file " + targetsFile + ": target 1: unknown key vm.address"))
+	})
+
+	It("rejects keys that apply to the whole batch", func() {
+		writeTargets("targets:\n- vm:\n    inventory_path: /dc/vm/base\n  construct:\n    max_parallel: 2\n")
+
+		_, err := LoadTargets(targetsFile)
+		Expect(err).To(MatchError(ContainSubstring("construct.max_parallel applies to the whole batch")))
+	})
+
+	It("rejects a file without targets", func() {
+		writeTargets("targets: []\n")
+
+		_, err := LoadTargets(targetsFile)
+		Expect(err).To(MatchError("targets file " + targetsFile + " lists no targets"))
+	})
+
+	It("names the target with an invalid value", func() {
+		writeTargets("targets:\n- vm:\n    inventory_path: /dc/vm/base\n  construct:\n    winrm:\n      port: https\n")
+
+		targets, err := LoadTargets(targetsFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = targets[0].Apply(ConstructSettings(&constructconfig.SourceConfig{}))
+		Expect(err).To(MatchError(`target base: invalid value for construct.winrm.port: expected a whole number, got "https"`))
+	})
+
+	It("returns an error when the file cannot be read", func() {
+		_, err := LoadTargets(filepath.Join(dir, "missing.yml"))
+		Expect(err).To(MatchError(ContainSubstring("unable to read targets file")))
+	})
+})
@@ -0,0 +1,53 @@
+package commandparser
+
+import (
+	"flag"
+	"sort"
+
+	"github.com/cloudfoundry-incubator/stembuild/buildconfig"
+)
+
+// applyBuildConfig populates settings from the build configuration file and STEMBUILD_* environment
+// variables, then restores the flags given on the command line, which take precedence over both.
+func applyBuildConfig(f *flag.FlagSet, path string, settings []buildconfig.Setting) error {
+	explicit := map[string]string{}
+	f.Visit(func(fl *flag.Flag) {
+		explicit[fl.Name] = fl.Value.String()
+	})
+
+	err := buildconfig.Apply(path, settings)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range explicit {
+		err = f.Set(name, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveCredentials replaces each scheme:// reference in secrets, keyed by what the secret is, with the secret itself
+func resolveCredentials(resolver CredentialResolver, secrets map[string]*string) error {
+	if resolver == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	// stdin:// prompts in a predictable order
+	sort.Strings(names)
+
+	for _, name := range names {
+		secret, err := resolver.Resolve(name, *secrets[name])
+		if err != nil {
+			return err
+		}
+		*secrets[name] = secret
+	}
+	return nil
+}
@@ -8,16 +8,6 @@ import (
 )
 
 type FakeConstructCmdValidator struct {
-	LGPOInDirectoryStub        func() bool
-	lGPOInDirectoryMutex       sync.RWMutex
-	lGPOInDirectoryArgsForCall []struct {
-	}
-	lGPOInDirectoryReturns struct {
-		result1 bool
-	}
-	lGPOInDirectoryReturnsOnCall map[int]struct {
-		result1 bool
-	}
 	PopulatedArgsStub        func(...string) bool
 	populatedArgsMutex       sync.RWMutex
 	populatedArgsArgsForCall []struct {
@@ -29,60 +19,20 @@ type FakeConstructCmdValidator struct {
 	populatedArgsReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
-}
-
-func (fake *FakeConstructCmdValidator) LGPOInDirectory() bool {
-	fake.lGPOInDirectoryMutex.Lock()
-	ret, specificReturn := fake.lGPOInDirectoryReturnsOnCall[len(fake.lGPOInDirectoryArgsForCall)]
-	fake.lGPOInDirectoryArgsForCall = append(fake.lGPOInDirectoryArgsForCall, struct {
-	}{})
-	fake.recordInvocation("LGPOInDirectory", []interface{}{})
-	fake.lGPOInDirectoryMutex.Unlock()
-	if fake.LGPOInDirectoryStub != nil {
-		return fake.LGPOInDirectoryStub()
+	VerifyAssetsStub        func(string, string) error
+	verifyAssetsMutex       sync.RWMutex
+	verifyAssetsArgsForCall []struct {
+		arg1 string
+		arg2 string
 	}
-	if specificReturn {
-		return ret.result1
+	verifyAssetsReturns struct {
+		result1 error
 	}
-	fakeReturns := fake.lGPOInDirectoryReturns
-	return fakeReturns.result1
-}
-
-func (fake *FakeConstructCmdValidator) LGPOInDirectoryCallCount() int {
-	fake.lGPOInDirectoryMutex.RLock()
-	defer fake.lGPOInDirectoryMutex.RUnlock()
-	return len(fake.lGPOInDirectoryArgsForCall)
-}
-
-func (fake *FakeConstructCmdValidator) LGPOInDirectoryCalls(stub func() bool) {
-	fake.lGPOInDirectoryMutex.Lock()
-	defer fake.lGPOInDirectoryMutex.Unlock()
-	fake.LGPOInDirectoryStub = stub
-}
-
-func (fake *FakeConstructCmdValidator) LGPOInDirectoryReturns(result1 bool) {
-	fake.lGPOInDirectoryMutex.Lock()
-	defer fake.lGPOInDirectoryMutex.Unlock()
-	fake.LGPOInDirectoryStub = nil
-	fake.lGPOInDirectoryReturns = struct {
-		result1 bool
-	}{result1}
-}
-
-func (fake *FakeConstructCmdValidator) LGPOInDirectoryReturnsOnCall(i int, result1 bool) {
-	fake.lGPOInDirectoryMutex.Lock()
-	defer fake.lGPOInDirectoryMutex.Unlock()
-	fake.LGPOInDirectoryStub = nil
-	if fake.lGPOInDirectoryReturnsOnCall == nil {
-		fake.lGPOInDirectoryReturnsOnCall = make(map[int]struct {
-			result1 bool
-		})
+	verifyAssetsReturnsOnCall map[int]struct {
+		result1 error
 	}
-	fake.lGPOInDirectoryReturnsOnCall[i] = struct {
-		result1 bool
-	}{result1}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
 }
 
 func (fake *FakeConstructCmdValidator) PopulatedArgs(arg1 ...string) bool {
@@ -91,15 +41,16 @@ func (fake *FakeConstructCmdValidator) PopulatedArgs(arg1 ...string) bool {
 	fake.populatedArgsArgsForCall = append(fake.populatedArgsArgsForCall, struct {
 		arg1 []string
 	}{arg1})
+	stub := fake.PopulatedArgsStub
+	fakeReturns := fake.populatedArgsReturns
 	fake.recordInvocation("PopulatedArgs", []interface{}{arg1})
 	fake.populatedArgsMutex.Unlock()
-	if fake.PopulatedArgsStub != nil {
-		return fake.PopulatedArgsStub(arg1...)
+	if stub != nil {
+		return stub(arg1...)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.populatedArgsReturns
 	return fakeReturns.result1
 }
 
@@ -145,13 +96,75 @@ func (fake *FakeConstructCmdValidator) PopulatedArgsReturnsOnCall(i int, result1
 	}{result1}
 }
 
+func (fake *FakeConstructCmdValidator) VerifyAssets(arg1 string, arg2 string) error {
+	fake.verifyAssetsMutex.Lock()
+	ret, specificReturn := fake.verifyAssetsReturnsOnCall[len(fake.verifyAssetsArgsForCall)]
+	fake.verifyAssetsArgsForCall = append(fake.verifyAssetsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.VerifyAssetsStub
+	fakeReturns := fake.verifyAssetsReturns
+	fake.recordInvocation("VerifyAssets", []interface{}{arg1, arg2})
+	fake.verifyAssetsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConstructCmdValidator) VerifyAssetsCallCount() int {
+	fake.verifyAssetsMutex.RLock()
+	defer fake.verifyAssetsMutex.RUnlock()
+	return len(fake.verifyAssetsArgsForCall)
+}
+
+func (fake *FakeConstructCmdValidator) VerifyAssetsCalls(stub func(string, string) error) {
+	fake.verifyAssetsMutex.Lock()
+	defer fake.verifyAssetsMutex.Unlock()
+	fake.VerifyAssetsStub = stub
+}
+
+func (fake *FakeConstructCmdValidator) VerifyAssetsArgsForCall(i int) (string, string) {
+	fake.verifyAssetsMutex.RLock()
+	defer fake.verifyAssetsMutex.RUnlock()
+	argsForCall := fake.verifyAssetsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeConstructCmdValidator) VerifyAssetsReturns(result1 error) {
+	fake.verifyAssetsMutex.Lock()
+	defer fake.verifyAssetsMutex.Unlock()
+	fake.VerifyAssetsStub = nil
+	fake.verifyAssetsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConstructCmdValidator) VerifyAssetsReturnsOnCall(i int, result1 error) {
+	fake.verifyAssetsMutex.Lock()
+	defer fake.verifyAssetsMutex.Unlock()
+	fake.VerifyAssetsStub = nil
+	if fake.verifyAssetsReturnsOnCall == nil {
+		fake.verifyAssetsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.verifyAssetsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeConstructCmdValidator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.lGPOInDirectoryMutex.RLock()
-	defer fake.lGPOInDirectoryMutex.RUnlock()
 	fake.populatedArgsMutex.RLock()
 	defer fake.populatedArgsMutex.RUnlock()
+	fake.verifyAssetsMutex.RLock()
+	defer fake.verifyAssetsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -12,6 +12,26 @@ type FakeConstructMessenger struct {
 	argumentsNotProvidedMutex       sync.RWMutex
 	argumentsNotProvidedArgsForCall []struct {
 	}
+	AssetsNotVerifiedStub        func(error)
+	assetsNotVerifiedMutex       sync.RWMutex
+	assetsNotVerifiedArgsForCall []struct {
+		arg1 error
+	}
+	BatchSummaryStub        func([]commandparser.BatchResult)
+	batchSummaryMutex       sync.RWMutex
+	batchSummaryArgsForCall []struct {
+		arg1 []commandparser.BatchResult
+	}
+	BatchTargetFinishedStub        func(commandparser.BatchResult)
+	batchTargetFinishedMutex       sync.RWMutex
+	batchTargetFinishedArgsForCall []struct {
+		arg1 commandparser.BatchResult
+	}
+	BatchTargetStartedStub        func(string)
+	batchTargetStartedMutex       sync.RWMutex
+	batchTargetStartedArgsForCall []struct {
+		arg1 string
+	}
 	CannotConnectToVMStub        func(error)
 	cannotConnectToVMMutex       sync.RWMutex
 	cannotConnectToVMArgsForCall []struct {
@@ -22,9 +42,25 @@ type FakeConstructMessenger struct {
 	cannotPrepareVMArgsForCall []struct {
 		arg1 error
 	}
-	LGPONotFoundStub        func()
-	lGPONotFoundMutex       sync.RWMutex
-	lGPONotFoundArgsForCall []struct {
+	CannotResolveCredentialStub        func(error)
+	cannotResolveCredentialMutex       sync.RWMutex
+	cannotResolveCredentialArgsForCall []struct {
+		arg1 error
+	}
+	InterruptReceivedStub        func(string)
+	interruptReceivedMutex       sync.RWMutex
+	interruptReceivedArgsForCall []struct {
+		arg1 string
+	}
+	InvalidBuildConfigStub        func(error)
+	invalidBuildConfigMutex       sync.RWMutex
+	invalidBuildConfigArgsForCall []struct {
+		arg1 error
+	}
+	SecondInterruptReceivedStub        func(string)
+	secondInterruptReceivedMutex       sync.RWMutex
+	secondInterruptReceivedArgsForCall []struct {
+		arg1 string
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
@@ -34,9 +70,10 @@ func (fake *FakeConstructMessenger) ArgumentsNotProvided() {
 	fake.argumentsNotProvidedMutex.Lock()
 	fake.argumentsNotProvidedArgsForCall = append(fake.argumentsNotProvidedArgsForCall, struct {
 	}{})
+	stub := fake.ArgumentsNotProvidedStub
 	fake.recordInvocation("ArgumentsNotProvided", []interface{}{})
 	fake.argumentsNotProvidedMutex.Unlock()
-	if fake.ArgumentsNotProvidedStub != nil {
+	if stub != nil {
 		fake.ArgumentsNotProvidedStub()
 	}
 }
@@ -53,14 +90,148 @@ func (fake *FakeConstructMessenger) ArgumentsNotProvidedCalls(stub func()) {
 	fake.ArgumentsNotProvidedStub = stub
 }
 
+func (fake *FakeConstructMessenger) AssetsNotVerified(arg1 error) {
+	fake.assetsNotVerifiedMutex.Lock()
+	fake.assetsNotVerifiedArgsForCall = append(fake.assetsNotVerifiedArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.AssetsNotVerifiedStub
+	fake.recordInvocation("AssetsNotVerified", []interface{}{arg1})
+	fake.assetsNotVerifiedMutex.Unlock()
+	if stub != nil {
+		fake.AssetsNotVerifiedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) AssetsNotVerifiedCallCount() int {
+	fake.assetsNotVerifiedMutex.RLock()
+	defer fake.assetsNotVerifiedMutex.RUnlock()
+	return len(fake.assetsNotVerifiedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) AssetsNotVerifiedCalls(stub func(error)) {
+	fake.assetsNotVerifiedMutex.Lock()
+	defer fake.assetsNotVerifiedMutex.Unlock()
+	fake.AssetsNotVerifiedStub = stub
+}
+
+func (fake *FakeConstructMessenger) AssetsNotVerifiedArgsForCall(i int) error {
+	fake.assetsNotVerifiedMutex.RLock()
+	defer fake.assetsNotVerifiedMutex.RUnlock()
+	argsForCall := fake.assetsNotVerifiedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) BatchSummary(arg1 []commandparser.BatchResult) {
+	var arg1Copy []commandparser.BatchResult
+	if arg1 != nil {
+		arg1Copy = make([]commandparser.BatchResult, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.batchSummaryMutex.Lock()
+	fake.batchSummaryArgsForCall = append(fake.batchSummaryArgsForCall, struct {
+		arg1 []commandparser.BatchResult
+	}{arg1Copy})
+	stub := fake.BatchSummaryStub
+	fake.recordInvocation("BatchSummary", []interface{}{arg1Copy})
+	fake.batchSummaryMutex.Unlock()
+	if stub != nil {
+		fake.BatchSummaryStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) BatchSummaryCallCount() int {
+	fake.batchSummaryMutex.RLock()
+	defer fake.batchSummaryMutex.RUnlock()
+	return len(fake.batchSummaryArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) BatchSummaryCalls(stub func([]commandparser.BatchResult)) {
+	fake.batchSummaryMutex.Lock()
+	defer fake.batchSummaryMutex.Unlock()
+	fake.BatchSummaryStub = stub
+}
+
+func (fake *FakeConstructMessenger) BatchSummaryArgsForCall(i int) []commandparser.BatchResult {
+	fake.batchSummaryMutex.RLock()
+	defer fake.batchSummaryMutex.RUnlock()
+	argsForCall := fake.batchSummaryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) BatchTargetFinished(arg1 commandparser.BatchResult) {
+	fake.batchTargetFinishedMutex.Lock()
+	fake.batchTargetFinishedArgsForCall = append(fake.batchTargetFinishedArgsForCall, struct {
+		arg1 commandparser.BatchResult
+	}{arg1})
+	stub := fake.BatchTargetFinishedStub
+	fake.recordInvocation("BatchTargetFinished", []interface{}{arg1})
+	fake.batchTargetFinishedMutex.Unlock()
+	if stub != nil {
+		fake.BatchTargetFinishedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) BatchTargetFinishedCallCount() int {
+	fake.batchTargetFinishedMutex.RLock()
+	defer fake.batchTargetFinishedMutex.RUnlock()
+	return len(fake.batchTargetFinishedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) BatchTargetFinishedCalls(stub func(commandparser.BatchResult)) {
+	fake.batchTargetFinishedMutex.Lock()
+	defer fake.batchTargetFinishedMutex.Unlock()
+	fake.BatchTargetFinishedStub = stub
+}
+
+func (fake *FakeConstructMessenger) BatchTargetFinishedArgsForCall(i int) commandparser.BatchResult {
+	fake.batchTargetFinishedMutex.RLock()
+	defer fake.batchTargetFinishedMutex.RUnlock()
+	argsForCall := fake.batchTargetFinishedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) BatchTargetStarted(arg1 string) {
+	fake.batchTargetStartedMutex.Lock()
+	fake.batchTargetStartedArgsForCall = append(fake.batchTargetStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.BatchTargetStartedStub
+	fake.recordInvocation("BatchTargetStarted", []interface{}{arg1})
+	fake.batchTargetStartedMutex.Unlock()
+	if stub != nil {
+		fake.BatchTargetStartedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) BatchTargetStartedCallCount() int {
+	fake.batchTargetStartedMutex.RLock()
+	defer fake.batchTargetStartedMutex.RUnlock()
+	return len(fake.batchTargetStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) BatchTargetStartedCalls(stub func(string)) {
+	fake.batchTargetStartedMutex.Lock()
+	defer fake.batchTargetStartedMutex.Unlock()
+	fake.BatchTargetStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) BatchTargetStartedArgsForCall(i int) string {
+	fake.batchTargetStartedMutex.RLock()
+	defer fake.batchTargetStartedMutex.RUnlock()
+	argsForCall := fake.batchTargetStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeConstructMessenger) CannotConnectToVM(arg1 error) {
 	fake.cannotConnectToVMMutex.Lock()
 	fake.cannotConnectToVMArgsForCall = append(fake.cannotConnectToVMArgsForCall, struct {
 		arg1 error
 	}{arg1})
+	stub := fake.CannotConnectToVMStub
 	fake.recordInvocation("CannotConnectToVM", []interface{}{arg1})
 	fake.cannotConnectToVMMutex.Unlock()
-	if fake.CannotConnectToVMStub != nil {
+	if stub != nil {
 		fake.CannotConnectToVMStub(arg1)
 	}
 }
@@ -89,9 +260,10 @@ func (fake *FakeConstructMessenger) CannotPrepareVM(arg1 error) {
 	fake.cannotPrepareVMArgsForCall = append(fake.cannotPrepareVMArgsForCall, struct {
 		arg1 error
 	}{arg1})
+	stub := fake.CannotPrepareVMStub
 	fake.recordInvocation("CannotPrepareVM", []interface{}{arg1})
 	fake.cannotPrepareVMMutex.Unlock()
-	if fake.CannotPrepareVMStub != nil {
+	if stub != nil {
 		fake.CannotPrepareVMStub(arg1)
 	}
 }
@@ -115,27 +287,132 @@ func (fake *FakeConstructMessenger) CannotPrepareVMArgsForCall(i int) error {
 	return argsForCall.arg1
 }
 
-func (fake *FakeConstructMessenger) LGPONotFound() {
-	fake.lGPONotFoundMutex.Lock()
-	fake.lGPONotFoundArgsForCall = append(fake.lGPONotFoundArgsForCall, struct {
-	}{})
-	fake.recordInvocation("LGPONotFound", []interface{}{})
-	fake.lGPONotFoundMutex.Unlock()
-	if fake.LGPONotFoundStub != nil {
-		fake.LGPONotFoundStub()
+func (fake *FakeConstructMessenger) CannotResolveCredential(arg1 error) {
+	fake.cannotResolveCredentialMutex.Lock()
+	fake.cannotResolveCredentialArgsForCall = append(fake.cannotResolveCredentialArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.CannotResolveCredentialStub
+	fake.recordInvocation("CannotResolveCredential", []interface{}{arg1})
+	fake.cannotResolveCredentialMutex.Unlock()
+	if stub != nil {
+		fake.CannotResolveCredentialStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) CannotResolveCredentialCallCount() int {
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	return len(fake.cannotResolveCredentialArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CannotResolveCredentialCalls(stub func(error)) {
+	fake.cannotResolveCredentialMutex.Lock()
+	defer fake.cannotResolveCredentialMutex.Unlock()
+	fake.CannotResolveCredentialStub = stub
+}
+
+func (fake *FakeConstructMessenger) CannotResolveCredentialArgsForCall(i int) error {
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	argsForCall := fake.cannotResolveCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) InterruptReceived(arg1 string) {
+	fake.interruptReceivedMutex.Lock()
+	fake.interruptReceivedArgsForCall = append(fake.interruptReceivedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.InterruptReceivedStub
+	fake.recordInvocation("InterruptReceived", []interface{}{arg1})
+	fake.interruptReceivedMutex.Unlock()
+	if stub != nil {
+		fake.InterruptReceivedStub(arg1)
 	}
 }
 
-func (fake *FakeConstructMessenger) LGPONotFoundCallCount() int {
-	fake.lGPONotFoundMutex.RLock()
-	defer fake.lGPONotFoundMutex.RUnlock()
-	return len(fake.lGPONotFoundArgsForCall)
+func (fake *FakeConstructMessenger) InterruptReceivedCallCount() int {
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	return len(fake.interruptReceivedArgsForCall)
 }
 
-func (fake *FakeConstructMessenger) LGPONotFoundCalls(stub func()) {
-	fake.lGPONotFoundMutex.Lock()
-	defer fake.lGPONotFoundMutex.Unlock()
-	fake.LGPONotFoundStub = stub
+func (fake *FakeConstructMessenger) InterruptReceivedCalls(stub func(string)) {
+	fake.interruptReceivedMutex.Lock()
+	defer fake.interruptReceivedMutex.Unlock()
+	fake.InterruptReceivedStub = stub
+}
+
+func (fake *FakeConstructMessenger) InterruptReceivedArgsForCall(i int) string {
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	argsForCall := fake.interruptReceivedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) InvalidBuildConfig(arg1 error) {
+	fake.invalidBuildConfigMutex.Lock()
+	fake.invalidBuildConfigArgsForCall = append(fake.invalidBuildConfigArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.InvalidBuildConfigStub
+	fake.recordInvocation("InvalidBuildConfig", []interface{}{arg1})
+	fake.invalidBuildConfigMutex.Unlock()
+	if stub != nil {
+		fake.InvalidBuildConfigStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) InvalidBuildConfigCallCount() int {
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	return len(fake.invalidBuildConfigArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) InvalidBuildConfigCalls(stub func(error)) {
+	fake.invalidBuildConfigMutex.Lock()
+	defer fake.invalidBuildConfigMutex.Unlock()
+	fake.InvalidBuildConfigStub = stub
+}
+
+func (fake *FakeConstructMessenger) InvalidBuildConfigArgsForCall(i int) error {
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	argsForCall := fake.invalidBuildConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) SecondInterruptReceived(arg1 string) {
+	fake.secondInterruptReceivedMutex.Lock()
+	fake.secondInterruptReceivedArgsForCall = append(fake.secondInterruptReceivedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SecondInterruptReceivedStub
+	fake.recordInvocation("SecondInterruptReceived", []interface{}{arg1})
+	fake.secondInterruptReceivedMutex.Unlock()
+	if stub != nil {
+		fake.SecondInterruptReceivedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) SecondInterruptReceivedCallCount() int {
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	return len(fake.secondInterruptReceivedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) SecondInterruptReceivedCalls(stub func(string)) {
+	fake.secondInterruptReceivedMutex.Lock()
+	defer fake.secondInterruptReceivedMutex.Unlock()
+	fake.SecondInterruptReceivedStub = stub
+}
+
+func (fake *FakeConstructMessenger) SecondInterruptReceivedArgsForCall(i int) string {
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	argsForCall := fake.secondInterruptReceivedArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *FakeConstructMessenger) Invocations() map[string][][]interface{} {
@@ -143,12 +420,26 @@ func (fake *FakeConstructMessenger) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.argumentsNotProvidedMutex.RLock()
 	defer fake.argumentsNotProvidedMutex.RUnlock()
+	fake.assetsNotVerifiedMutex.RLock()
+	defer fake.assetsNotVerifiedMutex.RUnlock()
+	fake.batchSummaryMutex.RLock()
+	defer fake.batchSummaryMutex.RUnlock()
+	fake.batchTargetFinishedMutex.RLock()
+	defer fake.batchTargetFinishedMutex.RUnlock()
+	fake.batchTargetStartedMutex.RLock()
+	defer fake.batchTargetStartedMutex.RUnlock()
 	fake.cannotConnectToVMMutex.RLock()
 	defer fake.cannotConnectToVMMutex.RUnlock()
 	fake.cannotPrepareVMMutex.RLock()
 	defer fake.cannotPrepareVMMutex.RUnlock()
-	fake.lGPONotFoundMutex.RLock()
-	defer fake.lGPONotFoundMutex.RUnlock()
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -13,11 +13,21 @@ type FakePackagerMessenger struct {
 	cannotCreatePackagerArgsForCall []struct {
 		arg1 error
 	}
+	CannotResolveCredentialStub        func(error)
+	cannotResolveCredentialMutex       sync.RWMutex
+	cannotResolveCredentialArgsForCall []struct {
+		arg1 error
+	}
 	DoesNotHaveEnoughSpaceStub        func(error)
 	doesNotHaveEnoughSpaceMutex       sync.RWMutex
 	doesNotHaveEnoughSpaceArgsForCall []struct {
 		arg1 error
 	}
+	InvalidBuildConfigStub        func(error)
+	invalidBuildConfigMutex       sync.RWMutex
+	invalidBuildConfigArgsForCall []struct {
+		arg1 error
+	}
 	InvalidOutputConfigStub        func(error)
 	invalidOutputConfigMutex       sync.RWMutex
 	invalidOutputConfigArgsForCall []struct {
@@ -42,9 +52,10 @@ func (fake *FakePackagerMessenger) CannotCreatePackager(arg1 error) {
 	fake.cannotCreatePackagerArgsForCall = append(fake.cannotCreatePackagerArgsForCall, struct {
 		arg1 error
 	}{arg1})
+	stub := fake.CannotCreatePackagerStub
 	fake.recordInvocation("CannotCreatePackager", []interface{}{arg1})
 	fake.cannotCreatePackagerMutex.Unlock()
-	if fake.CannotCreatePackagerStub != nil {
+	if stub != nil {
 		fake.CannotCreatePackagerStub(arg1)
 	}
 }
@@ -68,14 +79,47 @@ func (fake *FakePackagerMessenger) CannotCreatePackagerArgsForCall(i int) error
 	return argsForCall.arg1
 }
 
+func (fake *FakePackagerMessenger) CannotResolveCredential(arg1 error) {
+	fake.cannotResolveCredentialMutex.Lock()
+	fake.cannotResolveCredentialArgsForCall = append(fake.cannotResolveCredentialArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.CannotResolveCredentialStub
+	fake.recordInvocation("CannotResolveCredential", []interface{}{arg1})
+	fake.cannotResolveCredentialMutex.Unlock()
+	if stub != nil {
+		fake.CannotResolveCredentialStub(arg1)
+	}
+}
+
+func (fake *FakePackagerMessenger) CannotResolveCredentialCallCount() int {
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	return len(fake.cannotResolveCredentialArgsForCall)
+}
+
+func (fake *FakePackagerMessenger) CannotResolveCredentialCalls(stub func(error)) {
+	fake.cannotResolveCredentialMutex.Lock()
+	defer fake.cannotResolveCredentialMutex.Unlock()
+	fake.CannotResolveCredentialStub = stub
+}
+
+func (fake *FakePackagerMessenger) CannotResolveCredentialArgsForCall(i int) error {
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	argsForCall := fake.cannotResolveCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakePackagerMessenger) DoesNotHaveEnoughSpace(arg1 error) {
 	fake.doesNotHaveEnoughSpaceMutex.Lock()
 	fake.doesNotHaveEnoughSpaceArgsForCall = append(fake.doesNotHaveEnoughSpaceArgsForCall, struct {
 		arg1 error
 	}{arg1})
+	stub := fake.DoesNotHaveEnoughSpaceStub
 	fake.recordInvocation("DoesNotHaveEnoughSpace", []interface{}{arg1})
 	fake.doesNotHaveEnoughSpaceMutex.Unlock()
-	if fake.DoesNotHaveEnoughSpaceStub != nil {
+	if stub != nil {
 		fake.DoesNotHaveEnoughSpaceStub(arg1)
 	}
 }
@@ -99,14 +143,47 @@ func (fake *FakePackagerMessenger) DoesNotHaveEnoughSpaceArgsForCall(i int) erro
 	return argsForCall.arg1
 }
 
+func (fake *FakePackagerMessenger) InvalidBuildConfig(arg1 error) {
+	fake.invalidBuildConfigMutex.Lock()
+	fake.invalidBuildConfigArgsForCall = append(fake.invalidBuildConfigArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.InvalidBuildConfigStub
+	fake.recordInvocation("InvalidBuildConfig", []interface{}{arg1})
+	fake.invalidBuildConfigMutex.Unlock()
+	if stub != nil {
+		fake.InvalidBuildConfigStub(arg1)
+	}
+}
+
+func (fake *FakePackagerMessenger) InvalidBuildConfigCallCount() int {
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	return len(fake.invalidBuildConfigArgsForCall)
+}
+
+func (fake *FakePackagerMessenger) InvalidBuildConfigCalls(stub func(error)) {
+	fake.invalidBuildConfigMutex.Lock()
+	defer fake.invalidBuildConfigMutex.Unlock()
+	fake.InvalidBuildConfigStub = stub
+}
+
+func (fake *FakePackagerMessenger) InvalidBuildConfigArgsForCall(i int) error {
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	argsForCall := fake.invalidBuildConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakePackagerMessenger) InvalidOutputConfig(arg1 error) {
 	fake.invalidOutputConfigMutex.Lock()
 	fake.invalidOutputConfigArgsForCall = append(fake.invalidOutputConfigArgsForCall, struct {
 		arg1 error
 	}{arg1})
+	stub := fake.InvalidOutputConfigStub
 	fake.recordInvocation("InvalidOutputConfig", []interface{}{arg1})
 	fake.invalidOutputConfigMutex.Unlock()
-	if fake.InvalidOutputConfigStub != nil {
+	if stub != nil {
 		fake.InvalidOutputConfigStub(arg1)
 	}
 }
@@ -135,9 +212,10 @@ func (fake *FakePackagerMessenger) PackageFailed(arg1 error) {
 	fake.packageFailedArgsForCall = append(fake.packageFailedArgsForCall, struct {
 		arg1 error
 	}{arg1})
+	stub := fake.PackageFailedStub
 	fake.recordInvocation("PackageFailed", []interface{}{arg1})
 	fake.packageFailedMutex.Unlock()
-	if fake.PackageFailedStub != nil {
+	if stub != nil {
 		fake.PackageFailedStub(arg1)
 	}
 }
@@ -166,9 +244,10 @@ func (fake *FakePackagerMessenger) SourceParametersAreInvalid(arg1 error) {
 	fake.sourceParametersAreInvalidArgsForCall = append(fake.sourceParametersAreInvalidArgsForCall, struct {
 		arg1 error
 	}{arg1})
+	stub := fake.SourceParametersAreInvalidStub
 	fake.recordInvocation("SourceParametersAreInvalid", []interface{}{arg1})
 	fake.sourceParametersAreInvalidMutex.Unlock()
-	if fake.SourceParametersAreInvalidStub != nil {
+	if stub != nil {
 		fake.SourceParametersAreInvalidStub(arg1)
 	}
 }
@@ -197,8 +276,12 @@ func (fake *FakePackagerMessenger) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.cannotCreatePackagerMutex.RLock()
 	defer fake.cannotCreatePackagerMutex.RUnlock()
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
 	fake.doesNotHaveEnoughSpaceMutex.RLock()
 	defer fake.doesNotHaveEnoughSpaceMutex.RUnlock()
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
 	fake.invalidOutputConfigMutex.RLock()
 	defer fake.invalidOutputConfigMutex.RUnlock()
 	fake.packageFailedMutex.RLock()
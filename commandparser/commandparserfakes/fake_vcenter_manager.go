@@ -13,6 +13,46 @@ import (
 )
 
 type FakeVCenterManager struct {
+	CloneVMStub        func(context.Context, *object.VirtualMachine, string, vcenter_manager.CloneOptions) error
+	cloneVMMutex       sync.RWMutex
+	cloneVMArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+		arg4 vcenter_manager.CloneOptions
+	}
+	cloneVMReturns struct {
+		result1 error
+	}
+	cloneVMReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateSnapshotStub        func(context.Context, *object.VirtualMachine, string, string) error
+	createSnapshotMutex       sync.RWMutex
+	createSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+		arg4 string
+	}
+	createSnapshotReturns struct {
+		result1 error
+	}
+	createSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DestroyVMStub        func(context.Context, *object.VirtualMachine) error
+	destroyVMMutex       sync.RWMutex
+	destroyVMArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+	}
+	destroyVMReturns struct {
+		result1 error
+	}
+	destroyVMReturnsOnCall map[int]struct {
+		result1 error
+	}
 	FindVMStub        func(context.Context, string) (*object.VirtualMachine, error)
 	findVMMutex       sync.RWMutex
 	findVMArgsForCall []struct {
@@ -27,6 +67,20 @@ type FakeVCenterManager struct {
 		result1 *object.VirtualMachine
 		result2 error
 	}
+	GuestHeartbeatIsGreenStub        func(context.Context, *object.VirtualMachine) (bool, error)
+	guestHeartbeatIsGreenMutex       sync.RWMutex
+	guestHeartbeatIsGreenArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+	}
+	guestHeartbeatIsGreenReturns struct {
+		result1 bool
+		result2 error
+	}
+	guestHeartbeatIsGreenReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	GuestManagerStub        func(context.Context, vcenter_manager.OpsManager, string, string) (*guest_manager.GuestManager, error)
 	guestManagerMutex       sync.RWMutex
 	guestManagerArgsForCall []struct {
@@ -43,6 +97,21 @@ type FakeVCenterManager struct {
 		result1 *guest_manager.GuestManager
 		result2 error
 	}
+	HasSnapshotStub        func(context.Context, *object.VirtualMachine, string) (bool, error)
+	hasSnapshotMutex       sync.RWMutex
+	hasSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+	}
+	hasSnapshotReturns struct {
+		result1 bool
+		result2 error
+	}
+	hasSnapshotReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	LoginStub        func(context.Context) error
 	loginMutex       sync.RWMutex
 	loginArgsForCall []struct {
@@ -66,10 +135,240 @@ type FakeVCenterManager struct {
 	operationsManagerReturnsOnCall map[int]struct {
 		result1 *guest.OperationsManager
 	}
+	RemoveSnapshotStub        func(context.Context, *object.VirtualMachine, string) error
+	removeSnapshotMutex       sync.RWMutex
+	removeSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+	}
+	removeSnapshotReturns struct {
+		result1 error
+	}
+	removeSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RevertToSnapshotStub        func(context.Context, *object.VirtualMachine, string) error
+	revertToSnapshotMutex       sync.RWMutex
+	revertToSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+	}
+	revertToSnapshotReturns struct {
+		result1 error
+	}
+	revertToSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WaitForIPStub        func(context.Context, *object.VirtualMachine) (string, error)
+	waitForIPMutex       sync.RWMutex
+	waitForIPArgsForCall []struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+	}
+	waitForIPReturns struct {
+		result1 string
+		result2 error
+	}
+	waitForIPReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeVCenterManager) CloneVM(arg1 context.Context, arg2 *object.VirtualMachine, arg3 string, arg4 vcenter_manager.CloneOptions) error {
+	fake.cloneVMMutex.Lock()
+	ret, specificReturn := fake.cloneVMReturnsOnCall[len(fake.cloneVMArgsForCall)]
+	fake.cloneVMArgsForCall = append(fake.cloneVMArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+		arg4 vcenter_manager.CloneOptions
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.CloneVMStub
+	fakeReturns := fake.cloneVMReturns
+	fake.recordInvocation("CloneVM", []interface{}{arg1, arg2, arg3, arg4})
+	fake.cloneVMMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVCenterManager) CloneVMCallCount() int {
+	fake.cloneVMMutex.RLock()
+	defer fake.cloneVMMutex.RUnlock()
+	return len(fake.cloneVMArgsForCall)
+}
+
+func (fake *FakeVCenterManager) CloneVMCalls(stub func(context.Context, *object.VirtualMachine, string, vcenter_manager.CloneOptions) error) {
+	fake.cloneVMMutex.Lock()
+	defer fake.cloneVMMutex.Unlock()
+	fake.CloneVMStub = stub
+}
+
+func (fake *FakeVCenterManager) CloneVMArgsForCall(i int) (context.Context, *object.VirtualMachine, string, vcenter_manager.CloneOptions) {
+	fake.cloneVMMutex.RLock()
+	defer fake.cloneVMMutex.RUnlock()
+	argsForCall := fake.cloneVMArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeVCenterManager) CloneVMReturns(result1 error) {
+	fake.cloneVMMutex.Lock()
+	defer fake.cloneVMMutex.Unlock()
+	fake.CloneVMStub = nil
+	fake.cloneVMReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) CloneVMReturnsOnCall(i int, result1 error) {
+	fake.cloneVMMutex.Lock()
+	defer fake.cloneVMMutex.Unlock()
+	fake.CloneVMStub = nil
+	if fake.cloneVMReturnsOnCall == nil {
+		fake.cloneVMReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cloneVMReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) CreateSnapshot(arg1 context.Context, arg2 *object.VirtualMachine, arg3 string, arg4 string) error {
+	fake.createSnapshotMutex.Lock()
+	ret, specificReturn := fake.createSnapshotReturnsOnCall[len(fake.createSnapshotArgsForCall)]
+	fake.createSnapshotArgsForCall = append(fake.createSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.CreateSnapshotStub
+	fakeReturns := fake.createSnapshotReturns
+	fake.recordInvocation("CreateSnapshot", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVCenterManager) CreateSnapshotCallCount() int {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	return len(fake.createSnapshotArgsForCall)
+}
+
+func (fake *FakeVCenterManager) CreateSnapshotCalls(stub func(context.Context, *object.VirtualMachine, string, string) error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = stub
+}
+
+func (fake *FakeVCenterManager) CreateSnapshotArgsForCall(i int) (context.Context, *object.VirtualMachine, string, string) {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	argsForCall := fake.createSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeVCenterManager) CreateSnapshotReturns(result1 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	fake.createSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) CreateSnapshotReturnsOnCall(i int, result1 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	if fake.createSnapshotReturnsOnCall == nil {
+		fake.createSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) DestroyVM(arg1 context.Context, arg2 *object.VirtualMachine) error {
+	fake.destroyVMMutex.Lock()
+	ret, specificReturn := fake.destroyVMReturnsOnCall[len(fake.destroyVMArgsForCall)]
+	fake.destroyVMArgsForCall = append(fake.destroyVMArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+	}{arg1, arg2})
+	stub := fake.DestroyVMStub
+	fakeReturns := fake.destroyVMReturns
+	fake.recordInvocation("DestroyVM", []interface{}{arg1, arg2})
+	fake.destroyVMMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVCenterManager) DestroyVMCallCount() int {
+	fake.destroyVMMutex.RLock()
+	defer fake.destroyVMMutex.RUnlock()
+	return len(fake.destroyVMArgsForCall)
+}
+
+func (fake *FakeVCenterManager) DestroyVMCalls(stub func(context.Context, *object.VirtualMachine) error) {
+	fake.destroyVMMutex.Lock()
+	defer fake.destroyVMMutex.Unlock()
+	fake.DestroyVMStub = stub
+}
+
+func (fake *FakeVCenterManager) DestroyVMArgsForCall(i int) (context.Context, *object.VirtualMachine) {
+	fake.destroyVMMutex.RLock()
+	defer fake.destroyVMMutex.RUnlock()
+	argsForCall := fake.destroyVMArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVCenterManager) DestroyVMReturns(result1 error) {
+	fake.destroyVMMutex.Lock()
+	defer fake.destroyVMMutex.Unlock()
+	fake.DestroyVMStub = nil
+	fake.destroyVMReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) DestroyVMReturnsOnCall(i int, result1 error) {
+	fake.destroyVMMutex.Lock()
+	defer fake.destroyVMMutex.Unlock()
+	fake.DestroyVMStub = nil
+	if fake.destroyVMReturnsOnCall == nil {
+		fake.destroyVMReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.destroyVMReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeVCenterManager) FindVM(arg1 context.Context, arg2 string) (*object.VirtualMachine, error) {
 	fake.findVMMutex.Lock()
 	ret, specificReturn := fake.findVMReturnsOnCall[len(fake.findVMArgsForCall)]
@@ -77,15 +376,16 @@ func (fake *FakeVCenterManager) FindVM(arg1 context.Context, arg2 string) (*obje
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.FindVMStub
+	fakeReturns := fake.findVMReturns
 	fake.recordInvocation("FindVM", []interface{}{arg1, arg2})
 	fake.findVMMutex.Unlock()
-	if fake.FindVMStub != nil {
-		return fake.FindVMStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.findVMReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -134,6 +434,71 @@ func (fake *FakeVCenterManager) FindVMReturnsOnCall(i int, result1 *object.Virtu
 	}{result1, result2}
 }
 
+func (fake *FakeVCenterManager) GuestHeartbeatIsGreen(arg1 context.Context, arg2 *object.VirtualMachine) (bool, error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	ret, specificReturn := fake.guestHeartbeatIsGreenReturnsOnCall[len(fake.guestHeartbeatIsGreenArgsForCall)]
+	fake.guestHeartbeatIsGreenArgsForCall = append(fake.guestHeartbeatIsGreenArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+	}{arg1, arg2})
+	stub := fake.GuestHeartbeatIsGreenStub
+	fakeReturns := fake.guestHeartbeatIsGreenReturns
+	fake.recordInvocation("GuestHeartbeatIsGreen", []interface{}{arg1, arg2})
+	fake.guestHeartbeatIsGreenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVCenterManager) GuestHeartbeatIsGreenCallCount() int {
+	fake.guestHeartbeatIsGreenMutex.RLock()
+	defer fake.guestHeartbeatIsGreenMutex.RUnlock()
+	return len(fake.guestHeartbeatIsGreenArgsForCall)
+}
+
+func (fake *FakeVCenterManager) GuestHeartbeatIsGreenCalls(stub func(context.Context, *object.VirtualMachine) (bool, error)) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = stub
+}
+
+func (fake *FakeVCenterManager) GuestHeartbeatIsGreenArgsForCall(i int) (context.Context, *object.VirtualMachine) {
+	fake.guestHeartbeatIsGreenMutex.RLock()
+	defer fake.guestHeartbeatIsGreenMutex.RUnlock()
+	argsForCall := fake.guestHeartbeatIsGreenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVCenterManager) GuestHeartbeatIsGreenReturns(result1 bool, result2 error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = nil
+	fake.guestHeartbeatIsGreenReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVCenterManager) GuestHeartbeatIsGreenReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = nil
+	if fake.guestHeartbeatIsGreenReturnsOnCall == nil {
+		fake.guestHeartbeatIsGreenReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.guestHeartbeatIsGreenReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVCenterManager) GuestManager(arg1 context.Context, arg2 vcenter_manager.OpsManager, arg3 string, arg4 string) (*guest_manager.GuestManager, error) {
 	fake.guestManagerMutex.Lock()
 	ret, specificReturn := fake.guestManagerReturnsOnCall[len(fake.guestManagerArgsForCall)]
@@ -143,15 +508,16 @@ func (fake *FakeVCenterManager) GuestManager(arg1 context.Context, arg2 vcenter_
 		arg3 string
 		arg4 string
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.GuestManagerStub
+	fakeReturns := fake.guestManagerReturns
 	fake.recordInvocation("GuestManager", []interface{}{arg1, arg2, arg3, arg4})
 	fake.guestManagerMutex.Unlock()
-	if fake.GuestManagerStub != nil {
-		return fake.GuestManagerStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.guestManagerReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -200,21 +566,88 @@ func (fake *FakeVCenterManager) GuestManagerReturnsOnCall(i int, result1 *guest_
 	}{result1, result2}
 }
 
+func (fake *FakeVCenterManager) HasSnapshot(arg1 context.Context, arg2 *object.VirtualMachine, arg3 string) (bool, error) {
+	fake.hasSnapshotMutex.Lock()
+	ret, specificReturn := fake.hasSnapshotReturnsOnCall[len(fake.hasSnapshotArgsForCall)]
+	fake.hasSnapshotArgsForCall = append(fake.hasSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.HasSnapshotStub
+	fakeReturns := fake.hasSnapshotReturns
+	fake.recordInvocation("HasSnapshot", []interface{}{arg1, arg2, arg3})
+	fake.hasSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVCenterManager) HasSnapshotCallCount() int {
+	fake.hasSnapshotMutex.RLock()
+	defer fake.hasSnapshotMutex.RUnlock()
+	return len(fake.hasSnapshotArgsForCall)
+}
+
+func (fake *FakeVCenterManager) HasSnapshotCalls(stub func(context.Context, *object.VirtualMachine, string) (bool, error)) {
+	fake.hasSnapshotMutex.Lock()
+	defer fake.hasSnapshotMutex.Unlock()
+	fake.HasSnapshotStub = stub
+}
+
+func (fake *FakeVCenterManager) HasSnapshotArgsForCall(i int) (context.Context, *object.VirtualMachine, string) {
+	fake.hasSnapshotMutex.RLock()
+	defer fake.hasSnapshotMutex.RUnlock()
+	argsForCall := fake.hasSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeVCenterManager) HasSnapshotReturns(result1 bool, result2 error) {
+	fake.hasSnapshotMutex.Lock()
+	defer fake.hasSnapshotMutex.Unlock()
+	fake.HasSnapshotStub = nil
+	fake.hasSnapshotReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVCenterManager) HasSnapshotReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.hasSnapshotMutex.Lock()
+	defer fake.hasSnapshotMutex.Unlock()
+	fake.HasSnapshotStub = nil
+	if fake.hasSnapshotReturnsOnCall == nil {
+		fake.hasSnapshotReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.hasSnapshotReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVCenterManager) Login(arg1 context.Context) error {
 	fake.loginMutex.Lock()
 	ret, specificReturn := fake.loginReturnsOnCall[len(fake.loginArgsForCall)]
 	fake.loginArgsForCall = append(fake.loginArgsForCall, struct {
 		arg1 context.Context
 	}{arg1})
+	stub := fake.LoginStub
+	fakeReturns := fake.loginReturns
 	fake.recordInvocation("Login", []interface{}{arg1})
 	fake.loginMutex.Unlock()
-	if fake.LoginStub != nil {
-		return fake.LoginStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.loginReturns
 	return fakeReturns.result1
 }
 
@@ -267,15 +700,16 @@ func (fake *FakeVCenterManager) OperationsManager(arg1 context.Context, arg2 *ob
 		arg1 context.Context
 		arg2 *object.VirtualMachine
 	}{arg1, arg2})
+	stub := fake.OperationsManagerStub
+	fakeReturns := fake.operationsManagerReturns
 	fake.recordInvocation("OperationsManager", []interface{}{arg1, arg2})
 	fake.operationsManagerMutex.Unlock()
-	if fake.OperationsManagerStub != nil {
-		return fake.OperationsManagerStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.operationsManagerReturns
 	return fakeReturns.result1
 }
 
@@ -321,17 +755,224 @@ func (fake *FakeVCenterManager) OperationsManagerReturnsOnCall(i int, result1 *g
 	}{result1}
 }
 
+func (fake *FakeVCenterManager) RemoveSnapshot(arg1 context.Context, arg2 *object.VirtualMachine, arg3 string) error {
+	fake.removeSnapshotMutex.Lock()
+	ret, specificReturn := fake.removeSnapshotReturnsOnCall[len(fake.removeSnapshotArgsForCall)]
+	fake.removeSnapshotArgsForCall = append(fake.removeSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.RemoveSnapshotStub
+	fakeReturns := fake.removeSnapshotReturns
+	fake.recordInvocation("RemoveSnapshot", []interface{}{arg1, arg2, arg3})
+	fake.removeSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVCenterManager) RemoveSnapshotCallCount() int {
+	fake.removeSnapshotMutex.RLock()
+	defer fake.removeSnapshotMutex.RUnlock()
+	return len(fake.removeSnapshotArgsForCall)
+}
+
+func (fake *FakeVCenterManager) RemoveSnapshotCalls(stub func(context.Context, *object.VirtualMachine, string) error) {
+	fake.removeSnapshotMutex.Lock()
+	defer fake.removeSnapshotMutex.Unlock()
+	fake.RemoveSnapshotStub = stub
+}
+
+func (fake *FakeVCenterManager) RemoveSnapshotArgsForCall(i int) (context.Context, *object.VirtualMachine, string) {
+	fake.removeSnapshotMutex.RLock()
+	defer fake.removeSnapshotMutex.RUnlock()
+	argsForCall := fake.removeSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeVCenterManager) RemoveSnapshotReturns(result1 error) {
+	fake.removeSnapshotMutex.Lock()
+	defer fake.removeSnapshotMutex.Unlock()
+	fake.RemoveSnapshotStub = nil
+	fake.removeSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) RemoveSnapshotReturnsOnCall(i int, result1 error) {
+	fake.removeSnapshotMutex.Lock()
+	defer fake.removeSnapshotMutex.Unlock()
+	fake.RemoveSnapshotStub = nil
+	if fake.removeSnapshotReturnsOnCall == nil {
+		fake.removeSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) RevertToSnapshot(arg1 context.Context, arg2 *object.VirtualMachine, arg3 string) error {
+	fake.revertToSnapshotMutex.Lock()
+	ret, specificReturn := fake.revertToSnapshotReturnsOnCall[len(fake.revertToSnapshotArgsForCall)]
+	fake.revertToSnapshotArgsForCall = append(fake.revertToSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.RevertToSnapshotStub
+	fakeReturns := fake.revertToSnapshotReturns
+	fake.recordInvocation("RevertToSnapshot", []interface{}{arg1, arg2, arg3})
+	fake.revertToSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVCenterManager) RevertToSnapshotCallCount() int {
+	fake.revertToSnapshotMutex.RLock()
+	defer fake.revertToSnapshotMutex.RUnlock()
+	return len(fake.revertToSnapshotArgsForCall)
+}
+
+func (fake *FakeVCenterManager) RevertToSnapshotCalls(stub func(context.Context, *object.VirtualMachine, string) error) {
+	fake.revertToSnapshotMutex.Lock()
+	defer fake.revertToSnapshotMutex.Unlock()
+	fake.RevertToSnapshotStub = stub
+}
+
+func (fake *FakeVCenterManager) RevertToSnapshotArgsForCall(i int) (context.Context, *object.VirtualMachine, string) {
+	fake.revertToSnapshotMutex.RLock()
+	defer fake.revertToSnapshotMutex.RUnlock()
+	argsForCall := fake.revertToSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeVCenterManager) RevertToSnapshotReturns(result1 error) {
+	fake.revertToSnapshotMutex.Lock()
+	defer fake.revertToSnapshotMutex.Unlock()
+	fake.RevertToSnapshotStub = nil
+	fake.revertToSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) RevertToSnapshotReturnsOnCall(i int, result1 error) {
+	fake.revertToSnapshotMutex.Lock()
+	defer fake.revertToSnapshotMutex.Unlock()
+	fake.RevertToSnapshotStub = nil
+	if fake.revertToSnapshotReturnsOnCall == nil {
+		fake.revertToSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revertToSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVCenterManager) WaitForIP(arg1 context.Context, arg2 *object.VirtualMachine) (string, error) {
+	fake.waitForIPMutex.Lock()
+	ret, specificReturn := fake.waitForIPReturnsOnCall[len(fake.waitForIPArgsForCall)]
+	fake.waitForIPArgsForCall = append(fake.waitForIPArgsForCall, struct {
+		arg1 context.Context
+		arg2 *object.VirtualMachine
+	}{arg1, arg2})
+	stub := fake.WaitForIPStub
+	fakeReturns := fake.waitForIPReturns
+	fake.recordInvocation("WaitForIP", []interface{}{arg1, arg2})
+	fake.waitForIPMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVCenterManager) WaitForIPCallCount() int {
+	fake.waitForIPMutex.RLock()
+	defer fake.waitForIPMutex.RUnlock()
+	return len(fake.waitForIPArgsForCall)
+}
+
+func (fake *FakeVCenterManager) WaitForIPCalls(stub func(context.Context, *object.VirtualMachine) (string, error)) {
+	fake.waitForIPMutex.Lock()
+	defer fake.waitForIPMutex.Unlock()
+	fake.WaitForIPStub = stub
+}
+
+func (fake *FakeVCenterManager) WaitForIPArgsForCall(i int) (context.Context, *object.VirtualMachine) {
+	fake.waitForIPMutex.RLock()
+	defer fake.waitForIPMutex.RUnlock()
+	argsForCall := fake.waitForIPArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVCenterManager) WaitForIPReturns(result1 string, result2 error) {
+	fake.waitForIPMutex.Lock()
+	defer fake.waitForIPMutex.Unlock()
+	fake.WaitForIPStub = nil
+	fake.waitForIPReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVCenterManager) WaitForIPReturnsOnCall(i int, result1 string, result2 error) {
+	fake.waitForIPMutex.Lock()
+	defer fake.waitForIPMutex.Unlock()
+	fake.WaitForIPStub = nil
+	if fake.waitForIPReturnsOnCall == nil {
+		fake.waitForIPReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.waitForIPReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVCenterManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cloneVMMutex.RLock()
+	defer fake.cloneVMMutex.RUnlock()
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	fake.destroyVMMutex.RLock()
+	defer fake.destroyVMMutex.RUnlock()
 	fake.findVMMutex.RLock()
 	defer fake.findVMMutex.RUnlock()
+	fake.guestHeartbeatIsGreenMutex.RLock()
+	defer fake.guestHeartbeatIsGreenMutex.RUnlock()
 	fake.guestManagerMutex.RLock()
 	defer fake.guestManagerMutex.RUnlock()
+	fake.hasSnapshotMutex.RLock()
+	defer fake.hasSnapshotMutex.RUnlock()
 	fake.loginMutex.RLock()
 	defer fake.loginMutex.RUnlock()
 	fake.operationsManagerMutex.RLock()
 	defer fake.operationsManagerMutex.RUnlock()
+	fake.removeSnapshotMutex.RLock()
+	defer fake.removeSnapshotMutex.RUnlock()
+	fake.revertToSnapshotMutex.RLock()
+	defer fake.revertToSnapshotMutex.RUnlock()
+	fake.waitForIPMutex.RLock()
+	defer fake.waitForIPMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,302 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package commandparserfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+)
+
+type FakeVerifyMessenger struct {
+	ArgumentsNotProvidedStub        func()
+	argumentsNotProvidedMutex       sync.RWMutex
+	argumentsNotProvidedArgsForCall []struct {
+	}
+	CannotResolveCredentialStub        func(error)
+	cannotResolveCredentialMutex       sync.RWMutex
+	cannotResolveCredentialArgsForCall []struct {
+		arg1 error
+	}
+	CannotVerifyVMStub        func(error)
+	cannotVerifyVMMutex       sync.RWMutex
+	cannotVerifyVMArgsForCall []struct {
+		arg1 error
+	}
+	InterruptReceivedStub        func(string)
+	interruptReceivedMutex       sync.RWMutex
+	interruptReceivedArgsForCall []struct {
+		arg1 string
+	}
+	InvalidBuildConfigStub        func(error)
+	invalidBuildConfigMutex       sync.RWMutex
+	invalidBuildConfigArgsForCall []struct {
+		arg1 error
+	}
+	SecondInterruptReceivedStub        func(string)
+	secondInterruptReceivedMutex       sync.RWMutex
+	secondInterruptReceivedArgsForCall []struct {
+		arg1 string
+	}
+	VerifySummaryStub        func(verify.Report)
+	verifySummaryMutex       sync.RWMutex
+	verifySummaryArgsForCall []struct {
+		arg1 verify.Report
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeVerifyMessenger) ArgumentsNotProvided() {
+	fake.argumentsNotProvidedMutex.Lock()
+	fake.argumentsNotProvidedArgsForCall = append(fake.argumentsNotProvidedArgsForCall, struct {
+	}{})
+	stub := fake.ArgumentsNotProvidedStub
+	fake.recordInvocation("ArgumentsNotProvided", []interface{}{})
+	fake.argumentsNotProvidedMutex.Unlock()
+	if stub != nil {
+		fake.ArgumentsNotProvidedStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) ArgumentsNotProvidedCallCount() int {
+	fake.argumentsNotProvidedMutex.RLock()
+	defer fake.argumentsNotProvidedMutex.RUnlock()
+	return len(fake.argumentsNotProvidedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) ArgumentsNotProvidedCalls(stub func()) {
+	fake.argumentsNotProvidedMutex.Lock()
+	defer fake.argumentsNotProvidedMutex.Unlock()
+	fake.ArgumentsNotProvidedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CannotResolveCredential(arg1 error) {
+	fake.cannotResolveCredentialMutex.Lock()
+	fake.cannotResolveCredentialArgsForCall = append(fake.cannotResolveCredentialArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.CannotResolveCredentialStub
+	fake.recordInvocation("CannotResolveCredential", []interface{}{arg1})
+	fake.cannotResolveCredentialMutex.Unlock()
+	if stub != nil {
+		fake.CannotResolveCredentialStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) CannotResolveCredentialCallCount() int {
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	return len(fake.cannotResolveCredentialArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CannotResolveCredentialCalls(stub func(error)) {
+	fake.cannotResolveCredentialMutex.Lock()
+	defer fake.cannotResolveCredentialMutex.Unlock()
+	fake.CannotResolveCredentialStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CannotResolveCredentialArgsForCall(i int) error {
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	argsForCall := fake.cannotResolveCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) CannotVerifyVM(arg1 error) {
+	fake.cannotVerifyVMMutex.Lock()
+	fake.cannotVerifyVMArgsForCall = append(fake.cannotVerifyVMArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.CannotVerifyVMStub
+	fake.recordInvocation("CannotVerifyVM", []interface{}{arg1})
+	fake.cannotVerifyVMMutex.Unlock()
+	if stub != nil {
+		fake.CannotVerifyVMStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) CannotVerifyVMCallCount() int {
+	fake.cannotVerifyVMMutex.RLock()
+	defer fake.cannotVerifyVMMutex.RUnlock()
+	return len(fake.cannotVerifyVMArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CannotVerifyVMCalls(stub func(error)) {
+	fake.cannotVerifyVMMutex.Lock()
+	defer fake.cannotVerifyVMMutex.Unlock()
+	fake.CannotVerifyVMStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CannotVerifyVMArgsForCall(i int) error {
+	fake.cannotVerifyVMMutex.RLock()
+	defer fake.cannotVerifyVMMutex.RUnlock()
+	argsForCall := fake.cannotVerifyVMArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) InterruptReceived(arg1 string) {
+	fake.interruptReceivedMutex.Lock()
+	fake.interruptReceivedArgsForCall = append(fake.interruptReceivedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.InterruptReceivedStub
+	fake.recordInvocation("InterruptReceived", []interface{}{arg1})
+	fake.interruptReceivedMutex.Unlock()
+	if stub != nil {
+		fake.InterruptReceivedStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) InterruptReceivedCallCount() int {
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	return len(fake.interruptReceivedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) InterruptReceivedCalls(stub func(string)) {
+	fake.interruptReceivedMutex.Lock()
+	defer fake.interruptReceivedMutex.Unlock()
+	fake.InterruptReceivedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) InterruptReceivedArgsForCall(i int) string {
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	argsForCall := fake.interruptReceivedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) InvalidBuildConfig(arg1 error) {
+	fake.invalidBuildConfigMutex.Lock()
+	fake.invalidBuildConfigArgsForCall = append(fake.invalidBuildConfigArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.InvalidBuildConfigStub
+	fake.recordInvocation("InvalidBuildConfig", []interface{}{arg1})
+	fake.invalidBuildConfigMutex.Unlock()
+	if stub != nil {
+		fake.InvalidBuildConfigStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) InvalidBuildConfigCallCount() int {
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	return len(fake.invalidBuildConfigArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) InvalidBuildConfigCalls(stub func(error)) {
+	fake.invalidBuildConfigMutex.Lock()
+	defer fake.invalidBuildConfigMutex.Unlock()
+	fake.InvalidBuildConfigStub = stub
+}
+
+func (fake *FakeVerifyMessenger) InvalidBuildConfigArgsForCall(i int) error {
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	argsForCall := fake.invalidBuildConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) SecondInterruptReceived(arg1 string) {
+	fake.secondInterruptReceivedMutex.Lock()
+	fake.secondInterruptReceivedArgsForCall = append(fake.secondInterruptReceivedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SecondInterruptReceivedStub
+	fake.recordInvocation("SecondInterruptReceived", []interface{}{arg1})
+	fake.secondInterruptReceivedMutex.Unlock()
+	if stub != nil {
+		fake.SecondInterruptReceivedStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) SecondInterruptReceivedCallCount() int {
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	return len(fake.secondInterruptReceivedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) SecondInterruptReceivedCalls(stub func(string)) {
+	fake.secondInterruptReceivedMutex.Lock()
+	defer fake.secondInterruptReceivedMutex.Unlock()
+	fake.SecondInterruptReceivedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) SecondInterruptReceivedArgsForCall(i int) string {
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	argsForCall := fake.secondInterruptReceivedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) VerifySummary(arg1 verify.Report) {
+	fake.verifySummaryMutex.Lock()
+	fake.verifySummaryArgsForCall = append(fake.verifySummaryArgsForCall, struct {
+		arg1 verify.Report
+	}{arg1})
+	stub := fake.VerifySummaryStub
+	fake.recordInvocation("VerifySummary", []interface{}{arg1})
+	fake.verifySummaryMutex.Unlock()
+	if stub != nil {
+		fake.VerifySummaryStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) VerifySummaryCallCount() int {
+	fake.verifySummaryMutex.RLock()
+	defer fake.verifySummaryMutex.RUnlock()
+	return len(fake.verifySummaryArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) VerifySummaryCalls(stub func(verify.Report)) {
+	fake.verifySummaryMutex.Lock()
+	defer fake.verifySummaryMutex.Unlock()
+	fake.VerifySummaryStub = stub
+}
+
+func (fake *FakeVerifyMessenger) VerifySummaryArgsForCall(i int) verify.Report {
+	fake.verifySummaryMutex.RLock()
+	defer fake.verifySummaryMutex.RUnlock()
+	argsForCall := fake.verifySummaryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.argumentsNotProvidedMutex.RLock()
+	defer fake.argumentsNotProvidedMutex.RUnlock()
+	fake.cannotResolveCredentialMutex.RLock()
+	defer fake.cannotResolveCredentialMutex.RUnlock()
+	fake.cannotVerifyVMMutex.RLock()
+	defer fake.cannotVerifyVMMutex.RUnlock()
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	fake.invalidBuildConfigMutex.RLock()
+	defer fake.invalidBuildConfigMutex.RUnlock()
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	fake.verifySummaryMutex.RLock()
+	defer fake.verifySummaryMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeVerifyMessenger) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ commandparser.VerifyMessenger = new(FakeVerifyMessenger)
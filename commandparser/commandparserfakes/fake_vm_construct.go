@@ -18,6 +18,16 @@ type FakeVmConstruct struct {
 	prepareVMReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RevertSnapshotStub        func() error
+	revertSnapshotMutex       sync.RWMutex
+	revertSnapshotArgsForCall []struct {
+	}
+	revertSnapshotReturns struct {
+		result1 error
+	}
+	revertSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -27,15 +37,16 @@ func (fake *FakeVmConstruct) PrepareVM() error {
 	ret, specificReturn := fake.prepareVMReturnsOnCall[len(fake.prepareVMArgsForCall)]
 	fake.prepareVMArgsForCall = append(fake.prepareVMArgsForCall, struct {
 	}{})
+	stub := fake.PrepareVMStub
+	fakeReturns := fake.prepareVMReturns
 	fake.recordInvocation("PrepareVM", []interface{}{})
 	fake.prepareVMMutex.Unlock()
-	if fake.PrepareVMStub != nil {
-		return fake.PrepareVMStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.prepareVMReturns
 	return fakeReturns.result1
 }
 
@@ -74,11 +85,66 @@ func (fake *FakeVmConstruct) PrepareVMReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeVmConstruct) RevertSnapshot() error {
+	fake.revertSnapshotMutex.Lock()
+	ret, specificReturn := fake.revertSnapshotReturnsOnCall[len(fake.revertSnapshotArgsForCall)]
+	fake.revertSnapshotArgsForCall = append(fake.revertSnapshotArgsForCall, struct {
+	}{})
+	stub := fake.RevertSnapshotStub
+	fakeReturns := fake.revertSnapshotReturns
+	fake.recordInvocation("RevertSnapshot", []interface{}{})
+	fake.revertSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVmConstruct) RevertSnapshotCallCount() int {
+	fake.revertSnapshotMutex.RLock()
+	defer fake.revertSnapshotMutex.RUnlock()
+	return len(fake.revertSnapshotArgsForCall)
+}
+
+func (fake *FakeVmConstruct) RevertSnapshotCalls(stub func() error) {
+	fake.revertSnapshotMutex.Lock()
+	defer fake.revertSnapshotMutex.Unlock()
+	fake.RevertSnapshotStub = stub
+}
+
+func (fake *FakeVmConstruct) RevertSnapshotReturns(result1 error) {
+	fake.revertSnapshotMutex.Lock()
+	defer fake.revertSnapshotMutex.Unlock()
+	fake.RevertSnapshotStub = nil
+	fake.revertSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVmConstruct) RevertSnapshotReturnsOnCall(i int, result1 error) {
+	fake.revertSnapshotMutex.Lock()
+	defer fake.revertSnapshotMutex.Unlock()
+	fake.RevertSnapshotStub = nil
+	if fake.revertSnapshotReturnsOnCall == nil {
+		fake.revertSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revertSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeVmConstruct) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.prepareVMMutex.RLock()
 	defer fake.prepareVMMutex.RUnlock()
+	fake.revertSnapshotMutex.RLock()
+	defer fake.revertSnapshotMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
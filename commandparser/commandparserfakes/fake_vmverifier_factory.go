@@ -0,0 +1,124 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package commandparserfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/verify/config"
+)
+
+type FakeVMVerifierFactory struct {
+	VMVerifierStub        func(context.Context, config.SourceConfig, commandparser.VCenterManager, commandparser.VMOutput) (commandparser.VMVerifier, error)
+	vMVerifierMutex       sync.RWMutex
+	vMVerifierArgsForCall []struct {
+		arg1 context.Context
+		arg2 config.SourceConfig
+		arg3 commandparser.VCenterManager
+		arg4 commandparser.VMOutput
+	}
+	vMVerifierReturns struct {
+		result1 commandparser.VMVerifier
+		result2 error
+	}
+	vMVerifierReturnsOnCall map[int]struct {
+		result1 commandparser.VMVerifier
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeVMVerifierFactory) VMVerifier(arg1 context.Context, arg2 config.SourceConfig, arg3 commandparser.VCenterManager, arg4 commandparser.VMOutput) (commandparser.VMVerifier, error) {
+	fake.vMVerifierMutex.Lock()
+	ret, specificReturn := fake.vMVerifierReturnsOnCall[len(fake.vMVerifierArgsForCall)]
+	fake.vMVerifierArgsForCall = append(fake.vMVerifierArgsForCall, struct {
+		arg1 context.Context
+		arg2 config.SourceConfig
+		arg3 commandparser.VCenterManager
+		arg4 commandparser.VMOutput
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.VMVerifierStub
+	fakeReturns := fake.vMVerifierReturns
+	fake.recordInvocation("VMVerifier", []interface{}{arg1, arg2, arg3, arg4})
+	fake.vMVerifierMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVMVerifierFactory) VMVerifierCallCount() int {
+	fake.vMVerifierMutex.RLock()
+	defer fake.vMVerifierMutex.RUnlock()
+	return len(fake.vMVerifierArgsForCall)
+}
+
+func (fake *FakeVMVerifierFactory) VMVerifierCalls(stub func(context.Context, config.SourceConfig, commandparser.VCenterManager, commandparser.VMOutput) (commandparser.VMVerifier, error)) {
+	fake.vMVerifierMutex.Lock()
+	defer fake.vMVerifierMutex.Unlock()
+	fake.VMVerifierStub = stub
+}
+
+func (fake *FakeVMVerifierFactory) VMVerifierArgsForCall(i int) (context.Context, config.SourceConfig, commandparser.VCenterManager, commandparser.VMOutput) {
+	fake.vMVerifierMutex.RLock()
+	defer fake.vMVerifierMutex.RUnlock()
+	argsForCall := fake.vMVerifierArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeVMVerifierFactory) VMVerifierReturns(result1 commandparser.VMVerifier, result2 error) {
+	fake.vMVerifierMutex.Lock()
+	defer fake.vMVerifierMutex.Unlock()
+	fake.VMVerifierStub = nil
+	fake.vMVerifierReturns = struct {
+		result1 commandparser.VMVerifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVMVerifierFactory) VMVerifierReturnsOnCall(i int, result1 commandparser.VMVerifier, result2 error) {
+	fake.vMVerifierMutex.Lock()
+	defer fake.vMVerifierMutex.Unlock()
+	fake.VMVerifierStub = nil
+	if fake.vMVerifierReturnsOnCall == nil {
+		fake.vMVerifierReturnsOnCall = make(map[int]struct {
+			result1 commandparser.VMVerifier
+			result2 error
+		})
+	}
+	fake.vMVerifierReturnsOnCall[i] = struct {
+		result1 commandparser.VMVerifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVMVerifierFactory) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.vMVerifierMutex.RLock()
+	defer fake.vMVerifierMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeVMVerifierFactory) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ commandparser.VMVerifierFactory = new(FakeVMVerifierFactory)
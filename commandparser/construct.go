@@ -4,8 +4,12 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sync"
+	"syscall"
 
 	vcenter_client_factory "github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/factory"
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/guest_manager"
@@ -13,13 +17,16 @@ import (
 	"github.com/vmware/govmomi/guest"
 	"github.com/vmware/govmomi/object"
 
+	"github.com/cloudfoundry-incubator/stembuild/buildconfig"
 	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+	"github.com/cloudfoundry-incubator/stembuild/events"
 	"github.com/google/subcommands"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . VmConstruct
 type VmConstruct interface {
 	PrepareVM() error
+	RevertSnapshot() error
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . VCenterManager
@@ -27,12 +34,29 @@ type VCenterManager interface {
 	OperationsManager(ctx context.Context, vm *object.VirtualMachine) *guest.OperationsManager
 	GuestManager(ctx context.Context, opsManager vcenter_manager.OpsManager, username, password string) (*guest_manager.GuestManager, error)
 	FindVM(ctx context.Context, inventoryPath string) (*object.VirtualMachine, error)
+	CloneVM(ctx context.Context, vm *object.VirtualMachine, clonePath string, options vcenter_manager.CloneOptions) error
+	WaitForIP(ctx context.Context, vm *object.VirtualMachine) (string, error)
+	GuestHeartbeatIsGreen(ctx context.Context, vm *object.VirtualMachine) (bool, error)
+	HasSnapshot(ctx context.Context, vm *object.VirtualMachine, name string) (bool, error)
+	CreateSnapshot(ctx context.Context, vm *object.VirtualMachine, name, description string) error
+	RevertToSnapshot(ctx context.Context, vm *object.VirtualMachine, name string) error
+	RemoveSnapshot(ctx context.Context, vm *object.VirtualMachine, name string) error
+	DestroyVM(ctx context.Context, vm *object.VirtualMachine) error
 	Login(ctx context.Context) error
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . VMPreparerFactory
 type VMPreparerFactory interface {
-	VMPreparer(config config.SourceConfig, vCenterManager VCenterManager) (VmConstruct, error)
+	VMPreparer(ctx context.Context, config config.SourceConfig, vCenterManager VCenterManager, output VMOutput) (VmConstruct, error)
+}
+
+// VMOutput is where the construct of a VM reports its progress, as text to Stdout or as events to Events
+// when it is set. The output of the commands run on the VM goes to Stdout and Stderr, which are both
+// the standard error of stembuild when Events is set.
+type VMOutput struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	Events events.Sink
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . ManagerFactory
@@ -44,25 +68,47 @@ type ManagerFactory interface {
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . ConstructCmdValidator
 type ConstructCmdValidator interface {
 	PopulatedArgs(...string) bool
-	LGPOInDirectory() bool
+	VerifyAssets(automationBundle, lgpo string) error
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . ConstructMessenger
 type ConstructMessenger interface {
 	ArgumentsNotProvided()
-	LGPONotFound()
+	AssetsNotVerified(err error)
 	CannotConnectToVM(err error)
 	CannotPrepareVM(err error)
+	InvalidBuildConfig(err error)
+	CannotResolveCredential(err error)
+	InterruptReceived(signal string)
+	SecondInterruptReceived(signal string)
+	BatchTargetStarted(name string)
+	BatchTargetFinished(result BatchResult)
+	BatchSummary(results []BatchResult)
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . CredentialResolver
+type CredentialResolver interface {
+	Resolve(name, value string) (string, error)
 }
 
 type ConstructCmd struct {
 	ctx            context.Context
 	sourceConfig   config.SourceConfig
+	configFile     string
 	prepFactory    VMPreparerFactory
 	managerFactory ManagerFactory
 	validator      ConstructCmdValidator
 	messenger      ConstructMessenger
 	GlobalFlags    *GlobalFlags
+	Events         events.Sink
+	Credentials    CredentialResolver
+	// Stdout and Stderr receive the output of construct, os.Stdout and os.Stderr if they are not set.
+	// With Events set, stdout carries only the events, so everything written to Stdout goes to Stderr instead.
+	Stdout io.Writer
+	Stderr io.Writer
+	// managerMutex guards configuring managerFactory until it has made a vCenter manager,
+	// since the VMs of a batch construct are each made one concurrently
+	managerMutex sync.Mutex
 }
 
 func NewConstructCmd(ctx context.Context, prepFactory VMPreparerFactory, managerFactory ManagerFactory, validator ConstructCmdValidator, messenger ConstructMessenger) *ConstructCmd {
@@ -80,7 +126,7 @@ func (*ConstructCmd) Usage() string {
 Prepares a VM to be used by stembuild package. It leverages stemcell automation scripts to provision a VM to be used as a stemcell.
 
 Requirements:
-	LGPO.zip in current working directory
+	LGPO.zip in the current working directory or next to stembuild, or given with [lgpo]
 	Running Windows VM with:
 		- Up to date Operating System
 		- Reachable by IP
@@ -92,58 +138,309 @@ Requirements:
 Example:
 	%[1]s construct -vm-ip '10.0.0.5' -vm-username Admin -vm-password 'password' -vcenter-url vcenter.example.com -vcenter-username root -vcenter-password 'password' -vm-inventory-path '/datacenter/vm/folder/vm-name'
 
+Cloning:
+	With [clone-to], the VM at [vm-inventory-path] is left untouched. It is cloned to the given inventory path, the clone is
+	powered on and construct runs against the clone. [vm-ip] is optional in this mode: the clone's IP is discovered through
+	VMware Tools. Package the clone afterwards.
+
+	%[1]s construct -clone-to '/datacenter/vm/folder/vm-name-build' -vm-username Admin -vm-password 'password' -vcenter-url vcenter.example.com -vcenter-username root -vcenter-password 'password' -vm-inventory-path '/datacenter/vm/folder/vm-name'
+
+Snapshots:
+	With [snapshot], a snapshot of that name is taken before the first phase (or reused if the VM already has one) and the
+	VM is reverted to it if construct fails, so the VM can be used again. [delete-snapshot] removes it once construct
+	succeeds. [revert-snapshot] reverts the VM to [snapshot] without running construct.
+
+Hooks:
+	With [hooks-dir], the *.ps1 scripts in its pre-setup, post-setup and pre-sysprep subdirectories are uploaded to
+	C:\provision\hooks and run in file name order: pre-setup before the setup script, post-setup once the VM has
+	rebooted, and pre-sysprep after the post-reboot script has cleaned up the VM, right before sysprep. A script that
+	exits non-zero fails construct.
+
+WinRM:
+	By default construct talks to the VM over WinRM on plain HTTP port 5985. With [winrm-https], the VM is given an HTTPS
+	listener on port 5986 (or [winrm-port]) with a self-signed certificate, unless it already has one, and construct
+	connects over HTTPS only. The listener certificate is read back through VMware Tools and trusted, or, with
+	[winrm-ca-certs], verified against the given CA certs instead. [winrm-insecure] skips verification altogether.
+
+	With [winrm-auth] ntlm, construct authenticates with NTLM instead of Basic auth, which group policy often disables.
+	Domain users are given as DOMAIN\user. NTLM messages are not encrypted over HTTP, so use it with [winrm-https].
+
+	With [winrm-auth] kerberos, construct authenticates with a Kerberos ticket of the VM user, from [winrm-krb5-keytab]
+	in [winrm-krb5-realm] or from the credential cache [winrm-krb5-ccache], e.g. written by kinit. The KDCs come from
+	[winrm-krb5-conf]. Without a DNS name for the VM, give the service principal of WinRM with [winrm-krb5-spn], e.g.
+	HTTP/vm.example.com. Guest operations still use the VM password. Kerberos messages are not encrypted over HTTP
+	either, so use it with [winrm-https].
+
+Windows Updates:
+	With [windows-updates], construct installs the available Windows Updates after the VM has rebooted from the setup
+	script and the post-setup hooks have run. Each batch installs everything available, the VM is rebooted whenever a
+	batch needs it, and batches are installed until Test-InstalledUpdates finds none left or
+	[windows-updates-max-iterations] is reached. The KB of every update installed is reported.
+
+Proxy:
+	With [http-proxy] or [https-proxy], construct sets the proxy of the VM with Set-ProxySettings before the setup
+	script, so that the root certificates and Windows Updates are downloaded through it, and clears it again before
+	sysprep so that it does not end up in the stemcell. [proxy-bypass-list] lists the hosts reached directly.
+
+Hardening:
+	[hardening-profile] selects the security hardening applied to the VM. cis, the default, applies the embedded CIS
+	GPO and disables RC4, 3DES, TLS 1.0, TLS 1.1 and DCOM. minimal only disables RC4 and 3DES and enables TLS 1.2, and
+	none leaves the security settings alone. With [hardening-gpo-dir], a GPO backup made by LGPO /b is uploaded and
+	applied in place of the embedded one. The profile applied is recorded in the VM and in the stemcell manifest.
+
+Transport:
+	Directories and files are created on the VM through vSphere guest operations by default, which transfer files
+	through the ESXi host. With [transport] winrm, they go directly to the VM over WinRM instead. If the chosen
+	transport fails, construct retries over the other one, enabling WinRM first if needed. The progress, throughput
+	and ETA of each upload are reported either way.
+
+Guest operations only:
+	With [guest-ops-only], construct never connects to the VM over the network: extracting, logging out users, the setup
+	and post-reboot scripts and hooks all run through VMware Tools, and the end of the reboot is detected from the guest
+	heartbeat and boot time. [vm-ip] is optional in this mode, files are never retried over WinRM, and WinRM is not
+	enabled on the VM.
+
+Timeouts:
+	Every wait can be tuned for slow datastores or long update runs, e.g. -post-reboot-timeout 36h -winrm-timeout 5m.
+	[timeout] is an overall deadline for construct: once it passes, construct stops, collects diagnostics and reverts to
+	[snapshot] if one was given. A zero [timeout], [reboot-timeout] or [shutdown-timeout] waits as long as it takes.
+
+Dry run:
+	With [dry-run], construct logs in to vCenter, finds the VM and verifies LGPO.zip, [hooks-dir] and [snapshot], then prints
+	the changes it would make in order (the clone, snapshot, directories, uploads and scripts) without making any of them.
+
+Assets:
+	The stemcell automation scripts are compiled into stembuild and never written to the working directory. [automation-bundle]
+	uploads another StemcellAutomation.zip instead, and [lgpo] gives the path of LGPO.zip. Before anything is uploaded,
+	the files in the bundle and LGPO.zip are checked against the SHA-256 hashes in the deps.json of the bundle.
+
+Credentials:
+	Instead of the password itself, [vm-password] and [vcenter-password] take a reference to it, so it stays out of
+	shell history and process listings: env://NAME reads environment variable NAME, file:///path reads the first line
+	of a file, stdin:// prompts for it, and vault://secret/data/path#key reads key from a Vault KV secret using
+	VAULT_ADDR and VAULT_TOKEN.
+
+Build configuration:
+	With [config], options are read from a YAML file with vcenter, vm and construct sections. Each key can be
+	overridden by a STEMBUILD_* environment variable, e.g. STEMBUILD_VCENTER_PASSWORD, and flags override both.
+
+Batch:
+	With [targets], construct runs against every VM listed in a YAML file, up to [max-parallel] at a time. Each target
+	takes the keys of the build configuration file that differ for its VM, such as vm.inventory_path, vm.ip, vm.password
+	or vcenter.url, and every other setting comes from the flags, environment and [config]. The output of each VM is
+	prefixed with its name, and a summary of which VMs succeeded and failed is printed at the end.
+
+Resuming:
+	Each completed phase is recorded per VM inventory path. If construct fails, re-run the same command with [resume] to
+	continue from the last phase that can be confirmed on the VM instead of starting over. An interrupted construct
+	reports whether it can be resumed.
+
 Flags:
 `, filepath.Base(os.Args[0]))
 }
 
 func (p *ConstructCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.configFile, "config", "", "YAML build configuration file. Flags and STEMBUILD_* environment variables take precedence over it")
 	f.StringVar(&p.sourceConfig.GuestVmIp, "vm-ip", "", "IP of target machine")
 	f.StringVar(&p.sourceConfig.GuestVMUsername, "vm-username", "", "Username of target machine")
-	f.StringVar(&p.sourceConfig.GuestVMPassword, "vm-password", "", "Password of target machine. Needs to be wrapped in single quotations.")
+	f.StringVar(&p.sourceConfig.GuestVMPassword, "vm-password", "", "Password of target machine, or an env://, file://, stdin:// or vault:// reference to it")
 	f.StringVar(&p.sourceConfig.VCenterUrl, "vcenter-url", "", "vCenter url")
 	f.StringVar(&p.sourceConfig.VCenterUsername, "vcenter-username", "", "vCenter username")
-	f.StringVar(&p.sourceConfig.VCenterPassword, "vcenter-password", "", "vCenter password")
+	f.StringVar(&p.sourceConfig.VCenterPassword, "vcenter-password", "", "vCenter password, or an env://, file://, stdin:// or vault:// reference to it")
 	f.StringVar(&p.sourceConfig.VmInventoryPath, "vm-inventory-path", "", "vCenter VM inventory path. (e.g: <datacenter>/vm/<vm-folder>/<vm-name>)")
 	f.StringVar(&p.sourceConfig.CaCertFile, "vcenter-ca-certs", "", "filepath for custom ca certs")
+	f.BoolVar(&p.sourceConfig.Resume, "resume", false, "Resume a previously failed construct from the last phase confirmed on the VM")
+	f.StringVar(&p.sourceConfig.Organization, "organization", "", "Organization name stamped into the VM by sysprep")
+	f.StringVar(&p.sourceConfig.Owner, "owner", "", "Owner name stamped into the VM by sysprep")
+	f.BoolVar(&p.sourceConfig.SkipRandomPassword, "skip-random-password", false, "Keep the current Administrator password instead of randomizing it during sysprep. Intended for debug images only.")
+	f.StringVar(&p.sourceConfig.DiagnosticsDir, "diagnostics-dir", "", "Directory where a diagnostics bundle of guest logs is written if construct fails (default: current working directory)")
+	f.StringVar(&p.sourceConfig.CloneTo, "clone-to", "", "Inventory path of a clone of [vm-inventory-path] to construct instead of the VM itself")
+	f.StringVar(&p.sourceConfig.CloneFolder, "clone-folder", "", "vCenter folder for the clone (default: the folder in [clone-to])")
+	f.StringVar(&p.sourceConfig.CloneResourcePool, "clone-resource-pool", "", "vCenter resource pool for the clone (default: the resource pool of the source VM)")
+	f.StringVar(&p.sourceConfig.CloneDatastore, "clone-datastore", "", "vCenter datastore for the clone (default: the datastore of the source VM)")
+	f.StringVar(&p.sourceConfig.SnapshotName, "snapshot", "", "Name of a snapshot taken before construct and reverted to if construct fails")
+	f.BoolVar(&p.sourceConfig.RevertSnapshot, "revert-snapshot", false, "Revert the VM to [snapshot] and exit without running construct")
+	f.BoolVar(&p.sourceConfig.DeleteSnapshot, "delete-snapshot", false, "Delete [snapshot] after construct succeeds")
+	f.StringVar(&p.sourceConfig.HooksDir, "hooks-dir", "", "Directory of PowerShell scripts to run in the VM, in pre-setup, post-setup and pre-sysprep subdirectories")
+	f.BoolVar(&p.sourceConfig.DryRun, "dry-run", false, "Validate the arguments, vCenter credentials and VM, and print the changes construct would make, without making any")
+	f.StringVar(&p.sourceConfig.Transport, "transport", "guest-ops", "How to create directories and upload files on the VM, guest-ops or winrm. The other is tried if it fails")
+	f.BoolVar(&p.sourceConfig.GuestOpsOnly, "guest-ops-only", false, "Run every command on the VM through vSphere guest operations instead of connecting to it over WinRM. [vm-ip] is not needed")
+	f.StringVar(&p.sourceConfig.TargetsFile, "targets", "", "YAML file listing several VMs to construct at once, each with the settings that differ from the other flags")
+	f.IntVar(&p.sourceConfig.MaxParallel, "max-parallel", 4, "Number of VMs in [targets] constructed at the same time")
+	f.StringVar(&p.sourceConfig.AutomationBundle, "automation-bundle", "", "StemcellAutomation.zip to upload instead of the one compiled into stembuild")
+	f.StringVar(&p.sourceConfig.LGPO, "lgpo", "", "filepath of LGPO.zip (default: LGPO.zip in the current working directory or next to stembuild)")
+	f.BoolVar(&p.sourceConfig.WindowsUpdates, "windows-updates", false, "Install the available Windows Updates once the VM has rebooted after the setup script, rebooting as often as they need")
+	f.IntVar(&p.sourceConfig.WindowsUpdatesMaxIterations, "windows-updates-max-iterations", 5, "Number of batches of Windows Updates installed at most with [windows-updates]")
+	f.StringVar(&p.sourceConfig.Proxy.HTTP, "http-proxy", "", "host:port of the HTTP proxy the VM uses during construct. It is cleared before sysprep")
+	f.StringVar(&p.sourceConfig.Proxy.HTTPS, "https-proxy", "", "host:port of the HTTPS proxy the VM uses during construct. It is cleared before sysprep")
+	f.StringVar(&p.sourceConfig.Proxy.BypassList, "proxy-bypass-list", "", "Semicolon separated hosts the VM reaches without [http-proxy] or [https-proxy], e.g. '*.example.com;<local>'")
+	f.StringVar(&p.sourceConfig.Hardening.Profile, "hardening-profile", "", "Security hardening applied to the VM, cis, minimal or none (default: cis)")
+	f.StringVar(&p.sourceConfig.Hardening.GPODir, "hardening-gpo-dir", "", "Directory of a GPO backup made by LGPO /b, applied in place of the embedded CIS GPO. Excludes [hardening-profile]")
+	f.IntVar(&p.sourceConfig.WinRM.Port, "winrm-port", 0, "Port of the WinRM listener on the VM (default: 5985, or 5986 with [winrm-https])")
+	f.BoolVar(&p.sourceConfig.WinRM.HTTPS, "winrm-https", false, "Connect to WinRM over HTTPS, provisioning an HTTPS listener on the VM")
+	f.StringVar(&p.sourceConfig.WinRM.CACertFile, "winrm-ca-certs", "", "filepath for the CA certs the WinRM HTTPS listener is verified against (default: trust the listener certificate construct provisions)")
+	f.BoolVar(&p.sourceConfig.WinRM.Insecure, "winrm-insecure", false, "Skip verifying the certificate of the WinRM HTTPS listener")
+	f.StringVar(&p.sourceConfig.WinRM.Auth, "winrm-auth", "basic", "WinRM authentication, basic, ntlm or kerberos")
+	f.StringVar(&p.sourceConfig.WinRM.Kerberos.Realm, "winrm-krb5-realm", "", "Kerberos realm of the VM user, needed with [winrm-krb5-keytab]")
+	f.StringVar(&p.sourceConfig.WinRM.Kerberos.Keytab, "winrm-krb5-keytab", "", "filepath for the Kerberos keytab of the VM user, with [winrm-auth] kerberos")
+	f.StringVar(&p.sourceConfig.WinRM.Kerberos.CCache, "winrm-krb5-ccache", "", "filepath for a Kerberos credential cache with a ticket of the VM user, with [winrm-auth] kerberos")
+	f.StringVar(&p.sourceConfig.WinRM.Kerberos.Config, "winrm-krb5-conf", "", "filepath for the krb5.conf locating the KDCs of the realm (default: /etc/krb5.conf)")
+	f.StringVar(&p.sourceConfig.WinRM.Kerberos.SPN, "winrm-krb5-spn", "", "Service principal of WinRM on the VM (default: HTTP/<vm-ip>)")
+	f.StringVar(&p.sourceConfig.CheckpointFile, "checkpoint-file", "", "filepath where completed construct phases are recorded (default: ~/.stembuild/construct-checkpoints.json)")
+
+	timeouts := config.DefaultTimeouts()
+	f.DurationVar(&p.sourceConfig.Timeouts.Construct, "timeout", timeouts.Construct, "Overall deadline for construct, 0 for none")
+	f.DurationVar(&p.sourceConfig.Timeouts.RebootDelay, "reboot-delay", timeouts.RebootDelay, "Time to wait after the setup script before checking whether the VM has rebooted")
+	f.DurationVar(&p.sourceConfig.Timeouts.RebootPollInterval, "reboot-poll-interval", timeouts.RebootPollInterval, "Interval between checks whether the VM has rebooted")
+	f.DurationVar(&p.sourceConfig.Timeouts.Reboot, "reboot-timeout", timeouts.Reboot, "Time to wait for the VM to finish rebooting, 0 for no limit")
+	f.DurationVar(&p.sourceConfig.Timeouts.PostRebootScript, "post-reboot-timeout", timeouts.PostRebootScript, "Time to wait for the post-reboot script, which runs sysprep")
+	f.DurationVar(&p.sourceConfig.Timeouts.ShutdownPollInterval, "shutdown-poll-interval", timeouts.ShutdownPollInterval, "Interval between checks whether the VM has powered off")
+	f.DurationVar(&p.sourceConfig.Timeouts.Shutdown, "shutdown-timeout", timeouts.Shutdown, "Time to wait for the VM to power off after sysprep, 0 for no limit")
+	f.DurationVar(&p.sourceConfig.Timeouts.WinRMOperation, "winrm-timeout", timeouts.WinRMOperation, "Timeout for WinRM commands and uploads")
+	f.DurationVar(&p.sourceConfig.Timeouts.WinRMConnect, "winrm-connect-timeout", timeouts.WinRMConnect, "Timeout for connecting to WinRM on the VM")
+	f.DurationVar(&p.sourceConfig.Timeouts.Hook, "hook-timeout", timeouts.Hook, "Time to wait for each provisioning hook script")
+	f.DurationVar(&p.sourceConfig.Timeouts.WindowsUpdates, "windows-updates-timeout", timeouts.WindowsUpdates, "Time to wait for each search for Windows Updates and each batch of them to install")
 }
 
 func (p *ConstructCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	c := p.sourceConfig
-	if !p.validator.PopulatedArgs(c.GuestVmIp, c.GuestVMUsername, c.GuestVMPassword, c.VCenterUrl, c.VCenterUsername, c.VCenterPassword, c.VmInventoryPath) {
-		p.messenger.ArgumentsNotProvided()
-		return subcommands.ExitFailure
+	messenger := p.messenger
+	if p.Events != nil {
+		messenger = &JSONConstructCmdMessenger{Events: p.Events}
 	}
-	if !p.validator.LGPOInDirectory() {
-		p.messenger.LGPONotFound()
+
+	err := applyBuildConfig(f, p.configFile, buildconfig.ConstructSettings(&p.sourceConfig))
+	if err != nil {
+		messenger.InvalidBuildConfig(err)
 		return subcommands.ExitFailure
 	}
 
-	p.managerFactory.SetConfig(vcenter_client_factory.FactoryConfig{
-		p.sourceConfig.VCenterUrl,
-		p.sourceConfig.VCenterUsername,
-		p.sourceConfig.VCenterPassword,
-		&vcenter_client_factory.ClientCreator{},
-		&vcenter_client_factory.GovmomiFinderCreator{},
-		p.sourceConfig.CaCertFile,
-	})
+	if p.sourceConfig.TargetsFile != "" {
+		return p.executeBatch(messenger)
+	}
 
-	vCenterManager, err := p.managerFactory.VCenterManager(p.ctx)
+	err = resolveCredentials(p.Credentials, map[string]*string{
+		"vCenter password": &p.sourceConfig.VCenterPassword,
+		"VM password":      &p.sourceConfig.GuestVMPassword,
+	})
 	if err != nil {
-		p.messenger.CannotPrepareVM(err)
+		messenger.CannotResolveCredential(err)
 		return subcommands.ExitFailure
 	}
 
-	vmConstruct, err := p.prepFactory.VMPreparer(p.sourceConfig, vCenterManager)
+	if !p.validator.PopulatedArgs(requiredArgs(p.sourceConfig)...) {
+		messenger.ArgumentsNotProvided()
+		return subcommands.ExitFailure
+	}
+	err = p.validator.VerifyAssets(p.sourceConfig.AutomationBundle, p.sourceConfig.LGPO)
 	if err != nil {
-		p.messenger.CannotPrepareVM(err)
+		messenger.AssetsNotVerified(err)
 		return subcommands.ExitFailure
 	}
 
-	err = vmConstruct.PrepareVM()
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+	stopCatching := catchInterrupts(cancel, messenger)
+	defer stopCatching()
+
+	err = p.construct(ctx, p.sourceConfig, VMOutput{p.stdout(), p.stderr(), p.Events})
 	if err != nil {
-		p.messenger.CannotPrepareVM(err)
+		messenger.CannotPrepareVM(err)
 		return subcommands.ExitFailure
 	}
 
 	return subcommands.ExitSuccess
 }
+
+func requiredArgs(c config.SourceConfig) []string {
+	args := []string{c.GuestVMUsername, c.GuestVMPassword, c.VCenterUrl, c.VCenterUsername, c.VCenterPassword, c.VmInventoryPath}
+	if c.CloneTo == "" && !c.GuestOpsOnly {
+		// The IP of a fresh clone is discovered once it has booted, and guest operations do not need it
+		args = append(args, c.GuestVmIp)
+	}
+	if c.RevertSnapshot || c.DeleteSnapshot {
+		args = append(args, c.SnapshotName)
+	}
+	return args
+}
+
+// construct prepares the VM described by c, or reverts it to its snapshot with [revert-snapshot]
+func (p *ConstructCmd) construct(ctx context.Context, c config.SourceConfig, output VMOutput) error {
+	p.managerMutex.Lock()
+	p.managerFactory.SetConfig(vcenter_client_factory.FactoryConfig{
+		c.VCenterUrl,
+		c.VCenterUsername,
+		c.VCenterPassword,
+		&vcenter_client_factory.ClientCreator{},
+		&vcenter_client_factory.GovmomiFinderCreator{},
+		c.CaCertFile,
+	})
+	vCenterManager, err := p.managerFactory.VCenterManager(ctx)
+	p.managerMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	vmConstruct, err := p.prepFactory.VMPreparer(ctx, c, vCenterManager, output)
+	if err != nil {
+		return err
+	}
+
+	if c.RevertSnapshot {
+		return vmConstruct.RevertSnapshot()
+	}
+	return vmConstruct.PrepareVM()
+}
+
+func (p *ConstructCmd) stdout() io.Writer {
+	if p.Events != nil {
+		return p.stderr()
+	}
+	if p.Stdout == nil {
+		return os.Stdout
+	}
+	return p.Stdout
+}
+
+func (p *ConstructCmd) stderr() io.Writer {
+	if p.Stderr == nil {
+		return os.Stderr
+	}
+	return p.Stderr
+}
+
+// interruptMessenger reports the interrupts caught by catchInterrupts
+type interruptMessenger interface {
+	InterruptReceived(signal string)
+	SecondInterruptReceived(signal string)
+}
+
+// catchInterrupts cancels the command on the first interrupt, so that it stops cleanly and reports the state
+// of the VM, and exits on the second. The returned function stops catching interrupts.
+func catchInterrupts(cancel context.CancelFunc, messenger interruptMessenger) func() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		interrupted := false
+		for {
+			select {
+			case sig := <-signals:
+				if interrupted {
+					messenger.SecondInterruptReceived(sig.String())
+					os.Exit(1)
+				}
+				interrupted = true
+				messenger.InterruptReceived(sig.String())
+				cancel()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
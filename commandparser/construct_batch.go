@@ -0,0 +1,184 @@
+package commandparser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/buildconfig"
+	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/google/subcommands"
+)
+
+// BatchResult is the outcome of constructing one VM of a batch
+type BatchResult struct {
+	Name string
+	// InventoryPath is the VM that was constructed, which is the clone when the target clones its VM
+	InventoryPath string
+	Duration      time.Duration
+	Err           error
+}
+
+// executeBatch constructs every VM of the targets file, up to MaxParallel at a time. All targets are
+// validated and their credentials resolved before the first VM is touched.
+func (p *ConstructCmd) executeBatch(messenger ConstructMessenger) subcommands.ExitStatus {
+	targets, err := buildconfig.LoadTargets(p.sourceConfig.TargetsFile)
+	if err != nil {
+		messenger.InvalidBuildConfig(err)
+		return subcommands.ExitFailure
+	}
+	if p.sourceConfig.MaxParallel < 1 {
+		messenger.InvalidBuildConfig(fmt.Errorf("max-parallel must be at least 1, got %d", p.sourceConfig.MaxParallel))
+		return subcommands.ExitFailure
+	}
+
+	var credentials CredentialResolver
+	if p.Credentials != nil {
+		credentials = &onceCredentialResolver{resolver: p.Credentials, resolved: map[string]string{}}
+	}
+
+	configs := make([]config.SourceConfig, len(targets))
+	for i, target := range targets {
+		c := p.sourceConfig
+		err = target.Apply(buildconfig.ConstructSettings(&c))
+		if err != nil {
+			messenger.InvalidBuildConfig(err)
+			return subcommands.ExitFailure
+		}
+
+		err = resolveCredentials(credentials, map[string]*string{
+			"vCenter password": &c.VCenterPassword,
+			"VM password":      &c.GuestVMPassword,
+		})
+		if err != nil {
+			messenger.CannotResolveCredential(fmt.Errorf("target %s: %s", target.Name, err))
+			return subcommands.ExitFailure
+		}
+
+		if !p.validator.PopulatedArgs(requiredArgs(c)...) {
+			messenger.InvalidBuildConfig(fmt.Errorf("target %s: not all required parameters were provided", target.Name))
+			return subcommands.ExitFailure
+		}
+		configs[i] = c
+	}
+	err = p.verifyAssets(configs)
+	if err != nil {
+		messenger.AssetsNotVerified(err)
+		return subcommands.ExitFailure
+	}
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+	stopCatching := catchInterrupts(cancel, messenger)
+	defer stopCatching()
+
+	// One lock per underlying writer: with -output json, stdout is stderr too
+	stdoutLock, stderrLock := &sync.Mutex{}, &sync.Mutex{}
+	if p.stdout() == p.stderr() {
+		stdoutLock = stderrLock
+	}
+	results := make([]BatchResult, len(targets))
+	workers := make(chan struct{}, p.sourceConfig.MaxParallel)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		workers <- struct{}{}
+		if ctx.Err() != nil {
+			<-workers
+			results[i] = BatchResult{Name: target.Name, InventoryPath: inventoryPath(configs[i]), Err: fmt.Errorf("construct was interrupted before it started")}
+			continue
+		}
+
+		stdout := newPrefixWriter(p.stdout(), stdoutLock, target.Name)
+		stderr := newPrefixWriter(p.stderr(), stderrLock, target.Name)
+
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			results[i] = p.constructTarget(ctx, name, configs[i], stdout, stderr, messenger)
+		}(i, target.Name)
+	}
+	wg.Wait()
+
+	messenger.BatchSummary(results)
+	for _, result := range results {
+		if result.Err != nil {
+			return subcommands.ExitFailure
+		}
+	}
+	return subcommands.ExitSuccess
+}
+
+// verifyAssets verifies each automation bundle and LGPO.zip the targets upload once
+func (p *ConstructCmd) verifyAssets(configs []config.SourceConfig) error {
+	verified := map[[2]string]bool{}
+	for _, c := range configs {
+		assets := [2]string{c.AutomationBundle, c.LGPO}
+		if verified[assets] {
+			continue
+		}
+		err := p.validator.VerifyAssets(c.AutomationBundle, c.LGPO)
+		if err != nil {
+			return err
+		}
+		verified[assets] = true
+	}
+	return nil
+}
+
+func (p *ConstructCmd) constructTarget(ctx context.Context, name string, c config.SourceConfig, stdout, stderr *prefixWriter, messenger ConstructMessenger) BatchResult {
+	messenger.BatchTargetStarted(name)
+	start := time.Now()
+
+	output := VMOutput{Stdout: stdout, Stderr: stderr}
+	if p.Events != nil {
+		output.Events = &vmSink{p.Events, name}
+	}
+	err := p.construct(ctx, c, output)
+	_ = stdout.Flush()
+	_ = stderr.Flush()
+
+	result := BatchResult{Name: name, InventoryPath: inventoryPath(c), Duration: time.Since(start), Err: err}
+	messenger.BatchTargetFinished(result)
+	return result
+}
+
+func inventoryPath(c config.SourceConfig) string {
+	if c.CloneTo != "" {
+		return c.CloneTo
+	}
+	return c.VmInventoryPath
+}
+
+// onceCredentialResolver resolves each reference once, so that a stdin:// reference shared by
+// several targets is only prompted for once
+type onceCredentialResolver struct {
+	resolver CredentialResolver
+	resolved map[string]string
+}
+
+func (r *onceCredentialResolver) Resolve(name, value string) (string, error) {
+	key := name + "\x00" + value
+	if secret, ok := r.resolved[key]; ok {
+		return secret, nil
+	}
+	secret, err := r.resolver.Resolve(name, value)
+	if err != nil {
+		return "", err
+	}
+	r.resolved[key] = secret
+	return secret, nil
+}
+
+// vmSink names the VM of a batch construct in every event it emits
+type vmSink struct {
+	sink events.Sink
+	vm   string
+}
+
+func (s *vmSink) Emit(event events.Event) {
+	event.VM = s.vm
+	s.sink.Emit(event)
+}
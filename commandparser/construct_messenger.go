@@ -3,6 +3,11 @@ package commandparser
 import (
 	"fmt"
 	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
 )
 
 type ConstructCmdMessenger struct {
@@ -17,8 +22,8 @@ func (m *ConstructCmdMessenger) ArgumentsNotProvided() {
 	m.printMessage("Not all required parameters were provided. See stembuild --help for more details")
 }
 
-func (m *ConstructCmdMessenger) LGPONotFound() {
-	m.printMessage("Could not find LGPO.zip in the current directory")
+func (m *ConstructCmdMessenger) AssetsNotVerified(err error) {
+	m.printMessage(fmt.Sprintf("Could not verify StemcellAutomation.zip and LGPO.zip: %s", err))
 }
 
 func (m *ConstructCmdMessenger) CannotConnectToVM(err error) {
@@ -28,3 +33,118 @@ func (m *ConstructCmdMessenger) CannotConnectToVM(err error) {
 func (m *ConstructCmdMessenger) CannotPrepareVM(err error) {
 	m.printMessage(fmt.Sprintf("Could not prepare VM: %s", err))
 }
+
+func (m *ConstructCmdMessenger) InvalidBuildConfig(err error) {
+	m.printMessage(fmt.Sprintf("Invalid build configuration: %s", err))
+}
+
+func (m *ConstructCmdMessenger) CannotResolveCredential(err error) {
+	m.printMessage(err.Error())
+}
+
+func (m *ConstructCmdMessenger) InterruptReceived(signal string) {
+	m.printMessage(fmt.Sprintf("Received %s signal, stopping construct. Interrupt again to exit immediately", signal))
+}
+
+func (m *ConstructCmdMessenger) SecondInterruptReceived(signal string) {
+	m.printMessage(fmt.Sprintf("Received second %s signal, exiting now. The VM may be left part way through construct", signal))
+}
+
+func (m *ConstructCmdMessenger) BatchTargetStarted(name string) {
+	m.printMessage(fmt.Sprintf("Starting construct of %s", name))
+}
+
+func (m *ConstructCmdMessenger) BatchTargetFinished(result BatchResult) {
+	if result.Err != nil {
+		m.printMessage(fmt.Sprintf("Construct of %s failed after %s: %s", result.Name, result.Duration.Round(time.Second), result.Err))
+		return
+	}
+	m.printMessage(fmt.Sprintf("Construct of %s succeeded in %s", result.Name, result.Duration.Round(time.Second)))
+}
+
+func (m *ConstructCmdMessenger) BatchSummary(results []BatchResult) {
+	failed := 0
+	m.printMessage("")
+	table := tabwriter.NewWriter(m.OutputChannel, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VM\tINVENTORY PATH\tRESULT\tDURATION\tERROR")
+	for _, result := range results {
+		status, message := "succeeded", ""
+		if result.Err != nil {
+			failed++
+			status, message = "failed", result.Err.Error()
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", result.Name, result.InventoryPath, status, result.Duration.Round(time.Second), message)
+	}
+	_ = table.Flush()
+	m.printMessage(fmt.Sprintf("\n%d of %d VMs constructed, %d failed", len(results)-failed, len(results), failed))
+}
+
+// JSONConstructCmdMessenger reports construct command failures as events.
+type JSONConstructCmdMessenger struct {
+	Events events.Sink
+}
+
+func (m *JSONConstructCmdMessenger) emitFailure(phase, event string, err string) {
+	m.Events.Emit(events.Event{Command: "construct", Phase: phase, Event: event, Status: events.Failed, Error: err})
+}
+
+func (m *JSONConstructCmdMessenger) ArgumentsNotProvided() {
+	m.emitFailure("validate", "ArgumentsNotProvided", "Not all required parameters were provided")
+}
+
+func (m *JSONConstructCmdMessenger) AssetsNotVerified(err error) {
+	m.emitFailure("validate", "AssetsNotVerified", err.Error())
+}
+
+func (m *JSONConstructCmdMessenger) CannotConnectToVM(err error) {
+	m.emitFailure("", "CannotConnectToVM", err.Error())
+}
+
+func (m *JSONConstructCmdMessenger) CannotPrepareVM(err error) {
+	m.emitFailure("", "CannotPrepareVM", err.Error())
+}
+
+func (m *JSONConstructCmdMessenger) InvalidBuildConfig(err error) {
+	m.emitFailure("validate", "InvalidBuildConfig", err.Error())
+}
+
+func (m *JSONConstructCmdMessenger) CannotResolveCredential(err error) {
+	m.emitFailure("validate", "CannotResolveCredential", err.Error())
+}
+
+func (m *JSONConstructCmdMessenger) InterruptReceived(signal string) {
+	m.Events.Emit(events.Event{Command: "construct", Event: "InterruptReceived", Status: events.Warning, Message: signal})
+}
+
+func (m *JSONConstructCmdMessenger) SecondInterruptReceived(signal string) {
+	m.Events.Emit(events.Event{Command: "construct", Event: "SecondInterruptReceived", Status: events.Warning, Message: signal})
+}
+
+func (m *JSONConstructCmdMessenger) BatchTargetStarted(name string) {
+	m.Events.Emit(events.Event{Command: "construct", VM: name, Phase: "batch", Event: "BatchTargetStarted", Status: events.Started})
+}
+
+func (m *JSONConstructCmdMessenger) BatchTargetFinished(result BatchResult) {
+	if result.Err != nil {
+		m.Events.Emit(events.Event{Command: "construct", VM: result.Name, Phase: "batch", Event: "BatchTargetFailed", Status: events.Failed, Message: result.InventoryPath, Error: result.Err.Error()})
+		return
+	}
+	m.Events.Emit(events.Event{Command: "construct", VM: result.Name, Phase: "batch", Event: "BatchTargetSucceeded", Status: events.Succeeded, Message: result.InventoryPath})
+}
+
+func (m *JSONConstructCmdMessenger) BatchSummary(results []BatchResult) {
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.Name)
+		}
+	}
+
+	status := events.Succeeded
+	message := fmt.Sprintf("%d of %d VMs constructed", len(results)-len(failed), len(results))
+	if len(failed) > 0 {
+		status = events.Failed
+		message += ", failed: " + strings.Join(failed, ", ")
+	}
+	m.Events.Emit(events.Event{Command: "construct", Phase: "batch", Event: "BatchSummary", Status: status, Message: message})
+}
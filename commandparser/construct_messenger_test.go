@@ -2,7 +2,11 @@ package commandparser_test
 
 import (
 	"errors"
+	"time"
+
 	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
@@ -26,10 +30,10 @@ var _ = Describe("ConstructMessenger", func() {
 		})
 	})
 
-	Describe("LGPONotFound", func() {
+	Describe("AssetsNotVerified", func() {
 		It("should output an appropriate error", func() {
-			cm.LGPONotFound()
-			Eventually(g).Should(Say("Could not find LGPO.zip in the current directory"))
+			cm.AssetsNotVerified(errors.New("could not find LGPO.zip"))
+			Eventually(g).Should(Say("Could not verify StemcellAutomation.zip and LGPO.zip: could not find LGPO.zip"))
 		})
 	})
 
@@ -48,4 +52,129 @@ var _ = Describe("ConstructMessenger", func() {
 			Eventually(g).Should(Say("Could not prepare VM: %s", preparationError))
 		})
 	})
+
+	Describe("InterruptReceived", func() {
+		It("explains that construct is stopping and how to exit immediately", func() {
+			cm.InterruptReceived("interrupt")
+			cm.SecondInterruptReceived("interrupt")
+			Eventually(g).Should(Say("Received interrupt signal, stopping construct. Interrupt again to exit immediately"))
+			Eventually(g).Should(Say("Received second interrupt signal, exiting now. The VM may be left part way through construct"))
+		})
+	})
+
+	Describe("BatchTargetFinished", func() {
+		It("reports how long the VM took and why it failed", func() {
+			cm.BatchTargetFinished(commandparser.BatchResult{Name: "windows2019", Duration: 90 * time.Second})
+			cm.BatchTargetFinished(commandparser.BatchResult{Name: "windows1803", Duration: time.Hour, Err: errors.New("setup script failed")})
+			Eventually(g).Should(Say("Construct of windows2019 succeeded in 1m30s"))
+			Eventually(g).Should(Say("Construct of windows1803 failed after 1h0m0s: setup script failed"))
+		})
+	})
+
+	Describe("BatchSummary", func() {
+		It("prints a table of the results", func() {
+			cm.BatchSummary([]commandparser.BatchResult{
+				{Name: "windows2019", InventoryPath: "/dc/vm/windows2019", Duration: 2 * time.Hour},
+				{Name: "windows1803", InventoryPath: "/dc/vm/windows1803", Duration: 3 * time.Minute, Err: errors.New("setup script failed")},
+			})
+			Eventually(g).Should(Say(`VM\s+INVENTORY PATH\s+RESULT\s+DURATION\s+ERROR`))
+			Eventually(g).Should(Say(`windows2019\s+/dc/vm/windows2019\s+succeeded\s+2h0m0s`))
+			Eventually(g).Should(Say(`windows1803\s+/dc/vm/windows1803\s+failed\s+3m0s\s+setup script failed`))
+			Eventually(g).Should(Say("1 of 2 VMs constructed, 1 failed"))
+		})
+	})
+})
+
+var _ = Describe("JSONConstructCmdMessenger", func() {
+	var (
+		sink *eventsfakes.FakeSink
+		cm   *commandparser.JSONConstructCmdMessenger
+	)
+
+	BeforeEach(func() {
+		sink = &eventsfakes.FakeSink{}
+		cm = &commandparser.JSONConstructCmdMessenger{Events: sink}
+	})
+
+	It("emits a failed validate event when arguments are missing", func() {
+		cm.ArgumentsNotProvided()
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Command).To(Equal("construct"))
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("ArgumentsNotProvided"))
+		Expect(event.Status).To(Equal(events.Failed))
+	})
+
+	It("emits a failed event with the error when the VM cannot be prepared", func() {
+		cm.CannotPrepareVM(errors.New("some prepare error"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Event).To(Equal("CannotPrepareVM"))
+		Expect(event.Phase).To(BeEmpty())
+		Expect(event.Error).To(Equal("some prepare error"))
+	})
+
+	It("emits a failed validate event naming the invalid build configuration", func() {
+		cm.InvalidBuildConfig(errors.New("unknown key vcenter.uri"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("InvalidBuildConfig"))
+		Expect(event.Error).To(Equal("unknown key vcenter.uri"))
+	})
+
+	It("emits a failed validate event when a credential cannot be resolved", func() {
+		cm.CannotResolveCredential(errors.New("unable to read VM password: environment variable VM_PASSWORD is not set"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("CannotResolveCredential"))
+		Expect(event.Error).To(Equal("unable to read VM password: environment variable VM_PASSWORD is not set"))
+	})
+
+	It("emits a failed validate event when the assets cannot be verified", func() {
+		cm.AssetsNotVerified(errors.New("LGPO.zip does not match deps.json"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("AssetsNotVerified"))
+		Expect(event.Error).To(Equal("LGPO.zip does not match deps.json"))
+	})
+
+	It("emits the result of each VM of a batch for that VM", func() {
+		cm.BatchTargetStarted("windows2019")
+		cm.BatchTargetFinished(commandparser.BatchResult{Name: "windows2019", InventoryPath: "/dc/vm/windows2019", Err: errors.New("setup script failed")})
+
+		started := sink.EmitArgsForCall(0)
+		Expect(started.VM).To(Equal("windows2019"))
+		Expect(started.Status).To(Equal(events.Started))
+		finished := sink.EmitArgsForCall(1)
+		Expect(finished.VM).To(Equal("windows2019"))
+		Expect(finished.Phase).To(Equal(started.Phase))
+		Expect(finished.Event).To(Equal("BatchTargetFailed"))
+		Expect(finished.Message).To(Equal("/dc/vm/windows2019"))
+		Expect(finished.Error).To(Equal("setup script failed"))
+	})
+
+	It("emits a batch summary naming the VMs that failed", func() {
+		cm.BatchSummary([]commandparser.BatchResult{
+			{Name: "windows2019"},
+			{Name: "windows1803", Err: errors.New("setup script failed")},
+		})
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Event).To(Equal("BatchSummary"))
+		Expect(event.Status).To(Equal(events.Failed))
+		Expect(event.Message).To(Equal("1 of 2 VMs constructed, failed: windows1803"))
+	})
+
+	It("emits interrupts as warnings", func() {
+		cm.InterruptReceived("interrupt")
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Event).To(Equal("InterruptReceived"))
+		Expect(event.Status).To(Equal(events.Warning))
+		Expect(event.Message).To(Equal("interrupt"))
+	})
 })
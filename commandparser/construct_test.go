@@ -1,12 +1,23 @@
 package commandparser_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/cloudfoundry-incubator/stembuild/commandparser"
 	"github.com/cloudfoundry-incubator/stembuild/commandparser/commandparserfakes"
+	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
 	"github.com/google/subcommands"
 
 	. "github.com/onsi/ginkgo"
@@ -37,6 +48,39 @@ var _ = Describe("construct", func() {
 			"-vcenter-password", "vCenterPassword",
 			"-vm-inventory-path", "/my-datacenter/vm/my-folder/my-vm",
 			"-vcenter-ca-certs", "somecerts.txt",
+			"-resume",
+			"-checkpoint-file", "checkpoints.json",
+			"-organization", "some-org",
+			"-owner", "some-owner",
+			"-skip-random-password",
+			"-diagnostics-dir", "/tmp/diagnostics",
+			"-clone-to", "/dc/vm/folder/clone",
+			"-clone-folder", "/dc/vm/builds",
+			"-clone-resource-pool", "/dc/host/cluster/Resources/builds",
+			"-clone-datastore", "fast-ds",
+			"-snapshot", "pre-construct",
+			"-delete-snapshot",
+			"-timeout", "6h",
+			"-post-reboot-timeout", "36h",
+			"-winrm-timeout", "5m",
+			"-hooks-dir", "/tmp/hooks",
+			"-hook-timeout", "2h",
+			"-dry-run",
+			"-winrm-https",
+			"-winrm-port", "15986",
+			"-winrm-ca-certs", "winrm-ca.pem",
+			"-winrm-auth", "ntlm",
+			"-transport", "winrm",
+			"-guest-ops-only",
+			"-targets", "targets.yml",
+			"-max-parallel", "2",
+			"-windows-updates",
+			"-windows-updates-max-iterations", "3",
+			"-windows-updates-timeout", "90m",
+			"-http-proxy", "proxy.example.com:3128",
+			"-https-proxy", "proxy.example.com:3129",
+			"-proxy-bypass-list", "*.example.com;<local>",
+			"-hardening-profile", "minimal",
 		}
 
 		It("stores the value of a vm user", func() {
@@ -92,6 +136,150 @@ var _ = Describe("construct", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(ConstrCmd.GetSourceConfig().CaCertFile).To(Equal("somecerts.txt"))
 		})
+
+		It("stores the value of resume", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().Resume).To(BeTrue())
+		})
+
+		It("stores the value of the checkpoint filepath", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().CheckpointFile).To(Equal("checkpoints.json"))
+		})
+
+		It("stores the value of the organization", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().Organization).To(Equal("some-org"))
+		})
+
+		It("stores the value of the owner", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().Owner).To(Equal("some-owner"))
+		})
+
+		It("stores the value of skip random password", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().SkipRandomPassword).To(BeTrue())
+		})
+
+		It("stores the value of the diagnostics directory", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().DiagnosticsDir).To(Equal("/tmp/diagnostics"))
+		})
+
+		It("stores the clone options", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().CloneTo).To(Equal("/dc/vm/folder/clone"))
+			Expect(ConstrCmd.GetSourceConfig().CloneFolder).To(Equal("/dc/vm/builds"))
+			Expect(ConstrCmd.GetSourceConfig().CloneResourcePool).To(Equal("/dc/host/cluster/Resources/builds"))
+			Expect(ConstrCmd.GetSourceConfig().CloneDatastore).To(Equal("fast-ds"))
+		})
+
+		It("stores the timeouts and keeps the defaults of those not given", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			timeouts := ConstrCmd.GetSourceConfig().Timeouts
+			Expect(timeouts.Construct).To(Equal(6 * time.Hour))
+			Expect(timeouts.PostRebootScript).To(Equal(36 * time.Hour))
+			Expect(timeouts.WinRMOperation).To(Equal(5 * time.Minute))
+			Expect(timeouts.RebootDelay).To(Equal(config.DefaultTimeouts().RebootDelay))
+			Expect(timeouts.WinRMConnect).To(Equal(config.DefaultTimeouts().WinRMConnect))
+		})
+
+		It("stores the hook options", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().HooksDir).To(Equal("/tmp/hooks"))
+			Expect(ConstrCmd.GetSourceConfig().Timeouts.Hook).To(Equal(2 * time.Hour))
+		})
+
+		It("stores the dry run option", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().DryRun).To(BeTrue())
+		})
+
+		It("stores the WinRM transport options", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().WinRM).To(Equal(config.WinRM{Port: 15986, HTTPS: true, CACertFile: "winrm-ca.pem", Auth: "ntlm"}))
+		})
+
+		It("stores the Kerberos options", func() {
+			err := f.Parse([]string{
+				"-winrm-auth", "kerberos",
+				"-winrm-krb5-realm", "EXAMPLE.COM",
+				"-winrm-krb5-keytab", "admin.keytab",
+				"-winrm-krb5-conf", "krb5.conf",
+				"-winrm-krb5-spn", "HTTP/vm.example.com",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().WinRM).To(Equal(config.WinRM{
+				Auth:     "kerberos",
+				Kerberos: config.Kerberos{Realm: "EXAMPLE.COM", Keytab: "admin.keytab", Config: "krb5.conf", SPN: "HTTP/vm.example.com"},
+			}))
+		})
+
+		It("stores the transport", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().Transport).To(Equal("winrm"))
+		})
+
+		It("stores the guest-ops-only mode", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().GuestOpsOnly).To(BeTrue())
+		})
+
+		It("stores the batch options", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().TargetsFile).To(Equal("targets.yml"))
+			Expect(ConstrCmd.GetSourceConfig().MaxParallel).To(Equal(2))
+		})
+
+		It("stores the Windows Updates options", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().WindowsUpdates).To(BeTrue())
+			Expect(ConstrCmd.GetSourceConfig().WindowsUpdatesMaxIterations).To(Equal(3))
+			Expect(ConstrCmd.GetSourceConfig().Timeouts.WindowsUpdates).To(Equal(90 * time.Minute))
+		})
+
+		It("stores the proxy options", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().Proxy).To(Equal(config.Proxy{
+				HTTP:       "proxy.example.com:3128",
+				HTTPS:      "proxy.example.com:3129",
+				BypassList: "*.example.com;<local>",
+			}))
+		})
+
+		It("stores the hardening options", func() {
+			err := f.Parse(append(args, "-hardening-gpo-dir", "/tmp/gpo"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().Hardening).To(Equal(config.Hardening{
+				Profile: "minimal",
+				GPODir:  "/tmp/gpo",
+			}))
+		})
+
+		It("stores the snapshot options", func() {
+			err := f.Parse(args)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ConstrCmd.GetSourceConfig().SnapshotName).To(Equal("pre-construct"))
+			Expect(ConstrCmd.GetSourceConfig().DeleteSnapshot).To(BeTrue())
+			Expect(ConstrCmd.GetSourceConfig().RevertSnapshot).To(BeFalse())
+		})
 	})
 
 	Describe("Execute", func() {
@@ -109,7 +297,7 @@ var _ = Describe("construct", func() {
 
 		BeforeEach(func() {
 			f = flag.NewFlagSet("test", flag.ExitOnError)
-			gf = &GlobalFlags{false, false, false}
+			gf = &GlobalFlags{false, false, false, TextOutput}
 
 			fakeFactory = &commandparserfakes.FakeVMPreparerFactory{}
 			fakeVmConstruct = &commandparserfakes.FakeVmConstruct{}
@@ -126,13 +314,12 @@ var _ = Describe("construct", func() {
 
 		It("should execute the construct VM command", func() {
 			fakeValidator.PopulatedArgsReturns(true)
-			fakeValidator.LGPOInDirectoryReturns(true)
 
 			exitStatus := ConstrCmd.Execute(emptyContext, f)
 
 			Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
 			Expect(fakeValidator.PopulatedArgsCallCount()).To(Equal(1))
-			Expect(fakeValidator.LGPOInDirectoryCallCount()).To(Equal(1))
+			Expect(fakeValidator.VerifyAssetsCallCount()).To(Equal(1))
 
 			Expect(fakeVmConstruct.PrepareVMCallCount()).To(Equal(1))
 		})
@@ -146,24 +333,461 @@ var _ = Describe("construct", func() {
 				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
 				Expect(fakeMessenger.ArgumentsNotProvidedCallCount()).To(Equal(1))
 			})
+
+			It("requires the VM IP", func() {
+				Expect(f.Parse([]string{"-vm-ip", "10.0.0.5"})).To(Succeed())
+
+				ConstrCmd.Execute(emptyContext, f)
+
+				Expect(fakeValidator.PopulatedArgsArgsForCall(0)).To(ContainElement("10.0.0.5"))
+			})
+
+			It("does not require the VM IP when constructing a clone", func() {
+				Expect(f.Parse([]string{"-clone-to", "/dc/vm/clone"})).To(Succeed())
+
+				ConstrCmd.Execute(emptyContext, f)
+
+				Expect(fakeValidator.PopulatedArgsArgsForCall(0)).To(HaveLen(6))
+			})
+
+			It("does not require the VM IP in guest-ops-only mode", func() {
+				Expect(f.Parse([]string{"-guest-ops-only"})).To(Succeed())
+
+				ConstrCmd.Execute(emptyContext, f)
+
+				Expect(fakeValidator.PopulatedArgsArgsForCall(0)).To(HaveLen(6))
+			})
+
+			It("requires a snapshot name when reverting to a snapshot", func() {
+				Expect(f.Parse([]string{"-revert-snapshot"})).To(Succeed())
+
+				ConstrCmd.Execute(emptyContext, f)
+
+				Expect(fakeValidator.PopulatedArgsArgsForCall(0)).To(HaveLen(8))
+			})
+		})
+
+		It("prepares the VM with a context derived from the command's", func() {
+			fakeValidator.PopulatedArgsReturns(true)
+			parent, cancel := context.WithCancel(context.Background())
+			ConstrCmd = NewConstructCmd(parent, fakeFactory, fakeManagerFactory, fakeValidator, fakeMessenger)
+			fakeFactory.VMPreparerCalls(func(ctx context.Context, _ config.SourceConfig, _ VCenterManager, _ VMOutput) (VmConstruct, error) {
+				cancel()
+				Expect(ctx.Err()).To(MatchError(context.Canceled))
+				return fakeVmConstruct, nil
+			})
+
+			exitStatus := ConstrCmd.Execute(emptyContext, f)
+
+			Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+			managerCtx := fakeManagerFactory.VCenterManagerArgsForCall(0)
+			Expect(managerCtx.Err()).To(MatchError(context.Canceled))
+		})
+
+		It("reverts the VM to the snapshot instead of running construct", func() {
+			fakeValidator.PopulatedArgsReturns(true)
+			Expect(f.Parse([]string{"-snapshot", "pre-construct", "-revert-snapshot"})).To(Succeed())
+
+			exitStatus := ConstrCmd.Execute(emptyContext, f)
+
+			Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+			Expect(fakeVmConstruct.RevertSnapshotCallCount()).To(Equal(1))
+			Expect(fakeVmConstruct.PrepareVMCallCount()).To(Equal(0))
 		})
 
-		Context("with LGPO.zip not in current directory", func() {
+		Context("with JSON output", func() {
+			var stdout, stderr *bytes.Buffer
+
+			BeforeEach(func() {
+				fakeValidator.PopulatedArgsReturns(true)
+				stdout = new(bytes.Buffer)
+				stderr = new(bytes.Buffer)
+				ConstrCmd.Stdout = stdout
+				ConstrCmd.Stderr = stderr
+				ConstrCmd.Events = events.NewJSONSink(stdout)
+				fakeFactory.VMPreparerCalls(func(_ context.Context, c config.SourceConfig, _ VCenterManager, output VMOutput) (VmConstruct, error) {
+					_, _ = output.Stdout.Write([]byte("Running Setup.ps1 on " + c.GuestVmIp + "\n"))
+					_, _ = output.Stderr.Write([]byte("WARNING: restart required\n"))
+					output.Events.Emit(events.Event{Command: "construct", Phase: "upload-artifacts", Event: "UploadArtifactsStarted", Status: events.Started})
+					return fakeVmConstruct, nil
+				})
+			})
+
+			expectOnlyEvents := func() {
+				lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+				Expect(lines).NotTo(BeEmpty())
+				for _, line := range lines {
+					var event map[string]interface{}
+					Expect(json.Unmarshal([]byte(line), &event)).To(Succeed(), "stdout line is not JSON: %s", line)
+				}
+			}
+
+			It("writes only events to stdout and the output of the VM commands to stderr", func() {
+				Expect(f.Parse([]string{"-vm-ip", "10.0.0.5"})).To(Succeed())
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				expectOnlyEvents()
+				Expect(stdout.String()).To(ContainSubstring("UploadArtifactsStarted"))
+				Expect(stderr.String()).To(ContainSubstring("Running Setup.ps1 on 10.0.0.5\n"))
+				Expect(stderr.String()).To(ContainSubstring("WARNING: restart required\n"))
+			})
+
+			It("writes only events to stdout when constructing several VMs", func() {
+				targetsDir, err := ioutil.TempDir("", "construct-targets")
+				Expect(err).NotTo(HaveOccurred())
+				defer os.RemoveAll(targetsDir)
+				targetsFile := filepath.Join(targetsDir, "targets.yml")
+				Expect(ioutil.WriteFile(targetsFile, []byte("targets:\n- vm:\n    inventory_path: /dc/vm/windows2019\n    ip: 10.0.0.5\n- vm:\n    inventory_path: /dc/vm/windows1803\n    ip: 10.0.0.6\n"), 0600)).To(Succeed())
+				Expect(f.Parse([]string{"-targets", targetsFile})).To(Succeed())
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				expectOnlyEvents()
+				Expect(stdout.String()).To(ContainSubstring("BatchSummary"))
+				Expect(stderr.String()).To(ContainSubstring("[windows2019] Running Setup.ps1 on 10.0.0.5\n"))
+				Expect(stderr.String()).To(ContainSubstring("[windows1803] Running Setup.ps1 on 10.0.0.6\n"))
+			})
+		})
+
+		Context("with a build configuration file", func() {
+			var configDir string
+
+			BeforeEach(func() {
+				var err error
+				configDir, err = ioutil.TempDir("", "construct-config")
+				Expect(err).NotTo(HaveOccurred())
+				fakeValidator.PopulatedArgsReturns(true)
+			})
+
+			AfterEach(func() {
+				_ = os.RemoveAll(configDir)
+			})
+
+			It("reads the configuration from the file but lets flags take precedence", func() {
+				configFile := filepath.Join(configDir, "build.yml")
+				Expect(ioutil.WriteFile(configFile, []byte("vcenter:\n  url: vcenter.example.com\nvm:\n  ip: 10.0.0.5\nconstruct:\n  timeouts:\n    reboot: 2h\n"), 0600)).To(Succeed())
+				Expect(f.Parse([]string{"-config", configFile, "-vm-ip", "10.0.0.9"})).To(Succeed())
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				_, sourceConfig, _, _ := fakeFactory.VMPreparerArgsForCall(0)
+				Expect(sourceConfig.VCenterUrl).To(Equal("vcenter.example.com"))
+				Expect(sourceConfig.GuestVmIp).To(Equal("10.0.0.9"))
+				Expect(sourceConfig.Timeouts.Reboot).To(Equal(2 * time.Hour))
+				Expect(sourceConfig.Timeouts.RebootDelay).To(Equal(config.DefaultTimeouts().RebootDelay))
+			})
+
+			It("reports an invalid configuration", func() {
+				configFile := filepath.Join(configDir, "build.yml")
+				Expect(ioutil.WriteFile(configFile, []byte("vcenter:\n  uri: vcenter.example.com\n"), 0600)).To(Succeed())
+				Expect(f.Parse([]string{"-config", configFile})).To(Succeed())
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(fakeMessenger.InvalidBuildConfigArgsForCall(0)).To(MatchError(ContainSubstring("unknown key vcenter.uri")))
+				Expect(fakeFactory.VMPreparerCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("with a targets file", func() {
+			var (
+				targetsDir  string
+				targetsFile string
+				stdout      *bytes.Buffer
+			)
+
+			BeforeEach(func() {
+				var err error
+				targetsDir, err = ioutil.TempDir("", "construct-targets")
+				Expect(err).NotTo(HaveOccurred())
+				targetsFile = filepath.Join(targetsDir, "targets.yml")
+				Expect(ioutil.WriteFile(targetsFile, []byte(`
+targets:
+- vm:
+    inventory_path: /dc/vm/windows2019
+    ip: 10.0.0.5
+- vm:
+    inventory_path: /dc/vm/windows1803
+    ip: 10.0.0.6
+- name: other-vcenter
+  vcenter:
+    url: vcenter2.example.com
+  vm:
+    inventory_path: /dc2/vm/windows2019
+    ip: 10.0.0.7
+`), 0600)).To(Succeed())
+
+				fakeValidator.PopulatedArgsReturns(true)
+				stdout = new(bytes.Buffer)
+				ConstrCmd.Stdout = stdout
+				ConstrCmd.Stderr = ioutil.Discard
+				Expect(f.Parse([]string{"-targets", targetsFile, "-vcenter-url", "vcenter.example.com", "-vm-username", "Admin"})).To(Succeed())
+			})
+
+			AfterEach(func() {
+				_ = os.RemoveAll(targetsDir)
+			})
+
+			It("constructs every target with the shared configuration and its own settings", func() {
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				Expect(fakeVmConstruct.PrepareVMCallCount()).To(Equal(3))
+				configs := map[string]config.SourceConfig{}
+				for i := 0; i < fakeFactory.VMPreparerCallCount(); i++ {
+					_, sourceConfig, _, _ := fakeFactory.VMPreparerArgsForCall(i)
+					configs[sourceConfig.VmInventoryPath] = sourceConfig
+				}
+				Expect(configs["/dc/vm/windows2019"].GuestVmIp).To(Equal("10.0.0.5"))
+				Expect(configs["/dc/vm/windows2019"].GuestVMUsername).To(Equal("Admin"))
+				Expect(configs["/dc/vm/windows2019"].VCenterUrl).To(Equal("vcenter.example.com"))
+				Expect(configs["/dc2/vm/windows2019"].VCenterUrl).To(Equal("vcenter2.example.com"))
+
+				Expect(fakeMessenger.BatchTargetStartedCallCount()).To(Equal(3))
+				Expect(fakeMessenger.BatchTargetFinishedCallCount()).To(Equal(3))
+				results := fakeMessenger.BatchSummaryArgsForCall(0)
+				Expect(results).To(HaveLen(3))
+				Expect(results[0].Name).To(Equal("windows2019"))
+				Expect(results[1].Name).To(Equal("windows1803"))
+				Expect(results[2].Name).To(Equal("other-vcenter"))
+				Expect(results[2].InventoryPath).To(Equal("/dc2/vm/windows2019"))
+				Expect(results[2].Err).NotTo(HaveOccurred())
+			})
+
+			It("constructs no more VMs at the same time than max-parallel", func() {
+				Expect(f.Parse([]string{"-max-parallel", "2"})).To(Succeed())
+				var lock sync.Mutex
+				running, mostRunning := 0, 0
+				fakeVmConstruct.PrepareVMStub = func() error {
+					lock.Lock()
+					running++
+					if running > mostRunning {
+						mostRunning = running
+					}
+					lock.Unlock()
+
+					time.Sleep(20 * time.Millisecond)
+
+					lock.Lock()
+					running--
+					lock.Unlock()
+					return nil
+				}
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+				Expect(fakeVmConstruct.PrepareVMCallCount()).To(Equal(3))
+				Expect(mostRunning).To(Equal(2))
+			})
+
+			It("prefixes the output of each VM with its name", func() {
+				fakeFactory.VMPreparerCalls(func(_ context.Context, c config.SourceConfig, _ VCenterManager, output VMOutput) (VmConstruct, error) {
+					_, _ = output.Stdout.Write([]byte("Uploading to " + c.GuestVmIp + "\nUploaded"))
+					return fakeVmConstruct, nil
+				})
+
+				ConstrCmd.Execute(emptyContext, f)
+
+				Expect(stdout.String()).To(ContainSubstring("[windows1803] Uploading to 10.0.0.6\n"))
+				Expect(stdout.String()).To(ContainSubstring("[windows1803] Uploaded\n"))
+				Expect(stdout.String()).To(ContainSubstring("[other-vcenter] Uploading to 10.0.0.7\n"))
+			})
+
+			It("names the VM in every event when an event sink is set", func() {
+				sink := &eventsfakes.FakeSink{}
+				ConstrCmd.Events = sink
+				fakeFactory.VMPreparerCalls(func(_ context.Context, _ config.SourceConfig, _ VCenterManager, output VMOutput) (VmConstruct, error) {
+					output.Events.Emit(events.Event{Command: "construct", Event: "UploadArtifactsStarted"})
+					return fakeVmConstruct, nil
+				})
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				vms := map[string]bool{}
+				for i := 0; i < sink.EmitCallCount(); i++ {
+					if event := sink.EmitArgsForCall(i); event.Event == "UploadArtifactsStarted" {
+						vms[event.VM] = true
+					}
+				}
+				Expect(vms).To(Equal(map[string]bool{"windows2019": true, "windows1803": true, "other-vcenter": true}))
+			})
+
+			It("constructs the other targets when one fails and reports it in the summary", func() {
+				failing := &commandparserfakes.FakeVmConstruct{}
+				failing.PrepareVMReturns(errors.New("setup script failed"))
+				fakeFactory.VMPreparerCalls(func(_ context.Context, c config.SourceConfig, _ VCenterManager, _ VMOutput) (VmConstruct, error) {
+					if c.VmInventoryPath == "/dc/vm/windows1803" {
+						return failing, nil
+					}
+					return fakeVmConstruct, nil
+				})
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(fakeVmConstruct.PrepareVMCallCount()).To(Equal(2))
+				results := fakeMessenger.BatchSummaryArgsForCall(0)
+				Expect(results[0].Err).NotTo(HaveOccurred())
+				Expect(results[1].Err).To(MatchError("setup script failed"))
+				Expect(results[2].Err).NotTo(HaveOccurred())
+				Expect(fakeMessenger.CannotPrepareVMCallCount()).To(Equal(0))
+			})
+
+			It("resolves a credential reference shared by the targets once", func() {
+				fakeCredentials := &commandparserfakes.FakeCredentialResolver{}
+				fakeCredentials.ResolveStub = func(name, value string) (string, error) {
+					return "resolved " + value, nil
+				}
+				ConstrCmd.Credentials = fakeCredentials
+				Expect(f.Parse([]string{"-vm-password", "stdin://"})).To(Succeed())
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				Expect(fakeCredentials.ResolveCallCount()).To(Equal(2))
+				_, sourceConfig, _, _ := fakeFactory.VMPreparerArgsForCall(0)
+				Expect(sourceConfig.GuestVMPassword).To(Equal("resolved stdin://"))
+			})
+
+			It("validates every target before constructing any", func() {
+				fakeValidator.PopulatedArgsReturnsOnCall(2, false)
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(fakeMessenger.InvalidBuildConfigArgsForCall(0)).To(MatchError("target other-vcenter: not all required parameters were provided"))
+				Expect(fakeFactory.VMPreparerCallCount()).To(Equal(0))
+			})
+
+			It("verifies the assets of the targets once before constructing any", func() {
+				Expect(ioutil.WriteFile(targetsFile, []byte(`
+targets:
+- vm:
+    inventory_path: /dc/vm/windows2019
+    ip: 10.0.0.5
+- vm:
+    inventory_path: /dc/vm/windows1803
+    ip: 10.0.0.6
+  construct:
+    automation_bundle: /bundles/1803/StemcellAutomation.zip
+`), 0600)).To(Succeed())
+				fakeValidator.VerifyAssetsReturnsOnCall(1, errors.New("agent.zip does not match deps.json"))
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(fakeValidator.VerifyAssetsCallCount()).To(Equal(2))
+				automationBundle, _ := fakeValidator.VerifyAssetsArgsForCall(1)
+				Expect(automationBundle).To(Equal("/bundles/1803/StemcellAutomation.zip"))
+				Expect(fakeMessenger.AssetsNotVerifiedArgsForCall(0)).To(MatchError("agent.zip does not match deps.json"))
+				Expect(fakeFactory.VMPreparerCallCount()).To(Equal(0))
+			})
+
+			It("reports an invalid targets file", func() {
+				Expect(ioutil.WriteFile(targetsFile, []byte("targets: []\n"), 0600)).To(Succeed())
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(fakeMessenger.InvalidBuildConfigArgsForCall(0)).To(MatchError(ContainSubstring("lists no targets")))
+			})
+
+			It("does not start the remaining targets once it is interrupted", func() {
+				parent, cancel := context.WithCancel(context.Background())
+				ConstrCmd = NewConstructCmd(parent, fakeFactory, fakeManagerFactory, fakeValidator, fakeMessenger)
+				ConstrCmd.Stdout = ioutil.Discard
+				ConstrCmd.Stderr = ioutil.Discard
+				f = flag.NewFlagSet("test", flag.ExitOnError)
+				ConstrCmd.SetFlags(f)
+				Expect(f.Parse([]string{"-targets", targetsFile, "-max-parallel", "1"})).To(Succeed())
+				fakeVmConstruct.PrepareVMStub = func() error {
+					cancel()
+					return context.Canceled
+				}
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(fakeVmConstruct.PrepareVMCallCount()).To(Equal(1))
+				results := fakeMessenger.BatchSummaryArgsForCall(0)
+				Expect(results[1].Err).To(MatchError("construct was interrupted before it started"))
+				Expect(results[2].Err).To(MatchError("construct was interrupted before it started"))
+			})
+		})
+
+		Context("with credential references", func() {
+			var fakeCredentials *commandparserfakes.FakeCredentialResolver
+
+			BeforeEach(func() {
+				fakeValidator.PopulatedArgsReturns(true)
+				fakeCredentials = &commandparserfakes.FakeCredentialResolver{}
+				ConstrCmd.Credentials = fakeCredentials
+			})
+
+			It("resolves both passwords before constructing", func() {
+				fakeCredentials.ResolveStub = func(name, value string) (string, error) {
+					return "resolved " + value, nil
+				}
+				Expect(f.Parse([]string{"-vcenter-password", "env://VCENTER_PASSWORD", "-vm-password", "file:///tmp/vm-password"})).To(Succeed())
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				Expect(fakeCredentials.ResolveCallCount()).To(Equal(2))
+				name, _ := fakeCredentials.ResolveArgsForCall(0)
+				Expect(name).To(Equal("VM password"))
+				name, _ = fakeCredentials.ResolveArgsForCall(1)
+				Expect(name).To(Equal("vCenter password"))
+
+				_, sourceConfig, _, _ := fakeFactory.VMPreparerArgsForCall(0)
+				Expect(sourceConfig.VCenterPassword).To(Equal("resolved env://VCENTER_PASSWORD"))
+				Expect(sourceConfig.GuestVMPassword).To(Equal("resolved file:///tmp/vm-password"))
+			})
+
+			It("reports a credential that cannot be resolved", func() {
+				fakeCredentials.ResolveReturns("", errors.New("unable to read VM password: environment variable VM_PASSWORD is not set"))
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(fakeMessenger.CannotResolveCredentialArgsForCall(0)).To(MatchError(ContainSubstring("VM_PASSWORD is not set")))
+				Expect(fakeFactory.VMPreparerCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("with assets that cannot be verified", func() {
 			It("should return an error", func() {
 				fakeValidator.PopulatedArgsReturns(true)
-				fakeValidator.LGPOInDirectoryReturns(false)
+				fakeValidator.VerifyAssetsReturns(errors.New("could not find LGPO.zip"))
 
 				exitStatus := ConstrCmd.Execute(emptyContext, f)
 
 				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
-				Expect(fakeMessenger.LGPONotFoundCallCount()).To(Equal(1))
+				Expect(fakeMessenger.AssetsNotVerifiedArgsForCall(0)).To(MatchError("could not find LGPO.zip"))
+				Expect(fakeFactory.VMPreparerCallCount()).To(Equal(0))
+			})
+
+			It("verifies the automation bundle and LGPO.zip given by flags", func() {
+				fakeValidator.PopulatedArgsReturns(true)
+				Expect(f.Parse([]string{"-automation-bundle", "/bundles/StemcellAutomation.zip", "-lgpo", "/downloads/LGPO.zip"})).To(Succeed())
+
+				ConstrCmd.Execute(emptyContext, f)
+
+				automationBundle, lgpo := fakeValidator.VerifyAssetsArgsForCall(0)
+				Expect(automationBundle).To(Equal("/bundles/StemcellAutomation.zip"))
+				Expect(lgpo).To(Equal("/downloads/LGPO.zip"))
 			})
 		})
 
 		Context("with an error during VMPrepare", func() {
 			It("should return an error", func() {
 				fakeValidator.PopulatedArgsReturns(true)
-				fakeValidator.LGPOInDirectoryReturns(true)
 				fakeVmConstruct.PrepareVMReturns(errors.New("some error"))
 
 				exitStatus := ConstrCmd.Execute(emptyContext, f)
@@ -171,6 +795,22 @@ var _ = Describe("construct", func() {
 				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
 				Expect(fakeMessenger.CannotPrepareVMCallCount()).To(Equal(1))
 			})
+
+			It("reports the failure as an event when an event sink is set", func() {
+				sink := &eventsfakes.FakeSink{}
+				ConstrCmd.Events = sink
+				fakeValidator.PopulatedArgsReturns(true)
+				fakeVmConstruct.PrepareVMReturns(errors.New("some error"))
+
+				exitStatus := ConstrCmd.Execute(emptyContext, f)
+
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+				Expect(fakeMessenger.CannotPrepareVMCallCount()).To(Equal(0))
+				Expect(sink.EmitCallCount()).To(Equal(1))
+				event := sink.EmitArgsForCall(0)
+				Expect(event.Event).To(Equal("CannotPrepareVM"))
+				Expect(event.Error).To(Equal("some error"))
+			})
 		})
 	})
 })
@@ -1,9 +1,6 @@
 package commandparser
 
-import (
-	"os"
-	"path/filepath"
-)
+import "github.com/cloudfoundry-incubator/stembuild/assetmanager"
 
 type ConstructValidator struct{}
 
@@ -16,11 +13,8 @@ func (c *ConstructValidator) PopulatedArgs(args ...string) bool {
 	return true
 }
 
-func (c *ConstructValidator) LGPOInDirectory() bool {
-	dir, _ := os.Getwd()
-	_, err := os.Stat(filepath.Join(dir, "LGPO.zip"))
-	if err != nil {
-		return false
-	}
-	return true
+// VerifyAssets checks that LGPO.zip can be found and that the automation bundle and LGPO.zip match the deps.json
+// of the bundle. Empty paths select the bundle compiled into stembuild and the LGPO.zip next to it.
+func (c *ConstructValidator) VerifyAssets(automationBundle, lgpo string) error {
+	return assetmanager.New(automationBundle, lgpo).Verify()
 }
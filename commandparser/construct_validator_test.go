@@ -4,7 +4,6 @@ import (
 	"github.com/cloudfoundry-incubator/stembuild/commandparser"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"os"
 	"path/filepath"
 )
 
@@ -44,29 +43,11 @@ var _ = Describe("ConstructValidator", func() {
 		})
 	})
 
-	Describe("LGPOInDirectory", func() {
-		wd, _ := os.Getwd()
-		LGPOPath := filepath.Join(wd, "LGPO.zip")
+	Describe("VerifyAssets", func() {
+		It("should return an error naming the missing LGPO.zip", func() {
+			err := c.VerifyAssets("", filepath.Join("..", "test", "constructData", "emptyDir", "LGPO.zip"))
 
-		It("should return true if LGPO exists in the directory", func() {
-			file, err := os.Create(LGPOPath)
-			Expect(err).ToNot(HaveOccurred())
-			_, err = os.Stat(LGPOPath)
-			Expect(err).ToNot(HaveOccurred())
-			file.Close()
-
-			result := c.LGPOInDirectory()
-
-			Expect(result).To(BeTrue())
-
-			err = os.Remove(LGPOPath)
-			Expect(err).ToNot(HaveOccurred())
-		})
-
-		It("should return false if LGPO doesn't exist in the directory", func() {
-			result := c.LGPOInDirectory()
-
-			Expect(result).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("unable to read LGPO")))
 		})
 	})
 })
@@ -0,0 +1,7 @@
+package commandparser
+
+import "github.com/cloudfoundry-incubator/stembuild/verify/config"
+
+func (p *VerifyCmd) GetSourceConfig() config.SourceConfig {
+	return p.sourceConfig
+}
@@ -1,7 +1,13 @@
 package commandparser
 
+const (
+	TextOutput = "text"
+	JSONOutput = "json"
+)
+
 type GlobalFlags struct {
 	Debug       bool
 	Color       bool
 	ShowVersion bool
+	Output      string
 }
@@ -3,6 +3,8 @@ package commandparser
 import (
 	"fmt"
 	"io"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
 )
 
 type PackageMessenger struct {
@@ -29,3 +31,48 @@ func (m *PackageMessenger) PackageFailed(e error) {
 	fmt.Fprintln(m.Output, e)
 	fmt.Fprintln(m.Output, "Please provide the error logs to bosh-windows-eng@pivotal.io")
 }
+
+func (m *PackageMessenger) InvalidBuildConfig(e error) {
+	fmt.Fprintf(m.Output, "Invalid build configuration: %s\n", e)
+}
+
+func (m *PackageMessenger) CannotResolveCredential(e error) {
+	fmt.Fprintln(m.Output, e)
+}
+
+// JSONPackageMessenger reports package command failures as events.
+type JSONPackageMessenger struct {
+	Events events.Sink
+}
+
+func (m *JSONPackageMessenger) emitFailure(phase, event string, e error) {
+	m.Events.Emit(events.Event{Command: "package", Phase: phase, Event: event, Status: events.Failed, Error: e.Error()})
+}
+
+func (m *JSONPackageMessenger) InvalidOutputConfig(e error) {
+	m.emitFailure("validate", "InvalidOutputConfig", e)
+}
+
+func (m *JSONPackageMessenger) CannotCreatePackager(e error) {
+	m.emitFailure("validate", "CannotCreatePackager", e)
+}
+
+func (m *JSONPackageMessenger) DoesNotHaveEnoughSpace(e error) {
+	m.emitFailure("validate", "DoesNotHaveEnoughSpace", e)
+}
+
+func (m *JSONPackageMessenger) SourceParametersAreInvalid(e error) {
+	m.emitFailure("validate", "SourceParametersAreInvalid", e)
+}
+
+func (m *JSONPackageMessenger) PackageFailed(e error) {
+	m.emitFailure("", "PackageFailed", e)
+}
+
+func (m *JSONPackageMessenger) InvalidBuildConfig(e error) {
+	m.emitFailure("validate", "InvalidBuildConfig", e)
+}
+
+func (m *JSONPackageMessenger) CannotResolveCredential(e error) {
+	m.emitFailure("validate", "CannotResolveCredential", e)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -52,3 +54,53 @@ var _ = Describe("PackageMessenger", func() {
 		Eventually(buf).Should(gbytes.Say("Please provide the error logs to bosh-windows-eng@pivotal.io"))
 	})
 })
+
+var _ = Describe("JSONPackageMessenger", func() {
+	var (
+		sink      *eventsfakes.FakeSink
+		messenger *commandparser.JSONPackageMessenger
+	)
+
+	BeforeEach(func() {
+		sink = &eventsfakes.FakeSink{}
+		messenger = &commandparser.JSONPackageMessenger{Events: sink}
+	})
+
+	It("emits a failed validate event when the source parameters are invalid", func() {
+		messenger.SourceParametersAreInvalid(errors.New("source parameters invalid"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Command).To(Equal("package"))
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("SourceParametersAreInvalid"))
+		Expect(event.Status).To(Equal(events.Failed))
+		Expect(event.Error).To(Equal("source parameters invalid"))
+	})
+
+	It("emits a single failed event when PackageFailed is called", func() {
+		messenger.PackageFailed(errors.New("package failed"))
+
+		Expect(sink.EmitCallCount()).To(Equal(1))
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Event).To(Equal("PackageFailed"))
+		Expect(event.Error).To(Equal("package failed"))
+	})
+
+	It("emits a failed validate event naming the invalid build configuration", func() {
+		messenger.InvalidBuildConfig(errors.New("unknown key vcenter.uri"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("InvalidBuildConfig"))
+		Expect(event.Error).To(Equal("unknown key vcenter.uri"))
+	})
+
+	It("emits a failed validate event when a credential cannot be resolved", func() {
+		messenger.CannotResolveCredential(errors.New("unable to read VM password: environment variable VM_PASSWORD is not set"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("CannotResolveCredential"))
+		Expect(event.Error).To(Equal("unable to read VM password: environment variable VM_PASSWORD is not set"))
+	})
+})
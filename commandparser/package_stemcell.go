@@ -7,11 +7,14 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/cloudfoundry-incubator/stembuild/buildconfig"
 	"github.com/cloudfoundry-incubator/stembuild/colorlogger"
+	"github.com/cloudfoundry-incubator/stembuild/events"
 
 	"github.com/cloudfoundry-incubator/stembuild/filesystem"
 
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/config"
+	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/package_parameters"
 	"github.com/google/subcommands"
 )
 
@@ -41,15 +44,20 @@ type PackagerMessenger interface {
 	DoesNotHaveEnoughSpace(error)
 	SourceParametersAreInvalid(error)
 	PackageFailed(error)
+	InvalidBuildConfig(error)
+	CannotResolveCredential(error)
 }
 
 type PackageCmd struct {
 	GlobalFlags        *GlobalFlags
 	sourceConfig       config.SourceConfig
 	outputConfig       config.OutputConfig
+	configFile         string
 	osAndVersionGetter OSAndVersionGetter
 	packagerFactory    PackagerFactory
 	packagerMessenger  PackagerMessenger
+	Events             events.Sink
+	Credentials        CredentialResolver
 }
 
 func NewPackageCommand(o OSAndVersionGetter, p PackagerFactory, m PackagerMessenger) *PackageCmd {
@@ -98,17 +106,35 @@ VMDK:
     Will create an Windows 1803 stemcell using [vmdk] 'my-1803-vmdk.vmdk'
     The final stemcell will be found in the current working directory.
 
+Credentials:
+
+  Instead of the password itself, [vcenter-password] takes a reference to it: env://NAME, file:///path, stdin://
+  or vault://secret/data/path#key, which reads key from a Vault KV secret using VAULT_ADDR and VAULT_TOKEN.
+
+Build configuration:
+
+  With [config], options are read from a YAML file with vcenter, vm and package sections. Each key can be
+  overridden by a STEMBUILD_* environment variable, e.g. STEMBUILD_VCENTER_PASSWORD, and flags override both.
+
+Dry run:
+
+  With [dry-run], the source and output are validated as usual, but instead of packaging, the changes package
+  would make are printed in order: the floppy and network devices it would remove from the VM, the CD-ROMs it
+  would eject, and the stemcell it would create.
+
 Flags:
 `, filepath.Base(os.Args[0]))
 }
 
 func (p *PackageCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.configFile, "config", "", "YAML build configuration file. Flags and STEMBUILD_* environment variables take precedence over it")
 	f.StringVar(&p.sourceConfig.Vmdk, "vmdk", "", "VMDK file to create stemcell from")
 	f.StringVar(&p.sourceConfig.VmInventoryPath, "vm-inventory-path", "", "vCenter VM inventory path. (e.g: <datacenter>/vm/<vm-folder>/<vm-name>)")
 	f.StringVar(&p.sourceConfig.Username, "vcenter-username", "", "vCenter username")
-	f.StringVar(&p.sourceConfig.Password, "vcenter-password", "", "vCenter password")
+	f.StringVar(&p.sourceConfig.Password, "vcenter-password", "", "vCenter password, or an env://, file://, stdin:// or vault:// reference to it")
 	f.StringVar(&p.sourceConfig.URL, "vcenter-url", "", "vCenter url")
 	f.StringVar(&p.sourceConfig.CaCertFile, "vcenter-ca-certs", "", "filepath for custom ca certs")
+	f.BoolVar(&p.sourceConfig.DryRun, "dry-run", false, "Validate the source and print the changes package would make, without making any")
 
 	f.StringVar(&p.outputConfig.OutputDir, "outputDir", "", "Output directory, default is the current working directory.")
 	f.StringVar(&p.outputConfig.OutputDir, "o", "", "Output directory (shorthand)")
@@ -122,34 +148,62 @@ func (p *PackageCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{
 		logLevel = colorlogger.DEBUG
 	}
 
+	messenger := p.packagerMessenger
+	if p.Events != nil {
+		messenger = &JSONPackageMessenger{Events: p.Events}
+	}
+
+	err := applyBuildConfig(f, p.configFile, buildconfig.PackageSettings(&p.sourceConfig, &p.outputConfig, &patchVersion))
+	if err != nil {
+		messenger.InvalidBuildConfig(err)
+		return subcommands.ExitFailure
+	}
+
+	vmdkParameters, err := buildconfig.VmdkPackageParameters(p.configFile)
+	if err != nil {
+		messenger.InvalidBuildConfig(err)
+		return subcommands.ExitFailure
+	}
+	buildOptions := package_parameters.VmdkPackageParameters{VMDKFile: p.sourceConfig.Vmdk}
+	buildOptions.CopyFrom(vmdkParameters)
+	p.sourceConfig.Vmdk = buildOptions.VMDKFile
+
+	err = resolveCredentials(p.Credentials, map[string]*string{
+		"vCenter password": &p.sourceConfig.Password,
+	})
+	if err != nil {
+		messenger.CannotResolveCredential(err)
+		return subcommands.ExitFailure
+	}
+
 	p.setOSandStemcellVersions()
 
-	err := p.outputConfig.ValidateConfig()
+	err = p.outputConfig.ValidateConfig()
 	if err != nil {
-		p.packagerMessenger.InvalidOutputConfig(err)
+		messenger.InvalidOutputConfig(err)
 		return subcommands.ExitFailure
 	}
 
 	packager, err := p.packagerFactory.Packager(p.sourceConfig, p.outputConfig, logLevel, p.GlobalFlags.Color)
 	if err != nil {
-		p.packagerMessenger.CannotCreatePackager(err)
+		messenger.CannotCreatePackager(err)
 		return subcommands.ExitFailure
 	}
 
 	err = packager.ValidateFreeSpaceForPackage(&filesystem.OSFileSystem{})
 	if err != nil {
-		p.packagerMessenger.DoesNotHaveEnoughSpace(err)
+		messenger.DoesNotHaveEnoughSpace(err)
 		return subcommands.ExitFailure
 	}
 
 	err = packager.ValidateSourceParameters()
 	if err != nil {
-		p.packagerMessenger.SourceParametersAreInvalid(err)
+		messenger.SourceParametersAreInvalid(err)
 		return subcommands.ExitFailure
 	}
 
 	if err := packager.Package(); err != nil {
-		p.packagerMessenger.PackageFailed(err)
+		messenger.PackageFailed(err)
 		return subcommands.ExitFailure
 	}
 
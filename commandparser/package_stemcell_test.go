@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/google/subcommands"
 
 	"github.com/cloudfoundry-incubator/stembuild/commandparser/commandparserfakes"
 
 	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -42,7 +47,7 @@ var _ = Describe("package_stemcell", func() {
 
 			PkgCmd = commandparser.NewPackageCommand(oSAndVersionGetter, packagerFactory, packagerMessenger)
 			PkgCmd.SetFlags(f)
-			PkgCmd.GlobalFlags = &commandparser.GlobalFlags{false, false, false}
+			PkgCmd.GlobalFlags = &commandparser.GlobalFlags{false, false, false, commandparser.TextOutput}
 		})
 
 		var defaultArgs = []string{}
@@ -91,6 +96,98 @@ var _ = Describe("package_stemcell", func() {
 				Expect(actualSourceConfig.CaCertFile).To(Equal("/path/to/cert/file"))
 			})
 
+			It("packager is instantiated with a dry run source config when -dry-run is given", func() {
+				err := f.Parse([]string{"-vmdk", "some_vmdk_file", "-dry-run"})
+				Expect(err).ToNot(HaveOccurred())
+
+				exitStatus := PkgCmd.Execute(context.Background(), f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				actualSourceConfig, _, _, _ := packagerFactory.PackagerArgsForCall(0)
+				Expect(actualSourceConfig.DryRun).To(BeTrue())
+				Expect(packager.ValidateSourceParametersCallCount()).To(Equal(1))
+			})
+
+			It("packager is instantiated with the build configuration file, overridden by flags", func() {
+				configDir, err := ioutil.TempDir("", "package-config")
+				Expect(err).ToNot(HaveOccurred())
+				defer os.RemoveAll(configDir)
+				configFile := filepath.Join(configDir, "build.yml")
+				Expect(ioutil.WriteFile(configFile, []byte("vcenter:\n  url: https://vcenter.test\n  username: from-file\npackage:\n  output_dir: "+configDir+"\n"), 0600)).To(Succeed())
+
+				err = f.Parse([]string{"-config", configFile, "-vcenter-username", "from-flag"})
+				Expect(err).ToNot(HaveOccurred())
+
+				exitStatus := PkgCmd.Execute(context.Background(), f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				actualSourceConfig, actualOutputConfig, _, _ := packagerFactory.PackagerArgsForCall(0)
+				Expect(actualSourceConfig.URL).To(Equal("https://vcenter.test"))
+				Expect(actualSourceConfig.Username).To(Equal("from-flag"))
+				Expect(actualOutputConfig.OutputDir).To(Equal(configDir))
+			})
+
+			It("packager is instantiated with the vmdk of the build configuration file unless -vmdk is given", func() {
+				configDir, err := ioutil.TempDir("", "package-config")
+				Expect(err).ToNot(HaveOccurred())
+				defer os.RemoveAll(configDir)
+				configFile := filepath.Join(configDir, "build.yml")
+				Expect(ioutil.WriteFile(configFile, []byte("package:\n  vmdk_file: from-file.vmdk\n"), 0600)).To(Succeed())
+
+				err = f.Parse([]string{"-config", configFile})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(PkgCmd.Execute(context.Background(), f)).To(Equal(subcommands.ExitSuccess))
+				actualSourceConfig, _, _, _ := packagerFactory.PackagerArgsForCall(0)
+				Expect(actualSourceConfig.Vmdk).To(Equal("from-file.vmdk"))
+
+				err = f.Parse([]string{"-config", configFile, "-vmdk", "from-flag.vmdk"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(PkgCmd.Execute(context.Background(), f)).To(Equal(subcommands.ExitSuccess))
+				actualSourceConfig, _, _, _ = packagerFactory.PackagerArgsForCall(1)
+				Expect(actualSourceConfig.Vmdk).To(Equal("from-flag.vmdk"))
+			})
+
+			It("package is not called if the build configuration is invalid", func() {
+				err := f.Parse([]string{"-config", "/does/not/exist.yml"})
+				Expect(err).ToNot(HaveOccurred())
+
+				exitStatus := PkgCmd.Execute(context.Background(), f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(packagerMessenger.InvalidBuildConfigCallCount()).To(Equal(1))
+				Expect(packagerFactory.PackagerCallCount()).To(Equal(0))
+			})
+
+			It("packager is instantiated with the resolved vCenter password", func() {
+				credentials := new(commandparserfakes.FakeCredentialResolver)
+				credentials.ResolveReturns("verysecure", nil)
+				PkgCmd.Credentials = credentials
+
+				err := f.Parse([]string{"-vcenter-password", "vault://secret/data/vcenter#password"})
+				Expect(err).ToNot(HaveOccurred())
+
+				exitStatus := PkgCmd.Execute(context.Background(), f)
+				Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+
+				name, value := credentials.ResolveArgsForCall(0)
+				Expect(name).To(Equal("vCenter password"))
+				Expect(value).To(Equal("vault://secret/data/vcenter#password"))
+				actualSourceConfig, _, _, _ := packagerFactory.PackagerArgsForCall(0)
+				Expect(actualSourceConfig.Password).To(Equal("verysecure"))
+			})
+
+			It("package is not called if the vCenter password cannot be resolved", func() {
+				credentials := new(commandparserfakes.FakeCredentialResolver)
+				credentials.ResolveReturns("", errors.New("unable to read vCenter password: no input on stdin: EOF"))
+				PkgCmd.Credentials = credentials
+
+				exitStatus := PkgCmd.Execute(context.Background(), f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(packagerMessenger.CannotResolveCredentialCallCount()).To(Equal(1))
+				Expect(packagerFactory.PackagerCallCount()).To(Equal(0))
+			})
+
 			It("packager is instantiated with expected output config directory when using long form -outputdir", func() {
 				longformOutputDirArgs := []string{"-outputDir", "some_output_dir"}
 
@@ -223,6 +320,25 @@ var _ = Describe("package_stemcell", func() {
 				receivedError := packagerMessenger.PackageFailedArgsForCall(0)
 				Expect(receivedError).To(MatchError("Didn't make it"))
 			})
+
+			It("reports failures as events instead of text when an event sink is set", func() {
+				sink := &eventsfakes.FakeSink{}
+				PkgCmd.Events = sink
+				packager.PackageReturns(errors.New("Didn't make it"))
+
+				err := f.Parse(defaultArgs)
+				Expect(err).ToNot(HaveOccurred())
+
+				exitStatus := PkgCmd.Execute(context.Background(), f)
+				Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+
+				Expect(packagerMessenger.PackageFailedCallCount()).To(Equal(0))
+				Expect(sink.EmitCallCount()).To(Equal(1))
+				event := sink.EmitArgsForCall(0)
+				Expect(event.Event).To(Equal("PackageFailed"))
+				Expect(event.Status).To(Equal(events.Failed))
+				Expect(event.Error).To(Equal("Didn't make it"))
+			})
 		})
 	})
 })
@@ -0,0 +1,58 @@
+package commandparser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// prefixWriter starts every line written to it with the name of a VM, so the output of the VMs of a
+// batch construct can be told apart. It writes whole lines only, under a lock shared by every
+// prefixWriter of out, so lines of different VMs are never interleaved.
+type prefixWriter struct {
+	out    io.Writer
+	lock   *sync.Mutex
+	prefix []byte
+	line   []byte
+}
+
+func newPrefixWriter(out io.Writer, lock *sync.Mutex, name string) *prefixWriter {
+	return &prefixWriter{out: out, lock: lock, prefix: []byte(fmt.Sprintf("[%s] ", name))}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.line = append(w.line, p...)
+	for {
+		end := bytes.IndexByte(w.line, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		err := w.writeLine(w.line[:end+1])
+		w.line = w.line[end+1:]
+		if err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush writes what is left of an unfinished line
+func (w *prefixWriter) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.line) == 0 {
+		return nil
+	}
+	err := w.writeLine(append(w.line, '\n'))
+	w.line = nil
+	return err
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	_, err := w.out.Write(append(append([]byte{}, w.prefix...), line...))
+	return err
+}
@@ -0,0 +1,193 @@
+package commandparser
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/stembuild/buildconfig"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	vcenter_client_factory "github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/factory"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	verifyconfig "github.com/cloudfoundry-incubator/stembuild/verify/config"
+	"github.com/google/subcommands"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . VMVerifier
+type VMVerifier interface {
+	Verify() (verify.Report, error)
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . VMVerifierFactory
+type VMVerifierFactory interface {
+	VMVerifier(ctx context.Context, config verifyconfig.SourceConfig, vCenterManager VCenterManager, output VMOutput) (VMVerifier, error)
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . VerifyMessenger
+type VerifyMessenger interface {
+	ArgumentsNotProvided()
+	InvalidBuildConfig(err error)
+	CannotResolveCredential(err error)
+	CannotVerifyVM(err error)
+	InterruptReceived(signal string)
+	SecondInterruptReceived(signal string)
+	VerifySummary(report verify.Report)
+}
+
+type VerifyCmd struct {
+	ctx             context.Context
+	sourceConfig    verifyconfig.SourceConfig
+	configFile      string
+	verifierFactory VMVerifierFactory
+	managerFactory  ManagerFactory
+	messenger       VerifyMessenger
+	GlobalFlags     *GlobalFlags
+	Events          events.Sink
+	Credentials     CredentialResolver
+	// Stdout receives the progress of verify, os.Stdout if it is not set
+	Stdout io.Writer
+}
+
+func NewVerifyCmd(ctx context.Context, verifierFactory VMVerifierFactory, managerFactory ManagerFactory, messenger VerifyMessenger) *VerifyCmd {
+	return &VerifyCmd{ctx: ctx, verifierFactory: verifierFactory, managerFactory: managerFactory, messenger: messenger}
+}
+
+func (*VerifyCmd) Name() string { return "verify" }
+func (*VerifyCmd) Synopsis() string {
+	return "Checks that a VM prepared by construct is ready to be packaged into a stemcell"
+}
+
+func (*VerifyCmd) Usage() string {
+	return fmt.Sprintf(`%[1]s verify -vm-username <vm username> -vm-password <vm password> -vcenter-url <vCenter URL> -vcenter-username <vCenter username> -vcenter-password <vCenter password> -vm-inventory-path <vCenter VM inventory path>
+
+Checks a VM that stembuild construct has prepared and powered off, so that stembuild package only runs on a VM that
+passes. The VM itself is never powered on: a snapshot of it is taken, a linked clone of that snapshot is booted and
+the checks run in the clone through VMware Tools. The clone is destroyed and the snapshot removed afterwards.
+
+Checks:
+	bosh-agent           the bosh-agent service is installed
+	sysprep-generalize   sysprep generalized the VM
+	openssh              sshd.exe and the sshd service are installed
+	stemcell-version     C:\var\vcap\bosh\etc\stemcell_version exists
+	lgpo                 LGPO.exe is installed and a machine policy has been applied
+
+	verify exits non-zero if any check fails, or if the checks could not be run.
+
+Requirements:
+	A VM powered off by stembuild construct
+	[vm-username] and [vm-password] must still log in to the VM after sysprep, e.g. because construct was run with
+	-skip-random-password
+	The [vm-username], [vm-password], [vcenter-url], [vcenter-username], [vcenter-password], [vm-inventory-path] must be specified
+
+Example:
+	%[1]s verify -vm-username Administrator -vm-password 'password' -vcenter-url vcenter.example.com -vcenter-username root -vcenter-password 'password' -vm-inventory-path '/datacenter/vm/folder/vm-name'
+
+Clone:
+	The clone is made at [vm-inventory-path] with a -verify suffix, or at [clone-to]. With [keep-clone], the clone and
+	the stembuild-verify snapshot are left in place to look into a failed check; destroy the clone and then remove the
+	snapshot before verifying the VM again. [timeout] bounds the wait for the clone to boot, and then for the checks.
+
+Credentials:
+	Instead of the password itself, [vm-password] and [vcenter-password] take a reference to it: env://NAME, file:///path,
+	stdin:// or vault://secret/data/path#key, which reads key from a Vault KV secret using VAULT_ADDR and VAULT_TOKEN.
+
+Build configuration:
+	With [config], options are read from a YAML file with vcenter, vm and verify sections. Each key can be
+	overridden by a STEMBUILD_* environment variable, e.g. STEMBUILD_VCENTER_PASSWORD, and flags override both.
+
+Flags:
+`, filepath.Base(os.Args[0]))
+}
+
+func (p *VerifyCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.configFile, "config", "", "YAML build configuration file. Flags and STEMBUILD_* environment variables take precedence over it")
+	f.StringVar(&p.sourceConfig.GuestVMUsername, "vm-username", "", "Username of the VM")
+	f.StringVar(&p.sourceConfig.GuestVMPassword, "vm-password", "", "Password of the VM, or an env://, file://, stdin:// or vault:// reference to it")
+	f.StringVar(&p.sourceConfig.VCenterUrl, "vcenter-url", "", "vCenter url")
+	f.StringVar(&p.sourceConfig.VCenterUsername, "vcenter-username", "", "vCenter username")
+	f.StringVar(&p.sourceConfig.VCenterPassword, "vcenter-password", "", "vCenter password, or an env://, file://, stdin:// or vault:// reference to it")
+	f.StringVar(&p.sourceConfig.VmInventoryPath, "vm-inventory-path", "", "vCenter VM inventory path. (e.g: <datacenter>/vm/<vm-folder>/<vm-name>)")
+	f.StringVar(&p.sourceConfig.CaCertFile, "vcenter-ca-certs", "", "filepath for custom ca certs")
+	f.StringVar(&p.sourceConfig.CloneTo, "clone-to", "", "Inventory path of the linked clone the checks run in (default: [vm-inventory-path] with a -verify suffix)")
+	f.BoolVar(&p.sourceConfig.KeepClone, "keep-clone", false, "Leave the clone and its snapshot in place after the checks")
+	f.DurationVar(&p.sourceConfig.Timeout, "timeout", verifyconfig.DefaultTimeout, "Time to wait for the clone to boot, and then for the checks to run, 0 for no limit")
+}
+
+func (p *VerifyCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	messenger := p.messenger
+	if p.Events != nil {
+		messenger = &JSONVerifyCmdMessenger{Events: p.Events}
+	}
+
+	err := applyBuildConfig(f, p.configFile, buildconfig.VerifySettings(&p.sourceConfig))
+	if err != nil {
+		messenger.InvalidBuildConfig(err)
+		return subcommands.ExitFailure
+	}
+
+	err = resolveCredentials(p.Credentials, map[string]*string{
+		"vCenter password": &p.sourceConfig.VCenterPassword,
+		"VM password":      &p.sourceConfig.GuestVMPassword,
+	})
+	if err != nil {
+		messenger.CannotResolveCredential(err)
+		return subcommands.ExitFailure
+	}
+
+	c := p.sourceConfig
+	for _, arg := range []string{c.GuestVMUsername, c.GuestVMPassword, c.VCenterUrl, c.VCenterUsername, c.VCenterPassword, c.VmInventoryPath} {
+		if arg == "" {
+			messenger.ArgumentsNotProvided()
+			return subcommands.ExitFailure
+		}
+	}
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+	stopCatching := catchInterrupts(cancel, messenger)
+	defer stopCatching()
+
+	report, err := p.verify(ctx)
+	if err != nil {
+		messenger.CannotVerifyVM(err)
+		return subcommands.ExitFailure
+	}
+
+	messenger.VerifySummary(report)
+	if !report.Passed() {
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+func (p *VerifyCmd) verify(ctx context.Context) (verify.Report, error) {
+	c := p.sourceConfig
+	p.managerFactory.SetConfig(vcenter_client_factory.FactoryConfig{
+		VCenterServer:  c.VCenterUrl,
+		Username:       c.VCenterUsername,
+		Password:       c.VCenterPassword,
+		ClientCreator:  &vcenter_client_factory.ClientCreator{},
+		FinderCreator:  &vcenter_client_factory.GovmomiFinderCreator{},
+		RootCACertPath: c.CaCertFile,
+	})
+	vCenterManager, err := p.managerFactory.VCenterManager(ctx)
+	if err != nil {
+		return verify.Report{}, err
+	}
+
+	verifier, err := p.verifierFactory.VMVerifier(ctx, c, vCenterManager, VMOutput{Stdout: p.stdout(), Events: p.Events})
+	if err != nil {
+		return verify.Report{}, err
+	}
+	return verifier.Verify()
+}
+
+func (p *VerifyCmd) stdout() io.Writer {
+	if p.Stdout == nil {
+		return os.Stdout
+	}
+	return p.Stdout
+}
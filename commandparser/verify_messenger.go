@@ -0,0 +1,101 @@
+package commandparser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+)
+
+type VerifyCmdMessenger struct {
+	OutputChannel io.Writer
+}
+
+func (m *VerifyCmdMessenger) printMessage(message string) {
+	fmt.Fprintln(m.OutputChannel, message)
+}
+
+func (m *VerifyCmdMessenger) ArgumentsNotProvided() {
+	m.printMessage("Not all required parameters were provided. See stembuild --help for more details")
+}
+
+func (m *VerifyCmdMessenger) InvalidBuildConfig(err error) {
+	m.printMessage(fmt.Sprintf("Invalid build configuration: %s", err))
+}
+
+func (m *VerifyCmdMessenger) CannotResolveCredential(err error) {
+	m.printMessage(err.Error())
+}
+
+func (m *VerifyCmdMessenger) CannotVerifyVM(err error) {
+	m.printMessage(fmt.Sprintf("Could not verify VM: %s", err))
+}
+
+func (m *VerifyCmdMessenger) InterruptReceived(signal string) {
+	m.printMessage(fmt.Sprintf("Received %s signal, stopping verify and cleaning up the clone. Interrupt again to exit immediately", signal))
+}
+
+func (m *VerifyCmdMessenger) SecondInterruptReceived(signal string) {
+	m.printMessage(fmt.Sprintf("Received second %s signal, exiting now. The clone and the stembuild-verify snapshot may be left behind", signal))
+}
+
+func (m *VerifyCmdMessenger) VerifySummary(report verify.Report) {
+	m.printMessage("\n" + verifySummary(report))
+}
+
+// verifySummary counts the checks of report that passed and names the ones that failed
+func verifySummary(report verify.Report) string {
+	failed := report.Failed()
+	summary := fmt.Sprintf("%d of %d checks passed", len(report.Results)-len(failed), len(report.Results))
+	if !report.Passed() {
+		if len(failed) > 0 {
+			summary += ", failed: " + strings.Join(failed, ", ")
+		}
+		return fmt.Sprintf("Verification of %s failed: %s", report.VmInventoryPath, summary)
+	}
+	return fmt.Sprintf("Verification of %s passed: %s", report.VmInventoryPath, summary)
+}
+
+// JSONVerifyCmdMessenger reports verify command failures and the verdict of verify as events.
+type JSONVerifyCmdMessenger struct {
+	Events events.Sink
+}
+
+func (m *JSONVerifyCmdMessenger) emitFailure(phase, event string, err string) {
+	m.Events.Emit(events.Event{Command: "verify", Phase: phase, Event: event, Status: events.Failed, Error: err})
+}
+
+func (m *JSONVerifyCmdMessenger) ArgumentsNotProvided() {
+	m.emitFailure("validate", "ArgumentsNotProvided", "Not all required parameters were provided")
+}
+
+func (m *JSONVerifyCmdMessenger) InvalidBuildConfig(err error) {
+	m.emitFailure("validate", "InvalidBuildConfig", err.Error())
+}
+
+func (m *JSONVerifyCmdMessenger) CannotResolveCredential(err error) {
+	m.emitFailure("validate", "CannotResolveCredential", err.Error())
+}
+
+func (m *JSONVerifyCmdMessenger) CannotVerifyVM(err error) {
+	m.emitFailure("", "CannotVerifyVM", err.Error())
+}
+
+func (m *JSONVerifyCmdMessenger) InterruptReceived(signal string) {
+	m.Events.Emit(events.Event{Command: "verify", Event: "InterruptReceived", Status: events.Warning, Message: signal})
+}
+
+func (m *JSONVerifyCmdMessenger) SecondInterruptReceived(signal string) {
+	m.Events.Emit(events.Event{Command: "verify", Event: "SecondInterruptReceived", Status: events.Warning, Message: signal})
+}
+
+// VerifySummary emits a succeeded or failed VerifySummary event, the one to gate stembuild package on
+func (m *JSONVerifyCmdMessenger) VerifySummary(report verify.Report) {
+	status := events.Succeeded
+	if !report.Passed() {
+		status = events.Failed
+	}
+	m.Events.Emit(events.Event{Command: "verify", Phase: "summary", Event: "VerifySummary", Status: status, Target: report.VmInventoryPath, Message: verifySummary(report)})
+}
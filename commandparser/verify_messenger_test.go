@@ -0,0 +1,81 @@
+package commandparser_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("VerifyCmdMessenger", func() {
+	var (
+		vm *commandparser.VerifyCmdMessenger
+		g  *Buffer
+	)
+
+	BeforeEach(func() {
+		g = NewBuffer()
+		vm = &commandparser.VerifyCmdMessenger{OutputChannel: g}
+	})
+
+	It("outputs an appropriate error when the VM cannot be verified", func() {
+		vm.CannotVerifyVM(errors.New("the clone did not boot"))
+		Eventually(g).Should(Say("Could not verify VM: the clone did not boot"))
+	})
+
+	It("summarizes a report that passed", func() {
+		vm.VerifySummary(verify.Report{
+			VmInventoryPath: "/dc/vm/windows2019",
+			Results:         []verify.CheckResult{{Name: "bosh-agent", Passed: true}, {Name: "openssh", Passed: true}},
+		})
+		Eventually(g).Should(Say("Verification of /dc/vm/windows2019 passed: 2 of 2 checks passed"))
+	})
+
+	It("names the checks that failed", func() {
+		vm.VerifySummary(verify.Report{
+			VmInventoryPath: "/dc/vm/windows2019",
+			Results:         []verify.CheckResult{{Name: "bosh-agent", Passed: true}, {Name: "openssh"}, {Name: "lgpo"}},
+		})
+		Eventually(g).Should(Say("Verification of /dc/vm/windows2019 failed: 1 of 3 checks passed, failed: openssh, lgpo"))
+	})
+})
+
+var _ = Describe("JSONVerifyCmdMessenger", func() {
+	var (
+		sink *eventsfakes.FakeSink
+		vm   *commandparser.JSONVerifyCmdMessenger
+	)
+
+	BeforeEach(func() {
+		sink = &eventsfakes.FakeSink{}
+		vm = &commandparser.JSONVerifyCmdMessenger{Events: sink}
+	})
+
+	It("emits a failed validate event when arguments are missing", func() {
+		vm.ArgumentsNotProvided()
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Command).To(Equal("verify"))
+		Expect(event.Phase).To(Equal("validate"))
+		Expect(event.Event).To(Equal("ArgumentsNotProvided"))
+		Expect(event.Status).To(Equal(events.Failed))
+	})
+
+	It("emits a failed summary naming the VM and the checks that failed", func() {
+		vm.VerifySummary(verify.Report{
+			VmInventoryPath: "/dc/vm/windows2019",
+			Results:         []verify.CheckResult{{Name: "bosh-agent", Passed: true}, {Name: "openssh"}},
+		})
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Event).To(Equal("VerifySummary"))
+		Expect(event.Status).To(Equal(events.Failed))
+		Expect(event.Target).To(Equal("/dc/vm/windows2019"))
+		Expect(event.Message).To(Equal("Verification of /dc/vm/windows2019 failed: 1 of 2 checks passed, failed: openssh"))
+	})
+})
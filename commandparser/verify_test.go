@@ -0,0 +1,163 @@
+package commandparser_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/commandparser/commandparserfakes"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	"github.com/cloudfoundry-incubator/stembuild/verify/config"
+	"github.com/google/subcommands"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verify", func() {
+	var (
+		f                  *flag.FlagSet
+		verifyCmd          *VerifyCmd
+		fakeFactory        *commandparserfakes.FakeVMVerifierFactory
+		fakeVerifier       *commandparserfakes.FakeVMVerifier
+		fakeMessenger      *commandparserfakes.FakeVerifyMessenger
+		fakeManagerFactory *commandparserfakes.FakeManagerFactory
+		args               []string
+	)
+
+	BeforeEach(func() {
+		f = flag.NewFlagSet("test", flag.ContinueOnError)
+		fakeFactory = &commandparserfakes.FakeVMVerifierFactory{}
+		fakeVerifier = &commandparserfakes.FakeVMVerifier{}
+		fakeMessenger = &commandparserfakes.FakeVerifyMessenger{}
+		fakeManagerFactory = &commandparserfakes.FakeManagerFactory{}
+		fakeFactory.VMVerifierReturns(fakeVerifier, nil)
+		fakeVerifier.VerifyReturns(verify.Report{
+			VmInventoryPath: "/my-datacenter/vm/my-folder/my-vm",
+			Results:         []verify.CheckResult{{Name: "bosh-agent", Passed: true}},
+		}, nil)
+
+		verifyCmd = NewVerifyCmd(context.Background(), fakeFactory, fakeManagerFactory, fakeMessenger)
+		verifyCmd.SetFlags(f)
+		verifyCmd.GlobalFlags = &GlobalFlags{false, false, false, TextOutput}
+
+		args = []string{
+			"-vm-username", "Admin",
+			"-vm-password", "some_password",
+			"-vcenter-url", "vcenter.example.com",
+			"-vcenter-username", "vCenterUsername",
+			"-vcenter-password", "vCenterPassword",
+			"-vm-inventory-path", "/my-datacenter/vm/my-folder/my-vm",
+		}
+	})
+
+	Describe("SetFlags", func() {
+		It("stores the VM, vCenter and clone options", func() {
+			err := f.Parse(append(args,
+				"-vcenter-ca-certs", "somecerts.txt",
+				"-clone-to", "/my-datacenter/vm/checks/my-vm",
+				"-keep-clone",
+				"-timeout", "45m",
+			))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(verifyCmd.GetSourceConfig()).To(Equal(config.SourceConfig{
+				VCenterUrl:      "vcenter.example.com",
+				VCenterUsername: "vCenterUsername",
+				VCenterPassword: "vCenterPassword",
+				CaCertFile:      "somecerts.txt",
+				VmInventoryPath: "/my-datacenter/vm/my-folder/my-vm",
+				GuestVMUsername: "Admin",
+				GuestVMPassword: "some_password",
+				CloneTo:         "/my-datacenter/vm/checks/my-vm",
+				KeepClone:       true,
+				Timeout:         45 * time.Minute,
+			}))
+		})
+
+		It("waits 30 minutes by default", func() {
+			Expect(f.Parse(args)).To(Succeed())
+			Expect(verifyCmd.GetSourceConfig().Timeout).To(Equal(30 * time.Minute))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("verifies the VM and reports the result", func() {
+			Expect(f.Parse(args)).To(Succeed())
+
+			exitStatus := verifyCmd.Execute(context.Background(), f)
+
+			Expect(exitStatus).To(Equal(subcommands.ExitSuccess))
+			Expect(fakeManagerFactory.SetConfigArgsForCall(0).VCenterServer).To(Equal("vcenter.example.com"))
+			_, sourceConfig, _, _ := fakeFactory.VMVerifierArgsForCall(0)
+			Expect(sourceConfig.VmInventoryPath).To(Equal("/my-datacenter/vm/my-folder/my-vm"))
+			Expect(fakeVerifier.VerifyCallCount()).To(Equal(1))
+			Expect(fakeMessenger.VerifySummaryArgsForCall(0).Passed()).To(BeTrue())
+		})
+
+		It("fails when a check fails, so that package can be gated on it", func() {
+			Expect(f.Parse(args)).To(Succeed())
+			fakeVerifier.VerifyReturns(verify.Report{Results: []verify.CheckResult{{Name: "openssh"}}}, nil)
+
+			exitStatus := verifyCmd.Execute(context.Background(), f)
+
+			Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+			Expect(fakeMessenger.VerifySummaryArgsForCall(0).Failed()).To(Equal([]string{"openssh"}))
+		})
+
+		It("fails when the checks cannot be run", func() {
+			Expect(f.Parse(args)).To(Succeed())
+			fakeVerifier.VerifyReturns(verify.Report{}, errors.New("the clone did not boot"))
+
+			exitStatus := verifyCmd.Execute(context.Background(), f)
+
+			Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+			Expect(fakeMessenger.CannotVerifyVMArgsForCall(0)).To(MatchError("the clone did not boot"))
+			Expect(fakeMessenger.VerifySummaryCallCount()).To(Equal(0))
+		})
+
+		It("fails without verifying when arguments are missing", func() {
+			Expect(f.Parse(args[2:])).To(Succeed())
+
+			exitStatus := verifyCmd.Execute(context.Background(), f)
+
+			Expect(exitStatus).To(Equal(subcommands.ExitFailure))
+			Expect(fakeMessenger.ArgumentsNotProvidedCallCount()).To(Equal(1))
+			Expect(fakeFactory.VMVerifierCallCount()).To(Equal(0))
+		})
+
+		It("resolves credential references before verifying", func() {
+			Expect(f.Parse(args)).To(Succeed())
+			resolver := &commandparserfakes.FakeCredentialResolver{}
+			resolver.ResolveStub = func(name, value string) (string, error) {
+				return "resolved " + value, nil
+			}
+			verifyCmd.Credentials = resolver
+
+			verifyCmd.Execute(context.Background(), f)
+
+			_, sourceConfig, _, _ := fakeFactory.VMVerifierArgsForCall(0)
+			Expect(sourceConfig.GuestVMPassword).To(Equal("resolved some_password"))
+			Expect(sourceConfig.VCenterPassword).To(Equal("resolved vCenterPassword"))
+		})
+
+		It("reports through events when they are set", func() {
+			Expect(f.Parse(args)).To(Succeed())
+			sink := &eventsfakes.FakeSink{}
+			verifyCmd.Events = sink
+
+			verifyCmd.Execute(context.Background(), f)
+
+			_, _, _, output := fakeFactory.VMVerifierArgsForCall(0)
+			Expect(output.Events).To(Equal(sink))
+			event := sink.EmitArgsForCall(sink.EmitCallCount() - 1)
+			Expect(event.Event).To(Equal("VerifySummary"))
+			Expect(event.Status).To(Equal(events.Succeeded))
+			Expect(fakeMessenger.VerifySummaryCallCount()).To(Equal(0))
+		})
+	})
+})
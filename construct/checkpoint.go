@@ -0,0 +1,149 @@
+package construct
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type Phase string
+
+const (
+	PhaseCreateProvisionDir      Phase = "create-provision-dir"
+	PhaseUploadArtifacts         Phase = "upload-artifacts"
+	PhaseEnableWinRM             Phase = "enable-winrm"
+	PhaseValidateVMConnection    Phase = "validate-vm-connection"
+	PhaseExtractArtifacts        Phase = "extract-artifacts"
+	PhaseLogOutUsers             Phase = "log-out-users"
+	PhaseExecuteSetupScript      Phase = "execute-setup-script"
+	PhaseReboot                  Phase = "reboot"
+	PhaseExecutePostRebootScript Phase = "execute-post-reboot-script"
+	PhaseShutdown                Phase = "shutdown"
+	PhaseUploadHooks             Phase = "upload-hooks"
+	PhasePreSetupHooks           Phase = "pre-setup-hooks"
+	PhasePostSetupHooks          Phase = "post-setup-hooks"
+	PhasePreSysprepHooks         Phase = "pre-sysprep-hooks"
+	PhaseSysprep                 Phase = "sysprep"
+	PhaseWindowsUpdates          Phase = "windows-updates"
+	PhaseSetProxy                Phase = "set-proxy"
+	PhaseClearProxy              Phase = "clear-proxy"
+	PhaseRecordHardeningProfile  Phase = "record-hardening-profile"
+)
+
+type Checkpoint struct {
+	Phase     Phase     `json:"phase"`
+	Version   string    `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . CheckpointStore
+type CheckpointStore interface {
+	Load(vmInventoryPath string) (Checkpoint, bool, error)
+	Save(vmInventoryPath string, checkpoint Checkpoint) error
+	Clear(vmInventoryPath string) error
+}
+
+// FileCheckpointStore keeps the last completed construct phase of every VM,
+// keyed by inventory path, in a single JSON file on the local machine.
+type FileCheckpointStore struct {
+	path string
+}
+
+// checkpointFileLocks holds a mutex per checkpoint file, so that the VMs of a batch construct,
+// which share the file, do not overwrite each other's checkpoints
+var checkpointFileLocks sync.Map
+
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path}
+}
+
+func DefaultCheckpointFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory for construct checkpoints: %s", err)
+	}
+	return filepath.Join(home, ".stembuild", "construct-checkpoints.json"), nil
+}
+
+func (s *FileCheckpointStore) Load(vmInventoryPath string) (Checkpoint, bool, error) {
+	checkpoints, err := s.read()
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	checkpoint, found := checkpoints[vmInventoryPath]
+	return checkpoint, found, nil
+}
+
+func (s *FileCheckpointStore) Save(vmInventoryPath string, checkpoint Checkpoint) error {
+	defer s.lock()()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[vmInventoryPath] = checkpoint
+	return s.write(checkpoints)
+}
+
+func (s *FileCheckpointStore) Clear(vmInventoryPath string) error {
+	defer s.lock()()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, found := checkpoints[vmInventoryPath]; !found {
+		return nil
+	}
+	delete(checkpoints, vmInventoryPath)
+	return s.write(checkpoints)
+}
+
+// lock locks the checkpoint file against other stores in this process and returns the function that unlocks it
+func (s *FileCheckpointStore) lock() func() {
+	mutex, _ := checkpointFileLocks.LoadOrStore(s.path, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	return mutex.(*sync.Mutex).Unlock
+}
+
+func (s *FileCheckpointStore) read() (map[string]Checkpoint, error) {
+	checkpoints := map[string]Checkpoint{}
+
+	contents, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read checkpoint file %s: %s", s.path, err)
+	}
+
+	err = json.Unmarshal(contents, &checkpoints)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint file %s is corrupt: %s", s.path, err)
+	}
+	return checkpoints, nil
+}
+
+func (s *FileCheckpointStore) write(checkpoints map[string]Checkpoint) error {
+	err := os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return fmt.Errorf("unable to create checkpoint directory: %s", err)
+	}
+
+	contents, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a sibling file and rename so an interrupted write never leaves a truncated checkpoint file behind
+	tmpPath := s.path + ".tmp"
+	err = ioutil.WriteFile(tmpPath, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write checkpoint file %s: %s", s.path, err)
+	}
+	return os.Rename(tmpPath, s.path)
+}
@@ -0,0 +1,111 @@
+package construct_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/stembuild/construct"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileCheckpointStore", func() {
+	var (
+		tmpDir         string
+		checkpointFile string
+		store          *FileCheckpointStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "checkpoints")
+		Expect(err).NotTo(HaveOccurred())
+
+		checkpointFile = filepath.Join(tmpDir, "nested", "checkpoints.json")
+		store = NewFileCheckpointStore(checkpointFile)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("reports no checkpoint when the file does not exist", func() {
+		_, found, err := store.Load("/dc/vm/some-vm")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("saves and loads checkpoints per VM inventory path", func() {
+		updatedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		Expect(store.Save("/dc/vm/first", Checkpoint{Phase: PhaseReboot, Version: "2019.1", UpdatedAt: updatedAt})).To(Succeed())
+		Expect(store.Save("/dc/vm/second", Checkpoint{Phase: PhaseUploadArtifacts, Version: "2019.1", UpdatedAt: updatedAt})).To(Succeed())
+
+		checkpoint, found, err := NewFileCheckpointStore(checkpointFile).Load("/dc/vm/first")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(checkpoint.Phase).To(Equal(PhaseReboot))
+		Expect(checkpoint.Version).To(Equal("2019.1"))
+		Expect(checkpoint.UpdatedAt).To(BeTemporally("==", updatedAt))
+
+		checkpoint, found, err = store.Load("/dc/vm/second")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(checkpoint.Phase).To(Equal(PhaseUploadArtifacts))
+	})
+
+	It("clears only the checkpoint of the given VM", func() {
+		Expect(store.Save("/dc/vm/first", Checkpoint{Phase: PhaseReboot})).To(Succeed())
+		Expect(store.Save("/dc/vm/second", Checkpoint{Phase: PhaseReboot})).To(Succeed())
+
+		Expect(store.Clear("/dc/vm/first")).To(Succeed())
+
+		_, found, err := store.Load("/dc/vm/first")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		_, found, err = store.Load("/dc/vm/second")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	It("does not create the file when clearing a VM without a checkpoint", func() {
+		Expect(store.Clear("/dc/vm/some-vm")).To(Succeed())
+
+		_, err := os.Stat(checkpointFile)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("keeps the checkpoints of VMs saved at the same time by separate stores", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(NewFileCheckpointStore(checkpointFile).Save(fmt.Sprintf("/dc/vm/vm-%d", i), Checkpoint{Phase: PhaseReboot})).To(Succeed())
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < 20; i++ {
+			_, found, err := store.Load(fmt.Sprintf("/dc/vm/vm-%d", i))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+		}
+	})
+
+	It("returns an error when the checkpoint file is corrupt", func() {
+		Expect(os.MkdirAll(filepath.Dir(checkpointFile), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(checkpointFile, []byte("not json"), 0600)).To(Succeed())
+
+		_, _, err := store.Load("/dc/vm/first")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is corrupt"))
+	})
+})
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// HardeningCIS applies the embedded CIS GPO and disables RC4, 3DES, TLS 1.0, TLS 1.1 and DCOM
+	HardeningCIS = "cis"
+	// HardeningMinimal disables RC4 and 3DES and enables TLS 1.2, without applying a GPO
+	HardeningMinimal = "minimal"
+	// HardeningNone leaves the security settings of the VM alone
+	HardeningNone = "none"
+	// HardeningCustom applies a GPO backup given by the user in place of the embedded CIS GPO
+	HardeningCustom = "custom"
+)
+
+// Hardening selects the security hardening applied to the VM. The zero value is the cis profile.
+type Hardening struct {
+	// Profile is cis, minimal or none
+	Profile string
+	// GPODir holds a GPO backup, as made by LGPO /b, that is applied with the registry settings of the cis profile
+	GPODir string
+}
+
+// ProfileName is the name of the profile that is applied and recorded in the stemcell
+func (h Hardening) ProfileName() string {
+	if h.GPODir != "" {
+		return HardeningCustom
+	}
+	if h.Profile == "" {
+		return HardeningCIS
+	}
+	return h.Profile
+}
+
+func (h Hardening) Validate() error {
+	switch h.Profile {
+	case "", HardeningCIS, HardeningMinimal, HardeningNone:
+	default:
+		return fmt.Errorf("unsupported hardening profile %s, expected cis, minimal or none", h.Profile)
+	}
+	if h.GPODir == "" {
+		return nil
+	}
+	if h.Profile != "" {
+		return errors.New("hardening profile and hardening GPO directory are mutually exclusive")
+	}
+
+	info, err := os.Stat(h.GPODir)
+	if err != nil {
+		return fmt.Errorf("unable to read hardening GPO directory: %s", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("hardening GPO directory %s is not a directory", h.GPODir)
+	}
+	for _, scope := range []string{"Machine", "User"} {
+		info, err = os.Stat(filepath.Join(h.GPODir, "DomainSysvol", "GPO", scope))
+		if err == nil && info.IsDir() {
+			return nil
+		}
+	}
+	return fmt.Errorf("hardening GPO directory %s is not a GPO backup: it has no DomainSysvol/GPO/Machine or DomainSysvol/GPO/User directory", h.GPODir)
+}
@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+)
+
+var _ = Describe("Hardening", func() {
+	var gpoDir string
+
+	BeforeEach(func() {
+		var err error
+		gpoDir, err = ioutil.TempDir("", "hardening-gpo")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(gpoDir)).To(Succeed())
+	})
+
+	It("defaults to the cis profile", func() {
+		Expect(config.Hardening{}.Validate()).To(Succeed())
+		Expect(config.Hardening{}.ProfileName()).To(Equal("cis"))
+	})
+
+	It("accepts the builtin profiles", func() {
+		for _, profile := range []string{"cis", "minimal", "none"} {
+			hardening := config.Hardening{Profile: profile}
+			Expect(hardening.Validate()).To(Succeed())
+			Expect(hardening.ProfileName()).To(Equal(profile))
+		}
+	})
+
+	It("rejects other profiles", func() {
+		Expect(config.Hardening{Profile: "strict"}.Validate()).To(MatchError("unsupported hardening profile strict, expected cis, minimal or none"))
+		Expect(config.Hardening{Profile: "custom"}.Validate()).To(MatchError(ContainSubstring("unsupported hardening profile custom")))
+	})
+
+	It("accepts a GPO backup as the custom profile", func() {
+		Expect(os.MkdirAll(filepath.Join(gpoDir, "DomainSysvol", "GPO", "Machine"), 0700)).To(Succeed())
+
+		hardening := config.Hardening{GPODir: gpoDir}
+		Expect(hardening.Validate()).To(Succeed())
+		Expect(hardening.ProfileName()).To(Equal("custom"))
+	})
+
+	It("accepts a GPO backup of user policy only", func() {
+		Expect(os.MkdirAll(filepath.Join(gpoDir, "DomainSysvol", "GPO", "User"), 0700)).To(Succeed())
+
+		Expect(config.Hardening{GPODir: gpoDir}.Validate()).To(Succeed())
+	})
+
+	It("rejects a profile together with a GPO backup", func() {
+		Expect(os.MkdirAll(filepath.Join(gpoDir, "DomainSysvol", "GPO", "Machine"), 0700)).To(Succeed())
+
+		Expect(config.Hardening{Profile: "cis", GPODir: gpoDir}.Validate()).To(MatchError("hardening profile and hardening GPO directory are mutually exclusive"))
+	})
+
+	It("rejects a directory that is not a GPO backup", func() {
+		Expect(config.Hardening{GPODir: gpoDir}.Validate()).To(MatchError(ContainSubstring("is not a GPO backup")))
+	})
+
+	It("rejects a GPO directory that does not exist or is a file", func() {
+		Expect(config.Hardening{GPODir: filepath.Join(gpoDir, "missing")}.Validate()).To(MatchError(ContainSubstring("unable to read hardening GPO directory")))
+
+		file := filepath.Join(gpoDir, "gpo.zip")
+		Expect(ioutil.WriteFile(file, nil, 0600)).To(Succeed())
+		Expect(config.Hardening{GPODir: file}.Validate()).To(MatchError(ContainSubstring("is not a directory")))
+	})
+})
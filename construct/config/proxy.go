@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Proxy is the HTTP proxy the VM uses while it is constructed. The zero value leaves the proxy settings of the VM alone.
+type Proxy struct {
+	// HTTP and HTTPS are host:port addresses
+	HTTP  string
+	HTTPS string
+	// BypassList holds the hosts reached without the proxy, separated by semicolons
+	BypassList string
+}
+
+// Enabled reports whether a proxy is configured
+func (p Proxy) Enabled() bool {
+	return p.HTTP != "" || p.HTTPS != ""
+}
+
+func (p Proxy) Validate() error {
+	if p.BypassList != "" && !p.Enabled() {
+		return errors.New("proxy bypass list needs an HTTP or HTTPS proxy")
+	}
+	for _, proxy := range []struct{ name, address string }{{"HTTP", p.HTTP}, {"HTTPS", p.HTTPS}} {
+		if proxy.address == "" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(proxy.address)
+		if err == nil {
+			_, err = strconv.ParseUint(port, 10, 16)
+		}
+		if err != nil || host == "" || strings.ContainsAny(host, `/"'; `) {
+			return fmt.Errorf("%s proxy must be a host:port address, got %s", proxy.name, proxy.address)
+		}
+	}
+	if strings.ContainsAny(p.BypassList, `"' `) {
+		return fmt.Errorf("proxy bypass list must be hosts separated by semicolons, got %s", p.BypassList)
+	}
+	return nil
+}
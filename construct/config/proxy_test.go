@@ -0,0 +1,34 @@
+package config_test
+
+import (
+	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Proxy", func() {
+	It("accepts no proxy", func() {
+		Expect(config.Proxy{}.Validate()).To(Succeed())
+		Expect(config.Proxy{}.Enabled()).To(BeFalse())
+	})
+
+	It("accepts host:port proxies with a bypass list", func() {
+		proxy := config.Proxy{HTTP: "proxy.example.com:3128", HTTPS: "10.0.0.1:3129", BypassList: "*.example.com;<local>"}
+		Expect(proxy.Validate()).To(Succeed())
+		Expect(proxy.Enabled()).To(BeTrue())
+	})
+
+	It("rejects a bypass list without a proxy", func() {
+		Expect(config.Proxy{BypassList: "*.example.com"}.Validate()).To(MatchError("proxy bypass list needs an HTTP or HTTPS proxy"))
+	})
+
+	It("rejects proxies that are not host:port addresses", func() {
+		Expect(config.Proxy{HTTP: "http://proxy.example.com:3128"}.Validate()).To(MatchError("HTTP proxy must be a host:port address, got http://proxy.example.com:3128"))
+		Expect(config.Proxy{HTTPS: "proxy.example.com"}.Validate()).To(MatchError("HTTPS proxy must be a host:port address, got proxy.example.com"))
+		Expect(config.Proxy{HTTP: "proxy.example.com:99999"}.Validate()).To(MatchError(ContainSubstring("must be a host:port address")))
+	})
+
+	It("rejects a bypass list that would break out of the proxy command", func() {
+		Expect(config.Proxy{HTTP: "proxy:3128", BypassList: `*.example.com" & calc`}.Validate()).To(MatchError(ContainSubstring("hosts separated by semicolons")))
+	})
+})
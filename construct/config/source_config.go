@@ -1,12 +1,40 @@
 package config
 
 type SourceConfig struct {
-	GuestVmIp       string
-	GuestVMUsername string
-	GuestVMPassword string
-	VCenterUrl      string
-	VCenterUsername string
-	VCenterPassword string
-	VmInventoryPath string
-	CaCertFile      string
+	GuestVmIp          string
+	GuestVMUsername    string
+	GuestVMPassword    string
+	VCenterUrl         string
+	VCenterUsername    string
+	VCenterPassword    string
+	VmInventoryPath    string
+	CaCertFile         string
+	Resume             bool
+	CheckpointFile     string
+	Organization       string
+	Owner              string
+	SkipRandomPassword bool
+	DiagnosticsDir     string
+	CloneTo            string
+	CloneFolder        string
+	CloneResourcePool  string
+	CloneDatastore     string
+	SnapshotName       string
+	RevertSnapshot     bool
+	DeleteSnapshot     bool
+	Timeouts           Timeouts
+	HooksDir           string
+	DryRun             bool
+	WinRM              WinRM
+	Transport          string
+	GuestOpsOnly       bool
+	TargetsFile        string
+	MaxParallel        int
+	AutomationBundle   string
+	LGPO               string
+	WindowsUpdates     bool
+	// WindowsUpdatesMaxIterations limits how many batches of Windows Updates are installed
+	WindowsUpdatesMaxIterations int
+	Proxy                       Proxy
+	Hardening                   Hardening
 }
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timeouts bounds every wait in construct. WithDefaults gives the waits that cannot be zero their default.
+type Timeouts struct {
+	// Construct is the overall deadline for the construct phases, from creating the provision directory to shutdown, 0 for no limit
+	Construct time.Duration
+	// RebootDelay is the wait after the setup script before checking whether the VM has rebooted, 0 to check straight away
+	RebootDelay time.Duration
+	// RebootPollInterval is the wait between checks whether the VM has rebooted, 0 for the default
+	RebootPollInterval time.Duration
+	// Reboot bounds the wait for the VM to finish rebooting, 0 for no limit
+	Reboot time.Duration
+	// PostRebootScript bounds the post-reboot script, which runs sysprep, 0 for the default
+	PostRebootScript time.Duration
+	// ShutdownPollInterval is the wait between checks whether the VM has powered off, 0 for the default
+	ShutdownPollInterval time.Duration
+	// Shutdown bounds the wait for the VM to power off after sysprep, 0 for no limit
+	Shutdown time.Duration
+	// WinRMOperation bounds each WinRM command and upload, 0 for the default
+	WinRMOperation time.Duration
+	// WinRMConnect bounds connecting to WinRM on the VM, 0 for the default
+	WinRMConnect time.Duration
+	// Hook bounds each provisioning hook script, 0 for the default
+	Hook time.Duration
+	// WindowsUpdates bounds each search for Windows Updates and installing each batch of them, 0 for the default
+	WindowsUpdates time.Duration
+}
+
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		RebootDelay:          60 * time.Second,
+		RebootPollInterval:   10 * time.Second,
+		PostRebootScript:     24 * time.Hour,
+		ShutdownPollInterval: time.Minute,
+		WinRMOperation:       120 * time.Second,
+		WinRMConnect:         60 * time.Second,
+		Hook:                 30 * time.Minute,
+		WindowsUpdates:       2 * time.Hour,
+	}
+}
+
+// WithDefaults fills in the waits that cannot be zero, so a partially populated Timeouts can be used
+func (t Timeouts) WithDefaults() Timeouts {
+	defaults := DefaultTimeouts()
+	if t.RebootPollInterval == 0 {
+		t.RebootPollInterval = defaults.RebootPollInterval
+	}
+	if t.PostRebootScript == 0 {
+		t.PostRebootScript = defaults.PostRebootScript
+	}
+	if t.ShutdownPollInterval == 0 {
+		t.ShutdownPollInterval = defaults.ShutdownPollInterval
+	}
+	if t.WinRMOperation == 0 {
+		t.WinRMOperation = defaults.WinRMOperation
+	}
+	if t.WinRMConnect == 0 {
+		t.WinRMConnect = defaults.WinRMConnect
+	}
+	if t.Hook == 0 {
+		t.Hook = defaults.Hook
+	}
+	if t.WindowsUpdates == 0 {
+		t.WindowsUpdates = defaults.WindowsUpdates
+	}
+	return t
+}
+
+func (t Timeouts) Validate() error {
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"construct timeout", t.Construct},
+		{"reboot delay", t.RebootDelay},
+		{"reboot poll interval", t.RebootPollInterval},
+		{"reboot timeout", t.Reboot},
+		{"post-reboot script timeout", t.PostRebootScript},
+		{"shutdown poll interval", t.ShutdownPollInterval},
+		{"shutdown timeout", t.Shutdown},
+		{"WinRM operation timeout", t.WinRMOperation},
+		{"WinRM connect timeout", t.WinRMConnect},
+		{"hook timeout", t.Hook},
+		{"Windows Updates timeout", t.WindowsUpdates},
+	}
+
+	for _, d := range durations {
+		if d.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %s", d.name, d.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package config_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+)
+
+var _ = Describe("Timeouts", func() {
+	It("defaults to the waits construct has always used", func() {
+		timeouts := config.DefaultTimeouts()
+
+		Expect(timeouts.Validate()).To(Succeed())
+		Expect(timeouts.Construct).To(BeZero())
+		Expect(timeouts.RebootDelay).To(Equal(60 * time.Second))
+		Expect(timeouts.PostRebootScript).To(Equal(24 * time.Hour))
+		Expect(timeouts.WinRMOperation).To(Equal(120 * time.Second))
+		Expect(timeouts.WindowsUpdates).To(Equal(2 * time.Hour))
+	})
+
+	It("rejects negative timeouts", func() {
+		timeouts := config.DefaultTimeouts()
+		timeouts.Shutdown = -time.Minute
+
+		Expect(timeouts.Validate()).To(MatchError("shutdown timeout must not be negative, got -1m0s"))
+	})
+
+	It("fills in the waits that cannot be zero", func() {
+		timeouts := config.Timeouts{RebootDelay: 0, Shutdown: time.Hour}.WithDefaults()
+
+		Expect(timeouts.RebootDelay).To(BeZero())
+		Expect(timeouts.Shutdown).To(Equal(time.Hour))
+		Expect(timeouts.RebootPollInterval).To(Equal(10 * time.Second))
+		Expect(timeouts.ShutdownPollInterval).To(Equal(time.Minute))
+		Expect(timeouts.WinRMConnect).To(Equal(60 * time.Second))
+	})
+})
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WinRM selects how construct connects to the WinRM service of the VM. The zero value is plain HTTP on port 5985.
+type WinRM struct {
+	// Port defaults to 5985, or 5986 over HTTPS
+	Port  int
+	HTTPS bool
+	// CACertFile holds the certificate authorities the HTTPS listener is verified against.
+	// Without it, construct trusts the certificate it provisions for the listener.
+	CACertFile string
+	Insecure   bool
+	// Auth is basic, the default, ntlm or kerberos
+	Auth string
+	// Kerberos holds the credentials of kerberos auth
+	Kerberos Kerberos
+}
+
+// Kerberos authenticates the VM user with a ticket instead of the VM password
+type Kerberos struct {
+	// Realm of the VM user, needed with Keytab
+	Realm string
+	// Keytab holds the keys of the VM user
+	Keytab string
+	// CCache is a credential cache holding a ticket granting ticket of the VM user, e.g. written by kinit
+	CCache string
+	// Config is the krb5.conf locating the KDCs of the realm, /etc/krb5.conf by default
+	Config string
+	// SPN is the service principal of WinRM on the VM, HTTP/<vm-ip> by default
+	SPN string
+}
+
+func (w WinRM) Validate() error {
+	if w.Port < 0 || w.Port > 65535 {
+		return fmt.Errorf("WinRM port must be between 1 and 65535, got %d", w.Port)
+	}
+	if !w.HTTPS && (w.CACertFile != "" || w.Insecure) {
+		return errors.New("WinRM CA certificates and insecure mode only apply over HTTPS")
+	}
+	if w.CACertFile != "" && w.Insecure {
+		return errors.New("WinRM CA certificates and insecure mode are mutually exclusive")
+	}
+	switch w.Auth {
+	case "", "basic", "ntlm":
+		if w.Kerberos != (Kerberos{}) {
+			return errors.New("Kerberos settings only apply with kerberos WinRM authentication")
+		}
+	case "kerberos":
+		return w.Kerberos.Validate()
+	default:
+		return fmt.Errorf("unsupported WinRM authentication %s, expected basic, ntlm or kerberos", w.Auth)
+	}
+	return nil
+}
+
+func (k Kerberos) Validate() error {
+	if (k.Keytab == "") == (k.CCache == "") {
+		return errors.New("kerberos WinRM authentication needs either a keytab or a credential cache")
+	}
+	if k.Keytab != "" && k.Realm == "" {
+		return errors.New("a Kerberos keytab needs the realm of the VM user")
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+)
+
+var _ = Describe("WinRM", func() {
+	It("defaults to plain HTTP", func() {
+		Expect(config.WinRM{}.Validate()).To(Succeed())
+	})
+
+	It("accepts HTTPS with CA certificates or in insecure mode", func() {
+		Expect(config.WinRM{HTTPS: true, Port: 443, CACertFile: "ca.pem"}.Validate()).To(Succeed())
+		Expect(config.WinRM{HTTPS: true, Insecure: true}.Validate()).To(Succeed())
+	})
+
+	It("rejects ports out of range", func() {
+		Expect(config.WinRM{Port: 70000}.Validate()).To(MatchError("WinRM port must be between 1 and 65535, got 70000"))
+	})
+
+	It("rejects CA certificates or insecure mode without HTTPS", func() {
+		Expect(config.WinRM{CACertFile: "ca.pem"}.Validate()).To(MatchError(ContainSubstring("only apply over HTTPS")))
+		Expect(config.WinRM{Insecure: true}.Validate()).To(MatchError(ContainSubstring("only apply over HTTPS")))
+	})
+
+	It("rejects CA certificates in insecure mode", func() {
+		Expect(config.WinRM{HTTPS: true, CACertFile: "ca.pem", Insecure: true}.Validate()).To(MatchError(ContainSubstring("mutually exclusive")))
+	})
+
+	It("accepts Basic and NTLM authentication", func() {
+		Expect(config.WinRM{Auth: "basic"}.Validate()).To(Succeed())
+		Expect(config.WinRM{HTTPS: true, Auth: "ntlm"}.Validate()).To(Succeed())
+	})
+
+	It("accepts Kerberos authentication with a keytab or a credential cache", func() {
+		Expect(config.WinRM{HTTPS: true, Auth: "kerberos", Kerberos: config.Kerberos{Realm: "EXAMPLE.COM", Keytab: "user.keytab"}}.Validate()).To(Succeed())
+		Expect(config.WinRM{HTTPS: true, Auth: "kerberos", Kerberos: config.Kerberos{CCache: "/tmp/krb5cc_0", SPN: "HTTP/vm.example.com"}}.Validate()).To(Succeed())
+	})
+
+	It("rejects Kerberos authentication without exactly one of a keytab and a credential cache", func() {
+		Expect(config.WinRM{Auth: "kerberos"}.Validate()).To(MatchError(ContainSubstring("needs either a keytab or a credential cache")))
+		Expect(config.WinRM{Auth: "kerberos", Kerberos: config.Kerberos{Realm: "EXAMPLE.COM", Keytab: "user.keytab", CCache: "/tmp/krb5cc_0"}}.Validate()).To(MatchError(ContainSubstring("needs either a keytab or a credential cache")))
+	})
+
+	It("rejects a Kerberos keytab without a realm", func() {
+		Expect(config.WinRM{Auth: "kerberos", Kerberos: config.Kerberos{Keytab: "user.keytab"}}.Validate()).To(MatchError("a Kerberos keytab needs the realm of the VM user"))
+	})
+
+	It("rejects Kerberos settings with other authentication", func() {
+		Expect(config.WinRM{Auth: "ntlm", Kerberos: config.Kerberos{CCache: "/tmp/krb5cc_0"}}.Validate()).To(MatchError(ContainSubstring("only apply with kerberos")))
+	})
+
+	It("rejects other authentication", func() {
+		Expect(config.WinRM{Auth: "digest"}.Validate()).To(MatchError("unsupported WinRM authentication digest, expected basic, ntlm or kerberos"))
+	})
+})
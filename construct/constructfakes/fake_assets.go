@@ -0,0 +1,317 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package constructfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/construct"
+)
+
+type FakeAssets struct {
+	AutomationBundleStub        func() ([]byte, error)
+	automationBundleMutex       sync.RWMutex
+	automationBundleArgsForCall []struct {
+	}
+	automationBundleReturns struct {
+		result1 []byte
+		result2 error
+	}
+	automationBundleReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	AutomationBundleFileStub        func() (string, func(), error)
+	automationBundleFileMutex       sync.RWMutex
+	automationBundleFileArgsForCall []struct {
+	}
+	automationBundleFileReturns struct {
+		result1 string
+		result2 func()
+		result3 error
+	}
+	automationBundleFileReturnsOnCall map[int]struct {
+		result1 string
+		result2 func()
+		result3 error
+	}
+	AutomationBundleSourceStub        func() string
+	automationBundleSourceMutex       sync.RWMutex
+	automationBundleSourceArgsForCall []struct {
+	}
+	automationBundleSourceReturns struct {
+		result1 string
+	}
+	automationBundleSourceReturnsOnCall map[int]struct {
+		result1 string
+	}
+	LGPOFileStub        func() (string, error)
+	lGPOFileMutex       sync.RWMutex
+	lGPOFileArgsForCall []struct {
+	}
+	lGPOFileReturns struct {
+		result1 string
+		result2 error
+	}
+	lGPOFileReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAssets) AutomationBundle() ([]byte, error) {
+	fake.automationBundleMutex.Lock()
+	ret, specificReturn := fake.automationBundleReturnsOnCall[len(fake.automationBundleArgsForCall)]
+	fake.automationBundleArgsForCall = append(fake.automationBundleArgsForCall, struct {
+	}{})
+	stub := fake.AutomationBundleStub
+	fakeReturns := fake.automationBundleReturns
+	fake.recordInvocation("AutomationBundle", []interface{}{})
+	fake.automationBundleMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAssets) AutomationBundleCallCount() int {
+	fake.automationBundleMutex.RLock()
+	defer fake.automationBundleMutex.RUnlock()
+	return len(fake.automationBundleArgsForCall)
+}
+
+func (fake *FakeAssets) AutomationBundleCalls(stub func() ([]byte, error)) {
+	fake.automationBundleMutex.Lock()
+	defer fake.automationBundleMutex.Unlock()
+	fake.AutomationBundleStub = stub
+}
+
+func (fake *FakeAssets) AutomationBundleReturns(result1 []byte, result2 error) {
+	fake.automationBundleMutex.Lock()
+	defer fake.automationBundleMutex.Unlock()
+	fake.AutomationBundleStub = nil
+	fake.automationBundleReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAssets) AutomationBundleReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.automationBundleMutex.Lock()
+	defer fake.automationBundleMutex.Unlock()
+	fake.AutomationBundleStub = nil
+	if fake.automationBundleReturnsOnCall == nil {
+		fake.automationBundleReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.automationBundleReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAssets) AutomationBundleFile() (string, func(), error) {
+	fake.automationBundleFileMutex.Lock()
+	ret, specificReturn := fake.automationBundleFileReturnsOnCall[len(fake.automationBundleFileArgsForCall)]
+	fake.automationBundleFileArgsForCall = append(fake.automationBundleFileArgsForCall, struct {
+	}{})
+	stub := fake.AutomationBundleFileStub
+	fakeReturns := fake.automationBundleFileReturns
+	fake.recordInvocation("AutomationBundleFile", []interface{}{})
+	fake.automationBundleFileMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAssets) AutomationBundleFileCallCount() int {
+	fake.automationBundleFileMutex.RLock()
+	defer fake.automationBundleFileMutex.RUnlock()
+	return len(fake.automationBundleFileArgsForCall)
+}
+
+func (fake *FakeAssets) AutomationBundleFileCalls(stub func() (string, func(), error)) {
+	fake.automationBundleFileMutex.Lock()
+	defer fake.automationBundleFileMutex.Unlock()
+	fake.AutomationBundleFileStub = stub
+}
+
+func (fake *FakeAssets) AutomationBundleFileReturns(result1 string, result2 func(), result3 error) {
+	fake.automationBundleFileMutex.Lock()
+	defer fake.automationBundleFileMutex.Unlock()
+	fake.AutomationBundleFileStub = nil
+	fake.automationBundleFileReturns = struct {
+		result1 string
+		result2 func()
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAssets) AutomationBundleFileReturnsOnCall(i int, result1 string, result2 func(), result3 error) {
+	fake.automationBundleFileMutex.Lock()
+	defer fake.automationBundleFileMutex.Unlock()
+	fake.AutomationBundleFileStub = nil
+	if fake.automationBundleFileReturnsOnCall == nil {
+		fake.automationBundleFileReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 func()
+			result3 error
+		})
+	}
+	fake.automationBundleFileReturnsOnCall[i] = struct {
+		result1 string
+		result2 func()
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAssets) AutomationBundleSource() string {
+	fake.automationBundleSourceMutex.Lock()
+	ret, specificReturn := fake.automationBundleSourceReturnsOnCall[len(fake.automationBundleSourceArgsForCall)]
+	fake.automationBundleSourceArgsForCall = append(fake.automationBundleSourceArgsForCall, struct {
+	}{})
+	stub := fake.AutomationBundleSourceStub
+	fakeReturns := fake.automationBundleSourceReturns
+	fake.recordInvocation("AutomationBundleSource", []interface{}{})
+	fake.automationBundleSourceMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAssets) AutomationBundleSourceCallCount() int {
+	fake.automationBundleSourceMutex.RLock()
+	defer fake.automationBundleSourceMutex.RUnlock()
+	return len(fake.automationBundleSourceArgsForCall)
+}
+
+func (fake *FakeAssets) AutomationBundleSourceCalls(stub func() string) {
+	fake.automationBundleSourceMutex.Lock()
+	defer fake.automationBundleSourceMutex.Unlock()
+	fake.AutomationBundleSourceStub = stub
+}
+
+func (fake *FakeAssets) AutomationBundleSourceReturns(result1 string) {
+	fake.automationBundleSourceMutex.Lock()
+	defer fake.automationBundleSourceMutex.Unlock()
+	fake.AutomationBundleSourceStub = nil
+	fake.automationBundleSourceReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeAssets) AutomationBundleSourceReturnsOnCall(i int, result1 string) {
+	fake.automationBundleSourceMutex.Lock()
+	defer fake.automationBundleSourceMutex.Unlock()
+	fake.AutomationBundleSourceStub = nil
+	if fake.automationBundleSourceReturnsOnCall == nil {
+		fake.automationBundleSourceReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.automationBundleSourceReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeAssets) LGPOFile() (string, error) {
+	fake.lGPOFileMutex.Lock()
+	ret, specificReturn := fake.lGPOFileReturnsOnCall[len(fake.lGPOFileArgsForCall)]
+	fake.lGPOFileArgsForCall = append(fake.lGPOFileArgsForCall, struct {
+	}{})
+	stub := fake.LGPOFileStub
+	fakeReturns := fake.lGPOFileReturns
+	fake.recordInvocation("LGPOFile", []interface{}{})
+	fake.lGPOFileMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAssets) LGPOFileCallCount() int {
+	fake.lGPOFileMutex.RLock()
+	defer fake.lGPOFileMutex.RUnlock()
+	return len(fake.lGPOFileArgsForCall)
+}
+
+func (fake *FakeAssets) LGPOFileCalls(stub func() (string, error)) {
+	fake.lGPOFileMutex.Lock()
+	defer fake.lGPOFileMutex.Unlock()
+	fake.LGPOFileStub = stub
+}
+
+func (fake *FakeAssets) LGPOFileReturns(result1 string, result2 error) {
+	fake.lGPOFileMutex.Lock()
+	defer fake.lGPOFileMutex.Unlock()
+	fake.LGPOFileStub = nil
+	fake.lGPOFileReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAssets) LGPOFileReturnsOnCall(i int, result1 string, result2 error) {
+	fake.lGPOFileMutex.Lock()
+	defer fake.lGPOFileMutex.Unlock()
+	fake.LGPOFileStub = nil
+	if fake.lGPOFileReturnsOnCall == nil {
+		fake.lGPOFileReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.lGPOFileReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAssets) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.automationBundleMutex.RLock()
+	defer fake.automationBundleMutex.RUnlock()
+	fake.automationBundleFileMutex.RLock()
+	defer fake.automationBundleFileMutex.RUnlock()
+	fake.automationBundleSourceMutex.RLock()
+	defer fake.automationBundleSourceMutex.RUnlock()
+	fake.lGPOFileMutex.RLock()
+	defer fake.lGPOFileMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeAssets) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ construct.Assets = new(FakeAssets)
@@ -3,11 +3,70 @@ package constructfakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/cloudfoundry-incubator/stembuild/construct"
 )
 
 type FakeConstructMessenger struct {
+	ArtifactTransportFailedStub        func(string, string, error)
+	artifactTransportFailedMutex       sync.RWMutex
+	artifactTransportFailedArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 error
+	}
+	CheckpointNotConfirmedStub        func(string)
+	checkpointNotConfirmedMutex       sync.RWMutex
+	checkpointNotConfirmedArgsForCall []struct {
+		arg1 string
+	}
+	CheckpointsDisabledStub        func(error)
+	checkpointsDisabledMutex       sync.RWMutex
+	checkpointsDisabledArgsForCall []struct {
+		arg1 error
+	}
+	ClearProxyStartedStub        func()
+	clearProxyStartedMutex       sync.RWMutex
+	clearProxyStartedArgsForCall []struct {
+	}
+	ClearProxySucceededStub        func()
+	clearProxySucceededMutex       sync.RWMutex
+	clearProxySucceededArgsForCall []struct {
+	}
+	CloneIPDiscoveredStub        func(string)
+	cloneIPDiscoveredMutex       sync.RWMutex
+	cloneIPDiscoveredArgsForCall []struct {
+		arg1 string
+	}
+	CloneVMStartedStub        func(string)
+	cloneVMStartedMutex       sync.RWMutex
+	cloneVMStartedArgsForCall []struct {
+		arg1 string
+	}
+	CloneVMSucceededStub        func()
+	cloneVMSucceededMutex       sync.RWMutex
+	cloneVMSucceededArgsForCall []struct {
+	}
+	CollectDiagnosticsFailedStub        func(error)
+	collectDiagnosticsFailedMutex       sync.RWMutex
+	collectDiagnosticsFailedArgsForCall []struct {
+		arg1 error
+	}
+	CollectDiagnosticsStartedStub        func()
+	collectDiagnosticsStartedMutex       sync.RWMutex
+	collectDiagnosticsStartedArgsForCall []struct {
+	}
+	CollectDiagnosticsSucceededStub        func()
+	collectDiagnosticsSucceededMutex       sync.RWMutex
+	collectDiagnosticsSucceededArgsForCall []struct {
+	}
+	ConstructInterruptedStub        func(string, string)
+	constructInterruptedMutex       sync.RWMutex
+	constructInterruptedArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
 	CreateProvisionDirStartedStub        func()
 	createProvisionDirStartedMutex       sync.RWMutex
 	createProvisionDirStartedArgsForCall []struct {
@@ -16,6 +75,23 @@ type FakeConstructMessenger struct {
 	createProvisionDirSucceededMutex       sync.RWMutex
 	createProvisionDirSucceededArgsForCall []struct {
 	}
+	CreateSnapshotStartedStub        func(string)
+	createSnapshotStartedMutex       sync.RWMutex
+	createSnapshotStartedArgsForCall []struct {
+		arg1 string
+	}
+	CreateSnapshotSucceededStub        func()
+	createSnapshotSucceededMutex       sync.RWMutex
+	createSnapshotSucceededArgsForCall []struct {
+	}
+	DryRunStartedStub        func()
+	dryRunStartedMutex       sync.RWMutex
+	dryRunStartedArgsForCall []struct {
+	}
+	DryRunSucceededStub        func()
+	dryRunSucceededMutex       sync.RWMutex
+	dryRunSucceededArgsForCall []struct {
+	}
 	EnableWinRMStartedStub        func()
 	enableWinRMStartedMutex       sync.RWMutex
 	enableWinRMStartedArgsForCall []struct {
@@ -45,6 +121,14 @@ type FakeConstructMessenger struct {
 	executeSetupScriptSucceededMutex       sync.RWMutex
 	executeSetupScriptSucceededArgsForCall []struct {
 	}
+	ExecuteSysprepScriptStartedStub        func()
+	executeSysprepScriptStartedMutex       sync.RWMutex
+	executeSysprepScriptStartedArgsForCall []struct {
+	}
+	ExecuteSysprepScriptSucceededStub        func()
+	executeSysprepScriptSucceededMutex       sync.RWMutex
+	executeSysprepScriptSucceededArgsForCall []struct {
+	}
 	ExtractArtifactsStartedStub        func()
 	extractArtifactsStartedMutex       sync.RWMutex
 	extractArtifactsStartedArgsForCall []struct {
@@ -53,6 +137,16 @@ type FakeConstructMessenger struct {
 	extractArtifactsSucceededMutex       sync.RWMutex
 	extractArtifactsSucceededArgsForCall []struct {
 	}
+	InstallWindowsUpdatesStartedStub        func(int)
+	installWindowsUpdatesStartedMutex       sync.RWMutex
+	installWindowsUpdatesStartedArgsForCall []struct {
+		arg1 int
+	}
+	InstallWindowsUpdatesSucceededStub        func(int)
+	installWindowsUpdatesSucceededMutex       sync.RWMutex
+	installWindowsUpdatesSucceededArgsForCall []struct {
+		arg1 int
+	}
 	LogOutUsersStartedStub        func()
 	logOutUsersStartedMutex       sync.RWMutex
 	logOutUsersStartedArgsForCall []struct {
@@ -61,6 +155,16 @@ type FakeConstructMessenger struct {
 	logOutUsersSucceededMutex       sync.RWMutex
 	logOutUsersSucceededArgsForCall []struct {
 	}
+	NoCheckpointFoundStub        func()
+	noCheckpointFoundMutex       sync.RWMutex
+	noCheckpointFoundArgsForCall []struct {
+	}
+	PlannedOperationStub        func(string, string)
+	plannedOperationMutex       sync.RWMutex
+	plannedOperationArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
 	RebootHasFinishedStub        func()
 	rebootHasFinishedMutex       sync.RWMutex
 	rebootHasFinishedArgsForCall []struct {
@@ -69,6 +173,61 @@ type FakeConstructMessenger struct {
 	rebootHasStartedMutex       sync.RWMutex
 	rebootHasStartedArgsForCall []struct {
 	}
+	RecordHardeningProfileStartedStub        func(string)
+	recordHardeningProfileStartedMutex       sync.RWMutex
+	recordHardeningProfileStartedArgsForCall []struct {
+		arg1 string
+	}
+	RecordHardeningProfileSucceededStub        func()
+	recordHardeningProfileSucceededMutex       sync.RWMutex
+	recordHardeningProfileSucceededArgsForCall []struct {
+	}
+	RemoveSnapshotStartedStub        func(string)
+	removeSnapshotStartedMutex       sync.RWMutex
+	removeSnapshotStartedArgsForCall []struct {
+		arg1 string
+	}
+	RemoveSnapshotSucceededStub        func()
+	removeSnapshotSucceededMutex       sync.RWMutex
+	removeSnapshotSucceededArgsForCall []struct {
+	}
+	ResumingFromCheckpointStub        func(string)
+	resumingFromCheckpointMutex       sync.RWMutex
+	resumingFromCheckpointArgsForCall []struct {
+		arg1 string
+	}
+	RevertSnapshotFailedStub        func(error)
+	revertSnapshotFailedMutex       sync.RWMutex
+	revertSnapshotFailedArgsForCall []struct {
+		arg1 error
+	}
+	RevertSnapshotStartedStub        func(string)
+	revertSnapshotStartedMutex       sync.RWMutex
+	revertSnapshotStartedArgsForCall []struct {
+		arg1 string
+	}
+	RevertSnapshotSucceededStub        func()
+	revertSnapshotSucceededMutex       sync.RWMutex
+	revertSnapshotSucceededArgsForCall []struct {
+	}
+	RunHookStartedStub        func(string, string)
+	runHookStartedMutex       sync.RWMutex
+	runHookStartedArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	RunHookSucceededStub        func()
+	runHookSucceededMutex       sync.RWMutex
+	runHookSucceededArgsForCall []struct {
+	}
+	SetProxyStartedStub        func()
+	setProxyStartedMutex       sync.RWMutex
+	setProxyStartedArgsForCall []struct {
+	}
+	SetProxySucceededStub        func()
+	setProxySucceededMutex       sync.RWMutex
+	setProxySucceededArgsForCall []struct {
+	}
 	ShutdownCompletedStub        func()
 	shutdownCompletedMutex       sync.RWMutex
 	shutdownCompletedArgsForCall []struct {
@@ -81,6 +240,14 @@ type FakeConstructMessenger struct {
 	uploadArtifactsSucceededMutex       sync.RWMutex
 	uploadArtifactsSucceededArgsForCall []struct {
 	}
+	UploadFileProgressStub        func(int64, int64, float64, time.Duration)
+	uploadFileProgressMutex       sync.RWMutex
+	uploadFileProgressArgsForCall []struct {
+		arg1 int64
+		arg2 int64
+		arg3 float64
+		arg4 time.Duration
+	}
 	UploadFileStartedStub        func(string)
 	uploadFileStartedMutex       sync.RWMutex
 	uploadFileStartedArgsForCall []struct {
@@ -90,6 +257,19 @@ type FakeConstructMessenger struct {
 	uploadFileSucceededMutex       sync.RWMutex
 	uploadFileSucceededArgsForCall []struct {
 	}
+	UploadHooksStartedStub        func()
+	uploadHooksStartedMutex       sync.RWMutex
+	uploadHooksStartedArgsForCall []struct {
+	}
+	UploadHooksSucceededStub        func()
+	uploadHooksSucceededMutex       sync.RWMutex
+	uploadHooksSucceededArgsForCall []struct {
+	}
+	UsingExistingSnapshotStub        func(string)
+	usingExistingSnapshotMutex       sync.RWMutex
+	usingExistingSnapshotArgsForCall []struct {
+		arg1 string
+	}
 	ValidateVMConnectionStartedStub        func()
 	validateVMConnectionStartedMutex       sync.RWMutex
 	validateVMConnectionStartedArgsForCall []struct {
@@ -106,17 +286,384 @@ type FakeConstructMessenger struct {
 	winRMDisconnectedForRebootMutex       sync.RWMutex
 	winRMDisconnectedForRebootArgsForCall []struct {
 	}
+	WindowsUpdateInstalledStub        func(string, string)
+	windowsUpdateInstalledMutex       sync.RWMutex
+	windowsUpdateInstalledArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	WindowsUpdatesIterationLimitReachedStub        func(int)
+	windowsUpdatesIterationLimitReachedMutex       sync.RWMutex
+	windowsUpdatesIterationLimitReachedArgsForCall []struct {
+		arg1 int
+	}
+	WindowsUpdatesRebootFinishedStub        func()
+	windowsUpdatesRebootFinishedMutex       sync.RWMutex
+	windowsUpdatesRebootFinishedArgsForCall []struct {
+	}
+	WindowsUpdatesRebootStartedStub        func()
+	windowsUpdatesRebootStartedMutex       sync.RWMutex
+	windowsUpdatesRebootStartedArgsForCall []struct {
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeConstructMessenger) ArtifactTransportFailed(arg1 string, arg2 string, arg3 error) {
+	fake.artifactTransportFailedMutex.Lock()
+	fake.artifactTransportFailedArgsForCall = append(fake.artifactTransportFailedArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 error
+	}{arg1, arg2, arg3})
+	stub := fake.ArtifactTransportFailedStub
+	fake.recordInvocation("ArtifactTransportFailed", []interface{}{arg1, arg2, arg3})
+	fake.artifactTransportFailedMutex.Unlock()
+	if stub != nil {
+		fake.ArtifactTransportFailedStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeConstructMessenger) ArtifactTransportFailedCallCount() int {
+	fake.artifactTransportFailedMutex.RLock()
+	defer fake.artifactTransportFailedMutex.RUnlock()
+	return len(fake.artifactTransportFailedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) ArtifactTransportFailedCalls(stub func(string, string, error)) {
+	fake.artifactTransportFailedMutex.Lock()
+	defer fake.artifactTransportFailedMutex.Unlock()
+	fake.ArtifactTransportFailedStub = stub
+}
+
+func (fake *FakeConstructMessenger) ArtifactTransportFailedArgsForCall(i int) (string, string, error) {
+	fake.artifactTransportFailedMutex.RLock()
+	defer fake.artifactTransportFailedMutex.RUnlock()
+	argsForCall := fake.artifactTransportFailedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeConstructMessenger) CheckpointNotConfirmed(arg1 string) {
+	fake.checkpointNotConfirmedMutex.Lock()
+	fake.checkpointNotConfirmedArgsForCall = append(fake.checkpointNotConfirmedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CheckpointNotConfirmedStub
+	fake.recordInvocation("CheckpointNotConfirmed", []interface{}{arg1})
+	fake.checkpointNotConfirmedMutex.Unlock()
+	if stub != nil {
+		fake.CheckpointNotConfirmedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) CheckpointNotConfirmedCallCount() int {
+	fake.checkpointNotConfirmedMutex.RLock()
+	defer fake.checkpointNotConfirmedMutex.RUnlock()
+	return len(fake.checkpointNotConfirmedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CheckpointNotConfirmedCalls(stub func(string)) {
+	fake.checkpointNotConfirmedMutex.Lock()
+	defer fake.checkpointNotConfirmedMutex.Unlock()
+	fake.CheckpointNotConfirmedStub = stub
+}
+
+func (fake *FakeConstructMessenger) CheckpointNotConfirmedArgsForCall(i int) string {
+	fake.checkpointNotConfirmedMutex.RLock()
+	defer fake.checkpointNotConfirmedMutex.RUnlock()
+	argsForCall := fake.checkpointNotConfirmedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) CheckpointsDisabled(arg1 error) {
+	fake.checkpointsDisabledMutex.Lock()
+	fake.checkpointsDisabledArgsForCall = append(fake.checkpointsDisabledArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.CheckpointsDisabledStub
+	fake.recordInvocation("CheckpointsDisabled", []interface{}{arg1})
+	fake.checkpointsDisabledMutex.Unlock()
+	if stub != nil {
+		fake.CheckpointsDisabledStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) CheckpointsDisabledCallCount() int {
+	fake.checkpointsDisabledMutex.RLock()
+	defer fake.checkpointsDisabledMutex.RUnlock()
+	return len(fake.checkpointsDisabledArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CheckpointsDisabledCalls(stub func(error)) {
+	fake.checkpointsDisabledMutex.Lock()
+	defer fake.checkpointsDisabledMutex.Unlock()
+	fake.CheckpointsDisabledStub = stub
+}
+
+func (fake *FakeConstructMessenger) CheckpointsDisabledArgsForCall(i int) error {
+	fake.checkpointsDisabledMutex.RLock()
+	defer fake.checkpointsDisabledMutex.RUnlock()
+	argsForCall := fake.checkpointsDisabledArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) ClearProxyStarted() {
+	fake.clearProxyStartedMutex.Lock()
+	fake.clearProxyStartedArgsForCall = append(fake.clearProxyStartedArgsForCall, struct {
+	}{})
+	stub := fake.ClearProxyStartedStub
+	fake.recordInvocation("ClearProxyStarted", []interface{}{})
+	fake.clearProxyStartedMutex.Unlock()
+	if stub != nil {
+		fake.ClearProxyStartedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) ClearProxyStartedCallCount() int {
+	fake.clearProxyStartedMutex.RLock()
+	defer fake.clearProxyStartedMutex.RUnlock()
+	return len(fake.clearProxyStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) ClearProxyStartedCalls(stub func()) {
+	fake.clearProxyStartedMutex.Lock()
+	defer fake.clearProxyStartedMutex.Unlock()
+	fake.ClearProxyStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) ClearProxySucceeded() {
+	fake.clearProxySucceededMutex.Lock()
+	fake.clearProxySucceededArgsForCall = append(fake.clearProxySucceededArgsForCall, struct {
+	}{})
+	stub := fake.ClearProxySucceededStub
+	fake.recordInvocation("ClearProxySucceeded", []interface{}{})
+	fake.clearProxySucceededMutex.Unlock()
+	if stub != nil {
+		fake.ClearProxySucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) ClearProxySucceededCallCount() int {
+	fake.clearProxySucceededMutex.RLock()
+	defer fake.clearProxySucceededMutex.RUnlock()
+	return len(fake.clearProxySucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) ClearProxySucceededCalls(stub func()) {
+	fake.clearProxySucceededMutex.Lock()
+	defer fake.clearProxySucceededMutex.Unlock()
+	fake.ClearProxySucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) CloneIPDiscovered(arg1 string) {
+	fake.cloneIPDiscoveredMutex.Lock()
+	fake.cloneIPDiscoveredArgsForCall = append(fake.cloneIPDiscoveredArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CloneIPDiscoveredStub
+	fake.recordInvocation("CloneIPDiscovered", []interface{}{arg1})
+	fake.cloneIPDiscoveredMutex.Unlock()
+	if stub != nil {
+		fake.CloneIPDiscoveredStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) CloneIPDiscoveredCallCount() int {
+	fake.cloneIPDiscoveredMutex.RLock()
+	defer fake.cloneIPDiscoveredMutex.RUnlock()
+	return len(fake.cloneIPDiscoveredArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CloneIPDiscoveredCalls(stub func(string)) {
+	fake.cloneIPDiscoveredMutex.Lock()
+	defer fake.cloneIPDiscoveredMutex.Unlock()
+	fake.CloneIPDiscoveredStub = stub
+}
+
+func (fake *FakeConstructMessenger) CloneIPDiscoveredArgsForCall(i int) string {
+	fake.cloneIPDiscoveredMutex.RLock()
+	defer fake.cloneIPDiscoveredMutex.RUnlock()
+	argsForCall := fake.cloneIPDiscoveredArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) CloneVMStarted(arg1 string) {
+	fake.cloneVMStartedMutex.Lock()
+	fake.cloneVMStartedArgsForCall = append(fake.cloneVMStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CloneVMStartedStub
+	fake.recordInvocation("CloneVMStarted", []interface{}{arg1})
+	fake.cloneVMStartedMutex.Unlock()
+	if stub != nil {
+		fake.CloneVMStartedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) CloneVMStartedCallCount() int {
+	fake.cloneVMStartedMutex.RLock()
+	defer fake.cloneVMStartedMutex.RUnlock()
+	return len(fake.cloneVMStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CloneVMStartedCalls(stub func(string)) {
+	fake.cloneVMStartedMutex.Lock()
+	defer fake.cloneVMStartedMutex.Unlock()
+	fake.CloneVMStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) CloneVMStartedArgsForCall(i int) string {
+	fake.cloneVMStartedMutex.RLock()
+	defer fake.cloneVMStartedMutex.RUnlock()
+	argsForCall := fake.cloneVMStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) CloneVMSucceeded() {
+	fake.cloneVMSucceededMutex.Lock()
+	fake.cloneVMSucceededArgsForCall = append(fake.cloneVMSucceededArgsForCall, struct {
+	}{})
+	stub := fake.CloneVMSucceededStub
+	fake.recordInvocation("CloneVMSucceeded", []interface{}{})
+	fake.cloneVMSucceededMutex.Unlock()
+	if stub != nil {
+		fake.CloneVMSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) CloneVMSucceededCallCount() int {
+	fake.cloneVMSucceededMutex.RLock()
+	defer fake.cloneVMSucceededMutex.RUnlock()
+	return len(fake.cloneVMSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CloneVMSucceededCalls(stub func()) {
+	fake.cloneVMSucceededMutex.Lock()
+	defer fake.cloneVMSucceededMutex.Unlock()
+	fake.CloneVMSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsFailed(arg1 error) {
+	fake.collectDiagnosticsFailedMutex.Lock()
+	fake.collectDiagnosticsFailedArgsForCall = append(fake.collectDiagnosticsFailedArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.CollectDiagnosticsFailedStub
+	fake.recordInvocation("CollectDiagnosticsFailed", []interface{}{arg1})
+	fake.collectDiagnosticsFailedMutex.Unlock()
+	if stub != nil {
+		fake.CollectDiagnosticsFailedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsFailedCallCount() int {
+	fake.collectDiagnosticsFailedMutex.RLock()
+	defer fake.collectDiagnosticsFailedMutex.RUnlock()
+	return len(fake.collectDiagnosticsFailedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsFailedCalls(stub func(error)) {
+	fake.collectDiagnosticsFailedMutex.Lock()
+	defer fake.collectDiagnosticsFailedMutex.Unlock()
+	fake.CollectDiagnosticsFailedStub = stub
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsFailedArgsForCall(i int) error {
+	fake.collectDiagnosticsFailedMutex.RLock()
+	defer fake.collectDiagnosticsFailedMutex.RUnlock()
+	argsForCall := fake.collectDiagnosticsFailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsStarted() {
+	fake.collectDiagnosticsStartedMutex.Lock()
+	fake.collectDiagnosticsStartedArgsForCall = append(fake.collectDiagnosticsStartedArgsForCall, struct {
+	}{})
+	stub := fake.CollectDiagnosticsStartedStub
+	fake.recordInvocation("CollectDiagnosticsStarted", []interface{}{})
+	fake.collectDiagnosticsStartedMutex.Unlock()
+	if stub != nil {
+		fake.CollectDiagnosticsStartedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsStartedCallCount() int {
+	fake.collectDiagnosticsStartedMutex.RLock()
+	defer fake.collectDiagnosticsStartedMutex.RUnlock()
+	return len(fake.collectDiagnosticsStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsStartedCalls(stub func()) {
+	fake.collectDiagnosticsStartedMutex.Lock()
+	defer fake.collectDiagnosticsStartedMutex.Unlock()
+	fake.CollectDiagnosticsStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsSucceeded() {
+	fake.collectDiagnosticsSucceededMutex.Lock()
+	fake.collectDiagnosticsSucceededArgsForCall = append(fake.collectDiagnosticsSucceededArgsForCall, struct {
+	}{})
+	stub := fake.CollectDiagnosticsSucceededStub
+	fake.recordInvocation("CollectDiagnosticsSucceeded", []interface{}{})
+	fake.collectDiagnosticsSucceededMutex.Unlock()
+	if stub != nil {
+		fake.CollectDiagnosticsSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsSucceededCallCount() int {
+	fake.collectDiagnosticsSucceededMutex.RLock()
+	defer fake.collectDiagnosticsSucceededMutex.RUnlock()
+	return len(fake.collectDiagnosticsSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CollectDiagnosticsSucceededCalls(stub func()) {
+	fake.collectDiagnosticsSucceededMutex.Lock()
+	defer fake.collectDiagnosticsSucceededMutex.Unlock()
+	fake.CollectDiagnosticsSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) ConstructInterrupted(arg1 string, arg2 string) {
+	fake.constructInterruptedMutex.Lock()
+	fake.constructInterruptedArgsForCall = append(fake.constructInterruptedArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ConstructInterruptedStub
+	fake.recordInvocation("ConstructInterrupted", []interface{}{arg1, arg2})
+	fake.constructInterruptedMutex.Unlock()
+	if stub != nil {
+		fake.ConstructInterruptedStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeConstructMessenger) ConstructInterruptedCallCount() int {
+	fake.constructInterruptedMutex.RLock()
+	defer fake.constructInterruptedMutex.RUnlock()
+	return len(fake.constructInterruptedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) ConstructInterruptedCalls(stub func(string, string)) {
+	fake.constructInterruptedMutex.Lock()
+	defer fake.constructInterruptedMutex.Unlock()
+	fake.ConstructInterruptedStub = stub
+}
+
+func (fake *FakeConstructMessenger) ConstructInterruptedArgsForCall(i int) (string, string) {
+	fake.constructInterruptedMutex.RLock()
+	defer fake.constructInterruptedMutex.RUnlock()
+	argsForCall := fake.constructInterruptedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeConstructMessenger) CreateProvisionDirStarted() {
 	fake.createProvisionDirStartedMutex.Lock()
 	fake.createProvisionDirStartedArgsForCall = append(fake.createProvisionDirStartedArgsForCall, struct {
 	}{})
+	stub := fake.CreateProvisionDirStartedStub
 	fake.recordInvocation("CreateProvisionDirStarted", []interface{}{})
 	fake.createProvisionDirStartedMutex.Unlock()
-	if fake.CreateProvisionDirStartedStub != nil {
+	if stub != nil {
 		fake.CreateProvisionDirStartedStub()
 	}
 }
@@ -137,9 +684,10 @@ func (fake *FakeConstructMessenger) CreateProvisionDirSucceeded() {
 	fake.createProvisionDirSucceededMutex.Lock()
 	fake.createProvisionDirSucceededArgsForCall = append(fake.createProvisionDirSucceededArgsForCall, struct {
 	}{})
+	stub := fake.CreateProvisionDirSucceededStub
 	fake.recordInvocation("CreateProvisionDirSucceeded", []interface{}{})
 	fake.createProvisionDirSucceededMutex.Unlock()
-	if fake.CreateProvisionDirSucceededStub != nil {
+	if stub != nil {
 		fake.CreateProvisionDirSucceededStub()
 	}
 }
@@ -156,13 +704,118 @@ func (fake *FakeConstructMessenger) CreateProvisionDirSucceededCalls(stub func()
 	fake.CreateProvisionDirSucceededStub = stub
 }
 
+func (fake *FakeConstructMessenger) CreateSnapshotStarted(arg1 string) {
+	fake.createSnapshotStartedMutex.Lock()
+	fake.createSnapshotStartedArgsForCall = append(fake.createSnapshotStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CreateSnapshotStartedStub
+	fake.recordInvocation("CreateSnapshotStarted", []interface{}{arg1})
+	fake.createSnapshotStartedMutex.Unlock()
+	if stub != nil {
+		fake.CreateSnapshotStartedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) CreateSnapshotStartedCallCount() int {
+	fake.createSnapshotStartedMutex.RLock()
+	defer fake.createSnapshotStartedMutex.RUnlock()
+	return len(fake.createSnapshotStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CreateSnapshotStartedCalls(stub func(string)) {
+	fake.createSnapshotStartedMutex.Lock()
+	defer fake.createSnapshotStartedMutex.Unlock()
+	fake.CreateSnapshotStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) CreateSnapshotStartedArgsForCall(i int) string {
+	fake.createSnapshotStartedMutex.RLock()
+	defer fake.createSnapshotStartedMutex.RUnlock()
+	argsForCall := fake.createSnapshotStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) CreateSnapshotSucceeded() {
+	fake.createSnapshotSucceededMutex.Lock()
+	fake.createSnapshotSucceededArgsForCall = append(fake.createSnapshotSucceededArgsForCall, struct {
+	}{})
+	stub := fake.CreateSnapshotSucceededStub
+	fake.recordInvocation("CreateSnapshotSucceeded", []interface{}{})
+	fake.createSnapshotSucceededMutex.Unlock()
+	if stub != nil {
+		fake.CreateSnapshotSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) CreateSnapshotSucceededCallCount() int {
+	fake.createSnapshotSucceededMutex.RLock()
+	defer fake.createSnapshotSucceededMutex.RUnlock()
+	return len(fake.createSnapshotSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) CreateSnapshotSucceededCalls(stub func()) {
+	fake.createSnapshotSucceededMutex.Lock()
+	defer fake.createSnapshotSucceededMutex.Unlock()
+	fake.CreateSnapshotSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) DryRunStarted() {
+	fake.dryRunStartedMutex.Lock()
+	fake.dryRunStartedArgsForCall = append(fake.dryRunStartedArgsForCall, struct {
+	}{})
+	stub := fake.DryRunStartedStub
+	fake.recordInvocation("DryRunStarted", []interface{}{})
+	fake.dryRunStartedMutex.Unlock()
+	if stub != nil {
+		fake.DryRunStartedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) DryRunStartedCallCount() int {
+	fake.dryRunStartedMutex.RLock()
+	defer fake.dryRunStartedMutex.RUnlock()
+	return len(fake.dryRunStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) DryRunStartedCalls(stub func()) {
+	fake.dryRunStartedMutex.Lock()
+	defer fake.dryRunStartedMutex.Unlock()
+	fake.DryRunStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) DryRunSucceeded() {
+	fake.dryRunSucceededMutex.Lock()
+	fake.dryRunSucceededArgsForCall = append(fake.dryRunSucceededArgsForCall, struct {
+	}{})
+	stub := fake.DryRunSucceededStub
+	fake.recordInvocation("DryRunSucceeded", []interface{}{})
+	fake.dryRunSucceededMutex.Unlock()
+	if stub != nil {
+		fake.DryRunSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) DryRunSucceededCallCount() int {
+	fake.dryRunSucceededMutex.RLock()
+	defer fake.dryRunSucceededMutex.RUnlock()
+	return len(fake.dryRunSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) DryRunSucceededCalls(stub func()) {
+	fake.dryRunSucceededMutex.Lock()
+	defer fake.dryRunSucceededMutex.Unlock()
+	fake.DryRunSucceededStub = stub
+}
+
 func (fake *FakeConstructMessenger) EnableWinRMStarted() {
 	fake.enableWinRMStartedMutex.Lock()
 	fake.enableWinRMStartedArgsForCall = append(fake.enableWinRMStartedArgsForCall, struct {
 	}{})
+	stub := fake.EnableWinRMStartedStub
 	fake.recordInvocation("EnableWinRMStarted", []interface{}{})
 	fake.enableWinRMStartedMutex.Unlock()
-	if fake.EnableWinRMStartedStub != nil {
+	if stub != nil {
 		fake.EnableWinRMStartedStub()
 	}
 }
@@ -183,9 +836,10 @@ func (fake *FakeConstructMessenger) EnableWinRMSucceeded() {
 	fake.enableWinRMSucceededMutex.Lock()
 	fake.enableWinRMSucceededArgsForCall = append(fake.enableWinRMSucceededArgsForCall, struct {
 	}{})
+	stub := fake.EnableWinRMSucceededStub
 	fake.recordInvocation("EnableWinRMSucceeded", []interface{}{})
 	fake.enableWinRMSucceededMutex.Unlock()
-	if fake.EnableWinRMSucceededStub != nil {
+	if stub != nil {
 		fake.EnableWinRMSucceededStub()
 	}
 }
@@ -206,9 +860,10 @@ func (fake *FakeConstructMessenger) ExecutePostRebootScriptStarted() {
 	fake.executePostRebootScriptStartedMutex.Lock()
 	fake.executePostRebootScriptStartedArgsForCall = append(fake.executePostRebootScriptStartedArgsForCall, struct {
 	}{})
+	stub := fake.ExecutePostRebootScriptStartedStub
 	fake.recordInvocation("ExecutePostRebootScriptStarted", []interface{}{})
 	fake.executePostRebootScriptStartedMutex.Unlock()
-	if fake.ExecutePostRebootScriptStartedStub != nil {
+	if stub != nil {
 		fake.ExecutePostRebootScriptStartedStub()
 	}
 }
@@ -229,9 +884,10 @@ func (fake *FakeConstructMessenger) ExecutePostRebootScriptSucceeded() {
 	fake.executePostRebootScriptSucceededMutex.Lock()
 	fake.executePostRebootScriptSucceededArgsForCall = append(fake.executePostRebootScriptSucceededArgsForCall, struct {
 	}{})
+	stub := fake.ExecutePostRebootScriptSucceededStub
 	fake.recordInvocation("ExecutePostRebootScriptSucceeded", []interface{}{})
 	fake.executePostRebootScriptSucceededMutex.Unlock()
-	if fake.ExecutePostRebootScriptSucceededStub != nil {
+	if stub != nil {
 		fake.ExecutePostRebootScriptSucceededStub()
 	}
 }
@@ -253,9 +909,10 @@ func (fake *FakeConstructMessenger) ExecutePostRebootWarning(arg1 string) {
 	fake.executePostRebootWarningArgsForCall = append(fake.executePostRebootWarningArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.ExecutePostRebootWarningStub
 	fake.recordInvocation("ExecutePostRebootWarning", []interface{}{arg1})
 	fake.executePostRebootWarningMutex.Unlock()
-	if fake.ExecutePostRebootWarningStub != nil {
+	if stub != nil {
 		fake.ExecutePostRebootWarningStub(arg1)
 	}
 }
@@ -283,9 +940,10 @@ func (fake *FakeConstructMessenger) ExecuteSetupScriptStarted() {
 	fake.executeSetupScriptStartedMutex.Lock()
 	fake.executeSetupScriptStartedArgsForCall = append(fake.executeSetupScriptStartedArgsForCall, struct {
 	}{})
+	stub := fake.ExecuteSetupScriptStartedStub
 	fake.recordInvocation("ExecuteSetupScriptStarted", []interface{}{})
 	fake.executeSetupScriptStartedMutex.Unlock()
-	if fake.ExecuteSetupScriptStartedStub != nil {
+	if stub != nil {
 		fake.ExecuteSetupScriptStartedStub()
 	}
 }
@@ -306,9 +964,10 @@ func (fake *FakeConstructMessenger) ExecuteSetupScriptSucceeded() {
 	fake.executeSetupScriptSucceededMutex.Lock()
 	fake.executeSetupScriptSucceededArgsForCall = append(fake.executeSetupScriptSucceededArgsForCall, struct {
 	}{})
+	stub := fake.ExecuteSetupScriptSucceededStub
 	fake.recordInvocation("ExecuteSetupScriptSucceeded", []interface{}{})
 	fake.executeSetupScriptSucceededMutex.Unlock()
-	if fake.ExecuteSetupScriptSucceededStub != nil {
+	if stub != nil {
 		fake.ExecuteSetupScriptSucceededStub()
 	}
 }
@@ -325,13 +984,62 @@ func (fake *FakeConstructMessenger) ExecuteSetupScriptSucceededCalls(stub func()
 	fake.ExecuteSetupScriptSucceededStub = stub
 }
 
+func (fake *FakeConstructMessenger) ExecuteSysprepScriptStarted() {
+	fake.executeSysprepScriptStartedMutex.Lock()
+	fake.executeSysprepScriptStartedArgsForCall = append(fake.executeSysprepScriptStartedArgsForCall, struct {
+	}{})
+	stub := fake.ExecuteSysprepScriptStartedStub
+	fake.recordInvocation("ExecuteSysprepScriptStarted", []interface{}{})
+	fake.executeSysprepScriptStartedMutex.Unlock()
+	if stub != nil {
+		fake.ExecuteSysprepScriptStartedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) ExecuteSysprepScriptStartedCallCount() int {
+	fake.executeSysprepScriptStartedMutex.RLock()
+	defer fake.executeSysprepScriptStartedMutex.RUnlock()
+	return len(fake.executeSysprepScriptStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) ExecuteSysprepScriptStartedCalls(stub func()) {
+	fake.executeSysprepScriptStartedMutex.Lock()
+	defer fake.executeSysprepScriptStartedMutex.Unlock()
+	fake.ExecuteSysprepScriptStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) ExecuteSysprepScriptSucceeded() {
+	fake.executeSysprepScriptSucceededMutex.Lock()
+	fake.executeSysprepScriptSucceededArgsForCall = append(fake.executeSysprepScriptSucceededArgsForCall, struct {
+	}{})
+	stub := fake.ExecuteSysprepScriptSucceededStub
+	fake.recordInvocation("ExecuteSysprepScriptSucceeded", []interface{}{})
+	fake.executeSysprepScriptSucceededMutex.Unlock()
+	if stub != nil {
+		fake.ExecuteSysprepScriptSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) ExecuteSysprepScriptSucceededCallCount() int {
+	fake.executeSysprepScriptSucceededMutex.RLock()
+	defer fake.executeSysprepScriptSucceededMutex.RUnlock()
+	return len(fake.executeSysprepScriptSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) ExecuteSysprepScriptSucceededCalls(stub func()) {
+	fake.executeSysprepScriptSucceededMutex.Lock()
+	defer fake.executeSysprepScriptSucceededMutex.Unlock()
+	fake.ExecuteSysprepScriptSucceededStub = stub
+}
+
 func (fake *FakeConstructMessenger) ExtractArtifactsStarted() {
 	fake.extractArtifactsStartedMutex.Lock()
 	fake.extractArtifactsStartedArgsForCall = append(fake.extractArtifactsStartedArgsForCall, struct {
 	}{})
+	stub := fake.ExtractArtifactsStartedStub
 	fake.recordInvocation("ExtractArtifactsStarted", []interface{}{})
 	fake.extractArtifactsStartedMutex.Unlock()
-	if fake.ExtractArtifactsStartedStub != nil {
+	if stub != nil {
 		fake.ExtractArtifactsStartedStub()
 	}
 }
@@ -352,9 +1060,10 @@ func (fake *FakeConstructMessenger) ExtractArtifactsSucceeded() {
 	fake.extractArtifactsSucceededMutex.Lock()
 	fake.extractArtifactsSucceededArgsForCall = append(fake.extractArtifactsSucceededArgsForCall, struct {
 	}{})
+	stub := fake.ExtractArtifactsSucceededStub
 	fake.recordInvocation("ExtractArtifactsSucceeded", []interface{}{})
 	fake.extractArtifactsSucceededMutex.Unlock()
-	if fake.ExtractArtifactsSucceededStub != nil {
+	if stub != nil {
 		fake.ExtractArtifactsSucceededStub()
 	}
 }
@@ -371,13 +1080,78 @@ func (fake *FakeConstructMessenger) ExtractArtifactsSucceededCalls(stub func())
 	fake.ExtractArtifactsSucceededStub = stub
 }
 
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesStarted(arg1 int) {
+	fake.installWindowsUpdatesStartedMutex.Lock()
+	fake.installWindowsUpdatesStartedArgsForCall = append(fake.installWindowsUpdatesStartedArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.InstallWindowsUpdatesStartedStub
+	fake.recordInvocation("InstallWindowsUpdatesStarted", []interface{}{arg1})
+	fake.installWindowsUpdatesStartedMutex.Unlock()
+	if stub != nil {
+		fake.InstallWindowsUpdatesStartedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesStartedCallCount() int {
+	fake.installWindowsUpdatesStartedMutex.RLock()
+	defer fake.installWindowsUpdatesStartedMutex.RUnlock()
+	return len(fake.installWindowsUpdatesStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesStartedCalls(stub func(int)) {
+	fake.installWindowsUpdatesStartedMutex.Lock()
+	defer fake.installWindowsUpdatesStartedMutex.Unlock()
+	fake.InstallWindowsUpdatesStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesStartedArgsForCall(i int) int {
+	fake.installWindowsUpdatesStartedMutex.RLock()
+	defer fake.installWindowsUpdatesStartedMutex.RUnlock()
+	argsForCall := fake.installWindowsUpdatesStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesSucceeded(arg1 int) {
+	fake.installWindowsUpdatesSucceededMutex.Lock()
+	fake.installWindowsUpdatesSucceededArgsForCall = append(fake.installWindowsUpdatesSucceededArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.InstallWindowsUpdatesSucceededStub
+	fake.recordInvocation("InstallWindowsUpdatesSucceeded", []interface{}{arg1})
+	fake.installWindowsUpdatesSucceededMutex.Unlock()
+	if stub != nil {
+		fake.InstallWindowsUpdatesSucceededStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesSucceededCallCount() int {
+	fake.installWindowsUpdatesSucceededMutex.RLock()
+	defer fake.installWindowsUpdatesSucceededMutex.RUnlock()
+	return len(fake.installWindowsUpdatesSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesSucceededCalls(stub func(int)) {
+	fake.installWindowsUpdatesSucceededMutex.Lock()
+	defer fake.installWindowsUpdatesSucceededMutex.Unlock()
+	fake.InstallWindowsUpdatesSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) InstallWindowsUpdatesSucceededArgsForCall(i int) int {
+	fake.installWindowsUpdatesSucceededMutex.RLock()
+	defer fake.installWindowsUpdatesSucceededMutex.RUnlock()
+	argsForCall := fake.installWindowsUpdatesSucceededArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeConstructMessenger) LogOutUsersStarted() {
 	fake.logOutUsersStartedMutex.Lock()
 	fake.logOutUsersStartedArgsForCall = append(fake.logOutUsersStartedArgsForCall, struct {
 	}{})
+	stub := fake.LogOutUsersStartedStub
 	fake.recordInvocation("LogOutUsersStarted", []interface{}{})
 	fake.logOutUsersStartedMutex.Unlock()
-	if fake.LogOutUsersStartedStub != nil {
+	if stub != nil {
 		fake.LogOutUsersStartedStub()
 	}
 }
@@ -398,9 +1172,10 @@ func (fake *FakeConstructMessenger) LogOutUsersSucceeded() {
 	fake.logOutUsersSucceededMutex.Lock()
 	fake.logOutUsersSucceededArgsForCall = append(fake.logOutUsersSucceededArgsForCall, struct {
 	}{})
+	stub := fake.LogOutUsersSucceededStub
 	fake.recordInvocation("LogOutUsersSucceeded", []interface{}{})
 	fake.logOutUsersSucceededMutex.Unlock()
-	if fake.LogOutUsersSucceededStub != nil {
+	if stub != nil {
 		fake.LogOutUsersSucceededStub()
 	}
 }
@@ -417,13 +1192,71 @@ func (fake *FakeConstructMessenger) LogOutUsersSucceededCalls(stub func()) {
 	fake.LogOutUsersSucceededStub = stub
 }
 
+func (fake *FakeConstructMessenger) NoCheckpointFound() {
+	fake.noCheckpointFoundMutex.Lock()
+	fake.noCheckpointFoundArgsForCall = append(fake.noCheckpointFoundArgsForCall, struct {
+	}{})
+	stub := fake.NoCheckpointFoundStub
+	fake.recordInvocation("NoCheckpointFound", []interface{}{})
+	fake.noCheckpointFoundMutex.Unlock()
+	if stub != nil {
+		fake.NoCheckpointFoundStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) NoCheckpointFoundCallCount() int {
+	fake.noCheckpointFoundMutex.RLock()
+	defer fake.noCheckpointFoundMutex.RUnlock()
+	return len(fake.noCheckpointFoundArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) NoCheckpointFoundCalls(stub func()) {
+	fake.noCheckpointFoundMutex.Lock()
+	defer fake.noCheckpointFoundMutex.Unlock()
+	fake.NoCheckpointFoundStub = stub
+}
+
+func (fake *FakeConstructMessenger) PlannedOperation(arg1 string, arg2 string) {
+	fake.plannedOperationMutex.Lock()
+	fake.plannedOperationArgsForCall = append(fake.plannedOperationArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.PlannedOperationStub
+	fake.recordInvocation("PlannedOperation", []interface{}{arg1, arg2})
+	fake.plannedOperationMutex.Unlock()
+	if stub != nil {
+		fake.PlannedOperationStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeConstructMessenger) PlannedOperationCallCount() int {
+	fake.plannedOperationMutex.RLock()
+	defer fake.plannedOperationMutex.RUnlock()
+	return len(fake.plannedOperationArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) PlannedOperationCalls(stub func(string, string)) {
+	fake.plannedOperationMutex.Lock()
+	defer fake.plannedOperationMutex.Unlock()
+	fake.PlannedOperationStub = stub
+}
+
+func (fake *FakeConstructMessenger) PlannedOperationArgsForCall(i int) (string, string) {
+	fake.plannedOperationMutex.RLock()
+	defer fake.plannedOperationMutex.RUnlock()
+	argsForCall := fake.plannedOperationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeConstructMessenger) RebootHasFinished() {
 	fake.rebootHasFinishedMutex.Lock()
 	fake.rebootHasFinishedArgsForCall = append(fake.rebootHasFinishedArgsForCall, struct {
 	}{})
+	stub := fake.RebootHasFinishedStub
 	fake.recordInvocation("RebootHasFinished", []interface{}{})
 	fake.rebootHasFinishedMutex.Unlock()
-	if fake.RebootHasFinishedStub != nil {
+	if stub != nil {
 		fake.RebootHasFinishedStub()
 	}
 }
@@ -444,9 +1277,10 @@ func (fake *FakeConstructMessenger) RebootHasStarted() {
 	fake.rebootHasStartedMutex.Lock()
 	fake.rebootHasStartedArgsForCall = append(fake.rebootHasStartedArgsForCall, struct {
 	}{})
+	stub := fake.RebootHasStartedStub
 	fake.recordInvocation("RebootHasStarted", []interface{}{})
 	fake.rebootHasStartedMutex.Unlock()
-	if fake.RebootHasStartedStub != nil {
+	if stub != nil {
 		fake.RebootHasStartedStub()
 	}
 }
@@ -463,13 +1297,351 @@ func (fake *FakeConstructMessenger) RebootHasStartedCalls(stub func()) {
 	fake.RebootHasStartedStub = stub
 }
 
+func (fake *FakeConstructMessenger) RecordHardeningProfileStarted(arg1 string) {
+	fake.recordHardeningProfileStartedMutex.Lock()
+	fake.recordHardeningProfileStartedArgsForCall = append(fake.recordHardeningProfileStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RecordHardeningProfileStartedStub
+	fake.recordInvocation("RecordHardeningProfileStarted", []interface{}{arg1})
+	fake.recordHardeningProfileStartedMutex.Unlock()
+	if stub != nil {
+		fake.RecordHardeningProfileStartedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) RecordHardeningProfileStartedCallCount() int {
+	fake.recordHardeningProfileStartedMutex.RLock()
+	defer fake.recordHardeningProfileStartedMutex.RUnlock()
+	return len(fake.recordHardeningProfileStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RecordHardeningProfileStartedCalls(stub func(string)) {
+	fake.recordHardeningProfileStartedMutex.Lock()
+	defer fake.recordHardeningProfileStartedMutex.Unlock()
+	fake.RecordHardeningProfileStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) RecordHardeningProfileStartedArgsForCall(i int) string {
+	fake.recordHardeningProfileStartedMutex.RLock()
+	defer fake.recordHardeningProfileStartedMutex.RUnlock()
+	argsForCall := fake.recordHardeningProfileStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) RecordHardeningProfileSucceeded() {
+	fake.recordHardeningProfileSucceededMutex.Lock()
+	fake.recordHardeningProfileSucceededArgsForCall = append(fake.recordHardeningProfileSucceededArgsForCall, struct {
+	}{})
+	stub := fake.RecordHardeningProfileSucceededStub
+	fake.recordInvocation("RecordHardeningProfileSucceeded", []interface{}{})
+	fake.recordHardeningProfileSucceededMutex.Unlock()
+	if stub != nil {
+		fake.RecordHardeningProfileSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) RecordHardeningProfileSucceededCallCount() int {
+	fake.recordHardeningProfileSucceededMutex.RLock()
+	defer fake.recordHardeningProfileSucceededMutex.RUnlock()
+	return len(fake.recordHardeningProfileSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RecordHardeningProfileSucceededCalls(stub func()) {
+	fake.recordHardeningProfileSucceededMutex.Lock()
+	defer fake.recordHardeningProfileSucceededMutex.Unlock()
+	fake.RecordHardeningProfileSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) RemoveSnapshotStarted(arg1 string) {
+	fake.removeSnapshotStartedMutex.Lock()
+	fake.removeSnapshotStartedArgsForCall = append(fake.removeSnapshotStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RemoveSnapshotStartedStub
+	fake.recordInvocation("RemoveSnapshotStarted", []interface{}{arg1})
+	fake.removeSnapshotStartedMutex.Unlock()
+	if stub != nil {
+		fake.RemoveSnapshotStartedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) RemoveSnapshotStartedCallCount() int {
+	fake.removeSnapshotStartedMutex.RLock()
+	defer fake.removeSnapshotStartedMutex.RUnlock()
+	return len(fake.removeSnapshotStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RemoveSnapshotStartedCalls(stub func(string)) {
+	fake.removeSnapshotStartedMutex.Lock()
+	defer fake.removeSnapshotStartedMutex.Unlock()
+	fake.RemoveSnapshotStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) RemoveSnapshotStartedArgsForCall(i int) string {
+	fake.removeSnapshotStartedMutex.RLock()
+	defer fake.removeSnapshotStartedMutex.RUnlock()
+	argsForCall := fake.removeSnapshotStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) RemoveSnapshotSucceeded() {
+	fake.removeSnapshotSucceededMutex.Lock()
+	fake.removeSnapshotSucceededArgsForCall = append(fake.removeSnapshotSucceededArgsForCall, struct {
+	}{})
+	stub := fake.RemoveSnapshotSucceededStub
+	fake.recordInvocation("RemoveSnapshotSucceeded", []interface{}{})
+	fake.removeSnapshotSucceededMutex.Unlock()
+	if stub != nil {
+		fake.RemoveSnapshotSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) RemoveSnapshotSucceededCallCount() int {
+	fake.removeSnapshotSucceededMutex.RLock()
+	defer fake.removeSnapshotSucceededMutex.RUnlock()
+	return len(fake.removeSnapshotSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RemoveSnapshotSucceededCalls(stub func()) {
+	fake.removeSnapshotSucceededMutex.Lock()
+	defer fake.removeSnapshotSucceededMutex.Unlock()
+	fake.RemoveSnapshotSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) ResumingFromCheckpoint(arg1 string) {
+	fake.resumingFromCheckpointMutex.Lock()
+	fake.resumingFromCheckpointArgsForCall = append(fake.resumingFromCheckpointArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ResumingFromCheckpointStub
+	fake.recordInvocation("ResumingFromCheckpoint", []interface{}{arg1})
+	fake.resumingFromCheckpointMutex.Unlock()
+	if stub != nil {
+		fake.ResumingFromCheckpointStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) ResumingFromCheckpointCallCount() int {
+	fake.resumingFromCheckpointMutex.RLock()
+	defer fake.resumingFromCheckpointMutex.RUnlock()
+	return len(fake.resumingFromCheckpointArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) ResumingFromCheckpointCalls(stub func(string)) {
+	fake.resumingFromCheckpointMutex.Lock()
+	defer fake.resumingFromCheckpointMutex.Unlock()
+	fake.ResumingFromCheckpointStub = stub
+}
+
+func (fake *FakeConstructMessenger) ResumingFromCheckpointArgsForCall(i int) string {
+	fake.resumingFromCheckpointMutex.RLock()
+	defer fake.resumingFromCheckpointMutex.RUnlock()
+	argsForCall := fake.resumingFromCheckpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotFailed(arg1 error) {
+	fake.revertSnapshotFailedMutex.Lock()
+	fake.revertSnapshotFailedArgsForCall = append(fake.revertSnapshotFailedArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.RevertSnapshotFailedStub
+	fake.recordInvocation("RevertSnapshotFailed", []interface{}{arg1})
+	fake.revertSnapshotFailedMutex.Unlock()
+	if stub != nil {
+		fake.RevertSnapshotFailedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotFailedCallCount() int {
+	fake.revertSnapshotFailedMutex.RLock()
+	defer fake.revertSnapshotFailedMutex.RUnlock()
+	return len(fake.revertSnapshotFailedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotFailedCalls(stub func(error)) {
+	fake.revertSnapshotFailedMutex.Lock()
+	defer fake.revertSnapshotFailedMutex.Unlock()
+	fake.RevertSnapshotFailedStub = stub
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotFailedArgsForCall(i int) error {
+	fake.revertSnapshotFailedMutex.RLock()
+	defer fake.revertSnapshotFailedMutex.RUnlock()
+	argsForCall := fake.revertSnapshotFailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotStarted(arg1 string) {
+	fake.revertSnapshotStartedMutex.Lock()
+	fake.revertSnapshotStartedArgsForCall = append(fake.revertSnapshotStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RevertSnapshotStartedStub
+	fake.recordInvocation("RevertSnapshotStarted", []interface{}{arg1})
+	fake.revertSnapshotStartedMutex.Unlock()
+	if stub != nil {
+		fake.RevertSnapshotStartedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotStartedCallCount() int {
+	fake.revertSnapshotStartedMutex.RLock()
+	defer fake.revertSnapshotStartedMutex.RUnlock()
+	return len(fake.revertSnapshotStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotStartedCalls(stub func(string)) {
+	fake.revertSnapshotStartedMutex.Lock()
+	defer fake.revertSnapshotStartedMutex.Unlock()
+	fake.RevertSnapshotStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotStartedArgsForCall(i int) string {
+	fake.revertSnapshotStartedMutex.RLock()
+	defer fake.revertSnapshotStartedMutex.RUnlock()
+	argsForCall := fake.revertSnapshotStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotSucceeded() {
+	fake.revertSnapshotSucceededMutex.Lock()
+	fake.revertSnapshotSucceededArgsForCall = append(fake.revertSnapshotSucceededArgsForCall, struct {
+	}{})
+	stub := fake.RevertSnapshotSucceededStub
+	fake.recordInvocation("RevertSnapshotSucceeded", []interface{}{})
+	fake.revertSnapshotSucceededMutex.Unlock()
+	if stub != nil {
+		fake.RevertSnapshotSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotSucceededCallCount() int {
+	fake.revertSnapshotSucceededMutex.RLock()
+	defer fake.revertSnapshotSucceededMutex.RUnlock()
+	return len(fake.revertSnapshotSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RevertSnapshotSucceededCalls(stub func()) {
+	fake.revertSnapshotSucceededMutex.Lock()
+	defer fake.revertSnapshotSucceededMutex.Unlock()
+	fake.RevertSnapshotSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) RunHookStarted(arg1 string, arg2 string) {
+	fake.runHookStartedMutex.Lock()
+	fake.runHookStartedArgsForCall = append(fake.runHookStartedArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RunHookStartedStub
+	fake.recordInvocation("RunHookStarted", []interface{}{arg1, arg2})
+	fake.runHookStartedMutex.Unlock()
+	if stub != nil {
+		fake.RunHookStartedStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeConstructMessenger) RunHookStartedCallCount() int {
+	fake.runHookStartedMutex.RLock()
+	defer fake.runHookStartedMutex.RUnlock()
+	return len(fake.runHookStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RunHookStartedCalls(stub func(string, string)) {
+	fake.runHookStartedMutex.Lock()
+	defer fake.runHookStartedMutex.Unlock()
+	fake.RunHookStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) RunHookStartedArgsForCall(i int) (string, string) {
+	fake.runHookStartedMutex.RLock()
+	defer fake.runHookStartedMutex.RUnlock()
+	argsForCall := fake.runHookStartedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeConstructMessenger) RunHookSucceeded() {
+	fake.runHookSucceededMutex.Lock()
+	fake.runHookSucceededArgsForCall = append(fake.runHookSucceededArgsForCall, struct {
+	}{})
+	stub := fake.RunHookSucceededStub
+	fake.recordInvocation("RunHookSucceeded", []interface{}{})
+	fake.runHookSucceededMutex.Unlock()
+	if stub != nil {
+		fake.RunHookSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) RunHookSucceededCallCount() int {
+	fake.runHookSucceededMutex.RLock()
+	defer fake.runHookSucceededMutex.RUnlock()
+	return len(fake.runHookSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) RunHookSucceededCalls(stub func()) {
+	fake.runHookSucceededMutex.Lock()
+	defer fake.runHookSucceededMutex.Unlock()
+	fake.RunHookSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) SetProxyStarted() {
+	fake.setProxyStartedMutex.Lock()
+	fake.setProxyStartedArgsForCall = append(fake.setProxyStartedArgsForCall, struct {
+	}{})
+	stub := fake.SetProxyStartedStub
+	fake.recordInvocation("SetProxyStarted", []interface{}{})
+	fake.setProxyStartedMutex.Unlock()
+	if stub != nil {
+		fake.SetProxyStartedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) SetProxyStartedCallCount() int {
+	fake.setProxyStartedMutex.RLock()
+	defer fake.setProxyStartedMutex.RUnlock()
+	return len(fake.setProxyStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) SetProxyStartedCalls(stub func()) {
+	fake.setProxyStartedMutex.Lock()
+	defer fake.setProxyStartedMutex.Unlock()
+	fake.SetProxyStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) SetProxySucceeded() {
+	fake.setProxySucceededMutex.Lock()
+	fake.setProxySucceededArgsForCall = append(fake.setProxySucceededArgsForCall, struct {
+	}{})
+	stub := fake.SetProxySucceededStub
+	fake.recordInvocation("SetProxySucceeded", []interface{}{})
+	fake.setProxySucceededMutex.Unlock()
+	if stub != nil {
+		fake.SetProxySucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) SetProxySucceededCallCount() int {
+	fake.setProxySucceededMutex.RLock()
+	defer fake.setProxySucceededMutex.RUnlock()
+	return len(fake.setProxySucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) SetProxySucceededCalls(stub func()) {
+	fake.setProxySucceededMutex.Lock()
+	defer fake.setProxySucceededMutex.Unlock()
+	fake.SetProxySucceededStub = stub
+}
+
 func (fake *FakeConstructMessenger) ShutdownCompleted() {
 	fake.shutdownCompletedMutex.Lock()
 	fake.shutdownCompletedArgsForCall = append(fake.shutdownCompletedArgsForCall, struct {
 	}{})
+	stub := fake.ShutdownCompletedStub
 	fake.recordInvocation("ShutdownCompleted", []interface{}{})
 	fake.shutdownCompletedMutex.Unlock()
-	if fake.ShutdownCompletedStub != nil {
+	if stub != nil {
 		fake.ShutdownCompletedStub()
 	}
 }
@@ -490,9 +1662,10 @@ func (fake *FakeConstructMessenger) UploadArtifactsStarted() {
 	fake.uploadArtifactsStartedMutex.Lock()
 	fake.uploadArtifactsStartedArgsForCall = append(fake.uploadArtifactsStartedArgsForCall, struct {
 	}{})
+	stub := fake.UploadArtifactsStartedStub
 	fake.recordInvocation("UploadArtifactsStarted", []interface{}{})
 	fake.uploadArtifactsStartedMutex.Unlock()
-	if fake.UploadArtifactsStartedStub != nil {
+	if stub != nil {
 		fake.UploadArtifactsStartedStub()
 	}
 }
@@ -513,9 +1686,10 @@ func (fake *FakeConstructMessenger) UploadArtifactsSucceeded() {
 	fake.uploadArtifactsSucceededMutex.Lock()
 	fake.uploadArtifactsSucceededArgsForCall = append(fake.uploadArtifactsSucceededArgsForCall, struct {
 	}{})
+	stub := fake.UploadArtifactsSucceededStub
 	fake.recordInvocation("UploadArtifactsSucceeded", []interface{}{})
 	fake.uploadArtifactsSucceededMutex.Unlock()
-	if fake.UploadArtifactsSucceededStub != nil {
+	if stub != nil {
 		fake.UploadArtifactsSucceededStub()
 	}
 }
@@ -532,14 +1706,50 @@ func (fake *FakeConstructMessenger) UploadArtifactsSucceededCalls(stub func()) {
 	fake.UploadArtifactsSucceededStub = stub
 }
 
+func (fake *FakeConstructMessenger) UploadFileProgress(arg1 int64, arg2 int64, arg3 float64, arg4 time.Duration) {
+	fake.uploadFileProgressMutex.Lock()
+	fake.uploadFileProgressArgsForCall = append(fake.uploadFileProgressArgsForCall, struct {
+		arg1 int64
+		arg2 int64
+		arg3 float64
+		arg4 time.Duration
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UploadFileProgressStub
+	fake.recordInvocation("UploadFileProgress", []interface{}{arg1, arg2, arg3, arg4})
+	fake.uploadFileProgressMutex.Unlock()
+	if stub != nil {
+		fake.UploadFileProgressStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeConstructMessenger) UploadFileProgressCallCount() int {
+	fake.uploadFileProgressMutex.RLock()
+	defer fake.uploadFileProgressMutex.RUnlock()
+	return len(fake.uploadFileProgressArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) UploadFileProgressCalls(stub func(int64, int64, float64, time.Duration)) {
+	fake.uploadFileProgressMutex.Lock()
+	defer fake.uploadFileProgressMutex.Unlock()
+	fake.UploadFileProgressStub = stub
+}
+
+func (fake *FakeConstructMessenger) UploadFileProgressArgsForCall(i int) (int64, int64, float64, time.Duration) {
+	fake.uploadFileProgressMutex.RLock()
+	defer fake.uploadFileProgressMutex.RUnlock()
+	argsForCall := fake.uploadFileProgressArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
 func (fake *FakeConstructMessenger) UploadFileStarted(arg1 string) {
 	fake.uploadFileStartedMutex.Lock()
 	fake.uploadFileStartedArgsForCall = append(fake.uploadFileStartedArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.UploadFileStartedStub
 	fake.recordInvocation("UploadFileStarted", []interface{}{arg1})
 	fake.uploadFileStartedMutex.Unlock()
-	if fake.UploadFileStartedStub != nil {
+	if stub != nil {
 		fake.UploadFileStartedStub(arg1)
 	}
 }
@@ -567,9 +1777,10 @@ func (fake *FakeConstructMessenger) UploadFileSucceeded() {
 	fake.uploadFileSucceededMutex.Lock()
 	fake.uploadFileSucceededArgsForCall = append(fake.uploadFileSucceededArgsForCall, struct {
 	}{})
+	stub := fake.UploadFileSucceededStub
 	fake.recordInvocation("UploadFileSucceeded", []interface{}{})
 	fake.uploadFileSucceededMutex.Unlock()
-	if fake.UploadFileSucceededStub != nil {
+	if stub != nil {
 		fake.UploadFileSucceededStub()
 	}
 }
@@ -586,13 +1797,94 @@ func (fake *FakeConstructMessenger) UploadFileSucceededCalls(stub func()) {
 	fake.UploadFileSucceededStub = stub
 }
 
+func (fake *FakeConstructMessenger) UploadHooksStarted() {
+	fake.uploadHooksStartedMutex.Lock()
+	fake.uploadHooksStartedArgsForCall = append(fake.uploadHooksStartedArgsForCall, struct {
+	}{})
+	stub := fake.UploadHooksStartedStub
+	fake.recordInvocation("UploadHooksStarted", []interface{}{})
+	fake.uploadHooksStartedMutex.Unlock()
+	if stub != nil {
+		fake.UploadHooksStartedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) UploadHooksStartedCallCount() int {
+	fake.uploadHooksStartedMutex.RLock()
+	defer fake.uploadHooksStartedMutex.RUnlock()
+	return len(fake.uploadHooksStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) UploadHooksStartedCalls(stub func()) {
+	fake.uploadHooksStartedMutex.Lock()
+	defer fake.uploadHooksStartedMutex.Unlock()
+	fake.UploadHooksStartedStub = stub
+}
+
+func (fake *FakeConstructMessenger) UploadHooksSucceeded() {
+	fake.uploadHooksSucceededMutex.Lock()
+	fake.uploadHooksSucceededArgsForCall = append(fake.uploadHooksSucceededArgsForCall, struct {
+	}{})
+	stub := fake.UploadHooksSucceededStub
+	fake.recordInvocation("UploadHooksSucceeded", []interface{}{})
+	fake.uploadHooksSucceededMutex.Unlock()
+	if stub != nil {
+		fake.UploadHooksSucceededStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) UploadHooksSucceededCallCount() int {
+	fake.uploadHooksSucceededMutex.RLock()
+	defer fake.uploadHooksSucceededMutex.RUnlock()
+	return len(fake.uploadHooksSucceededArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) UploadHooksSucceededCalls(stub func()) {
+	fake.uploadHooksSucceededMutex.Lock()
+	defer fake.uploadHooksSucceededMutex.Unlock()
+	fake.UploadHooksSucceededStub = stub
+}
+
+func (fake *FakeConstructMessenger) UsingExistingSnapshot(arg1 string) {
+	fake.usingExistingSnapshotMutex.Lock()
+	fake.usingExistingSnapshotArgsForCall = append(fake.usingExistingSnapshotArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.UsingExistingSnapshotStub
+	fake.recordInvocation("UsingExistingSnapshot", []interface{}{arg1})
+	fake.usingExistingSnapshotMutex.Unlock()
+	if stub != nil {
+		fake.UsingExistingSnapshotStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) UsingExistingSnapshotCallCount() int {
+	fake.usingExistingSnapshotMutex.RLock()
+	defer fake.usingExistingSnapshotMutex.RUnlock()
+	return len(fake.usingExistingSnapshotArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) UsingExistingSnapshotCalls(stub func(string)) {
+	fake.usingExistingSnapshotMutex.Lock()
+	defer fake.usingExistingSnapshotMutex.Unlock()
+	fake.UsingExistingSnapshotStub = stub
+}
+
+func (fake *FakeConstructMessenger) UsingExistingSnapshotArgsForCall(i int) string {
+	fake.usingExistingSnapshotMutex.RLock()
+	defer fake.usingExistingSnapshotMutex.RUnlock()
+	argsForCall := fake.usingExistingSnapshotArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeConstructMessenger) ValidateVMConnectionStarted() {
 	fake.validateVMConnectionStartedMutex.Lock()
 	fake.validateVMConnectionStartedArgsForCall = append(fake.validateVMConnectionStartedArgsForCall, struct {
 	}{})
+	stub := fake.ValidateVMConnectionStartedStub
 	fake.recordInvocation("ValidateVMConnectionStarted", []interface{}{})
 	fake.validateVMConnectionStartedMutex.Unlock()
-	if fake.ValidateVMConnectionStartedStub != nil {
+	if stub != nil {
 		fake.ValidateVMConnectionStartedStub()
 	}
 }
@@ -613,9 +1905,10 @@ func (fake *FakeConstructMessenger) ValidateVMConnectionSucceeded() {
 	fake.validateVMConnectionSucceededMutex.Lock()
 	fake.validateVMConnectionSucceededArgsForCall = append(fake.validateVMConnectionSucceededArgsForCall, struct {
 	}{})
+	stub := fake.ValidateVMConnectionSucceededStub
 	fake.recordInvocation("ValidateVMConnectionSucceeded", []interface{}{})
 	fake.validateVMConnectionSucceededMutex.Unlock()
-	if fake.ValidateVMConnectionSucceededStub != nil {
+	if stub != nil {
 		fake.ValidateVMConnectionSucceededStub()
 	}
 }
@@ -636,9 +1929,10 @@ func (fake *FakeConstructMessenger) WaitingForShutdown() {
 	fake.waitingForShutdownMutex.Lock()
 	fake.waitingForShutdownArgsForCall = append(fake.waitingForShutdownArgsForCall, struct {
 	}{})
+	stub := fake.WaitingForShutdownStub
 	fake.recordInvocation("WaitingForShutdown", []interface{}{})
 	fake.waitingForShutdownMutex.Unlock()
-	if fake.WaitingForShutdownStub != nil {
+	if stub != nil {
 		fake.WaitingForShutdownStub()
 	}
 }
@@ -659,9 +1953,10 @@ func (fake *FakeConstructMessenger) WinRMDisconnectedForReboot() {
 	fake.winRMDisconnectedForRebootMutex.Lock()
 	fake.winRMDisconnectedForRebootArgsForCall = append(fake.winRMDisconnectedForRebootArgsForCall, struct {
 	}{})
+	stub := fake.WinRMDisconnectedForRebootStub
 	fake.recordInvocation("WinRMDisconnectedForReboot", []interface{}{})
 	fake.winRMDisconnectedForRebootMutex.Unlock()
-	if fake.WinRMDisconnectedForRebootStub != nil {
+	if stub != nil {
 		fake.WinRMDisconnectedForRebootStub()
 	}
 }
@@ -678,13 +1973,158 @@ func (fake *FakeConstructMessenger) WinRMDisconnectedForRebootCalls(stub func())
 	fake.WinRMDisconnectedForRebootStub = stub
 }
 
+func (fake *FakeConstructMessenger) WindowsUpdateInstalled(arg1 string, arg2 string) {
+	fake.windowsUpdateInstalledMutex.Lock()
+	fake.windowsUpdateInstalledArgsForCall = append(fake.windowsUpdateInstalledArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.WindowsUpdateInstalledStub
+	fake.recordInvocation("WindowsUpdateInstalled", []interface{}{arg1, arg2})
+	fake.windowsUpdateInstalledMutex.Unlock()
+	if stub != nil {
+		fake.WindowsUpdateInstalledStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdateInstalledCallCount() int {
+	fake.windowsUpdateInstalledMutex.RLock()
+	defer fake.windowsUpdateInstalledMutex.RUnlock()
+	return len(fake.windowsUpdateInstalledArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdateInstalledCalls(stub func(string, string)) {
+	fake.windowsUpdateInstalledMutex.Lock()
+	defer fake.windowsUpdateInstalledMutex.Unlock()
+	fake.WindowsUpdateInstalledStub = stub
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdateInstalledArgsForCall(i int) (string, string) {
+	fake.windowsUpdateInstalledMutex.RLock()
+	defer fake.windowsUpdateInstalledMutex.RUnlock()
+	argsForCall := fake.windowsUpdateInstalledArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesIterationLimitReached(arg1 int) {
+	fake.windowsUpdatesIterationLimitReachedMutex.Lock()
+	fake.windowsUpdatesIterationLimitReachedArgsForCall = append(fake.windowsUpdatesIterationLimitReachedArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.WindowsUpdatesIterationLimitReachedStub
+	fake.recordInvocation("WindowsUpdatesIterationLimitReached", []interface{}{arg1})
+	fake.windowsUpdatesIterationLimitReachedMutex.Unlock()
+	if stub != nil {
+		fake.WindowsUpdatesIterationLimitReachedStub(arg1)
+	}
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesIterationLimitReachedCallCount() int {
+	fake.windowsUpdatesIterationLimitReachedMutex.RLock()
+	defer fake.windowsUpdatesIterationLimitReachedMutex.RUnlock()
+	return len(fake.windowsUpdatesIterationLimitReachedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesIterationLimitReachedCalls(stub func(int)) {
+	fake.windowsUpdatesIterationLimitReachedMutex.Lock()
+	defer fake.windowsUpdatesIterationLimitReachedMutex.Unlock()
+	fake.WindowsUpdatesIterationLimitReachedStub = stub
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesIterationLimitReachedArgsForCall(i int) int {
+	fake.windowsUpdatesIterationLimitReachedMutex.RLock()
+	defer fake.windowsUpdatesIterationLimitReachedMutex.RUnlock()
+	argsForCall := fake.windowsUpdatesIterationLimitReachedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesRebootFinished() {
+	fake.windowsUpdatesRebootFinishedMutex.Lock()
+	fake.windowsUpdatesRebootFinishedArgsForCall = append(fake.windowsUpdatesRebootFinishedArgsForCall, struct {
+	}{})
+	stub := fake.WindowsUpdatesRebootFinishedStub
+	fake.recordInvocation("WindowsUpdatesRebootFinished", []interface{}{})
+	fake.windowsUpdatesRebootFinishedMutex.Unlock()
+	if stub != nil {
+		fake.WindowsUpdatesRebootFinishedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesRebootFinishedCallCount() int {
+	fake.windowsUpdatesRebootFinishedMutex.RLock()
+	defer fake.windowsUpdatesRebootFinishedMutex.RUnlock()
+	return len(fake.windowsUpdatesRebootFinishedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesRebootFinishedCalls(stub func()) {
+	fake.windowsUpdatesRebootFinishedMutex.Lock()
+	defer fake.windowsUpdatesRebootFinishedMutex.Unlock()
+	fake.WindowsUpdatesRebootFinishedStub = stub
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesRebootStarted() {
+	fake.windowsUpdatesRebootStartedMutex.Lock()
+	fake.windowsUpdatesRebootStartedArgsForCall = append(fake.windowsUpdatesRebootStartedArgsForCall, struct {
+	}{})
+	stub := fake.WindowsUpdatesRebootStartedStub
+	fake.recordInvocation("WindowsUpdatesRebootStarted", []interface{}{})
+	fake.windowsUpdatesRebootStartedMutex.Unlock()
+	if stub != nil {
+		fake.WindowsUpdatesRebootStartedStub()
+	}
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesRebootStartedCallCount() int {
+	fake.windowsUpdatesRebootStartedMutex.RLock()
+	defer fake.windowsUpdatesRebootStartedMutex.RUnlock()
+	return len(fake.windowsUpdatesRebootStartedArgsForCall)
+}
+
+func (fake *FakeConstructMessenger) WindowsUpdatesRebootStartedCalls(stub func()) {
+	fake.windowsUpdatesRebootStartedMutex.Lock()
+	defer fake.windowsUpdatesRebootStartedMutex.Unlock()
+	fake.WindowsUpdatesRebootStartedStub = stub
+}
+
 func (fake *FakeConstructMessenger) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.artifactTransportFailedMutex.RLock()
+	defer fake.artifactTransportFailedMutex.RUnlock()
+	fake.checkpointNotConfirmedMutex.RLock()
+	defer fake.checkpointNotConfirmedMutex.RUnlock()
+	fake.checkpointsDisabledMutex.RLock()
+	defer fake.checkpointsDisabledMutex.RUnlock()
+	fake.clearProxyStartedMutex.RLock()
+	defer fake.clearProxyStartedMutex.RUnlock()
+	fake.clearProxySucceededMutex.RLock()
+	defer fake.clearProxySucceededMutex.RUnlock()
+	fake.cloneIPDiscoveredMutex.RLock()
+	defer fake.cloneIPDiscoveredMutex.RUnlock()
+	fake.cloneVMStartedMutex.RLock()
+	defer fake.cloneVMStartedMutex.RUnlock()
+	fake.cloneVMSucceededMutex.RLock()
+	defer fake.cloneVMSucceededMutex.RUnlock()
+	fake.collectDiagnosticsFailedMutex.RLock()
+	defer fake.collectDiagnosticsFailedMutex.RUnlock()
+	fake.collectDiagnosticsStartedMutex.RLock()
+	defer fake.collectDiagnosticsStartedMutex.RUnlock()
+	fake.collectDiagnosticsSucceededMutex.RLock()
+	defer fake.collectDiagnosticsSucceededMutex.RUnlock()
+	fake.constructInterruptedMutex.RLock()
+	defer fake.constructInterruptedMutex.RUnlock()
 	fake.createProvisionDirStartedMutex.RLock()
 	defer fake.createProvisionDirStartedMutex.RUnlock()
 	fake.createProvisionDirSucceededMutex.RLock()
 	defer fake.createProvisionDirSucceededMutex.RUnlock()
+	fake.createSnapshotStartedMutex.RLock()
+	defer fake.createSnapshotStartedMutex.RUnlock()
+	fake.createSnapshotSucceededMutex.RLock()
+	defer fake.createSnapshotSucceededMutex.RUnlock()
+	fake.dryRunStartedMutex.RLock()
+	defer fake.dryRunStartedMutex.RUnlock()
+	fake.dryRunSucceededMutex.RLock()
+	defer fake.dryRunSucceededMutex.RUnlock()
 	fake.enableWinRMStartedMutex.RLock()
 	defer fake.enableWinRMStartedMutex.RUnlock()
 	fake.enableWinRMSucceededMutex.RLock()
@@ -699,28 +2139,72 @@ func (fake *FakeConstructMessenger) Invocations() map[string][][]interface{} {
 	defer fake.executeSetupScriptStartedMutex.RUnlock()
 	fake.executeSetupScriptSucceededMutex.RLock()
 	defer fake.executeSetupScriptSucceededMutex.RUnlock()
+	fake.executeSysprepScriptStartedMutex.RLock()
+	defer fake.executeSysprepScriptStartedMutex.RUnlock()
+	fake.executeSysprepScriptSucceededMutex.RLock()
+	defer fake.executeSysprepScriptSucceededMutex.RUnlock()
 	fake.extractArtifactsStartedMutex.RLock()
 	defer fake.extractArtifactsStartedMutex.RUnlock()
 	fake.extractArtifactsSucceededMutex.RLock()
 	defer fake.extractArtifactsSucceededMutex.RUnlock()
+	fake.installWindowsUpdatesStartedMutex.RLock()
+	defer fake.installWindowsUpdatesStartedMutex.RUnlock()
+	fake.installWindowsUpdatesSucceededMutex.RLock()
+	defer fake.installWindowsUpdatesSucceededMutex.RUnlock()
 	fake.logOutUsersStartedMutex.RLock()
 	defer fake.logOutUsersStartedMutex.RUnlock()
 	fake.logOutUsersSucceededMutex.RLock()
 	defer fake.logOutUsersSucceededMutex.RUnlock()
+	fake.noCheckpointFoundMutex.RLock()
+	defer fake.noCheckpointFoundMutex.RUnlock()
+	fake.plannedOperationMutex.RLock()
+	defer fake.plannedOperationMutex.RUnlock()
 	fake.rebootHasFinishedMutex.RLock()
 	defer fake.rebootHasFinishedMutex.RUnlock()
 	fake.rebootHasStartedMutex.RLock()
 	defer fake.rebootHasStartedMutex.RUnlock()
+	fake.recordHardeningProfileStartedMutex.RLock()
+	defer fake.recordHardeningProfileStartedMutex.RUnlock()
+	fake.recordHardeningProfileSucceededMutex.RLock()
+	defer fake.recordHardeningProfileSucceededMutex.RUnlock()
+	fake.removeSnapshotStartedMutex.RLock()
+	defer fake.removeSnapshotStartedMutex.RUnlock()
+	fake.removeSnapshotSucceededMutex.RLock()
+	defer fake.removeSnapshotSucceededMutex.RUnlock()
+	fake.resumingFromCheckpointMutex.RLock()
+	defer fake.resumingFromCheckpointMutex.RUnlock()
+	fake.revertSnapshotFailedMutex.RLock()
+	defer fake.revertSnapshotFailedMutex.RUnlock()
+	fake.revertSnapshotStartedMutex.RLock()
+	defer fake.revertSnapshotStartedMutex.RUnlock()
+	fake.revertSnapshotSucceededMutex.RLock()
+	defer fake.revertSnapshotSucceededMutex.RUnlock()
+	fake.runHookStartedMutex.RLock()
+	defer fake.runHookStartedMutex.RUnlock()
+	fake.runHookSucceededMutex.RLock()
+	defer fake.runHookSucceededMutex.RUnlock()
+	fake.setProxyStartedMutex.RLock()
+	defer fake.setProxyStartedMutex.RUnlock()
+	fake.setProxySucceededMutex.RLock()
+	defer fake.setProxySucceededMutex.RUnlock()
 	fake.shutdownCompletedMutex.RLock()
 	defer fake.shutdownCompletedMutex.RUnlock()
 	fake.uploadArtifactsStartedMutex.RLock()
 	defer fake.uploadArtifactsStartedMutex.RUnlock()
 	fake.uploadArtifactsSucceededMutex.RLock()
 	defer fake.uploadArtifactsSucceededMutex.RUnlock()
+	fake.uploadFileProgressMutex.RLock()
+	defer fake.uploadFileProgressMutex.RUnlock()
 	fake.uploadFileStartedMutex.RLock()
 	defer fake.uploadFileStartedMutex.RUnlock()
 	fake.uploadFileSucceededMutex.RLock()
 	defer fake.uploadFileSucceededMutex.RUnlock()
+	fake.uploadHooksStartedMutex.RLock()
+	defer fake.uploadHooksStartedMutex.RUnlock()
+	fake.uploadHooksSucceededMutex.RLock()
+	defer fake.uploadHooksSucceededMutex.RUnlock()
+	fake.usingExistingSnapshotMutex.RLock()
+	defer fake.usingExistingSnapshotMutex.RUnlock()
 	fake.validateVMConnectionStartedMutex.RLock()
 	defer fake.validateVMConnectionStartedMutex.RUnlock()
 	fake.validateVMConnectionSucceededMutex.RLock()
@@ -729,6 +2213,14 @@ func (fake *FakeConstructMessenger) Invocations() map[string][][]interface{} {
 	defer fake.waitingForShutdownMutex.RUnlock()
 	fake.winRMDisconnectedForRebootMutex.RLock()
 	defer fake.winRMDisconnectedForRebootMutex.RUnlock()
+	fake.windowsUpdateInstalledMutex.RLock()
+	defer fake.windowsUpdateInstalledMutex.RUnlock()
+	fake.windowsUpdatesIterationLimitReachedMutex.RLock()
+	defer fake.windowsUpdatesIterationLimitReachedMutex.RUnlock()
+	fake.windowsUpdatesRebootFinishedMutex.RLock()
+	defer fake.windowsUpdatesRebootFinishedMutex.RUnlock()
+	fake.windowsUpdatesRebootStartedMutex.RLock()
+	defer fake.windowsUpdatesRebootStartedMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,118 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package constructfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/construct"
+)
+
+type FakeDiagnosticsCollector struct {
+	CollectStub        func(construct.Phase, error) (string, error)
+	collectMutex       sync.RWMutex
+	collectArgsForCall []struct {
+		arg1 construct.Phase
+		arg2 error
+	}
+	collectReturns struct {
+		result1 string
+		result2 error
+	}
+	collectReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeDiagnosticsCollector) Collect(arg1 construct.Phase, arg2 error) (string, error) {
+	fake.collectMutex.Lock()
+	ret, specificReturn := fake.collectReturnsOnCall[len(fake.collectArgsForCall)]
+	fake.collectArgsForCall = append(fake.collectArgsForCall, struct {
+		arg1 construct.Phase
+		arg2 error
+	}{arg1, arg2})
+	stub := fake.CollectStub
+	fakeReturns := fake.collectReturns
+	fake.recordInvocation("Collect", []interface{}{arg1, arg2})
+	fake.collectMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDiagnosticsCollector) CollectCallCount() int {
+	fake.collectMutex.RLock()
+	defer fake.collectMutex.RUnlock()
+	return len(fake.collectArgsForCall)
+}
+
+func (fake *FakeDiagnosticsCollector) CollectCalls(stub func(construct.Phase, error) (string, error)) {
+	fake.collectMutex.Lock()
+	defer fake.collectMutex.Unlock()
+	fake.CollectStub = stub
+}
+
+func (fake *FakeDiagnosticsCollector) CollectArgsForCall(i int) (construct.Phase, error) {
+	fake.collectMutex.RLock()
+	defer fake.collectMutex.RUnlock()
+	argsForCall := fake.collectArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDiagnosticsCollector) CollectReturns(result1 string, result2 error) {
+	fake.collectMutex.Lock()
+	defer fake.collectMutex.Unlock()
+	fake.CollectStub = nil
+	fake.collectReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDiagnosticsCollector) CollectReturnsOnCall(i int, result1 string, result2 error) {
+	fake.collectMutex.Lock()
+	defer fake.collectMutex.Unlock()
+	fake.CollectStub = nil
+	if fake.collectReturnsOnCall == nil {
+		fake.collectReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.collectReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDiagnosticsCollector) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.collectMutex.RLock()
+	defer fake.collectMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeDiagnosticsCollector) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ construct.DiagnosticsCollector = new(FakeDiagnosticsCollector)
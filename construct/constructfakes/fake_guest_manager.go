@@ -55,6 +55,20 @@ type FakeGuestManager struct {
 		result1 int64
 		result2 error
 	}
+	UploadFileInGuestStub        func(context.Context, string, string, func(sent int64, total int64)) error
+	uploadFileInGuestMutex       sync.RWMutex
+	uploadFileInGuestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 func(sent int64, total int64)
+	}
+	uploadFileInGuestReturns struct {
+		result1 error
+	}
+	uploadFileInGuestReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -66,15 +80,16 @@ func (fake *FakeGuestManager) DownloadFileInGuest(arg1 context.Context, arg2 str
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.DownloadFileInGuestStub
+	fakeReturns := fake.downloadFileInGuestReturns
 	fake.recordInvocation("DownloadFileInGuest", []interface{}{arg1, arg2})
 	fake.downloadFileInGuestMutex.Unlock()
-	if fake.DownloadFileInGuestStub != nil {
-		return fake.DownloadFileInGuestStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.downloadFileInGuestReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -133,15 +148,16 @@ func (fake *FakeGuestManager) ExitCodeForProgramInGuest(arg1 context.Context, ar
 		arg1 context.Context
 		arg2 int64
 	}{arg1, arg2})
+	stub := fake.ExitCodeForProgramInGuestStub
+	fakeReturns := fake.exitCodeForProgramInGuestReturns
 	fake.recordInvocation("ExitCodeForProgramInGuest", []interface{}{arg1, arg2})
 	fake.exitCodeForProgramInGuestMutex.Unlock()
-	if fake.ExitCodeForProgramInGuestStub != nil {
-		return fake.ExitCodeForProgramInGuestStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.exitCodeForProgramInGuestReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -198,15 +214,16 @@ func (fake *FakeGuestManager) StartProgramInGuest(arg1 context.Context, arg2 str
 		arg2 string
 		arg3 string
 	}{arg1, arg2, arg3})
+	stub := fake.StartProgramInGuestStub
+	fakeReturns := fake.startProgramInGuestReturns
 	fake.recordInvocation("StartProgramInGuest", []interface{}{arg1, arg2, arg3})
 	fake.startProgramInGuestMutex.Unlock()
-	if fake.StartProgramInGuestStub != nil {
-		return fake.StartProgramInGuestStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.startProgramInGuestReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -255,6 +272,70 @@ func (fake *FakeGuestManager) StartProgramInGuestReturnsOnCall(i int, result1 in
 	}{result1, result2}
 }
 
+func (fake *FakeGuestManager) UploadFileInGuest(arg1 context.Context, arg2 string, arg3 string, arg4 func(sent int64, total int64)) error {
+	fake.uploadFileInGuestMutex.Lock()
+	ret, specificReturn := fake.uploadFileInGuestReturnsOnCall[len(fake.uploadFileInGuestArgsForCall)]
+	fake.uploadFileInGuestArgsForCall = append(fake.uploadFileInGuestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 func(sent int64, total int64)
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UploadFileInGuestStub
+	fakeReturns := fake.uploadFileInGuestReturns
+	fake.recordInvocation("UploadFileInGuest", []interface{}{arg1, arg2, arg3, arg4})
+	fake.uploadFileInGuestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGuestManager) UploadFileInGuestCallCount() int {
+	fake.uploadFileInGuestMutex.RLock()
+	defer fake.uploadFileInGuestMutex.RUnlock()
+	return len(fake.uploadFileInGuestArgsForCall)
+}
+
+func (fake *FakeGuestManager) UploadFileInGuestCalls(stub func(context.Context, string, string, func(sent int64, total int64)) error) {
+	fake.uploadFileInGuestMutex.Lock()
+	defer fake.uploadFileInGuestMutex.Unlock()
+	fake.UploadFileInGuestStub = stub
+}
+
+func (fake *FakeGuestManager) UploadFileInGuestArgsForCall(i int) (context.Context, string, string, func(sent int64, total int64)) {
+	fake.uploadFileInGuestMutex.RLock()
+	defer fake.uploadFileInGuestMutex.RUnlock()
+	argsForCall := fake.uploadFileInGuestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGuestManager) UploadFileInGuestReturns(result1 error) {
+	fake.uploadFileInGuestMutex.Lock()
+	defer fake.uploadFileInGuestMutex.Unlock()
+	fake.UploadFileInGuestStub = nil
+	fake.uploadFileInGuestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGuestManager) UploadFileInGuestReturnsOnCall(i int, result1 error) {
+	fake.uploadFileInGuestMutex.Lock()
+	defer fake.uploadFileInGuestMutex.Unlock()
+	fake.UploadFileInGuestStub = nil
+	if fake.uploadFileInGuestReturnsOnCall == nil {
+		fake.uploadFileInGuestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uploadFileInGuestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGuestManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -264,6 +345,8 @@ func (fake *FakeGuestManager) Invocations() map[string][][]interface{} {
 	defer fake.exitCodeForProgramInGuestMutex.RUnlock()
 	fake.startProgramInGuestMutex.RLock()
 	defer fake.startProgramInGuestMutex.RUnlock()
+	fake.uploadFileInGuestMutex.RLock()
+	defer fake.uploadFileInGuestMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -8,6 +8,18 @@ import (
 )
 
 type FakeIaasClient struct {
+	CaptureScreenshotStub        func(string, string) error
+	captureScreenshotMutex       sync.RWMutex
+	captureScreenshotArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	captureScreenshotReturns struct {
+		result1 error
+	}
+	captureScreenshotReturnsOnCall map[int]struct {
+		result1 error
+	}
 	IsPoweredOffStub        func(string) (bool, error)
 	isPoweredOffMutex       sync.RWMutex
 	isPoweredOffArgsForCall []struct {
@@ -35,6 +47,18 @@ type FakeIaasClient struct {
 	makeDirectoryReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetHardeningProfileStub        func(string, string) error
+	setHardeningProfileMutex       sync.RWMutex
+	setHardeningProfileArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	setHardeningProfileReturns struct {
+		result1 error
+	}
+	setHardeningProfileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StartStub        func(string, string, string, string, ...string) (string, error)
 	startMutex       sync.RWMutex
 	startArgsForCall []struct {
@@ -52,21 +76,6 @@ type FakeIaasClient struct {
 		result1 string
 		result2 error
 	}
-	UploadArtifactStub        func(string, string, string, string, string) error
-	uploadArtifactMutex       sync.RWMutex
-	uploadArtifactArgsForCall []struct {
-		arg1 string
-		arg2 string
-		arg3 string
-		arg4 string
-		arg5 string
-	}
-	uploadArtifactReturns struct {
-		result1 error
-	}
-	uploadArtifactReturnsOnCall map[int]struct {
-		result1 error
-	}
 	WaitForExitStub        func(string, string, string, string) (int, error)
 	waitForExitMutex       sync.RWMutex
 	waitForExitArgsForCall []struct {
@@ -87,21 +96,84 @@ type FakeIaasClient struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeIaasClient) CaptureScreenshot(arg1 string, arg2 string) error {
+	fake.captureScreenshotMutex.Lock()
+	ret, specificReturn := fake.captureScreenshotReturnsOnCall[len(fake.captureScreenshotArgsForCall)]
+	fake.captureScreenshotArgsForCall = append(fake.captureScreenshotArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CaptureScreenshotStub
+	fakeReturns := fake.captureScreenshotReturns
+	fake.recordInvocation("CaptureScreenshot", []interface{}{arg1, arg2})
+	fake.captureScreenshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeIaasClient) CaptureScreenshotCallCount() int {
+	fake.captureScreenshotMutex.RLock()
+	defer fake.captureScreenshotMutex.RUnlock()
+	return len(fake.captureScreenshotArgsForCall)
+}
+
+func (fake *FakeIaasClient) CaptureScreenshotCalls(stub func(string, string) error) {
+	fake.captureScreenshotMutex.Lock()
+	defer fake.captureScreenshotMutex.Unlock()
+	fake.CaptureScreenshotStub = stub
+}
+
+func (fake *FakeIaasClient) CaptureScreenshotArgsForCall(i int) (string, string) {
+	fake.captureScreenshotMutex.RLock()
+	defer fake.captureScreenshotMutex.RUnlock()
+	argsForCall := fake.captureScreenshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIaasClient) CaptureScreenshotReturns(result1 error) {
+	fake.captureScreenshotMutex.Lock()
+	defer fake.captureScreenshotMutex.Unlock()
+	fake.CaptureScreenshotStub = nil
+	fake.captureScreenshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIaasClient) CaptureScreenshotReturnsOnCall(i int, result1 error) {
+	fake.captureScreenshotMutex.Lock()
+	defer fake.captureScreenshotMutex.Unlock()
+	fake.CaptureScreenshotStub = nil
+	if fake.captureScreenshotReturnsOnCall == nil {
+		fake.captureScreenshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.captureScreenshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeIaasClient) IsPoweredOff(arg1 string) (bool, error) {
 	fake.isPoweredOffMutex.Lock()
 	ret, specificReturn := fake.isPoweredOffReturnsOnCall[len(fake.isPoweredOffArgsForCall)]
 	fake.isPoweredOffArgsForCall = append(fake.isPoweredOffArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.IsPoweredOffStub
+	fakeReturns := fake.isPoweredOffReturns
 	fake.recordInvocation("IsPoweredOff", []interface{}{arg1})
 	fake.isPoweredOffMutex.Unlock()
-	if fake.IsPoweredOffStub != nil {
-		return fake.IsPoweredOffStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.isPoweredOffReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -159,15 +231,16 @@ func (fake *FakeIaasClient) MakeDirectory(arg1 string, arg2 string, arg3 string,
 		arg3 string
 		arg4 string
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.MakeDirectoryStub
+	fakeReturns := fake.makeDirectoryReturns
 	fake.recordInvocation("MakeDirectory", []interface{}{arg1, arg2, arg3, arg4})
 	fake.makeDirectoryMutex.Unlock()
-	if fake.MakeDirectoryStub != nil {
-		return fake.MakeDirectoryStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.makeDirectoryReturns
 	return fakeReturns.result1
 }
 
@@ -213,6 +286,68 @@ func (fake *FakeIaasClient) MakeDirectoryReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeIaasClient) SetHardeningProfile(arg1 string, arg2 string) error {
+	fake.setHardeningProfileMutex.Lock()
+	ret, specificReturn := fake.setHardeningProfileReturnsOnCall[len(fake.setHardeningProfileArgsForCall)]
+	fake.setHardeningProfileArgsForCall = append(fake.setHardeningProfileArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetHardeningProfileStub
+	fakeReturns := fake.setHardeningProfileReturns
+	fake.recordInvocation("SetHardeningProfile", []interface{}{arg1, arg2})
+	fake.setHardeningProfileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeIaasClient) SetHardeningProfileCallCount() int {
+	fake.setHardeningProfileMutex.RLock()
+	defer fake.setHardeningProfileMutex.RUnlock()
+	return len(fake.setHardeningProfileArgsForCall)
+}
+
+func (fake *FakeIaasClient) SetHardeningProfileCalls(stub func(string, string) error) {
+	fake.setHardeningProfileMutex.Lock()
+	defer fake.setHardeningProfileMutex.Unlock()
+	fake.SetHardeningProfileStub = stub
+}
+
+func (fake *FakeIaasClient) SetHardeningProfileArgsForCall(i int) (string, string) {
+	fake.setHardeningProfileMutex.RLock()
+	defer fake.setHardeningProfileMutex.RUnlock()
+	argsForCall := fake.setHardeningProfileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIaasClient) SetHardeningProfileReturns(result1 error) {
+	fake.setHardeningProfileMutex.Lock()
+	defer fake.setHardeningProfileMutex.Unlock()
+	fake.SetHardeningProfileStub = nil
+	fake.setHardeningProfileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIaasClient) SetHardeningProfileReturnsOnCall(i int, result1 error) {
+	fake.setHardeningProfileMutex.Lock()
+	defer fake.setHardeningProfileMutex.Unlock()
+	fake.SetHardeningProfileStub = nil
+	if fake.setHardeningProfileReturnsOnCall == nil {
+		fake.setHardeningProfileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setHardeningProfileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeIaasClient) Start(arg1 string, arg2 string, arg3 string, arg4 string, arg5 ...string) (string, error) {
 	fake.startMutex.Lock()
 	ret, specificReturn := fake.startReturnsOnCall[len(fake.startArgsForCall)]
@@ -223,15 +358,16 @@ func (fake *FakeIaasClient) Start(arg1 string, arg2 string, arg3 string, arg4 st
 		arg4 string
 		arg5 []string
 	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.StartStub
+	fakeReturns := fake.startReturns
 	fake.recordInvocation("Start", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.startMutex.Unlock()
-	if fake.StartStub != nil {
-		return fake.StartStub(arg1, arg2, arg3, arg4, arg5...)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.startReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -280,70 +416,6 @@ func (fake *FakeIaasClient) StartReturnsOnCall(i int, result1 string, result2 er
 	}{result1, result2}
 }
 
-func (fake *FakeIaasClient) UploadArtifact(arg1 string, arg2 string, arg3 string, arg4 string, arg5 string) error {
-	fake.uploadArtifactMutex.Lock()
-	ret, specificReturn := fake.uploadArtifactReturnsOnCall[len(fake.uploadArtifactArgsForCall)]
-	fake.uploadArtifactArgsForCall = append(fake.uploadArtifactArgsForCall, struct {
-		arg1 string
-		arg2 string
-		arg3 string
-		arg4 string
-		arg5 string
-	}{arg1, arg2, arg3, arg4, arg5})
-	fake.recordInvocation("UploadArtifact", []interface{}{arg1, arg2, arg3, arg4, arg5})
-	fake.uploadArtifactMutex.Unlock()
-	if fake.UploadArtifactStub != nil {
-		return fake.UploadArtifactStub(arg1, arg2, arg3, arg4, arg5)
-	}
-	if specificReturn {
-		return ret.result1
-	}
-	fakeReturns := fake.uploadArtifactReturns
-	return fakeReturns.result1
-}
-
-func (fake *FakeIaasClient) UploadArtifactCallCount() int {
-	fake.uploadArtifactMutex.RLock()
-	defer fake.uploadArtifactMutex.RUnlock()
-	return len(fake.uploadArtifactArgsForCall)
-}
-
-func (fake *FakeIaasClient) UploadArtifactCalls(stub func(string, string, string, string, string) error) {
-	fake.uploadArtifactMutex.Lock()
-	defer fake.uploadArtifactMutex.Unlock()
-	fake.UploadArtifactStub = stub
-}
-
-func (fake *FakeIaasClient) UploadArtifactArgsForCall(i int) (string, string, string, string, string) {
-	fake.uploadArtifactMutex.RLock()
-	defer fake.uploadArtifactMutex.RUnlock()
-	argsForCall := fake.uploadArtifactArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
-}
-
-func (fake *FakeIaasClient) UploadArtifactReturns(result1 error) {
-	fake.uploadArtifactMutex.Lock()
-	defer fake.uploadArtifactMutex.Unlock()
-	fake.UploadArtifactStub = nil
-	fake.uploadArtifactReturns = struct {
-		result1 error
-	}{result1}
-}
-
-func (fake *FakeIaasClient) UploadArtifactReturnsOnCall(i int, result1 error) {
-	fake.uploadArtifactMutex.Lock()
-	defer fake.uploadArtifactMutex.Unlock()
-	fake.UploadArtifactStub = nil
-	if fake.uploadArtifactReturnsOnCall == nil {
-		fake.uploadArtifactReturnsOnCall = make(map[int]struct {
-			result1 error
-		})
-	}
-	fake.uploadArtifactReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
-}
-
 func (fake *FakeIaasClient) WaitForExit(arg1 string, arg2 string, arg3 string, arg4 string) (int, error) {
 	fake.waitForExitMutex.Lock()
 	ret, specificReturn := fake.waitForExitReturnsOnCall[len(fake.waitForExitArgsForCall)]
@@ -353,15 +425,16 @@ func (fake *FakeIaasClient) WaitForExit(arg1 string, arg2 string, arg3 string, a
 		arg3 string
 		arg4 string
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.WaitForExitStub
+	fakeReturns := fake.waitForExitReturns
 	fake.recordInvocation("WaitForExit", []interface{}{arg1, arg2, arg3, arg4})
 	fake.waitForExitMutex.Unlock()
-	if fake.WaitForExitStub != nil {
-		return fake.WaitForExitStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.waitForExitReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -413,14 +486,16 @@ func (fake *FakeIaasClient) WaitForExitReturnsOnCall(i int, result1 int, result2
 func (fake *FakeIaasClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.captureScreenshotMutex.RLock()
+	defer fake.captureScreenshotMutex.RUnlock()
 	fake.isPoweredOffMutex.RLock()
 	defer fake.isPoweredOffMutex.RUnlock()
 	fake.makeDirectoryMutex.RLock()
 	defer fake.makeDirectoryMutex.RUnlock()
+	fake.setHardeningProfileMutex.RLock()
+	defer fake.setHardeningProfileMutex.RUnlock()
 	fake.startMutex.RLock()
 	defer fake.startMutex.RUnlock()
-	fake.uploadArtifactMutex.RLock()
-	defer fake.uploadArtifactMutex.RUnlock()
 	fake.waitForExitMutex.RLock()
 	defer fake.waitForExitMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -2,15 +2,29 @@
 package constructfakes
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/cloudfoundry-incubator/stembuild/construct"
 )
 
 type FakeRebootWaiterI struct {
-	WaitForRebootFinishedStub        func() error
+	RecordBootTimeStub        func() error
+	recordBootTimeMutex       sync.RWMutex
+	recordBootTimeArgsForCall []struct {
+	}
+	recordBootTimeReturns struct {
+		result1 error
+	}
+	recordBootTimeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WaitForRebootFinishedStub        func(context.Context, time.Duration) error
 	waitForRebootFinishedMutex       sync.RWMutex
 	waitForRebootFinishedArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Duration
 	}
 	waitForRebootFinishedReturns struct {
 		result1 error
@@ -22,20 +36,76 @@ type FakeRebootWaiterI struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeRebootWaiterI) WaitForRebootFinished() error {
+func (fake *FakeRebootWaiterI) RecordBootTime() error {
+	fake.recordBootTimeMutex.Lock()
+	ret, specificReturn := fake.recordBootTimeReturnsOnCall[len(fake.recordBootTimeArgsForCall)]
+	fake.recordBootTimeArgsForCall = append(fake.recordBootTimeArgsForCall, struct {
+	}{})
+	stub := fake.RecordBootTimeStub
+	fakeReturns := fake.recordBootTimeReturns
+	fake.recordInvocation("RecordBootTime", []interface{}{})
+	fake.recordBootTimeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRebootWaiterI) RecordBootTimeCallCount() int {
+	fake.recordBootTimeMutex.RLock()
+	defer fake.recordBootTimeMutex.RUnlock()
+	return len(fake.recordBootTimeArgsForCall)
+}
+
+func (fake *FakeRebootWaiterI) RecordBootTimeCalls(stub func() error) {
+	fake.recordBootTimeMutex.Lock()
+	defer fake.recordBootTimeMutex.Unlock()
+	fake.RecordBootTimeStub = stub
+}
+
+func (fake *FakeRebootWaiterI) RecordBootTimeReturns(result1 error) {
+	fake.recordBootTimeMutex.Lock()
+	defer fake.recordBootTimeMutex.Unlock()
+	fake.RecordBootTimeStub = nil
+	fake.recordBootTimeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRebootWaiterI) RecordBootTimeReturnsOnCall(i int, result1 error) {
+	fake.recordBootTimeMutex.Lock()
+	defer fake.recordBootTimeMutex.Unlock()
+	fake.RecordBootTimeStub = nil
+	if fake.recordBootTimeReturnsOnCall == nil {
+		fake.recordBootTimeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordBootTimeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRebootWaiterI) WaitForRebootFinished(arg1 context.Context, arg2 time.Duration) error {
 	fake.waitForRebootFinishedMutex.Lock()
 	ret, specificReturn := fake.waitForRebootFinishedReturnsOnCall[len(fake.waitForRebootFinishedArgsForCall)]
 	fake.waitForRebootFinishedArgsForCall = append(fake.waitForRebootFinishedArgsForCall, struct {
-	}{})
-	fake.recordInvocation("WaitForRebootFinished", []interface{}{})
+		arg1 context.Context
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.WaitForRebootFinishedStub
+	fakeReturns := fake.waitForRebootFinishedReturns
+	fake.recordInvocation("WaitForRebootFinished", []interface{}{arg1, arg2})
 	fake.waitForRebootFinishedMutex.Unlock()
-	if fake.WaitForRebootFinishedStub != nil {
-		return fake.WaitForRebootFinishedStub()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.waitForRebootFinishedReturns
 	return fakeReturns.result1
 }
 
@@ -45,12 +115,19 @@ func (fake *FakeRebootWaiterI) WaitForRebootFinishedCallCount() int {
 	return len(fake.waitForRebootFinishedArgsForCall)
 }
 
-func (fake *FakeRebootWaiterI) WaitForRebootFinishedCalls(stub func() error) {
+func (fake *FakeRebootWaiterI) WaitForRebootFinishedCalls(stub func(context.Context, time.Duration) error) {
 	fake.waitForRebootFinishedMutex.Lock()
 	defer fake.waitForRebootFinishedMutex.Unlock()
 	fake.WaitForRebootFinishedStub = stub
 }
 
+func (fake *FakeRebootWaiterI) WaitForRebootFinishedArgsForCall(i int) (context.Context, time.Duration) {
+	fake.waitForRebootFinishedMutex.RLock()
+	defer fake.waitForRebootFinishedMutex.RUnlock()
+	argsForCall := fake.waitForRebootFinishedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeRebootWaiterI) WaitForRebootFinishedReturns(result1 error) {
 	fake.waitForRebootFinishedMutex.Lock()
 	defer fake.waitForRebootFinishedMutex.Unlock()
@@ -77,6 +154,8 @@ func (fake *FakeRebootWaiterI) WaitForRebootFinishedReturnsOnCall(i int, result1
 func (fake *FakeRebootWaiterI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.recordBootTimeMutex.RLock()
+	defer fake.recordBootTimeMutex.RUnlock()
 	fake.waitForRebootFinishedMutex.RLock()
 	defer fake.waitForRebootFinishedMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
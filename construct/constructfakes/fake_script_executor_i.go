@@ -20,6 +20,17 @@ type FakeScriptExecutorI struct {
 	executePostRebootScriptReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExecutePostRebootScriptWithoutSysprepStub        func(time.Duration) error
+	executePostRebootScriptWithoutSysprepMutex       sync.RWMutex
+	executePostRebootScriptWithoutSysprepArgsForCall []struct {
+		arg1 time.Duration
+	}
+	executePostRebootScriptWithoutSysprepReturns struct {
+		result1 error
+	}
+	executePostRebootScriptWithoutSysprepReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ExecuteSetupScriptStub        func(string) error
 	executeSetupScriptMutex       sync.RWMutex
 	executeSetupScriptArgsForCall []struct {
@@ -31,6 +42,17 @@ type FakeScriptExecutorI struct {
 	executeSetupScriptReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExecuteSysprepScriptStub        func(time.Duration) error
+	executeSysprepScriptMutex       sync.RWMutex
+	executeSysprepScriptArgsForCall []struct {
+		arg1 time.Duration
+	}
+	executeSysprepScriptReturns struct {
+		result1 error
+	}
+	executeSysprepScriptReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -41,15 +63,16 @@ func (fake *FakeScriptExecutorI) ExecutePostRebootScript(arg1 time.Duration) err
 	fake.executePostRebootScriptArgsForCall = append(fake.executePostRebootScriptArgsForCall, struct {
 		arg1 time.Duration
 	}{arg1})
+	stub := fake.ExecutePostRebootScriptStub
+	fakeReturns := fake.executePostRebootScriptReturns
 	fake.recordInvocation("ExecutePostRebootScript", []interface{}{arg1})
 	fake.executePostRebootScriptMutex.Unlock()
-	if fake.ExecutePostRebootScriptStub != nil {
-		return fake.ExecutePostRebootScriptStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.executePostRebootScriptReturns
 	return fakeReturns.result1
 }
 
@@ -95,21 +118,83 @@ func (fake *FakeScriptExecutorI) ExecutePostRebootScriptReturnsOnCall(i int, res
 	}{result1}
 }
 
+func (fake *FakeScriptExecutorI) ExecutePostRebootScriptWithoutSysprep(arg1 time.Duration) error {
+	fake.executePostRebootScriptWithoutSysprepMutex.Lock()
+	ret, specificReturn := fake.executePostRebootScriptWithoutSysprepReturnsOnCall[len(fake.executePostRebootScriptWithoutSysprepArgsForCall)]
+	fake.executePostRebootScriptWithoutSysprepArgsForCall = append(fake.executePostRebootScriptWithoutSysprepArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.ExecutePostRebootScriptWithoutSysprepStub
+	fakeReturns := fake.executePostRebootScriptWithoutSysprepReturns
+	fake.recordInvocation("ExecutePostRebootScriptWithoutSysprep", []interface{}{arg1})
+	fake.executePostRebootScriptWithoutSysprepMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScriptExecutorI) ExecutePostRebootScriptWithoutSysprepCallCount() int {
+	fake.executePostRebootScriptWithoutSysprepMutex.RLock()
+	defer fake.executePostRebootScriptWithoutSysprepMutex.RUnlock()
+	return len(fake.executePostRebootScriptWithoutSysprepArgsForCall)
+}
+
+func (fake *FakeScriptExecutorI) ExecutePostRebootScriptWithoutSysprepCalls(stub func(time.Duration) error) {
+	fake.executePostRebootScriptWithoutSysprepMutex.Lock()
+	defer fake.executePostRebootScriptWithoutSysprepMutex.Unlock()
+	fake.ExecutePostRebootScriptWithoutSysprepStub = stub
+}
+
+func (fake *FakeScriptExecutorI) ExecutePostRebootScriptWithoutSysprepArgsForCall(i int) time.Duration {
+	fake.executePostRebootScriptWithoutSysprepMutex.RLock()
+	defer fake.executePostRebootScriptWithoutSysprepMutex.RUnlock()
+	argsForCall := fake.executePostRebootScriptWithoutSysprepArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeScriptExecutorI) ExecutePostRebootScriptWithoutSysprepReturns(result1 error) {
+	fake.executePostRebootScriptWithoutSysprepMutex.Lock()
+	defer fake.executePostRebootScriptWithoutSysprepMutex.Unlock()
+	fake.ExecutePostRebootScriptWithoutSysprepStub = nil
+	fake.executePostRebootScriptWithoutSysprepReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScriptExecutorI) ExecutePostRebootScriptWithoutSysprepReturnsOnCall(i int, result1 error) {
+	fake.executePostRebootScriptWithoutSysprepMutex.Lock()
+	defer fake.executePostRebootScriptWithoutSysprepMutex.Unlock()
+	fake.ExecutePostRebootScriptWithoutSysprepStub = nil
+	if fake.executePostRebootScriptWithoutSysprepReturnsOnCall == nil {
+		fake.executePostRebootScriptWithoutSysprepReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.executePostRebootScriptWithoutSysprepReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeScriptExecutorI) ExecuteSetupScript(arg1 string) error {
 	fake.executeSetupScriptMutex.Lock()
 	ret, specificReturn := fake.executeSetupScriptReturnsOnCall[len(fake.executeSetupScriptArgsForCall)]
 	fake.executeSetupScriptArgsForCall = append(fake.executeSetupScriptArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.ExecuteSetupScriptStub
+	fakeReturns := fake.executeSetupScriptReturns
 	fake.recordInvocation("ExecuteSetupScript", []interface{}{arg1})
 	fake.executeSetupScriptMutex.Unlock()
-	if fake.ExecuteSetupScriptStub != nil {
-		return fake.ExecuteSetupScriptStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.executeSetupScriptReturns
 	return fakeReturns.result1
 }
 
@@ -155,13 +240,78 @@ func (fake *FakeScriptExecutorI) ExecuteSetupScriptReturnsOnCall(i int, result1
 	}{result1}
 }
 
+func (fake *FakeScriptExecutorI) ExecuteSysprepScript(arg1 time.Duration) error {
+	fake.executeSysprepScriptMutex.Lock()
+	ret, specificReturn := fake.executeSysprepScriptReturnsOnCall[len(fake.executeSysprepScriptArgsForCall)]
+	fake.executeSysprepScriptArgsForCall = append(fake.executeSysprepScriptArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.ExecuteSysprepScriptStub
+	fakeReturns := fake.executeSysprepScriptReturns
+	fake.recordInvocation("ExecuteSysprepScript", []interface{}{arg1})
+	fake.executeSysprepScriptMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScriptExecutorI) ExecuteSysprepScriptCallCount() int {
+	fake.executeSysprepScriptMutex.RLock()
+	defer fake.executeSysprepScriptMutex.RUnlock()
+	return len(fake.executeSysprepScriptArgsForCall)
+}
+
+func (fake *FakeScriptExecutorI) ExecuteSysprepScriptCalls(stub func(time.Duration) error) {
+	fake.executeSysprepScriptMutex.Lock()
+	defer fake.executeSysprepScriptMutex.Unlock()
+	fake.ExecuteSysprepScriptStub = stub
+}
+
+func (fake *FakeScriptExecutorI) ExecuteSysprepScriptArgsForCall(i int) time.Duration {
+	fake.executeSysprepScriptMutex.RLock()
+	defer fake.executeSysprepScriptMutex.RUnlock()
+	argsForCall := fake.executeSysprepScriptArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeScriptExecutorI) ExecuteSysprepScriptReturns(result1 error) {
+	fake.executeSysprepScriptMutex.Lock()
+	defer fake.executeSysprepScriptMutex.Unlock()
+	fake.ExecuteSysprepScriptStub = nil
+	fake.executeSysprepScriptReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScriptExecutorI) ExecuteSysprepScriptReturnsOnCall(i int, result1 error) {
+	fake.executeSysprepScriptMutex.Lock()
+	defer fake.executeSysprepScriptMutex.Unlock()
+	fake.ExecuteSysprepScriptStub = nil
+	if fake.executeSysprepScriptReturnsOnCall == nil {
+		fake.executeSysprepScriptReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.executeSysprepScriptReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeScriptExecutorI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.executePostRebootScriptMutex.RLock()
 	defer fake.executePostRebootScriptMutex.RUnlock()
+	fake.executePostRebootScriptWithoutSysprepMutex.RLock()
+	defer fake.executePostRebootScriptWithoutSysprepMutex.RUnlock()
 	fake.executeSetupScriptMutex.RLock()
 	defer fake.executeSetupScriptMutex.RUnlock()
+	fake.executeSysprepScriptMutex.RLock()
+	defer fake.executeSysprepScriptMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,220 @@
+package construct
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/stembuild/remotemanager"
+)
+
+const diagnosticsDir = provisionDir + "diagnostics\\"
+
+var diagnosticLogFiles = []string{
+	provisionDir + "log.log",
+	"C:\\Windows\\Panther\\setupact.log",
+	"C:\\Windows\\Panther\\setuperr.log",
+	"C:\\Windows\\Panther\\UnattendGC\\setupact.log",
+	"C:\\Windows\\Panther\\UnattendGC\\setuperr.log",
+	"C:\\Windows\\System32\\Sysprep\\Panther\\setupact.log",
+	"C:\\Windows\\System32\\Sysprep\\Panther\\setuperr.log",
+}
+
+var diagnosticEventLogs = []string{"System", "Application", "Setup"}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . DiagnosticsCollector
+type DiagnosticsCollector interface {
+	Collect(failedPhase Phase, failure error) (string, error)
+}
+
+// GuestDiagnostics gathers provisioning logs, sysprep Panther logs, exported event logs
+// and a console screenshot from a VM whose construct failed, and writes them to a local tarball.
+// Collection is best effort: anything that cannot be retrieved is listed in the bundle instead.
+type GuestDiagnostics struct {
+	ctx             context.Context
+	guestManager    GuestManager
+	remoteManager   RemoteManager
+	client          IaasClient
+	vmInventoryPath string
+	outputDir       string
+}
+
+func NewGuestDiagnostics(ctx context.Context, guestManager GuestManager, remoteManager RemoteManager, client IaasClient, vmInventoryPath, outputDir string) *GuestDiagnostics {
+	return &GuestDiagnostics{
+		ctx,
+		guestManager,
+		remoteManager,
+		client,
+		vmInventoryPath,
+		outputDir,
+	}
+}
+
+type diagnosticsEntry struct {
+	name     string
+	contents []byte
+}
+
+func (d *GuestDiagnostics) Collect(failedPhase Phase, failure error) (string, error) {
+	now := time.Now()
+	var entries []diagnosticsEntry
+	var collectionErrors []string
+
+	entries = append(entries, diagnosticsEntry{
+		name: "failure.txt",
+		contents: []byte(fmt.Sprintf("vm: %s\nphase: %s\ntime: %s\nerror: %s\n",
+			d.vmInventoryPath, failedPhase, now.Format(time.RFC3339), failure)),
+	})
+
+	guestFiles := append([]string{}, diagnosticLogFiles...)
+	err := d.exportEventLogs()
+	if err != nil {
+		collectionErrors = append(collectionErrors, fmt.Sprintf("exporting event logs: %s", err))
+	} else {
+		for _, eventLog := range diagnosticEventLogs {
+			guestFiles = append(guestFiles, diagnosticsDir+eventLog+".evtx")
+		}
+	}
+
+	for _, guestFile := range guestFiles {
+		contents, err := d.downloadGuestFile(guestFile)
+		if err != nil {
+			collectionErrors = append(collectionErrors, fmt.Sprintf("%s: %s", guestFile, err))
+			continue
+		}
+		entries = append(entries, diagnosticsEntry{name: bundlePath(guestFile), contents: contents})
+	}
+
+	screenshot, err := d.captureScreenshot()
+	if err != nil {
+		collectionErrors = append(collectionErrors, fmt.Sprintf("console screenshot: %s", err))
+	} else {
+		entries = append(entries, diagnosticsEntry{name: "console.png", contents: screenshot})
+	}
+
+	if len(collectionErrors) > 0 {
+		entries = append(entries, diagnosticsEntry{
+			name:     "collection-errors.txt",
+			contents: []byte(strings.Join(collectionErrors, "\n") + "\n"),
+		})
+	}
+
+	bundleName := fmt.Sprintf("stembuild-diagnostics-%s-%s.tgz", path.Base(d.vmInventoryPath), now.Format("20060102T150405"))
+	bundle := filepath.Join(d.outputDir, bundleName)
+	err = writeDiagnosticsBundle(bundle, entries)
+	if err != nil {
+		return "", fmt.Errorf("unable to write diagnostics bundle: %s", err)
+	}
+	return bundle, nil
+}
+
+func (d *GuestDiagnostics) exportEventLogs() error {
+	rawCommand := fmt.Sprintf(
+		"New-Item -ItemType Directory -Force -Path '%s' | Out-Null; foreach ($log in '%s') { wevtutil.exe epl $log \"%s$log.evtx\" /ow:true }",
+		diagnosticsDir, strings.Join(diagnosticEventLogs, "','"), diagnosticsDir,
+	)
+	encodedCommand := EncodePowershellCommand([]byte(rawCommand))
+
+	pid, err := d.guestManager.StartProgramInGuest(d.ctx, powershell, "-EncodedCommand "+encodedCommand)
+	if err == nil {
+		var exitCode int32
+		exitCode, err = d.guestManager.ExitCodeForProgramInGuest(d.ctx, pid)
+		if err == nil && exitCode != 0 {
+			return fmt.Errorf("wevtutil exited with code %d", exitCode)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	_, winrmErr := d.remoteManager.ExecuteCommand("powershell.exe -EncodedCommand " + encodedCommand)
+	if winrmErr != nil {
+		return fmt.Errorf("%s; over WinRM: %s", err, winrmErr)
+	}
+	return nil
+}
+
+func (d *GuestDiagnostics) downloadGuestFile(guestFile string) ([]byte, error) {
+	reader, _, err := d.guestManager.DownloadFileInGuest(d.ctx, guestFile)
+	if err == nil {
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+		var contents []byte
+		contents, err = ioutil.ReadAll(reader)
+		if err == nil {
+			return contents, nil
+		}
+	}
+
+	contents, winrmErr := d.remoteManager.DownloadFile(guestFile)
+	if winrmErr != nil {
+		return nil, fmt.Errorf("%s; over WinRM: %s", err, winrmErr)
+	}
+	return contents, nil
+}
+
+func (d *GuestDiagnostics) captureScreenshot() ([]byte, error) {
+	tmpDir, err := ioutil.TempDir("", "stembuild-diagnostics")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	screenshot := filepath.Join(tmpDir, "console.png")
+	err = d.client.CaptureScreenshot(d.vmInventoryPath, screenshot)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(screenshot)
+}
+
+// bundlePath maps a guest path such as C:\Windows\Panther\setupact.log to guest/C/Windows/Panther/setupact.log
+func bundlePath(guestFile string) string {
+	p := strings.Replace(guestFile, ":", "", 1)
+	p = strings.Replace(p, "\\", "/", -1)
+	return "guest/" + p
+}
+
+func writeDiagnosticsBundle(bundle string, entries []diagnosticsEntry) error {
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:    entry.name,
+			Size:    int64(len(entry.contents)),
+			Mode:    int64(os.FileMode(0644)),
+			ModTime: time.Now(),
+		}
+		err := tw.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(entry.contents)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tw.Close()
+	if err != nil {
+		return err
+	}
+	err = gzw.Close()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(bundle, buf.Bytes(), 0644)
+}
@@ -0,0 +1,150 @@
+package construct_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry-incubator/stembuild/construct"
+	"github.com/cloudfoundry-incubator/stembuild/construct/constructfakes"
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager/remotemanagerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func readBundle(bundle string) map[string]string {
+	f, err := os.Open(bundle)
+	Expect(err).NotTo(HaveOccurred())
+	defer f.Close()
+
+	gzr, err := gzip.NewReader(f)
+	Expect(err).NotTo(HaveOccurred())
+	tr := tar.NewReader(gzr)
+
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		Expect(err).NotTo(HaveOccurred())
+		contents, err := ioutil.ReadAll(tr)
+		Expect(err).NotTo(HaveOccurred())
+		files[header.Name] = string(contents)
+	}
+	return files
+}
+
+var _ = Describe("GuestDiagnostics", func() {
+	var (
+		outputDir         string
+		fakeGuestManager  *constructfakes.FakeGuestManager
+		fakeRemoteManager *remotemanagerfakes.FakeRemoteManager
+		fakeVcenterClient *constructfakes.FakeIaasClient
+		diagnostics       *GuestDiagnostics
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputDir, err = ioutil.TempDir("", "diagnostics-output")
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeGuestManager = &constructfakes.FakeGuestManager{}
+		fakeRemoteManager = &remotemanagerfakes.FakeRemoteManager{}
+		fakeVcenterClient = &constructfakes.FakeIaasClient{}
+
+		fakeGuestManager.DownloadFileInGuestCalls(func(ctx context.Context, path string) (io.Reader, int64, error) {
+			return bytes.NewBufferString("contents of " + path), 0, nil
+		})
+		fakeVcenterClient.CaptureScreenshotCalls(func(vmPath, destination string) error {
+			return ioutil.WriteFile(destination, []byte("png"), 0644)
+		})
+
+		diagnostics = NewGuestDiagnostics(context.TODO(), fakeGuestManager, fakeRemoteManager, fakeVcenterClient, "/dc/vm/folder/my-vm", outputDir)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(outputDir)).To(Succeed())
+	})
+
+	It("writes a bundle with the failure, guest logs, event logs and a console screenshot", func() {
+		bundle, err := diagnostics.Collect(PhaseExecuteSetupScript, errors.New("setup exploded"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Dir(bundle)).To(Equal(outputDir))
+		Expect(filepath.Base(bundle)).To(MatchRegexp(`^stembuild-diagnostics-my-vm-\d{8}T\d{6}\.tgz$`))
+
+		files := readBundle(bundle)
+		Expect(files["failure.txt"]).To(ContainSubstring("phase: execute-setup-script"))
+		Expect(files["failure.txt"]).To(ContainSubstring("error: setup exploded"))
+		Expect(files["guest/C/provision/log.log"]).To(Equal("contents of C:\\provision\\log.log"))
+		Expect(files["guest/C/Windows/System32/Sysprep/Panther/setupact.log"]).To(Equal("contents of C:\\Windows\\System32\\Sysprep\\Panther\\setupact.log"))
+		Expect(files["guest/C/provision/diagnostics/System.evtx"]).To(Equal("contents of C:\\provision\\diagnostics\\System.evtx"))
+		Expect(files["console.png"]).To(Equal("png"))
+		Expect(files).NotTo(HaveKey("collection-errors.txt"))
+
+		_, command, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+		Expect(command).To(Equal("C:\\Windows\\System32\\WindowsPowerShell\\V1.0\\powershell.exe"))
+		Expect(args).To(HavePrefix("-EncodedCommand "))
+
+		vmPath, _ := fakeVcenterClient.CaptureScreenshotArgsForCall(0)
+		Expect(vmPath).To(Equal("/dc/vm/folder/my-vm"))
+		Expect(fakeRemoteManager.DownloadFileCallCount()).To(Equal(0))
+	})
+
+	It("falls back to WinRM when guest operations cannot download a file", func() {
+		fakeGuestManager.DownloadFileInGuestReturns(nil, 0, errors.New("guest ops unavailable"))
+		fakeRemoteManager.DownloadFileReturns([]byte("over winrm"), nil)
+
+		bundle, err := diagnostics.Collect(PhaseReboot, errors.New("reboot failed"))
+		Expect(err).NotTo(HaveOccurred())
+
+		files := readBundle(bundle)
+		Expect(files["guest/C/provision/log.log"]).To(Equal("over winrm"))
+		Expect(fakeRemoteManager.DownloadFileArgsForCall(0)).To(Equal("C:\\provision\\log.log"))
+	})
+
+	It("exports event logs over WinRM when guest operations cannot start a program", func() {
+		fakeGuestManager.StartProgramInGuestReturns(-1, errors.New("guest ops unavailable"))
+
+		_, err := diagnostics.Collect(PhaseReboot, errors.New("reboot failed"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeRemoteManager.ExecuteCommandCallCount()).To(Equal(1))
+		Expect(fakeRemoteManager.ExecuteCommandArgsForCall(0)).To(HavePrefix("powershell.exe -EncodedCommand "))
+	})
+
+	It("lists anything it could not collect in the bundle", func() {
+		fakeGuestManager.StartProgramInGuestReturns(-1, errors.New("guest ops unavailable"))
+		fakeRemoteManager.ExecuteCommandReturns(1, errors.New("winrm unavailable"))
+		fakeGuestManager.DownloadFileInGuestReturns(nil, 0, errors.New("guest ops unavailable"))
+		fakeRemoteManager.DownloadFileReturns(nil, errors.New("winrm unavailable"))
+		fakeVcenterClient.CaptureScreenshotCalls(nil)
+		fakeVcenterClient.CaptureScreenshotReturns(errors.New("no console"))
+
+		bundle, err := diagnostics.Collect(PhaseCreateProvisionDir, errors.New("mkdir failed"))
+		Expect(err).NotTo(HaveOccurred())
+
+		files := readBundle(bundle)
+		Expect(files).To(HaveKey("failure.txt"))
+		Expect(files).NotTo(HaveKey("console.png"))
+		Expect(files["collection-errors.txt"]).To(ContainSubstring("exporting event logs: "))
+		Expect(files["collection-errors.txt"]).To(ContainSubstring("C:\\provision\\log.log: guest ops unavailable; over WinRM: winrm unavailable"))
+		Expect(files["collection-errors.txt"]).To(ContainSubstring("console screenshot: no console"))
+		Expect(files["collection-errors.txt"]).NotTo(ContainSubstring("System.evtx"))
+	})
+
+	It("returns an error when the bundle cannot be written", func() {
+		diagnostics = NewGuestDiagnostics(context.TODO(), fakeGuestManager, fakeRemoteManager, fakeVcenterClient, "my-vm", filepath.Join(outputDir, "missing"))
+
+		_, err := diagnostics.Collect(PhaseReboot, errors.New("reboot failed"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to write diagnostics bundle"))
+	})
+})
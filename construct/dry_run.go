@@ -0,0 +1,71 @@
+package construct
+
+import (
+	"fmt"
+)
+
+// dryRun reports, in order, the changes PrepareVM would make to the VM without making any of them.
+// Everything it looks up to do so, such as checkpoints and snapshots, is only read.
+func (c *VMConstruct) dryRun(phases []constructPhase, stembuildVersion string) error {
+	resumeAfter := -1
+	if c.Resume && c.CloneSource == "" {
+		var err error
+		resumeAfter, err = c.resumePoint(phases, stembuildVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.messenger.DryRunStarted()
+
+	if c.CloneSource != "" {
+		c.messenger.PlannedOperation(phaseCloneVM, fmt.Sprintf("clone %s to %s", c.CloneSource, c.vmInventoryPath))
+	}
+
+	if c.SnapshotName != "" {
+		// A fresh clone has no snapshots, so there is nothing to look up
+		exists := false
+		if c.CloneSource == "" {
+			var err error
+			exists, err = c.Snapshots.HasSnapshot(c.SnapshotName)
+			if err != nil {
+				return fmt.Errorf("unable to look up snapshot %s: %s", c.SnapshotName, err)
+			}
+		}
+		if !exists {
+			c.messenger.PlannedOperation(phaseCreateSnapshot, "create snapshot "+c.SnapshotName)
+		}
+	}
+
+	for i, phase := range phases {
+		if i <= resumeAfter && !(phase.reconnect && winRMNeededAfter(phases, resumeAfter)) {
+			continue
+		}
+
+		for _, operation := range phase.plan {
+			c.messenger.PlannedOperation(string(phase.name), operation)
+		}
+	}
+
+	if c.SnapshotName != "" && c.DeleteSnapshot {
+		c.messenger.PlannedOperation(phaseRemoveSnapshot, "delete snapshot "+c.SnapshotName)
+	}
+
+	c.messenger.DryRunSucceeded()
+	return nil
+}
+
+func (c *VMConstruct) dryRunRevertSnapshot() error {
+	exists, err := c.Snapshots.HasSnapshot(c.SnapshotName)
+	if err != nil {
+		return fmt.Errorf("unable to look up snapshot %s: %s", c.SnapshotName, err)
+	}
+	if !exists {
+		return fmt.Errorf("unable to revert to snapshot %s: the VM has no snapshot of that name", c.SnapshotName)
+	}
+
+	c.messenger.DryRunStarted()
+	c.messenger.PlannedOperation(phaseRevertSnapshot, "revert to snapshot "+c.SnapshotName)
+	c.messenger.DryRunSucceeded()
+	return nil
+}
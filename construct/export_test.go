@@ -0,0 +1,8 @@
+package construct
+
+import "io"
+
+// NewTerminalMessenger returns a Messenger that writes to out as if it were a terminal
+func NewTerminalMessenger(out io.Writer) *Messenger {
+	return &Messenger{out: out, tty: true}
+}
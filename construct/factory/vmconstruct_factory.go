@@ -2,19 +2,24 @@ package vmconstruct_factory
 
 import (
 	"context"
-	"os"
+	"fmt"
+	"path"
 
 	p "github.com/cloudfoundry-incubator/stembuild/poller"
 
 	"github.com/cloudfoundry-incubator/stembuild/version"
 
+	"github.com/cloudfoundry-incubator/stembuild/assetmanager"
 	"github.com/cloudfoundry-incubator/stembuild/commandparser"
 	"github.com/cloudfoundry-incubator/stembuild/construct"
 	"github.com/cloudfoundry-incubator/stembuild/construct/archive"
 	"github.com/cloudfoundry-incubator/stembuild/construct/config"
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli"
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients"
+	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/vcenter_manager"
 	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
 
 	. "github.com/cloudfoundry-incubator/stembuild/remotemanager"
 )
@@ -22,19 +27,93 @@ import (
 type VMConstructFactory struct {
 }
 
-func (f *VMConstructFactory) VMPreparer(config config.SourceConfig, vCenterManager commandparser.VCenterManager) (commandparser.VmConstruct, error) {
-	runner := &iaas_cli.GovcRunner{}
+func (f *VMConstructFactory) VMPreparer(ctx context.Context, config config.SourceConfig, vCenterManager commandparser.VCenterManager, output commandparser.VMOutput) (commandparser.VmConstruct, error) {
+	timeouts := config.Timeouts.WithDefaults()
+	err := timeouts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.WinRM.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.Proxy.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.Hardening.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := construct.ArtifactTransport(config.Transport)
+	switch transport {
+	case "":
+		transport = construct.TransportGuestOps
+	case construct.TransportGuestOps, construct.TransportWinRM:
+	default:
+		return nil, fmt.Errorf("unsupported transport %s, expected %s or %s", config.Transport, construct.TransportGuestOps, construct.TransportWinRM)
+	}
+	if config.WindowsUpdates && config.WindowsUpdatesMaxIterations < 1 {
+		return nil, fmt.Errorf("windows-updates-max-iterations must be at least 1, got %d", config.WindowsUpdatesMaxIterations)
+	}
+	if config.GuestOpsOnly && transport == construct.TransportWinRM {
+		return nil, fmt.Errorf("transport %s needs a WinRM connection, which guest-ops-only mode does not make", transport)
+	}
+	winRMTransport := &WinRMTransport{
+		Port:     config.WinRM.Port,
+		HTTPS:    config.WinRM.HTTPS,
+		Insecure: config.WinRM.Insecure,
+		Auth:     WinRMAuth(config.WinRM.Auth),
+	}
+	if winRMTransport.Auth == WinRMAuthKerberos {
+		kerberos := config.WinRM.Kerberos
+		winRMTransport.Kerberos = &WinRMKerberos{
+			Realm:  kerberos.Realm,
+			Keytab: kerberos.Keytab,
+			CCache: kerberos.CCache,
+			Config: kerberos.Config,
+			SPN:    kerberos.SPN,
+		}
+	}
+	if config.WinRM.CACertFile != "" {
+		err = winRMTransport.LoadCACert(config.WinRM.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var hooks construct.Hooks
+	if config.HooksDir != "" {
+		hooks, err = construct.LoadHooks(config.HooksDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	runner := &iaas_cli.GovcRunner{Context: ctx}
 	client := iaas_clients.NewVcenterClient(config.VCenterUsername, config.VCenterPassword, config.VCenterUrl, config.CaCertFile, runner)
 
-	messenger := construct.NewMessenger(os.Stdout)
+	var messenger construct.ConstructMessenger = construct.NewMessenger(output.Stdout)
+	if output.Events != nil {
+		messenger = construct.NewJSONMessenger(output.Events)
+	}
 
-	ctx := context.Background()
-	err := vCenterManager.Login(ctx)
+	err = vCenterManager.Login(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot complete login due to an incorrect vCenter user name or password")
 	}
 
-	vm, err := vCenterManager.FindVM(ctx, config.VmInventoryPath)
+	var vm *object.VirtualMachine
+	var cloneSource string
+	if config.CloneTo != "" {
+		vm, cloneSource, err = cloneVM(ctx, &config, vCenterManager, messenger)
+	} else {
+		vm, err = vCenterManager.FindVM(ctx, config.VmInventoryPath)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -46,14 +125,42 @@ func (f *VMConstructFactory) VMPreparer(config config.SourceConfig, vCenterManag
 		return nil, err
 	}
 
+	assets := assetmanager.New(config.AutomationBundle, config.LGPO)
+
 	winRMManager := &construct.WinRMManager{
 		GuestManager: guestManager,
 		Unarchiver:   &archive.Zip{},
+		Transport:    winRMTransport,
+		Context:      ctx,
+		Assets:       assets,
 	}
 	versionGetter := version.NewVersionGetter()
 
-	winRmClientFactory := NewWinRmClientFactory(config.GuestVmIp, config.GuestVMUsername, config.GuestVMPassword)
-	remoteManager := NewWinRM(config.GuestVmIp, config.GuestVMUsername, config.GuestVMPassword, winRmClientFactory)
+	var remoteManager RemoteManager
+	var rebootChecker RebootCheckerI
+	if config.GuestOpsOnly {
+		heartbeat := &vmHeartbeat{ctx, vCenterManager, vm}
+		guestOps := NewGuestOps(guestManager, heartbeat)
+		guestOps.Timeout = timeouts.WinRMOperation
+		guestOps.Context = ctx
+		guestOps.Stdout = output.Stdout
+		guestOps.Stderr = output.Stderr
+		remoteManager = guestOps
+		rebootChecker = NewGuestHeartbeatRebootChecker(heartbeat, NewRebootChecker(guestOps))
+	} else {
+		winRmClientFactory := NewWinRmClientFactory(config.GuestVmIp, config.GuestVMUsername, config.GuestVMPassword)
+		winRmClientFactory.ConnectTimeout = timeouts.WinRMConnect
+		winRmClientFactory.Transport = winRMTransport
+		winRM := NewWinRM(config.GuestVmIp, config.GuestVMUsername, config.GuestVMPassword, winRmClientFactory)
+		winRM.Timeout = timeouts.WinRMOperation
+		winRM.ConnectTimeout = timeouts.WinRMConnect
+		winRM.Transport = winRMTransport
+		winRM.Context = ctx
+		winRM.Stdout = output.Stdout
+		winRM.Stderr = output.Stderr
+		remoteManager = winRM
+		rebootChecker = NewRebootChecker(winRM)
+	}
 
 	vmConnectionValidator := &construct.WinRMConnectionValidator{
 		RemoteManager: remoteManager,
@@ -61,13 +168,28 @@ func (f *VMConstructFactory) VMPreparer(config config.SourceConfig, vCenterManag
 
 	poller := &p.Poller{}
 
-	rebootChecker := NewRebootChecker(remoteManager)
-
 	rebootWaiter := NewRebootWaiter(poller, rebootChecker)
+	rebootWaiter.PollInterval = timeouts.RebootPollInterval
+
+	scriptExecutor := construct.NewScriptExecutor(remoteManager, construct.SysprepOptions{
+		Organization:       config.Organization,
+		Owner:              config.Owner,
+		SkipRandomPassword: config.SkipRandomPassword,
+		HardeningProfile:   config.Hardening.ProfileName(),
+	})
 
-	scriptExecutor := construct.NewScriptExecutor(remoteManager)
+	checkpointFile := config.CheckpointFile
+	if checkpointFile == "" {
+		checkpointFile, err = construct.DefaultCheckpointFile()
+		if err != nil && config.Resume {
+			return nil, err
+		}
+		if err != nil {
+			messenger.CheckpointsDisabled(err)
+		}
+	}
 
-	return construct.NewVMConstruct(
+	vmConstruct := construct.NewVMConstruct(
 		ctx,
 		remoteManager,
 		config.GuestVMUsername,
@@ -82,5 +204,130 @@ func (f *VMConstructFactory) VMPreparer(config config.SourceConfig, vCenterManag
 		versionGetter,
 		rebootWaiter,
 		scriptExecutor,
-	), nil
+		assets,
+	)
+	vmConstruct.RebootWaitTime = timeouts.RebootDelay
+	vmConstruct.RebootTimeout = timeouts.Reboot
+	vmConstruct.PostRebootTimeout = timeouts.PostRebootScript
+	vmConstruct.ShutdownPollInterval = timeouts.ShutdownPollInterval
+	vmConstruct.ShutdownTimeout = timeouts.Shutdown
+	vmConstruct.Timeout = timeouts.Construct
+	vmConstruct.Hooks = hooks
+	vmConstruct.HookTimeout = timeouts.Hook
+	if checkpointFile != "" {
+		vmConstruct.Checkpoints = construct.NewFileCheckpointStore(checkpointFile)
+	}
+	vmConstruct.Resume = config.Resume
+	vmConstruct.HardeningProfile = config.Hardening.ProfileName()
+	vmConstruct.HardeningGPODir = config.Hardening.GPODir
+	vmConstruct.Diagnostics = construct.NewGuestDiagnostics(ctx, guestManager, remoteManager, client, config.VmInventoryPath, config.DiagnosticsDir)
+	vmConstruct.Snapshots = &vmSnapshots{ctx, vCenterManager, vm}
+	vmConstruct.SnapshotName = config.SnapshotName
+	vmConstruct.DeleteSnapshot = config.DeleteSnapshot
+	vmConstruct.DryRun = config.DryRun
+	vmConstruct.CloneSource = cloneSource
+	vmConstruct.Transport = transport
+	vmConstruct.GuestOpsOnly = config.GuestOpsOnly
+	vmConstruct.WindowsUpdates = config.WindowsUpdates
+	vmConstruct.WindowsUpdatesMaxIterations = config.WindowsUpdatesMaxIterations
+	vmConstruct.WindowsUpdatesTimeout = timeouts.WindowsUpdates
+	vmConstruct.HTTPProxy = config.Proxy.HTTP
+	vmConstruct.HTTPSProxy = config.Proxy.HTTPS
+	vmConstruct.ProxyBypassList = config.Proxy.BypassList
+
+	return vmConstruct, nil
+}
+
+// cloneVM clones the VM at config.VmInventoryPath and points config at the clone, so that
+// everything after it provisions the clone and the source VM is left untouched.
+// A resumed construct reuses the clone made by the failed run.
+// In a dry run the clone is not made: the source VM and its inventory path are returned instead,
+// so the clone can be reported as the first change construct would make.
+func cloneVM(ctx context.Context, config *config.SourceConfig, vCenterManager commandparser.VCenterManager, messenger construct.ConstructMessenger) (*object.VirtualMachine, string, error) {
+	clonePath := config.CloneTo
+	if config.CloneFolder != "" {
+		clonePath = path.Join(config.CloneFolder, path.Base(config.CloneTo))
+	}
+
+	clone, err := vCenterManager.FindVM(ctx, clonePath)
+	if _, notFound := errors.Cause(err).(*find.NotFoundError); err != nil && !notFound {
+		return nil, "", errors.Wrapf(err, "unable to check whether %s exists", clonePath)
+	}
+	if err == nil && !config.Resume {
+		return nil, "", fmt.Errorf("%s already exists, choose another clone path or use -resume to continue constructing it", clonePath)
+	}
+	if err != nil {
+		source, err := vCenterManager.FindVM(ctx, config.VmInventoryPath)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if config.DryRun {
+			sourcePath := config.VmInventoryPath
+			config.VmInventoryPath = clonePath
+			return source, sourcePath, nil
+		}
+
+		messenger.CloneVMStarted(clonePath)
+		err = vCenterManager.CloneVM(ctx, source, clonePath, vcenter_manager.CloneOptions{
+			Folder:       config.CloneFolder,
+			ResourcePool: config.CloneResourcePool,
+			Datastore:    config.CloneDatastore,
+		})
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "unable to clone %s to %s", config.VmInventoryPath, clonePath)
+		}
+		messenger.CloneVMSucceeded()
+
+		clone, err = vCenterManager.FindVM(ctx, clonePath)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if config.GuestVmIp == "" && !config.GuestOpsOnly {
+		ip, err := vCenterManager.WaitForIP(ctx, clone)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "unable to discover the IP of %s", clonePath)
+		}
+		messenger.CloneIPDiscovered(ip)
+		config.GuestVmIp = ip
+	}
+	config.VmInventoryPath = clonePath
+
+	return clone, "", nil
+}
+
+// vmSnapshots binds the snapshot operations of the vCenter manager to the VM being constructed
+type vmSnapshots struct {
+	ctx            context.Context
+	vCenterManager commandparser.VCenterManager
+	vm             *object.VirtualMachine
+}
+
+func (s *vmSnapshots) HasSnapshot(name string) (bool, error) {
+	return s.vCenterManager.HasSnapshot(s.ctx, s.vm, name)
+}
+
+func (s *vmSnapshots) CreateSnapshot(name, description string) error {
+	return s.vCenterManager.CreateSnapshot(s.ctx, s.vm, name, description)
+}
+
+func (s *vmSnapshots) RevertToSnapshot(name string) error {
+	return s.vCenterManager.RevertToSnapshot(s.ctx, s.vm, name)
+}
+
+func (s *vmSnapshots) RemoveSnapshot(name string) error {
+	return s.vCenterManager.RemoveSnapshot(s.ctx, s.vm, name)
+}
+
+// vmHeartbeat binds reading the guest heartbeat of the vCenter manager to the VM being constructed
+type vmHeartbeat struct {
+	ctx            context.Context
+	vCenterManager commandparser.VCenterManager
+	vm             *object.VirtualMachine
+}
+
+func (h *vmHeartbeat) GuestHeartbeatIsGreen() (bool, error) {
+	return h.vCenterManager.GuestHeartbeatIsGreen(h.ctx, h.vm)
 }
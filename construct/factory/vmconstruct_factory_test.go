@@ -1,22 +1,37 @@
 package vmconstruct_factory
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/commandparser"
 	"github.com/cloudfoundry-incubator/stembuild/commandparser/commandparserfakes"
 	"github.com/cloudfoundry-incubator/stembuild/construct"
 	"github.com/cloudfoundry-incubator/stembuild/construct/config"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
+	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/vcenter_manager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
 )
 
 var _ = Describe("Factory", func() {
 	Describe("GetVMPreparer", func() {
 		var (
 			factory *VMConstructFactory
+			stdout  *bytes.Buffer
+			output  commandparser.VMOutput
 		)
 
 		BeforeEach(func() {
 			factory = &VMConstructFactory{}
+			stdout = new(bytes.Buffer)
+			output = commandparser.VMOutput{Stdout: stdout, Stderr: ioutil.Discard}
 		})
 
 		It("should return a VMPreparer", func() {
@@ -32,7 +47,7 @@ var _ = Describe("Factory", func() {
 				VmInventoryPath: "some-vm-inventory-path",
 			}
 
-			vmPreparer, err := factory.VMPreparer(sourceConfig, fakeVCenterManager)
+			vmPreparer, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(vmPreparer).To(BeAssignableToTypeOf(&construct.VMConstruct{}))
 		})
@@ -44,12 +59,301 @@ var _ = Describe("Factory", func() {
 			fakeVCenterManager.LoginReturns(loginFailure)
 			sourceConfig := config.SourceConfig{}
 
-			vmPreparer, err := factory.VMPreparer(sourceConfig, fakeVCenterManager)
+			vmPreparer, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
 
 			Expect(vmPreparer).To(BeNil())
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Cannot complete login due to an incorrect vCenter user name or password"))
 			Expect(err.Error()).To(ContainSubstring(loginFailure.Error()))
 		})
+
+		It("applies the configured timeouts", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{
+				GuestVmIp: "vmIP",
+				Timeouts:  config.Timeouts{Construct: time.Hour, RebootDelay: 90 * time.Second},
+			}
+
+			vmPreparer, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+			Expect(err).ToNot(HaveOccurred())
+
+			vmConstruct := vmPreparer.(*construct.VMConstruct)
+			Expect(vmConstruct.Timeout).To(Equal(time.Hour))
+			Expect(vmConstruct.RebootWaitTime).To(Equal(90 * time.Second))
+			Expect(vmConstruct.PostRebootTimeout).To(Equal(24 * time.Hour))
+		})
+
+		Context("when the home directory cannot be determined", func() {
+			var home string
+
+			BeforeEach(func() {
+				home = os.Getenv("HOME")
+				Expect(os.Setenv("HOME", "")).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Setenv("HOME", home)).To(Succeed())
+			})
+
+			It("constructs without checkpoints and warns that construct cannot be resumed", func() {
+				fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+				sourceConfig := config.SourceConfig{GuestVmIp: "vmIP"}
+
+				vmPreparer, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(vmPreparer.(*construct.VMConstruct).Checkpoints).To(BeNil())
+				Expect(stdout.String()).To(ContainSubstring("Warning: construct checkpoints are disabled, so this construct cannot be resumed"))
+			})
+
+			It("returns an error when resuming", func() {
+				fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+				sourceConfig := config.SourceConfig{GuestVmIp: "vmIP", Resume: true}
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+				Expect(err).To(MatchError(ContainSubstring("unable to determine home directory for construct checkpoints")))
+			})
+		})
+
+		It("returns an error for a negative timeout without logging in", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{Timeouts: config.Timeouts{Reboot: -time.Minute}}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError("reboot timeout must not be negative, got -1m0s"))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("returns an error for an invalid proxy without logging in", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{Proxy: config.Proxy{BypassList: "<local>"}}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError("proxy bypass list needs an HTTP or HTTPS proxy"))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("returns an error for an invalid hardening profile without logging in", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{Hardening: config.Hardening{Profile: "stig"}}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError("unsupported hardening profile stig, expected cis, minimal or none"))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("returns an error for Windows Updates without any batches without logging in", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{WindowsUpdates: true, WindowsUpdatesMaxIterations: 0}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError("windows-updates-max-iterations must be at least 1, got 0"))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("returns an error for an invalid WinRM transport without logging in", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{WinRM: config.WinRM{Insecure: true}}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError("WinRM CA certificates and insecure mode only apply over HTTPS"))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("returns an error for an unknown transport without logging in", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{Transport: "ftp"}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError("unsupported transport ftp, expected guest-ops or winrm"))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("returns an error for the winrm transport in guest-ops-only mode without logging in", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{Transport: "winrm", GuestOpsOnly: true}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError("transport winrm needs a WinRM connection, which guest-ops-only mode does not make"))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("constructs without a VM IP in guest-ops-only mode", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{
+				GuestVMUsername: "vmUser",
+				GuestVMPassword: "vmPwd",
+				VmInventoryPath: "some-vm-inventory-path",
+				GuestOpsOnly:    true,
+			}
+
+			vmPreparer, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vmPreparer.(*construct.VMConstruct).GuestOpsOnly).To(BeTrue())
+			Expect(vmPreparer.(*construct.VMConstruct).Transport).To(Equal(construct.TransportGuestOps))
+		})
+
+		It("returns an error when the WinRM CA certificates cannot be read", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{WinRM: config.WinRM{HTTPS: true, CACertFile: "/does/not/exist"}}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError(ContainSubstring("unable to read WinRM CA certificates")))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the hooks directory cannot be loaded", func() {
+			fakeVCenterManager := &commandparserfakes.FakeVCenterManager{}
+			sourceConfig := config.SourceConfig{HooksDir: "/does/not/exist"}
+
+			_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+			Expect(err).To(MatchError(ContainSubstring("unable to read hooks directory")))
+			Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+		})
+
+		Context("when a clone path is given", func() {
+			var (
+				fakeVCenterManager *commandparserfakes.FakeVCenterManager
+				sourceConfig       config.SourceConfig
+				clonedVMs          map[string]bool
+			)
+
+			BeforeEach(func() {
+				fakeVCenterManager = &commandparserfakes.FakeVCenterManager{}
+				clonedVMs = map[string]bool{"/dc/vm/base": true}
+				fakeVCenterManager.FindVMCalls(func(ctx context.Context, inventoryPath string) (*object.VirtualMachine, error) {
+					if !clonedVMs[inventoryPath] {
+						return nil, &find.NotFoundError{}
+					}
+					return &object.VirtualMachine{}, nil
+				})
+				fakeVCenterManager.CloneVMCalls(func(ctx context.Context, vm *object.VirtualMachine, clonePath string, options vcenter_manager.CloneOptions) error {
+					clonedVMs[clonePath] = true
+					return nil
+				})
+				fakeVCenterManager.WaitForIPReturns("10.0.0.7", nil)
+
+				sourceConfig = config.SourceConfig{
+					GuestVMUsername: "vmUser",
+					GuestVMPassword: "vmPwd",
+					VmInventoryPath: "/dc/vm/base",
+					CloneTo:         "/dc/vm/base-build",
+				}
+			})
+
+			It("clones the VM, discovers the IP of the clone and constructs the clone", func() {
+				sourceConfig.CloneFolder = "/dc/vm/builds"
+				sourceConfig.CloneResourcePool = "/dc/host/cluster/Resources/builds"
+				sourceConfig.CloneDatastore = "fast-ds"
+
+				vmPreparer, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeVCenterManager.CloneVMCallCount()).To(Equal(1))
+				_, _, clonePath, options := fakeVCenterManager.CloneVMArgsForCall(0)
+				Expect(clonePath).To(Equal("/dc/vm/builds/base-build"))
+				Expect(options).To(Equal(vcenter_manager.CloneOptions{
+					Folder:       "/dc/vm/builds",
+					ResourcePool: "/dc/host/cluster/Resources/builds",
+					Datastore:    "fast-ds",
+				}))
+				Expect(fakeVCenterManager.WaitForIPCallCount()).To(Equal(1))
+				Expect(vmPreparer).To(BeAssignableToTypeOf(&construct.VMConstruct{}))
+			})
+
+			It("reports the clone to the output of the VM", func() {
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(stdout.String()).To(ContainSubstring("Cloning VM to /dc/vm/base-build..."))
+			})
+
+			It("reports the clone as events when the output has an event sink", func() {
+				sink := &eventsfakes.FakeSink{}
+				output.Events = sink
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(stdout.String()).To(BeEmpty())
+				Expect(sink.EmitArgsForCall(0).Event).To(Equal("CloneVMStarted"))
+			})
+
+			It("does not wait for an IP when one is given", func() {
+				sourceConfig.GuestVmIp = "10.0.0.8"
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeVCenterManager.WaitForIPCallCount()).To(Equal(0))
+			})
+
+			It("does not wait for an IP in guest-ops-only mode", func() {
+				sourceConfig.GuestOpsOnly = true
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeVCenterManager.WaitForIPCallCount()).To(Equal(0))
+			})
+
+			It("refuses to overwrite an existing clone", func() {
+				clonedVMs["/dc/vm/base-build"] = true
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).To(MatchError(ContainSubstring("/dc/vm/base-build already exists")))
+				Expect(fakeVCenterManager.CloneVMCallCount()).To(Equal(0))
+			})
+
+			It("returns an error instead of cloning when the clone cannot be looked up", func() {
+				fakeVCenterManager.FindVMCalls(nil)
+				fakeVCenterManager.FindVMReturns(nil, errors.New("session is not authenticated"))
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).To(MatchError("unable to check whether /dc/vm/base-build exists: session is not authenticated"))
+				Expect(fakeVCenterManager.CloneVMCallCount()).To(Equal(0))
+			})
+
+			It("reuses an existing clone when resuming", func() {
+				clonedVMs["/dc/vm/base-build"] = true
+				sourceConfig.Resume = true
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeVCenterManager.CloneVMCallCount()).To(Equal(0))
+				Expect(fakeVCenterManager.WaitForIPCallCount()).To(Equal(1))
+			})
+
+			It("only plans the clone in a dry run", func() {
+				sourceConfig.DryRun = true
+
+				vmPreparer, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeVCenterManager.CloneVMCallCount()).To(Equal(0))
+				Expect(fakeVCenterManager.WaitForIPCallCount()).To(Equal(0))
+				vmConstruct := vmPreparer.(*construct.VMConstruct)
+				Expect(vmConstruct.DryRun).To(BeTrue())
+				Expect(vmConstruct.CloneSource).To(Equal("/dc/vm/base"))
+			})
+
+			It("returns an error when the clone fails", func() {
+				fakeVCenterManager.CloneVMCalls(nil)
+				fakeVCenterManager.CloneVMReturns(errors.New("no space on datastore"))
+
+				_, err := factory.VMPreparer(context.Background(), sourceConfig, fakeVCenterManager, output)
+				Expect(err).To(MatchError("unable to clone /dc/vm/base to /dc/vm/base-build: no space on datastore"))
+			})
+		})
 	})
 })
@@ -0,0 +1,95 @@
+package construct
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// The GPO backup of the custom hardening profile is extracted to hardeningGPODir, where the sysprep script applies it
+const hardeningGPODest = provisionDir + "hardening-gpo.zip"
+const hardeningGPODir = provisionDir + "hardening-gpo"
+
+func (c *VMConstruct) uploadHardeningGPO() error {
+	gpo, cleanup, err := zipDirectory(c.HardeningGPODir)
+	if err != nil {
+		return fmt.Errorf("unable to archive hardening GPO directory %s: %s", c.HardeningGPODir, err)
+	}
+	defer cleanup()
+
+	c.messenger.UploadFileStarted("hardening GPO backup")
+	err = c.upload(gpo, hardeningGPODest, c.uploadProgress())
+	if err != nil {
+		return err
+	}
+	c.messenger.UploadFileSucceeded()
+	return nil
+}
+
+// recordHardeningProfile stores the profile applied to the VM in its configuration, where package reads it for the stemcell manifest
+func (c *VMConstruct) recordHardeningProfile() error {
+	c.messenger.RecordHardeningProfileStarted(c.HardeningProfile)
+	err := c.Client.SetHardeningProfile(c.vmInventoryPath, c.HardeningProfile)
+	if err != nil {
+		return fmt.Errorf("failed to record the hardening profile of the VM: %s", err)
+	}
+	c.messenger.RecordHardeningProfileSucceeded()
+	return nil
+}
+
+// zipDirectory archives the contents of dir into a temporary file, and returns a function that removes the file
+func zipDirectory(dir string) (string, func(), error) {
+	archive, err := ioutil.TempFile("", "hardening-gpo-*.zip")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.Remove(archive.Name()) }
+
+	w := zip.NewWriter(archive)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+			_, err = w.CreateHeader(header)
+			return err
+		}
+		header.Method = zip.Deflate
+
+		entry, err := w.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(entry, f)
+		return err
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return archive.Name(), cleanup, nil
+}
@@ -0,0 +1,141 @@
+package construct
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type HookPoint string
+
+const (
+	// HookPreSetup scripts run before the setup script, once WinRM is available
+	HookPreSetup HookPoint = "pre-setup"
+	// HookPostSetup scripts run once the VM has rebooted after the setup script
+	HookPostSetup HookPoint = "post-setup"
+	// HookPreSysprep scripts run after the post-reboot script has cleaned up the VM, right before sysprep
+	HookPreSysprep HookPoint = "pre-sysprep"
+)
+
+var hookPoints = []HookPoint{HookPreSetup, HookPostSetup, HookPreSysprep}
+
+const hooksDir = provisionDir + "hooks\\"
+
+// Hooks holds the local paths of the scripts to run at each hook point, in the order they run
+type Hooks map[HookPoint][]string
+
+// LoadHooks finds the PowerShell scripts in the pre-setup, post-setup and pre-sysprep
+// subdirectories of dir. The scripts of a hook point run in lexical order of their file names.
+func LoadHooks(dir string) (Hooks, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read hooks directory: %s", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("hooks directory %s is not a directory", dir)
+	}
+
+	hooks := Hooks{}
+	for _, point := range hookPoints {
+		pointDir := filepath.Join(dir, string(point))
+		entries, err := ioutil.ReadDir(pointDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read hooks directory: %s", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".ps1") {
+				continue
+			}
+			hooks[point] = append(hooks[point], filepath.Join(pointDir, entry.Name()))
+		}
+	}
+
+	if len(hooks) == 0 {
+		return nil, fmt.Errorf("hooks directory %s has no .ps1 scripts in its pre-setup, post-setup or pre-sysprep directories", dir)
+	}
+	return hooks, nil
+}
+
+func hookDestination(point HookPoint, script string) string {
+	return hooksDir + string(point) + "\\" + filepath.Base(script)
+}
+
+func (h Hooks) destinations() []string {
+	var destinations []string
+	for _, point := range hookPoints {
+		for _, script := range h[point] {
+			destinations = append(destinations, hookDestination(point, script))
+		}
+	}
+	return destinations
+}
+
+func (h Hooks) uploadPlan() []string {
+	var plan []string
+	for _, point := range hookPoints {
+		if len(h[point]) == 0 {
+			continue
+		}
+
+		plan = append(plan, "create directory "+hooksDir+string(point))
+		for _, script := range h[point] {
+			plan = append(plan, fmt.Sprintf("upload %s to %s", script, hookDestination(point, script)))
+		}
+	}
+	return plan
+}
+
+func (h Hooks) runPlan(point HookPoint) []string {
+	var plan []string
+	for _, script := range h[point] {
+		plan = append(plan, fmt.Sprintf("run %s hook %s", point, hookDestination(point, script)))
+	}
+	return plan
+}
+
+func (c *VMConstruct) uploadHooks() error {
+	c.messenger.UploadHooksStarted()
+	for _, point := range hookPoints {
+		if len(c.Hooks[point]) == 0 {
+			continue
+		}
+
+		err := c.makeDirectory(hooksDir + string(point))
+		if err != nil {
+			return err
+		}
+		for _, script := range c.Hooks[point] {
+			err = c.upload(script, hookDestination(point, script), nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	c.messenger.UploadHooksSucceeded()
+	return nil
+}
+
+func (c *VMConstruct) runHooks(point HookPoint) error {
+	for _, script := range c.Hooks[point] {
+		name := filepath.Base(script)
+		c.messenger.RunHookStarted(string(point), name)
+
+		command := fmt.Sprintf(`powershell.exe -NoProfile -ExecutionPolicy Bypass -File "%s"`, hookDestination(point, script))
+		exitCode, err := c.remoteManager.ExecuteCommandWithTimeout(command, c.untilDeadline(c.HookTimeout))
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("exit code %d", exitCode)
+		}
+		if err != nil {
+			return fmt.Errorf("%s hook %s failed: %s", point, name, err)
+		}
+
+		c.messenger.RunHookSucceeded()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package construct_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry-incubator/stembuild/construct"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadHooks", func() {
+	var dir string
+
+	writeScript := func(path string) {
+		path = filepath.Join(dir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte("exit 0"), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "hooks")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("finds the scripts of every hook point in file name order", func() {
+		writeScript("pre-setup/20-agent.ps1")
+		writeScript("pre-setup/10-certs.PS1")
+		writeScript("pre-sysprep/seal.ps1")
+
+		hooks, err := LoadHooks(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(hooks).To(Equal(Hooks{
+			HookPreSetup: {
+				filepath.Join(dir, "pre-setup", "10-certs.PS1"),
+				filepath.Join(dir, "pre-setup", "20-agent.ps1"),
+			},
+			HookPreSysprep: {filepath.Join(dir, "pre-sysprep", "seal.ps1")},
+		}))
+	})
+
+	It("ignores anything that is not a PowerShell script", func() {
+		writeScript("post-setup/tweaks.ps1")
+		writeScript("post-setup/README.md")
+		writeScript("post-setup/lib/helpers.ps1")
+		writeScript("unknown/other.ps1")
+
+		hooks, err := LoadHooks(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(hooks).To(Equal(Hooks{
+			HookPostSetup: {filepath.Join(dir, "post-setup", "tweaks.ps1")},
+		}))
+	})
+
+	It("returns an error when there are no scripts to run", func() {
+		writeScript("notes.txt")
+
+		_, err := LoadHooks(dir)
+		Expect(err).To(MatchError(ContainSubstring("has no .ps1 scripts")))
+	})
+
+	It("returns an error when the directory does not exist", func() {
+		_, err := LoadHooks(filepath.Join(dir, "missing"))
+		Expect(err).To(MatchError(ContainSubstring("unable to read hooks directory")))
+	})
+})
@@ -0,0 +1,323 @@
+package construct
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
+)
+
+const (
+	phaseResume             = "resume"
+	phaseCollectDiagnostics = "collect-diagnostics"
+	phaseCloneVM            = "clone-vm"
+	phaseCreateSnapshot     = "create-snapshot"
+	phaseRevertSnapshot     = "revert-snapshot"
+	phaseRemoveSnapshot     = "remove-snapshot"
+	phaseDryRun             = "dry-run"
+	phaseArtifactTransport  = "artifact-transport"
+)
+
+// JSONMessenger reports construct progress as events instead of human readable text.
+type JSONMessenger struct {
+	sink      events.Sink
+	uploading string
+	// reported is how much of the upload the last UploadFileProgress event reported
+	reported  int64
+	hookPhase string
+	hook      string
+}
+
+func NewJSONMessenger(sink events.Sink) *JSONMessenger {
+	return &JSONMessenger{sink: sink}
+}
+
+func (m *JSONMessenger) emit(phase Phase, event string, status events.Status) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(phase), Event: event, Status: status})
+}
+
+func (m *JSONMessenger) CreateProvisionDirStarted() {
+	m.emit(PhaseCreateProvisionDir, "CreateProvisionDirStarted", events.Started)
+}
+
+func (m *JSONMessenger) CreateProvisionDirSucceeded() {
+	m.emit(PhaseCreateProvisionDir, "CreateProvisionDirSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) UploadArtifactsStarted() {
+	m.emit(PhaseUploadArtifacts, "UploadArtifactsStarted", events.Started)
+}
+
+func (m *JSONMessenger) UploadArtifactsSucceeded() {
+	m.emit(PhaseUploadArtifacts, "UploadArtifactsSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) UploadFileStarted(artifact string) {
+	m.uploading = artifact
+	m.reported = 0
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseUploadArtifacts), Event: "UploadFileStarted", Status: events.Started, Target: artifact})
+}
+
+// UploadFileProgress emits an info event each time another tenth of the file has been sent
+func (m *JSONMessenger) UploadFileProgress(sent, total int64, bytesPerSecond float64, eta time.Duration) {
+	if total <= 0 || sent >= total || sent*10/total <= m.reported*10/total {
+		return
+	}
+	m.reported = sent
+	m.sink.Emit(events.Event{
+		Command:        "construct",
+		Phase:          string(PhaseUploadArtifacts),
+		Event:          "UploadFileProgress",
+		Status:         events.Info,
+		Target:         m.uploading,
+		Message:        fmt.Sprintf("%d%%", sent*100/total),
+		BytesSent:      sent,
+		BytesTotal:     total,
+		BytesPerSecond: bytesPerSecond,
+		ETASeconds:     eta.Seconds(),
+	})
+}
+
+func (m *JSONMessenger) UploadFileSucceeded() {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseUploadArtifacts), Event: "UploadFileSucceeded", Status: events.Succeeded, Target: m.uploading})
+	m.uploading = ""
+}
+
+func (m *JSONMessenger) EnableWinRMStarted() {
+	m.emit(PhaseEnableWinRM, "EnableWinRMStarted", events.Started)
+}
+
+func (m *JSONMessenger) EnableWinRMSucceeded() {
+	m.emit(PhaseEnableWinRM, "EnableWinRMSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) ValidateVMConnectionStarted() {
+	m.emit(PhaseValidateVMConnection, "ValidateVMConnectionStarted", events.Started)
+}
+
+func (m *JSONMessenger) ValidateVMConnectionSucceeded() {
+	m.emit(PhaseValidateVMConnection, "ValidateVMConnectionSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) ExtractArtifactsStarted() {
+	m.emit(PhaseExtractArtifacts, "ExtractArtifactsStarted", events.Started)
+}
+
+func (m *JSONMessenger) ExtractArtifactsSucceeded() {
+	m.emit(PhaseExtractArtifacts, "ExtractArtifactsSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) LogOutUsersStarted() {
+	m.emit(PhaseLogOutUsers, "LogOutUsersStarted", events.Started)
+}
+
+func (m *JSONMessenger) LogOutUsersSucceeded() {
+	m.emit(PhaseLogOutUsers, "LogOutUsersSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) ExecuteSetupScriptStarted() {
+	m.emit(PhaseExecuteSetupScript, "ExecuteSetupScriptStarted", events.Started)
+}
+
+func (m *JSONMessenger) ExecuteSetupScriptSucceeded() {
+	m.emit(PhaseExecuteSetupScript, "ExecuteSetupScriptSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) WinRMDisconnectedForReboot() {
+	m.emit(PhaseExecuteSetupScript, "WinRMDisconnectedForReboot", events.Info)
+}
+
+func (m *JSONMessenger) RebootHasStarted() {
+	m.emit(PhaseReboot, "RebootHasStarted", events.Started)
+}
+
+func (m *JSONMessenger) RebootHasFinished() {
+	m.emit(PhaseReboot, "RebootHasFinished", events.Succeeded)
+}
+
+func (m *JSONMessenger) ExecutePostRebootScriptStarted() {
+	m.emit(PhaseExecutePostRebootScript, "ExecutePostRebootScriptStarted", events.Started)
+}
+
+func (m *JSONMessenger) ExecutePostRebootScriptSucceeded() {
+	m.emit(PhaseExecutePostRebootScript, "ExecutePostRebootScriptSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) ExecutePostRebootWarning(warning string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseExecutePostRebootScript), Event: "ExecutePostRebootWarning", Status: events.Warning, Message: warning})
+}
+
+func (m *JSONMessenger) WaitingForShutdown() {
+	m.emit(PhaseShutdown, "WaitingForShutdown", events.Info)
+}
+
+func (m *JSONMessenger) ShutdownCompleted() {
+	m.emit(PhaseShutdown, "ShutdownCompleted", events.Succeeded)
+}
+
+func (m *JSONMessenger) NoCheckpointFound() {
+	m.emit(phaseResume, "NoCheckpointFound", events.Info)
+}
+
+func (m *JSONMessenger) CheckpointsDisabled(err error) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseResume, Event: "CheckpointsDisabled", Status: events.Warning, Error: err.Error()})
+}
+
+func (m *JSONMessenger) CheckpointNotConfirmed(phase string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseResume, Event: "CheckpointNotConfirmed", Status: events.Warning, Target: phase})
+}
+
+func (m *JSONMessenger) ResumingFromCheckpoint(phase string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseResume, Event: "ResumingFromCheckpoint", Status: events.Info, Target: phase})
+}
+
+func (m *JSONMessenger) CollectDiagnosticsStarted() {
+	m.emit(phaseCollectDiagnostics, "CollectDiagnosticsStarted", events.Started)
+}
+
+func (m *JSONMessenger) CollectDiagnosticsSucceeded() {
+	m.emit(phaseCollectDiagnostics, "CollectDiagnosticsSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) CollectDiagnosticsFailed(err error) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseCollectDiagnostics, Event: "CollectDiagnosticsFailed", Status: events.Failed, Error: err.Error()})
+}
+
+func (m *JSONMessenger) CloneVMStarted(clonePath string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseCloneVM, Event: "CloneVMStarted", Status: events.Started, Message: clonePath})
+}
+
+func (m *JSONMessenger) CloneVMSucceeded() {
+	m.emit(phaseCloneVM, "CloneVMSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) CloneIPDiscovered(ip string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseCloneVM, Event: "CloneIPDiscovered", Status: events.Info, Message: ip})
+}
+
+func (m *JSONMessenger) CreateSnapshotStarted(name string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseCreateSnapshot, Event: "CreateSnapshotStarted", Status: events.Started, Message: name})
+}
+
+func (m *JSONMessenger) CreateSnapshotSucceeded() {
+	m.emit(phaseCreateSnapshot, "CreateSnapshotSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) UsingExistingSnapshot(name string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseCreateSnapshot, Event: "UsingExistingSnapshot", Status: events.Info, Message: name})
+}
+
+func (m *JSONMessenger) RevertSnapshotStarted(name string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseRevertSnapshot, Event: "RevertSnapshotStarted", Status: events.Started, Message: name})
+}
+
+func (m *JSONMessenger) RevertSnapshotSucceeded() {
+	m.emit(phaseRevertSnapshot, "RevertSnapshotSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) RevertSnapshotFailed(err error) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseRevertSnapshot, Event: "RevertSnapshotFailed", Status: events.Failed, Error: err.Error()})
+}
+
+func (m *JSONMessenger) RemoveSnapshotStarted(name string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseRemoveSnapshot, Event: "RemoveSnapshotStarted", Status: events.Started, Message: name})
+}
+
+func (m *JSONMessenger) RemoveSnapshotSucceeded() {
+	m.emit(phaseRemoveSnapshot, "RemoveSnapshotSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) UploadHooksStarted() {
+	m.emit(PhaseUploadHooks, "UploadHooksStarted", events.Started)
+}
+
+func (m *JSONMessenger) UploadHooksSucceeded() {
+	m.emit(PhaseUploadHooks, "UploadHooksSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) RunHookStarted(point, name string) {
+	m.hookPhase = point + "-hooks"
+	m.hook = name
+	m.sink.Emit(events.Event{Command: "construct", Phase: m.hookPhase, Event: "RunHookStarted", Status: events.Started, Message: name})
+}
+
+func (m *JSONMessenger) RunHookSucceeded() {
+	m.sink.Emit(events.Event{Command: "construct", Phase: m.hookPhase, Event: "RunHookSucceeded", Status: events.Succeeded, Message: m.hook})
+	m.hook = ""
+}
+
+func (m *JSONMessenger) ExecuteSysprepScriptStarted() {
+	m.emit(PhaseSysprep, "ExecuteSysprepScriptStarted", events.Started)
+}
+
+func (m *JSONMessenger) ExecuteSysprepScriptSucceeded() {
+	m.emit(PhaseSysprep, "ExecuteSysprepScriptSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) DryRunStarted() {
+	m.emit(phaseDryRun, "DryRunStarted", events.Started)
+}
+
+func (m *JSONMessenger) PlannedOperation(phase, operation string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phase, Event: "PlannedOperation", Status: events.Info, Message: operation})
+}
+
+func (m *JSONMessenger) DryRunSucceeded() {
+	m.emit(phaseDryRun, "DryRunSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) ArtifactTransportFailed(transport, fallback string, err error) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phaseArtifactTransport, Event: "ArtifactTransportFailed", Status: events.Warning, Target: transport, Message: "retrying over " + fallback, Error: err.Error()})
+}
+
+func (m *JSONMessenger) ConstructInterrupted(phase, state string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: phase, Event: "ConstructInterrupted", Status: events.Failed, Message: state})
+}
+
+func (m *JSONMessenger) InstallWindowsUpdatesStarted(iteration int) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseWindowsUpdates), Event: "InstallWindowsUpdatesStarted", Status: events.Started, Message: fmt.Sprintf("batch %d", iteration)})
+}
+
+func (m *JSONMessenger) WindowsUpdateInstalled(kb, title string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseWindowsUpdates), Event: "WindowsUpdateInstalled", Status: events.Info, Target: kb, Message: title})
+}
+
+func (m *JSONMessenger) WindowsUpdatesRebootStarted() {
+	m.emit(PhaseWindowsUpdates, "WindowsUpdatesRebootStarted", events.Started)
+}
+
+func (m *JSONMessenger) WindowsUpdatesRebootFinished() {
+	m.emit(PhaseWindowsUpdates, "WindowsUpdatesRebootFinished", events.Succeeded)
+}
+
+func (m *JSONMessenger) WindowsUpdatesIterationLimitReached(iterations int) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseWindowsUpdates), Event: "WindowsUpdatesIterationLimitReached", Status: events.Warning, Message: fmt.Sprintf("Windows Updates may still be available after %d batches", iterations)})
+}
+
+func (m *JSONMessenger) InstallWindowsUpdatesSucceeded(installed int) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseWindowsUpdates), Event: "InstallWindowsUpdatesSucceeded", Status: events.Succeeded, Message: fmt.Sprintf("%d installed", installed)})
+}
+
+func (m *JSONMessenger) SetProxyStarted() {
+	m.emit(PhaseSetProxy, "SetProxyStarted", events.Started)
+}
+
+func (m *JSONMessenger) SetProxySucceeded() {
+	m.emit(PhaseSetProxy, "SetProxySucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) ClearProxyStarted() {
+	m.emit(PhaseClearProxy, "ClearProxyStarted", events.Started)
+}
+
+func (m *JSONMessenger) ClearProxySucceeded() {
+	m.emit(PhaseClearProxy, "ClearProxySucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) RecordHardeningProfileStarted(profile string) {
+	m.sink.Emit(events.Event{Command: "construct", Phase: string(PhaseRecordHardeningProfile), Event: "RecordHardeningProfileStarted", Status: events.Started, Message: profile})
+}
+
+func (m *JSONMessenger) RecordHardeningProfileSucceeded() {
+	m.emit(PhaseRecordHardeningProfile, "RecordHardeningProfileSucceeded", events.Succeeded)
+}
@@ -0,0 +1,143 @@
+package construct_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/construct"
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONMessenger", func() {
+	var (
+		sink *eventsfakes.FakeSink
+		m    *construct.JSONMessenger
+	)
+
+	BeforeEach(func() {
+		sink = &eventsfakes.FakeSink{}
+		m = construct.NewJSONMessenger(sink)
+	})
+
+	It("emits started and succeeded events for a phase", func() {
+		m.EnableWinRMStarted()
+		m.EnableWinRMSucceeded()
+
+		Expect(sink.EmitCallCount()).To(Equal(2))
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "enable-winrm", Event: "EnableWinRMStarted", Status: events.Started}))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "construct", Phase: "enable-winrm", Event: "EnableWinRMSucceeded", Status: events.Succeeded}))
+	})
+
+	It("names the uploaded artifact on both upload events", func() {
+		m.UploadFileStarted("LGPO")
+		m.UploadFileSucceeded()
+
+		Expect(sink.EmitArgsForCall(0).Target).To(Equal("LGPO"))
+		Expect(sink.EmitArgsForCall(1).Target).To(Equal("LGPO"))
+		Expect(sink.EmitArgsForCall(1).Phase).To(Equal("upload-artifacts"))
+	})
+
+	It("emits the progress of an upload each tenth of the file", func() {
+		m.UploadFileStarted("LGPO")
+		m.UploadFileProgress(100, 1000, 50, 18*time.Second)
+		m.UploadFileProgress(150, 1000, 50, 17*time.Second)
+		m.UploadFileProgress(450, 1000, 75, 8*time.Second)
+		m.UploadFileProgress(1000, 1000, 100, 0)
+
+		Expect(sink.EmitCallCount()).To(Equal(3))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{
+			Command:        "construct",
+			Phase:          "upload-artifacts",
+			Event:          "UploadFileProgress",
+			Status:         events.Info,
+			Target:         "LGPO",
+			Message:        "10%",
+			BytesSent:      100,
+			BytesTotal:     1000,
+			BytesPerSecond: 50,
+			ETASeconds:     18,
+		}))
+		Expect(sink.EmitArgsForCall(2).Message).To(Equal("45%"))
+	})
+
+	It("emits post reboot warnings with their message", func() {
+		m.ExecutePostRebootWarning("some warning")
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("execute-post-reboot-script"))
+		Expect(event.Status).To(Equal(events.Warning))
+		Expect(event.Message).To(Equal("some warning"))
+	})
+
+	It("emits a warning with the error when checkpoints are disabled", func() {
+		m.CheckpointsDisabled(errors.New("$HOME is not defined"))
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "resume", Event: "CheckpointsDisabled", Status: events.Warning, Error: "$HOME is not defined"}))
+	})
+
+	It("emits the error when diagnostics cannot be collected", func() {
+		m.CollectDiagnosticsFailed(errors.New("no guest"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("collect-diagnostics"))
+		Expect(event.Status).To(Equal(events.Failed))
+		Expect(event.Error).To(Equal("no guest"))
+	})
+
+	It("names the snapshot in the message so that durations still pair up", func() {
+		m.CreateSnapshotStarted("pre-construct")
+		m.CreateSnapshotSucceeded()
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "create-snapshot", Event: "CreateSnapshotStarted", Status: events.Started, Message: "pre-construct"}))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "construct", Phase: "create-snapshot", Event: "CreateSnapshotSucceeded", Status: events.Succeeded}))
+	})
+
+	It("emits hooks under the phase of their hook point", func() {
+		m.RunHookStarted("post-setup", "tweaks.ps1")
+		m.RunHookSucceeded()
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "post-setup-hooks", Event: "RunHookStarted", Status: events.Started, Message: "tweaks.ps1"}))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "construct", Phase: "post-setup-hooks", Event: "RunHookSucceeded", Status: events.Succeeded, Message: "tweaks.ps1"}))
+	})
+
+	It("emits planned changes as info events of the phase that would make them", func() {
+		m.DryRunStarted()
+		m.PlannedOperation("upload-hooks", "create directory C:\\provision\\hooks\\pre-setup")
+		m.DryRunSucceeded()
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "dry-run", Event: "DryRunStarted", Status: events.Started}))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "construct", Phase: "upload-hooks", Event: "PlannedOperation", Status: events.Info, Message: "create directory C:\\provision\\hooks\\pre-setup"}))
+		Expect(sink.EmitArgsForCall(2)).To(Equal(events.Event{Command: "construct", Phase: "dry-run", Event: "DryRunSucceeded", Status: events.Succeeded}))
+	})
+
+	It("emits a failed artifact transport as a warning naming the fallback", func() {
+		m.ArtifactTransportFailed("WinRM", "vSphere guest operations", errors.New("connection refused"))
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "artifact-transport", Event: "ArtifactTransportFailed", Status: events.Warning, Target: "WinRM", Message: "retrying over vSphere guest operations", Error: "connection refused"}))
+	})
+
+	It("names each installed Windows Update as the target of its event", func() {
+		m.InstallWindowsUpdatesStarted(2)
+		m.WindowsUpdateInstalled("KB4534273", "2020-01 Cumulative Update")
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "windows-updates", Event: "InstallWindowsUpdatesStarted", Status: events.Started, Message: "batch 2"}))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "construct", Phase: "windows-updates", Event: "WindowsUpdateInstalled", Status: events.Info, Target: "KB4534273", Message: "2020-01 Cumulative Update"}))
+	})
+
+	It("emits an interrupted construct as a failure of the phase it was in", func() {
+		m.ConstructInterrupted("reboot", "The VM was not changed.")
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "reboot", Event: "ConstructInterrupted", Status: events.Failed, Message: "The VM was not changed."}))
+	})
+
+	It("names the hardening profile it records", func() {
+		m.RecordHardeningProfileStarted("minimal")
+		m.RecordHardeningProfileSucceeded()
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "construct", Phase: "record-hardening-profile", Event: "RecordHardeningProfileStarted", Status: events.Started, Message: "minimal"}))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "construct", Phase: "record-hardening-profile", Event: "RecordHardeningProfileSucceeded", Status: events.Succeeded}))
+	})
+})
@@ -3,15 +3,30 @@ package construct
 import (
 	"fmt"
 	"io"
+	"os"
 	"time"
 )
 
 type Messenger struct {
 	out io.Writer
+	// tty redraws the progress of an upload in place instead of printing a line every quarter
+	tty bool
+	// uploading is the artifact being uploaded, and reported how much of it has been reported
+	uploading string
+	reported  int64
 }
 
 func NewMessenger(out io.Writer) *Messenger {
-	return &Messenger{out}
+	return &Messenger{out: out, tty: isTerminal(out)}
+}
+
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func (m *Messenger) EnableWinRMStarted() {
@@ -39,7 +54,7 @@ func (m *Messenger) CreateProvisionDirSucceeded() {
 }
 
 func (m *Messenger) UploadArtifactsStarted() {
-	m.out.Write([]byte("\nTransferring ~20 MB to the Windows VM. Depending on your connection, the transfer may take 15-45 minutes\n"))
+	m.out.Write([]byte("\nTransferring the stemcell preparation artifacts to the Windows VM. Progress is shown as each file is uploaded\n"))
 }
 
 func (m *Messenger) UploadArtifactsSucceeded() {
@@ -86,11 +101,42 @@ func (m *Messenger) ExecutePostRebootWarning(warning string) {
 }
 
 func (m *Messenger) UploadFileStarted(artifact string) {
+	m.uploading = artifact
+	m.reported = 0
 	m.out.Write([]byte(fmt.Sprintf("\tUploading %s to target VM...", artifact)))
 }
 
+// UploadFileProgress redraws the upload line on a terminal. Elsewhere it prints a line each time another quarter
+// of the file has been sent, leaving the end of the upload to UploadFileSucceeded.
+func (m *Messenger) UploadFileProgress(sent, total int64, bytesPerSecond float64, eta time.Duration) {
+	if total <= 0 {
+		return
+	}
+	progress := formatUploadProgress(sent, total, bytesPerSecond, eta)
+	if m.tty {
+		m.out.Write([]byte(fmt.Sprintf("\r\tUploading %s to target VM... %s\x1b[K", m.uploading, progress)))
+		m.reported = sent
+		return
+	}
+	quarter := sent * 4 / total
+	if sent >= total || quarter <= m.reported*4/total {
+		return
+	}
+	m.out.Write([]byte(fmt.Sprintf("\n\t\t%s", progress)))
+	m.reported = sent
+}
+
 func (m *Messenger) UploadFileSucceeded() {
-	m.out.Write([]byte("succeeded.\n"))
+	if m.reported == 0 {
+		m.out.Write([]byte("succeeded.\n"))
+		return
+	}
+	if m.tty {
+		m.out.Write([]byte(fmt.Sprintf("\r\tUploading %s to target VM...succeeded.\x1b[K\n", m.uploading)))
+	} else {
+		m.out.Write([]byte(fmt.Sprintf("\n\tUploading %s to target VM...succeeded.\n", m.uploading)))
+	}
+	m.reported = 0
 }
 
 func (m *Messenger) LogOutUsersStarted() {
@@ -133,3 +179,167 @@ func (m *Messenger) WinRMDisconnectedForReboot() {
 	m.out.Write([]byte("\nWinRM has been disconnected so the VM can reboot.\n"))
 
 }
+
+func (m *Messenger) NoCheckpointFound() {
+	m.out.Write([]byte("\nNo checkpoint found for this VM, starting construct from the beginning.\n"))
+}
+
+func (m *Messenger) CheckpointsDisabled(err error) {
+	m.out.Write([]byte(fmt.Sprintf("\nWarning: construct checkpoints are disabled, so this construct cannot be resumed: %s\n", err)))
+}
+
+func (m *Messenger) CheckpointNotConfirmed(phase string) {
+	m.out.Write([]byte(fmt.Sprintf("\nCould not confirm phase '%s' on the guest VM, it will be run again.\n", phase)))
+}
+
+func (m *Messenger) ResumingFromCheckpoint(phase string) {
+	m.out.Write([]byte(fmt.Sprintf("\nResuming construct after phase '%s'.\n", phase)))
+}
+
+func (m *Messenger) CollectDiagnosticsStarted() {
+	m.out.Write([]byte("\nCollecting diagnostics from the guest VM..."))
+}
+
+func (m *Messenger) CollectDiagnosticsSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) CollectDiagnosticsFailed(err error) {
+	m.out.Write([]byte(fmt.Sprintf("failed: %s\n", err)))
+}
+
+func (m *Messenger) CloneVMStarted(clonePath string) {
+	m.out.Write([]byte(fmt.Sprintf("\nCloning VM to %s...", clonePath)))
+}
+
+func (m *Messenger) CloneVMSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) CloneIPDiscovered(ip string) {
+	m.out.Write([]byte(fmt.Sprintf("\nThe clone is reachable at %s\n", ip)))
+}
+
+func (m *Messenger) CreateSnapshotStarted(name string) {
+	m.out.Write([]byte(fmt.Sprintf("\nTaking snapshot %s of the VM...", name)))
+}
+
+func (m *Messenger) CreateSnapshotSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) UsingExistingSnapshot(name string) {
+	m.out.Write([]byte(fmt.Sprintf("\nThe VM already has snapshot %s, it will be reverted to if construct fails.\n", name)))
+}
+
+func (m *Messenger) RevertSnapshotStarted(name string) {
+	m.out.Write([]byte(fmt.Sprintf("\nReverting the VM to snapshot %s...", name)))
+}
+
+func (m *Messenger) RevertSnapshotSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) RevertSnapshotFailed(err error) {
+	m.out.Write([]byte(fmt.Sprintf("failed: %s\n", err)))
+}
+
+func (m *Messenger) RemoveSnapshotStarted(name string) {
+	m.out.Write([]byte(fmt.Sprintf("\nDeleting snapshot %s...", name)))
+}
+
+func (m *Messenger) RemoveSnapshotSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) UploadHooksStarted() {
+	m.out.Write([]byte("\nUploading hooks to target VM..."))
+}
+
+func (m *Messenger) UploadHooksSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) RunHookStarted(point, name string) {
+	m.out.Write([]byte(fmt.Sprintf("\nRunning %s hook %s...", point, name)))
+}
+
+func (m *Messenger) RunHookSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) ExecuteSysprepScriptStarted() {
+	m.out.Write([]byte("\nExecuting sysprep...\n"))
+}
+
+func (m *Messenger) ExecuteSysprepScriptSucceeded() {
+	m.out.Write([]byte("\nFinished executing sysprep.\n"))
+}
+
+func (m *Messenger) DryRunStarted() {
+	m.out.Write([]byte("\nDry run: no changes will be made. Construct would, in order:\n"))
+}
+
+func (m *Messenger) PlannedOperation(phase, operation string) {
+	m.out.Write([]byte(fmt.Sprintf("  [%s] %s\n", phase, operation)))
+}
+
+func (m *Messenger) DryRunSucceeded() {
+	m.out.Write([]byte("\nDry run complete, the VM was not changed.\n"))
+}
+
+func (m *Messenger) ConstructInterrupted(phase, state string) {
+	m.out.Write([]byte(fmt.Sprintf("\nConstruct was interrupted during phase '%s'. %s\n", phase, state)))
+}
+
+func (m *Messenger) ArtifactTransportFailed(transport, fallback string, err error) {
+	m.out.Write([]byte(fmt.Sprintf("\nFailed over %s: %s\nRetrying over %s... ", transport, err, fallback)))
+}
+
+func (m *Messenger) InstallWindowsUpdatesStarted(iteration int) {
+	m.out.Write([]byte(fmt.Sprintf("\nInstalling Windows Updates, batch %d...\n", iteration)))
+}
+
+func (m *Messenger) WindowsUpdateInstalled(kb, title string) {
+	m.out.Write([]byte(fmt.Sprintf("\tInstalled %s %s\n", kb, title)))
+}
+
+func (m *Messenger) WindowsUpdatesRebootStarted() {
+	m.out.Write([]byte("\nRebooting the VM to finish installing Windows Updates...\n"))
+}
+
+func (m *Messenger) WindowsUpdatesRebootFinished() {
+	m.out.Write([]byte("\nThe reboot has finished.\n"))
+}
+
+func (m *Messenger) WindowsUpdatesIterationLimitReached(iterations int) {
+	m.out.Write([]byte(fmt.Sprintf("\nWarning: Windows Updates may still be available after %d batches, continuing without them.\n", iterations)))
+}
+
+func (m *Messenger) InstallWindowsUpdatesSucceeded(installed int) {
+	m.out.Write([]byte(fmt.Sprintf("\nFinished installing Windows Updates, %d installed.\n", installed)))
+}
+
+func (m *Messenger) SetProxyStarted() {
+	m.out.Write([]byte("\nSetting the proxy of the guest VM..."))
+}
+
+func (m *Messenger) SetProxySucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) ClearProxyStarted() {
+	m.out.Write([]byte("\nClearing the proxy of the guest VM..."))
+}
+
+func (m *Messenger) ClearProxySucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) RecordHardeningProfileStarted(profile string) {
+	m.out.Write([]byte(fmt.Sprintf("\nRecording the %s hardening profile in the VM configuration...", profile)))
+}
+
+func (m *Messenger) RecordHardeningProfileSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
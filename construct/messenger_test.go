@@ -1,7 +1,9 @@
 package construct_test
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cloudfoundry-incubator/stembuild/construct"
 	. "github.com/onsi/ginkgo"
@@ -93,7 +95,7 @@ var _ = Describe("Messenger", func() {
 			m := construct.NewMessenger(buf)
 			m.UploadArtifactsStarted()
 
-			Expect(buf).To(gbytes.Say("\nTransferring ~20 MB to the Windows VM. Depending on your connection, the transfer may take 15-45 minutes\n"))
+			Expect(buf).To(gbytes.Say("\nTransferring the stemcell preparation artifacts to the Windows VM. Progress is shown as each file is uploaded\n"))
 		})
 
 		It("writes the succeeded message to the writer", func() {
@@ -236,6 +238,32 @@ var _ = Describe("Messenger", func() {
 
 			Expect(buf).To(gbytes.Say("Uploading some third artifact to target VM...succeed."))
 		})
+
+		It("prints the progress of an upload each quarter when the output is not a terminal", func() {
+			m := construct.NewMessenger(buf)
+			m.UploadFileStarted("LGPO")
+			m.UploadFileProgress(1<<20, 4<<20, 512*1024, 6*time.Second)
+			m.UploadFileProgress(1<<20+1, 4<<20, 512*1024, 6*time.Second)
+			m.UploadFileProgress(3<<20, 4<<20, 1<<20, time.Second)
+			m.UploadFileProgress(4<<20, 4<<20, 1<<20, 0)
+			m.UploadFileSucceeded()
+
+			Expect(string(buf.Contents())).To(Equal("\tUploading LGPO to target VM..." +
+				"\n\t\t25% (1.0 MB of 4.0 MB, 512.0 KB/s, ETA 6s)" +
+				"\n\t\t75% (3.0 MB of 4.0 MB, 1.0 MB/s, ETA 1s)" +
+				"\n\tUploading LGPO to target VM...succeeded.\n"))
+		})
+
+		It("redraws the progress of an upload on a terminal", func() {
+			m := construct.NewTerminalMessenger(buf)
+			m.UploadFileStarted("LGPO")
+			m.UploadFileProgress(512, 2048, 256, 6*time.Second)
+			m.UploadFileSucceeded()
+
+			Expect(string(buf.Contents())).To(Equal("\tUploading LGPO to target VM..." +
+				"\r\tUploading LGPO to target VM... 25% (512 B of 2.0 KB, 256 B/s, ETA 6s)\x1b[K" +
+				"\r\tUploading LGPO to target VM...succeeded.\x1b[K\n"))
+		})
 	})
 
 	Describe("validate OS", func() {
@@ -286,4 +314,176 @@ var _ = Describe("Messenger", func() {
 
 	})
 
+	Describe("Diagnostics messages", func() {
+		It("writes the started and succeeded messages on one line", func() {
+			m := construct.NewMessenger(buf)
+			m.CollectDiagnosticsStarted()
+			m.CollectDiagnosticsSucceeded()
+
+			Expect(buf).To(gbytes.Say("\nCollecting diagnostics from the guest VM...succeeded.\n"))
+		})
+
+		It("writes the failed message with the error", func() {
+			m := construct.NewMessenger(buf)
+			m.CollectDiagnosticsStarted()
+			m.CollectDiagnosticsFailed(errors.New("disk full"))
+
+			Expect(buf).To(gbytes.Say("Collecting diagnostics from the guest VM...failed: disk full\n"))
+		})
+	})
+
+	Describe("Checkpoint messages", func() {
+		It("writes the no checkpoint found message to the writer", func() {
+			m := construct.NewMessenger(buf)
+			m.NoCheckpointFound()
+
+			Expect(buf).To(gbytes.Say("No checkpoint found for this VM, starting construct from the beginning.\n"))
+		})
+
+		It("writes the checkpoints disabled warning with the error", func() {
+			m := construct.NewMessenger(buf)
+			m.CheckpointsDisabled(errors.New("$HOME is not defined"))
+
+			Expect(buf).To(gbytes.Say("Warning: construct checkpoints are disabled, so this construct cannot be resumed: \\$HOME is not defined\n"))
+		})
+
+		It("writes the checkpoint not confirmed message to the writer", func() {
+			m := construct.NewMessenger(buf)
+			m.CheckpointNotConfirmed("upload-artifacts")
+
+			Expect(buf).To(gbytes.Say("Could not confirm phase 'upload-artifacts' on the guest VM, it will be run again.\n"))
+		})
+
+		It("writes the resuming message to the writer", func() {
+			m := construct.NewMessenger(buf)
+			m.ResumingFromCheckpoint("reboot")
+
+			Expect(buf).To(gbytes.Say("Resuming construct after phase 'reboot'.\n"))
+		})
+	})
+
+	Describe("Clone messages", func() {
+		It("writes the clone messages on one line", func() {
+			m := construct.NewMessenger(buf)
+			m.CloneVMStarted("/dc/vm/clone")
+			m.CloneVMSucceeded()
+
+			Expect(buf).To(gbytes.Say("\nCloning VM to /dc/vm/clone...succeeded.\n"))
+		})
+
+		It("writes the discovered IP of the clone", func() {
+			m := construct.NewMessenger(buf)
+			m.CloneIPDiscovered("10.0.0.7")
+
+			Expect(buf).To(gbytes.Say("\nThe clone is reachable at 10.0.0.7\n"))
+		})
+	})
+
+	Describe("Snapshot messages", func() {
+		It("writes the snapshot messages on one line", func() {
+			m := construct.NewMessenger(buf)
+			m.CreateSnapshotStarted("pre-construct")
+			m.CreateSnapshotSucceeded()
+
+			Expect(buf).To(gbytes.Say("\nTaking snapshot pre-construct of the VM...succeeded.\n"))
+		})
+
+		It("writes the existing snapshot message", func() {
+			m := construct.NewMessenger(buf)
+			m.UsingExistingSnapshot("pre-construct")
+
+			Expect(buf).To(gbytes.Say("\nThe VM already has snapshot pre-construct, it will be reverted to if construct fails.\n"))
+		})
+
+		It("writes why a revert failed", func() {
+			m := construct.NewMessenger(buf)
+			m.RevertSnapshotStarted("pre-construct")
+			m.RevertSnapshotFailed(errors.New("task failed"))
+
+			Expect(buf).To(gbytes.Say("\nReverting the VM to snapshot pre-construct...failed: task failed\n"))
+		})
+	})
+
+	Describe("Hook messages", func() {
+		It("writes the hook point and name of the running hook", func() {
+			m := construct.NewMessenger(buf)
+			m.RunHookStarted("pre-setup", "10-certs.ps1")
+			m.RunHookSucceeded()
+
+			Expect(buf).To(gbytes.Say("\nRunning pre-setup hook 10-certs.ps1...succeeded.\n"))
+		})
+	})
+
+	Describe("Dry run messages", func() {
+		It("lists each planned change under its phase", func() {
+			m := construct.NewMessenger(buf)
+			m.DryRunStarted()
+			m.PlannedOperation("create-provision-dir", `create directory C:\provision\`)
+			m.DryRunSucceeded()
+
+			Expect(buf).To(gbytes.Say("\nDry run: no changes will be made. Construct would, in order:\n"))
+			Expect(buf).To(gbytes.Say(`  \[create-provision-dir\] create directory C:\\provision\\` + "\n"))
+			Expect(buf).To(gbytes.Say("\nDry run complete, the VM was not changed.\n"))
+		})
+	})
+
+	Describe("Artifact transport messages", func() {
+		It("reports the failed transport and the one it retries over", func() {
+			m := construct.NewMessenger(buf)
+			m.UploadFileStarted("LGPO")
+			m.ArtifactTransportFailed("vSphere guest operations", "WinRM", errors.New("connection refused"))
+			m.UploadFileSucceeded()
+
+			Expect(buf).To(gbytes.Say("\tUploading LGPO to target VM...\nFailed over vSphere guest operations: connection refused\nRetrying over WinRM... succeeded.\n"))
+		})
+	})
+
+	Describe("Proxy messages", func() {
+		It("writes setting and clearing the proxy on their own lines", func() {
+			m := construct.NewMessenger(buf)
+			m.SetProxyStarted()
+			m.SetProxySucceeded()
+			m.ClearProxyStarted()
+			m.ClearProxySucceeded()
+
+			Expect(buf).To(gbytes.Say("\nSetting the proxy of the guest VM...succeeded.\n"))
+			Expect(buf).To(gbytes.Say("\nClearing the proxy of the guest VM...succeeded.\n"))
+		})
+	})
+
+	Describe("RecordHardeningProfile messages", func() {
+		It("names the hardening profile it records", func() {
+			m := construct.NewMessenger(buf)
+			m.RecordHardeningProfileStarted("custom")
+			m.RecordHardeningProfileSucceeded()
+
+			Expect(buf).To(gbytes.Say("\nRecording the custom hardening profile in the VM configuration...succeeded.\n"))
+		})
+	})
+
+	Describe("Windows Updates messages", func() {
+		It("lists the updates of each batch and the reboots between them", func() {
+			m := construct.NewMessenger(buf)
+			m.InstallWindowsUpdatesStarted(1)
+			m.WindowsUpdateInstalled("KB4534273", "2020-01 Cumulative Update")
+			m.WindowsUpdatesRebootStarted()
+			m.WindowsUpdatesRebootFinished()
+			m.WindowsUpdatesIterationLimitReached(1)
+			m.InstallWindowsUpdatesSucceeded(1)
+
+			Expect(buf).To(gbytes.Say("\nInstalling Windows Updates, batch 1...\n\tInstalled KB4534273 2020-01 Cumulative Update\n"))
+			Expect(buf).To(gbytes.Say("\nRebooting the VM to finish installing Windows Updates...\n\nThe reboot has finished.\n"))
+			Expect(buf).To(gbytes.Say("\nWarning: Windows Updates may still be available after 1 batches, continuing without them.\n"))
+			Expect(buf).To(gbytes.Say("\nFinished installing Windows Updates, 1 installed.\n"))
+		})
+	})
+
+	Describe("Interruption messages", func() {
+		It("reports the phase construct was interrupted in and the state of the VM", func() {
+			m := construct.NewMessenger(buf)
+			m.ConstructInterrupted("reboot", "The VM was left part way through the reboot phase.")
+
+			Expect(buf).To(gbytes.Say("\nConstruct was interrupted during phase 'reboot'. The VM was left part way through the reboot phase.\n"))
+		})
+	})
 })
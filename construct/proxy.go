@@ -0,0 +1,78 @@
+package construct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// boshPsModulesDest is where the setup script installs the BOSH PowerShell modules. The proxy is set before
+// the setup script runs, so they are installed there early to make Set-ProxySettings available.
+const boshPsModulesDest = "C:\\Program Files\\WindowsPowerShell\\Modules\\"
+
+func (c *VMConstruct) proxyEnabled() bool {
+	return c.HTTPProxy != "" || c.HTTPSProxy != ""
+}
+
+func (c *VMConstruct) setProxy() error {
+	c.messenger.SetProxyStarted()
+	var args string
+	for _, arg := range []struct{ name, value string }{{"HTTPProxy", c.HTTPProxy}, {"HTTPSProxy", c.HTTPSProxy}, {"BypassList", c.ProxyBypassList}} {
+		if arg.value != "" {
+			args += fmt.Sprintf(" -%s %s", arg.name, PowershellStringArgument(arg.value))
+		}
+	}
+	command := fmt.Sprintf(`powershell.exe -NoProfile -Command "Expand-Archive -LiteralPath '%s' -DestinationPath '%s' -Force; Import-Module BOSH.Utils; Set-ProxySettings%s"`,
+		provisionDir+boshPsModules, boshPsModulesDest, args)
+	err := c.runProxyCommand(command)
+	if err != nil {
+		return fmt.Errorf("failed to set the proxy of the VM: %s", err)
+	}
+	c.messenger.SetProxySucceeded()
+	return nil
+}
+
+func (c *VMConstruct) clearProxy() error {
+	c.messenger.ClearProxyStarted()
+	err := c.runProxyCommand(`powershell.exe -NoProfile -Command "Import-Module BOSH.Utils; Clear-ProxySettings"`)
+	if err != nil {
+		return fmt.Errorf("failed to clear the proxy of the VM: %s", err)
+	}
+	c.messenger.ClearProxySucceeded()
+	return nil
+}
+
+func (c *VMConstruct) runProxyCommand(command string) error {
+	exitCode, err := c.remoteManager.ExecuteCommand(command)
+	if err == nil && exitCode != 0 {
+		err = fmt.Errorf("exit code %d", exitCode)
+	}
+	return err
+}
+
+// setProxyPlan describes the proxy settings for a dry run
+func (c *VMConstruct) setProxyPlan() string {
+	var settings []string
+	if c.HTTPProxy != "" {
+		settings = append(settings, "HTTP proxy "+c.HTTPProxy)
+	}
+	if c.HTTPSProxy != "" {
+		settings = append(settings, "HTTPS proxy "+c.HTTPSProxy)
+	}
+	if c.ProxyBypassList != "" {
+		settings = append(settings, "bypassing "+c.ProxyBypassList)
+	}
+	return "set the " + strings.Join(settings, ", ") + " with Set-ProxySettings"
+}
+
+// appendClearProxyPhase clears the proxy once nothing before sysprep needs it any more
+func (c *VMConstruct) appendClearProxyPhase(phases []constructPhase) []constructPhase {
+	if !c.proxyEnabled() {
+		return phases
+	}
+	return append(phases, constructPhase{
+		name:       PhaseClearProxy,
+		run:        c.clearProxy,
+		needsWinRM: true,
+		plan:       []string{"clear the proxy settings with Clear-ProxySettings, so they do not end up in the stemcell"},
+	})
+}
@@ -0,0 +1,87 @@
+package construct
+
+import (
+	"fmt"
+)
+
+const snapshotDescription = "Taken by stembuild construct"
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . SnapshotManager
+type SnapshotManager interface {
+	HasSnapshot(name string) (bool, error)
+	CreateSnapshot(name, description string) error
+	RevertToSnapshot(name string) error
+	RemoveSnapshot(name string) error
+}
+
+// RevertSnapshot reverts the VM to SnapshotName without running construct. The VM is then back in
+// the state it was in before construct started, so any recorded checkpoint no longer applies.
+func (c *VMConstruct) RevertSnapshot() error {
+	if c.DryRun {
+		return c.dryRunRevertSnapshot()
+	}
+
+	c.messenger.RevertSnapshotStarted(c.SnapshotName)
+	err := c.Snapshots.RevertToSnapshot(c.SnapshotName)
+	if err != nil {
+		return fmt.Errorf("unable to revert to snapshot %s: %s", c.SnapshotName, err)
+	}
+	c.messenger.RevertSnapshotSucceeded()
+
+	if c.Checkpoints != nil {
+		return c.Checkpoints.Clear(c.vmInventoryPath)
+	}
+	return nil
+}
+
+// takeSnapshot snapshots the VM before the first phase. A snapshot left behind by an earlier
+// construct of the same VM is reused, so that a retry still reverts to the untouched VM.
+func (c *VMConstruct) takeSnapshot() error {
+	if c.SnapshotName == "" {
+		return nil
+	}
+
+	exists, err := c.Snapshots.HasSnapshot(c.SnapshotName)
+	if err != nil {
+		return fmt.Errorf("unable to look up snapshot %s: %s", c.SnapshotName, err)
+	}
+	if exists {
+		c.messenger.UsingExistingSnapshot(c.SnapshotName)
+		return nil
+	}
+
+	c.messenger.CreateSnapshotStarted(c.SnapshotName)
+	err = c.Snapshots.CreateSnapshot(c.SnapshotName, snapshotDescription)
+	if err != nil {
+		return fmt.Errorf("unable to create snapshot %s: %s", c.SnapshotName, err)
+	}
+	c.messenger.CreateSnapshotSucceeded()
+	return nil
+}
+
+func (c *VMConstruct) revertAfterFailure(err error) error {
+	if c.SnapshotName == "" {
+		return err
+	}
+
+	revertErr := c.RevertSnapshot()
+	if revertErr != nil {
+		c.messenger.RevertSnapshotFailed(revertErr)
+		return err
+	}
+	return fmt.Errorf("%s\nThe VM was reverted to snapshot %s", err, c.SnapshotName)
+}
+
+func (c *VMConstruct) removeSnapshot() error {
+	if c.SnapshotName == "" || !c.DeleteSnapshot {
+		return nil
+	}
+
+	c.messenger.RemoveSnapshotStarted(c.SnapshotName)
+	err := c.Snapshots.RemoveSnapshot(c.SnapshotName)
+	if err != nil {
+		return fmt.Errorf("unable to delete snapshot %s: %s", c.SnapshotName, err)
+	}
+	c.messenger.RemoveSnapshotSucceeded()
+	return nil
+}
@@ -0,0 +1,98 @@
+package construct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArtifactTransport is how construct creates directories on the guest and uploads files to it
+type ArtifactTransport string
+
+const (
+	// TransportGuestOps uses vSphere guest operations, which transfer files through the ESXi host
+	TransportGuestOps ArtifactTransport = "guest-ops"
+	// TransportWinRM uses WinRM, which connects to the guest directly
+	TransportWinRM ArtifactTransport = "winrm"
+)
+
+// name is how messages refer to the transport
+func (t ArtifactTransport) name() string {
+	if t == TransportWinRM {
+		return "WinRM"
+	}
+	return "vSphere guest operations"
+}
+
+// transports returns the configured transport followed by the one construct falls back to, if any
+func (c *VMConstruct) transports() []ArtifactTransport {
+	if c.GuestOpsOnly {
+		return []ArtifactTransport{TransportGuestOps}
+	}
+	if c.Transport == TransportWinRM {
+		return []ArtifactTransport{TransportWinRM, TransportGuestOps}
+	}
+	return []ArtifactTransport{TransportGuestOps, TransportWinRM}
+}
+
+func (c *VMConstruct) makeDirectory(path string) error {
+	return c.overTransports(fmt.Sprintf("creating %s", path), func(t ArtifactTransport) error {
+		if t == TransportWinRM {
+			escapedPath := strings.Replace(path, "'", "''", -1)
+			_, err := c.remoteManager.ExecuteCommand(fmt.Sprintf(`powershell.exe -NoProfile -Command "New-Item -ItemType Directory -Force -Path '%s' | Out-Null"`, escapedPath))
+			return err
+		}
+		return c.Client.MakeDirectory(c.vmInventoryPath, path, c.vmUsername, c.vmPassword)
+	})
+}
+
+// upload copies source to destination on the guest, calling progress, if it is not nil, as the file is sent.
+// Uploads over guest operations go through the guest manager rather than govc so that they can report progress.
+func (c *VMConstruct) upload(source, destination string, progress func(sent, total int64)) error {
+	return c.overTransports(fmt.Sprintf("uploading %s", source), func(t ArtifactTransport) error {
+		if t == TransportWinRM {
+			return c.remoteManager.UploadArtifact(source, destination, progress)
+		}
+		return c.guestManager.UploadFileInGuest(c.ctx, source, destination, progress)
+	})
+}
+
+// overTransports runs operation over the configured transport and, if that fails, over the other one
+func (c *VMConstruct) overTransports(description string, operation func(t ArtifactTransport) error) error {
+	transports := c.transports()
+
+	err := c.runOver(transports[0], operation)
+	if err == nil || len(transports) == 1 {
+		return err
+	}
+	c.messenger.ArtifactTransportFailed(transports[0].name(), transports[1].name(), err)
+
+	fallbackErr := c.runOver(transports[1], operation)
+	if fallbackErr != nil {
+		return fmt.Errorf("%s failed over %s: %s, and over %s: %s", description, transports[0].name(), err, transports[1].name(), fallbackErr)
+	}
+	return nil
+}
+
+func (c *VMConstruct) runOver(t ArtifactTransport, operation func(t ArtifactTransport) error) error {
+	if t == TransportWinRM {
+		err := c.ensureWinRM()
+		if err != nil {
+			return err
+		}
+	}
+	return operation(t)
+}
+
+// ensureWinRM enables WinRM unless it already is. Uploads run before the phase that enables WinRM, so falling back to it
+// enables WinRM early, and the phase then has nothing left to do.
+func (c *VMConstruct) ensureWinRM() error {
+	if c.winRMEnabled {
+		return nil
+	}
+	err := c.winRMEnabler.Enable()
+	if err != nil {
+		return err
+	}
+	c.winRMEnabled = true
+	return nil
+}
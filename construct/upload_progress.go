@@ -0,0 +1,58 @@
+package construct
+
+import (
+	"fmt"
+	"time"
+)
+
+// uploadProgressInterval is how often the progress of an upload is passed on to the messenger
+const uploadProgressInterval = time.Second
+
+// uploadProgress returns a progress callback that passes the progress of one upload on to the messenger,
+// at most once per uploadProgressInterval and always once the upload is complete, with its throughput and ETA.
+// It starts over when fewer bytes have been sent than before, which happens when the upload falls back to another transport.
+func (c *VMConstruct) uploadProgress() func(sent, total int64) {
+	var started, reported time.Time
+	var last int64
+	return func(sent, total int64) {
+		now := time.Now()
+		if started.IsZero() || sent < last {
+			started = now
+			reported = time.Time{}
+		}
+		last = sent
+		if sent < total && now.Sub(reported) < uploadProgressInterval {
+			return
+		}
+		reported = now
+
+		var bytesPerSecond float64
+		var eta time.Duration
+		if elapsed := now.Sub(started).Seconds(); elapsed > 0 {
+			bytesPerSecond = float64(sent) / elapsed
+		}
+		if bytesPerSecond > 0 {
+			eta = time.Duration(float64(total-sent) / bytesPerSecond * float64(time.Second))
+		}
+		c.messenger.UploadFileProgress(sent, total, bytesPerSecond, eta)
+	}
+}
+
+// formatBytes gives a size in bytes in the largest unit that keeps it at least 1
+func formatBytes(n float64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	unit := 0
+	for n >= 1024 && unit < len(units)-1 {
+		n /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", n, units[unit])
+}
+
+// formatUploadProgress describes how far an upload has got, e.g. "45% (9.0 MB of 20.0 MB, 1.2 MB/s, ETA 9s)"
+func formatUploadProgress(sent, total int64, bytesPerSecond float64, eta time.Duration) string {
+	return fmt.Sprintf("%d%% (%s of %s, %s/s, ETA %s)", sent*100/total, formatBytes(float64(sent)), formatBytes(float64(total)), formatBytes(bytesPerSecond), eta.Round(time.Second))
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -21,6 +22,16 @@ type VersionGetter interface {
 	GetVersion() string
 }
 
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Assets
+type Assets interface {
+	// AutomationBundle returns the contents of the StemcellAutomation.zip uploaded to the VM
+	AutomationBundle() ([]byte, error)
+	// AutomationBundleFile returns the path of StemcellAutomation.zip, and a function that removes it once it has been uploaded
+	AutomationBundleFile() (string, func(), error)
+	AutomationBundleSource() string
+	LGPOFile() (string, error)
+}
+
 type VMConstruct struct {
 	ctx                   context.Context
 	remoteManager         RemoteManager
@@ -36,7 +47,41 @@ type VMConstruct struct {
 	versionGetter         VersionGetter
 	rebootWaiter          RebootWaiterI
 	scriptExecutor        ScriptExecutorI
+	assets                Assets
 	RebootWaitTime        time.Duration
+	RebootTimeout         time.Duration
+	PostRebootTimeout     time.Duration
+	ShutdownPollInterval  time.Duration
+	ShutdownTimeout       time.Duration
+	Timeout               time.Duration
+	deadline              time.Time
+	Checkpoints           CheckpointStore
+	Resume                bool
+	Diagnostics           DiagnosticsCollector
+	Snapshots             SnapshotManager
+	SnapshotName          string
+	DeleteSnapshot        bool
+	Hooks                 Hooks
+	HookTimeout           time.Duration
+	DryRun                bool
+	CloneSource           string
+	Transport             ArtifactTransport
+	winRMEnabled          bool
+	GuestOpsOnly          bool
+	WindowsUpdates        bool
+	// WindowsUpdatesMaxIterations limits how many batches of Windows Updates are installed
+	WindowsUpdatesMaxIterations int
+	// WindowsUpdatesTimeout bounds each search for Windows Updates and installing each batch of them
+	WindowsUpdatesTimeout time.Duration
+	// HTTPProxy and HTTPSProxy, and the hosts in ProxyBypassList that skip them, are set in the VM before the
+	// setup script and cleared before sysprep
+	HTTPProxy       string
+	HTTPSProxy      string
+	ProxyBypassList string
+	// HardeningProfile, when set, is recorded in the configuration of the VM before the setup script applies it
+	HardeningProfile string
+	// HardeningGPODir holds the GPO backup that the custom hardening profile applies in place of the embedded CIS GPO
+	HardeningGPODir string
 }
 
 const provisionDir = "C:\\provision\\"
@@ -45,6 +90,7 @@ const stemcellAutomationDest = provisionDir + stemcellAutomationName
 const lgpoDest = provisionDir + "LGPO.zip"
 const stemcellAutomationSetupScript = provisionDir + "Setup.ps1"
 const stemcellAutomationPostRebootScript = provisionDir + "PostReboot.ps1"
+const stemcellAutomationSysprepScript = provisionDir + "Sysprep.ps1"
 const powershell = "C:\\Windows\\System32\\WindowsPowerShell\\V1.0\\powershell.exe"
 const boshPsModules = "bosh-psmodules.zip"
 const winRMPsScript = "BOSH.WinRM.psm1"
@@ -64,24 +110,32 @@ func NewVMConstruct(
 	versionGetter VersionGetter,
 	rebootWaiter RebootWaiterI,
 	scriptExecutor ScriptExecutorI,
+	assets Assets,
 ) *VMConstruct {
 
 	return &VMConstruct{
-		ctx,
-		remoteManager,
-		client,
-		guestManager,
-		vmInventoryPath,
-		vmUsername,
-		vmPassword,
-		winRMEnabler,
-		vmConnectionValidator,
-		messenger,
-		poller,
-		versionGetter,
-		rebootWaiter,
-		scriptExecutor,
-		time.Second * 60,
+		ctx:                         ctx,
+		remoteManager:               remoteManager,
+		Client:                      client,
+		guestManager:                guestManager,
+		vmInventoryPath:             vmInventoryPath,
+		vmUsername:                  vmUsername,
+		vmPassword:                  vmPassword,
+		winRMEnabler:                winRMEnabler,
+		vmConnectionValidator:       vmConnectionValidator,
+		messenger:                   messenger,
+		poller:                      poller,
+		versionGetter:               versionGetter,
+		rebootWaiter:                rebootWaiter,
+		scriptExecutor:              scriptExecutor,
+		assets:                      assets,
+		RebootWaitTime:              time.Second * 60,
+		PostRebootTimeout:           24 * time.Hour,
+		ShutdownPollInterval:        time.Minute,
+		HookTimeout:                 30 * time.Minute,
+		Transport:                   TransportGuestOps,
+		WindowsUpdatesMaxIterations: 5,
+		WindowsUpdatesTimeout:       2 * time.Hour,
 	}
 }
 
@@ -89,11 +143,14 @@ func NewVMConstruct(
 type ScriptExecutorI interface {
 	ExecuteSetupScript(stembuildVersion string) error
 	ExecutePostRebootScript(timeout time.Duration) error
+	ExecutePostRebootScriptWithoutSysprep(timeout time.Duration) error
+	ExecuteSysprepScript(timeout time.Duration) error
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . RebootWaiterI
 type RebootWaiterI interface {
-	WaitForRebootFinished() error
+	RecordBootTime() error
+	WaitForRebootFinished(ctx context.Context, timeout time.Duration) error
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . GuestManager
@@ -101,15 +158,17 @@ type GuestManager interface {
 	ExitCodeForProgramInGuest(ctx context.Context, pid int64) (int32, error)
 	StartProgramInGuest(ctx context.Context, command, args string) (int64, error)
 	DownloadFileInGuest(ctx context.Context, path string) (io.Reader, int64, error)
+	UploadFileInGuest(ctx context.Context, source, destination string, progress func(sent, total int64)) error
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . IaasClient
 type IaasClient interface {
-	UploadArtifact(vmInventoryPath, artifact, destination, username, password string) error
 	MakeDirectory(vmInventoryPath, path, username, password string) error
+	CaptureScreenshot(vmInventoryPath, destination string) error
 	Start(vmInventoryPath, username, password, command string, args ...string) (string, error)
 	WaitForExit(vmInventoryPath, username, password, pid string) (int, error)
 	IsPoweredOff(vmInventoryPath string) (bool, error)
+	SetHardeningProfile(vmInventoryPath, profile string) error
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . WinRMEnabler
@@ -142,96 +201,576 @@ type ConstructMessenger interface {
 	ExecutePostRebootScriptSucceeded()
 	ExecutePostRebootWarning(warning string)
 	UploadFileStarted(artifact string)
+	UploadFileProgress(sent, total int64, bytesPerSecond float64, eta time.Duration)
 	UploadFileSucceeded()
 	WaitingForShutdown()
 	ShutdownCompleted()
 	WinRMDisconnectedForReboot()
 	LogOutUsersStarted()
 	LogOutUsersSucceeded()
+	NoCheckpointFound()
+	CheckpointsDisabled(err error)
+	CheckpointNotConfirmed(phase string)
+	ResumingFromCheckpoint(phase string)
+	CollectDiagnosticsStarted()
+	CollectDiagnosticsSucceeded()
+	CollectDiagnosticsFailed(err error)
+	CloneVMStarted(clonePath string)
+	CloneVMSucceeded()
+	CloneIPDiscovered(ip string)
+	CreateSnapshotStarted(name string)
+	CreateSnapshotSucceeded()
+	UsingExistingSnapshot(name string)
+	RevertSnapshotStarted(name string)
+	RevertSnapshotSucceeded()
+	RevertSnapshotFailed(err error)
+	RemoveSnapshotStarted(name string)
+	RemoveSnapshotSucceeded()
+	UploadHooksStarted()
+	UploadHooksSucceeded()
+	RunHookStarted(point, name string)
+	RunHookSucceeded()
+	ExecuteSysprepScriptStarted()
+	ExecuteSysprepScriptSucceeded()
+	DryRunStarted()
+	PlannedOperation(phase, operation string)
+	DryRunSucceeded()
+	ArtifactTransportFailed(transport, fallback string, err error)
+	ConstructInterrupted(phase, state string)
+	InstallWindowsUpdatesStarted(iteration int)
+	WindowsUpdateInstalled(kb, title string)
+	WindowsUpdatesRebootStarted()
+	WindowsUpdatesRebootFinished()
+	WindowsUpdatesIterationLimitReached(iterations int)
+	InstallWindowsUpdatesSucceeded(installed int)
+	SetProxyStarted()
+	SetProxySucceeded()
+	ClearProxyStarted()
+	ClearProxySucceeded()
+	RecordHardeningProfileStarted(profile string)
+	RecordHardeningProfileSucceeded()
+}
+
+type constructPhase struct {
+	name Phase
+	run  func() error
+	// reconnect phases establish the WinRM session and are always re-run on resume
+	reconnect bool
+	// needsWinRM phases depend on a reconnect phase having run first
+	needsWinRM bool
+	// evidence lists the guest files a completed phase leaves behind, used to confirm a checkpoint
+	evidence []string
+	// plan describes the changes the phase makes to the VM, reported instead of running it in a dry run
+	plan []string
 }
 
 func (c *VMConstruct) PrepareVM() error {
+	c.deadline = time.Time{}
+	if c.Timeout > 0 {
+		c.deadline = time.Now().Add(c.Timeout)
+	}
+
 	stembuildVersion := c.versionGetter.GetVersion()
+	phases := c.constructPhases(stembuildVersion)
+	if c.DryRun {
+		return c.dryRun(phases, stembuildVersion)
+	}
 
-	err := c.createProvisionDirectory()
+	resumeAfter, err := c.resumePoint(phases, stembuildVersion)
 	if err != nil {
 		return err
 	}
-	c.messenger.UploadArtifactsStarted()
-	err = c.uploadArtifacts()
+
+	err = c.takeSnapshot()
 	if err != nil {
 		return err
 	}
-	c.messenger.UploadArtifactsSucceeded()
 
-	c.messenger.EnableWinRMStarted()
-	err = c.winRMEnabler.Enable()
-	if err != nil {
-		return err
+	changed := false
+	for i, phase := range phases {
+		if i <= resumeAfter && !(phase.reconnect && winRMNeededAfter(phases, resumeAfter)) {
+			continue
+		}
+
+		if c.ctx.Err() != nil {
+			return c.interrupted(phase.name, changed)
+		}
+		if c.deadlineExceeded() {
+			return c.phaseFailed(phase.name, fmt.Errorf("construct did not finish within %s", c.Timeout))
+		}
+
+		changed = true
+		err = phase.run()
+		if err != nil {
+			if c.ctx.Err() != nil {
+				return c.interrupted(phase.name, changed)
+			}
+			if c.deadlineExceeded() {
+				err = fmt.Errorf("construct did not finish within %s: %s", c.Timeout, err)
+			}
+			return c.phaseFailed(phase.name, err)
+		}
+
+		if i > resumeAfter {
+			err = c.recordPhase(phase.name, stembuildVersion)
+			if err != nil {
+				return err
+			}
+		}
 	}
-	c.messenger.EnableWinRMSucceeded()
 
-	c.messenger.ValidateVMConnectionStarted()
-	err = c.vmConnectionValidator.Validate()
-	if err != nil {
-		return err
+	if c.Checkpoints != nil {
+		err = c.Checkpoints.Clear(c.vmInventoryPath)
+		if err != nil {
+			return err
+		}
 	}
-	c.messenger.ValidateVMConnectionSucceeded()
+	return c.removeSnapshot()
+}
 
-	c.messenger.ExtractArtifactsStarted()
-	err = c.extractArchive()
-	if err != nil {
-		return err
+func (c *VMConstruct) constructPhases(stembuildVersion string) []constructPhase {
+	uploadEvidence := []string{lgpoDest, stemcellAutomationDest}
+	uploadPlan := []string{
+		fmt.Sprintf("upload %s to %s", c.lgpoSource(), lgpoDest),
+		fmt.Sprintf("upload %s to %s", c.assets.AutomationBundleSource(), stemcellAutomationDest),
+	}
+	extractEvidence := []string{stemcellAutomationSetupScript, stemcellAutomationPostRebootScript}
+	extractPlan := []string{fmt.Sprintf("extract %s to %s", stemcellAutomationDest, provisionDir)}
+	setupArgs := "-Version " + stembuildVersion
+	if c.HardeningProfile != "" {
+		setupArgs += " -HardeningProfile " + PowershellStringArgument(c.HardeningProfile)
+	}
+	if c.HardeningGPODir != "" {
+		uploadEvidence = append(uploadEvidence, hardeningGPODest)
+		uploadPlan = append(uploadPlan, fmt.Sprintf("upload the GPO backup in %s to %s", c.HardeningGPODir, hardeningGPODest))
+		extractEvidence = append(extractEvidence, hardeningGPODir)
+		extractPlan = append(extractPlan, fmt.Sprintf("extract %s to %s", hardeningGPODest, hardeningGPODir))
 	}
-	c.messenger.ExtractArtifactsSucceeded()
 
-	c.messenger.LogOutUsersStarted()
-	err = c.logOutUsers()
-	if err != nil {
-		return err
+	phases := []constructPhase{
+		{
+			name:     PhaseCreateProvisionDir,
+			run:      c.createProvisionDirectory,
+			evidence: []string{provisionDir},
+			plan:     []string{"create directory " + provisionDir},
+		},
+		{
+			name: PhaseUploadArtifacts,
+			run: func() error {
+				c.messenger.UploadArtifactsStarted()
+				err := c.uploadArtifacts()
+				if err != nil {
+					return err
+				}
+				c.messenger.UploadArtifactsSucceeded()
+				return nil
+			},
+			evidence: uploadEvidence,
+			plan:     uploadPlan,
+		},
 	}
-	c.messenger.LogOutUsersSucceeded()
 
-	c.messenger.ExecuteSetupScriptStarted()
-	err = c.scriptExecutor.ExecuteSetupScript(stembuildVersion)
+	if len(c.Hooks) > 0 {
+		phases = append(phases, constructPhase{
+			name:     PhaseUploadHooks,
+			run:      c.uploadHooks,
+			evidence: c.Hooks.destinations(),
+			plan:     c.Hooks.uploadPlan(),
+		})
+	}
+
+	// In guest-ops-only mode every command runs through guest operations, so WinRM is left as it is
+	if !c.GuestOpsOnly {
+		phases = append(phases, constructPhase{
+			name: PhaseEnableWinRM,
+			run: func() error {
+				c.messenger.EnableWinRMStarted()
+				err := c.ensureWinRM()
+				if err != nil {
+					return err
+				}
+				c.messenger.EnableWinRMSucceeded()
+				return nil
+			},
+			reconnect: true,
+			plan:      []string{"enable WinRM through VMware Tools guest operations"},
+		})
+	}
+
+	phases = append(phases,
+		constructPhase{
+			name: PhaseValidateVMConnection,
+			run: func() error {
+				c.messenger.ValidateVMConnectionStarted()
+				err := c.vmConnectionValidator.Validate()
+				if err != nil {
+					return err
+				}
+				c.messenger.ValidateVMConnectionSucceeded()
+				return nil
+			},
+			reconnect: true,
+		},
+		constructPhase{
+			name: PhaseExtractArtifacts,
+			run: func() error {
+				c.messenger.ExtractArtifactsStarted()
+				err := c.extractArchive()
+				if err != nil {
+					return err
+				}
+				c.messenger.ExtractArtifactsSucceeded()
+				return nil
+			},
+			needsWinRM: true,
+			evidence:   extractEvidence,
+			plan:       extractPlan,
+		},
+		constructPhase{
+			name: PhaseLogOutUsers,
+			run: func() error {
+				c.messenger.LogOutUsersStarted()
+				err := c.logOutUsers()
+				if err != nil {
+					return err
+				}
+				c.messenger.LogOutUsersSucceeded()
+				return nil
+			},
+			needsWinRM: true,
+			plan:       []string{"log out any user logged in to the VM"},
+		},
+	)
+
+	if c.proxyEnabled() {
+		phases = append(phases, constructPhase{
+			name:       PhaseSetProxy,
+			run:        c.setProxy,
+			needsWinRM: true,
+			plan:       []string{c.setProxyPlan()},
+		})
+	}
+
+	phases = c.appendHookPhase(phases, PhasePreSetupHooks, HookPreSetup)
+
+	if c.HardeningProfile != "" {
+		phases = append(phases, constructPhase{
+			name: PhaseRecordHardeningProfile,
+			run:  c.recordHardeningProfile,
+			plan: []string{fmt.Sprintf("record the %s hardening profile in the configuration of %s", c.HardeningProfile, c.vmInventoryPath)},
+		})
+	}
+
+	phases = append(phases,
+		constructPhase{
+			name: PhaseExecuteSetupScript,
+			run: func() error {
+				c.messenger.ExecuteSetupScriptStarted()
+				err := c.rebootWaiter.RecordBootTime()
+				if err != nil {
+					return err
+				}
+				err = c.scriptExecutor.ExecuteSetupScript(stembuildVersion)
+				if err != nil {
+					return err
+				}
+				c.messenger.ExecuteSetupScriptSucceeded()
+				c.messenger.WinRMDisconnectedForReboot()
+				return nil
+			},
+			needsWinRM: true,
+			plan:       []string{fmt.Sprintf("run %s %s, which reboots the VM", stemcellAutomationSetupScript, setupArgs)},
+		},
+		constructPhase{
+			name: PhaseReboot,
+			run: func() error {
+				c.messenger.RebootHasStarted()
+				err := c.waitForReboot()
+				if err != nil {
+					return err
+				}
+				c.messenger.RebootHasFinished()
+				return nil
+			},
+			needsWinRM: true,
+		},
+	)
+
+	phases = c.appendHookPhase(phases, PhasePostSetupHooks, HookPostSetup)
+
+	if c.WindowsUpdates {
+		phases = append(phases, constructPhase{
+			name:       PhaseWindowsUpdates,
+			run:        c.installWindowsUpdates,
+			needsWinRM: true,
+			plan: []string{fmt.Sprintf("install the available Windows Updates in up to %d batches, rebooting the VM after each batch that needs it",
+				c.WindowsUpdatesMaxIterations)},
+		})
+	}
+
+	if len(c.Hooks[HookPreSysprep]) == 0 {
+		phases = c.appendClearProxyPhase(phases)
+		phases = append(phases, constructPhase{
+			name: PhaseExecutePostRebootScript,
+			run: func() error {
+				c.messenger.ExecutePostRebootScriptStarted()
+				err := c.sysprepFinished(c.scriptExecutor.ExecutePostRebootScript(c.untilDeadline(c.PostRebootTimeout)))
+				if err != nil {
+					return fmt.Errorf("failure in post-reboot script: %s", err)
+				}
+				c.messenger.ExecutePostRebootScriptSucceeded()
+				return nil
+			},
+			needsWinRM: true,
+			plan:       []string{fmt.Sprintf("run %s, which syspreps the VM and shuts it down", stemcellAutomationPostRebootScript)},
+		})
+	} else {
+		// Sysprep shuts the VM down, so the post-reboot script stops short of it and
+		// the pre-sysprep hooks run before sysprep is started on its own
+		phases = append(phases, constructPhase{
+			name: PhaseExecutePostRebootScript,
+			run: func() error {
+				c.messenger.ExecutePostRebootScriptStarted()
+				err := c.scriptExecutor.ExecutePostRebootScriptWithoutSysprep(c.untilDeadline(c.PostRebootTimeout))
+				if err != nil {
+					return fmt.Errorf("failure in post-reboot script: %s", err)
+				}
+				c.messenger.ExecutePostRebootScriptSucceeded()
+				return nil
+			},
+			needsWinRM: true,
+			plan:       []string{fmt.Sprintf("run %s -SkipSysprep", stemcellAutomationPostRebootScript)},
+		})
+
+		phases = c.appendHookPhase(phases, PhasePreSysprepHooks, HookPreSysprep)
+		phases = c.appendClearProxyPhase(phases)
+
+		phases = append(phases, constructPhase{
+			name: PhaseSysprep,
+			run: func() error {
+				c.messenger.ExecuteSysprepScriptStarted()
+				err := c.sysprepFinished(c.scriptExecutor.ExecuteSysprepScript(c.untilDeadline(c.PostRebootTimeout)))
+				if err != nil {
+					return fmt.Errorf("failure in sysprep script: %s", err)
+				}
+				c.messenger.ExecuteSysprepScriptSucceeded()
+				return nil
+			},
+			needsWinRM: true,
+			plan:       []string{fmt.Sprintf("run %s, which syspreps the VM and shuts it down", stemcellAutomationSysprepScript)},
+		})
+	}
+
+	phases = append(phases, constructPhase{
+		name: PhaseShutdown,
+		run: func() error {
+			err := c.isPoweredOff(c.ShutdownPollInterval)
+			if err != nil {
+				return err
+			}
+			c.messenger.ShutdownCompleted()
+			return nil
+		},
+	})
+	return phases
+}
+
+func (c *VMConstruct) appendHookPhase(phases []constructPhase, name Phase, point HookPoint) []constructPhase {
+	if len(c.Hooks[point]) == 0 {
+		return phases
+	}
+
+	return append(phases, constructPhase{
+		name: name,
+		run: func() error {
+			return c.runHooks(point)
+		},
+		needsWinRM: true,
+		plan:       c.Hooks.runPlan(point),
+	})
+}
+
+// sysprepFinished treats losing the WinRM connection as a warning, since sysprep
+// shuts the VM down while the script is still running
+func (c *VMConstruct) sysprepFinished(err error) error {
+	if err != nil && strings.Contains(err.Error(), "winrm connection event") {
+		c.messenger.ExecutePostRebootWarning(err.Error())
+		return nil
+	}
+	return err
+}
+
+// resumePoint returns the index of the last phase that does not need to run again,
+// or -1 when construct should start from the beginning.
+func (c *VMConstruct) resumePoint(phases []constructPhase, stembuildVersion string) (int, error) {
+	if c.Checkpoints == nil {
+		return -1, nil
+	}
+
+	if !c.Resume {
+		return -1, c.Checkpoints.Clear(c.vmInventoryPath)
+	}
+
+	checkpoint, found, err := c.Checkpoints.Load(c.vmInventoryPath)
 	if err != nil {
+		return -1, err
+	}
+	if !found {
+		c.messenger.NoCheckpointFound()
+		return -1, nil
+	}
+	if checkpoint.Version != stembuildVersion {
+		return -1, fmt.Errorf("checkpoint for %s was recorded by stembuild %s and cannot be resumed by stembuild %s", c.vmInventoryPath, checkpoint.Version, stembuildVersion)
+	}
+
+	last := -1
+	for i, phase := range phases {
+		if phase.name == checkpoint.Phase {
+			last = i
+		}
+	}
+	if last == -1 {
+		return -1, fmt.Errorf("checkpoint for %s has unknown phase: %s", c.vmInventoryPath, checkpoint.Phase)
+	}
+
+	for ; last >= 0; last-- {
+		confirmed, err := c.guestFilesExist(phases[last].evidence)
+		if err != nil {
+			return -1, err
+		}
+		if confirmed {
+			break
+		}
+		c.messenger.CheckpointNotConfirmed(string(phases[last].name))
+	}
+
+	if last >= 0 {
+		c.messenger.ResumingFromCheckpoint(string(phases[last].name))
+	}
+	return last, nil
+}
+
+// interrupted stops construct once its context is cancelled, e.g. by Ctrl-C. Diagnostics are not collected
+// and the VM is not reverted to the snapshot, so that construct exits promptly and can be resumed.
+func (c *VMConstruct) interrupted(phase Phase, changed bool) error {
+	c.messenger.ConstructInterrupted(string(phase), c.interruptedState(phase, changed))
+	return fmt.Errorf("construct was interrupted during the %s phase: %s", phase, c.ctx.Err())
+}
+
+// interruptedState describes what state an interrupted construct left the VM in
+func (c *VMConstruct) interruptedState(phase Phase, changed bool) string {
+	var revert string
+	if c.SnapshotName != "" {
+		revert = fmt.Sprintf(" Run construct with -snapshot %s -revert-snapshot to start over.", c.SnapshotName)
+	}
+
+	switch {
+	case !changed:
+		return "The VM was not changed."
+	case phase == PhaseShutdown:
+		return "Sysprep has finished and the VM is shutting down. Package it once it has powered off."
+	case phase == PhaseSysprep || (phase == PhaseExecutePostRebootScript && len(c.Hooks[HookPreSysprep]) == 0):
+		return "Sysprep may still be running in the VM and shut it down, so construct cannot be resumed." + revert
+	}
+
+	state := fmt.Sprintf("The VM was left part way through the %s phase.", phase)
+	if c.Checkpoints != nil {
+		state += " Re-run construct with -resume to continue after the last completed phase."
+	}
+	return state + revert
+}
+
+// phaseFailed collects diagnostics before reverting to the snapshot, which would discard the guest logs
+func (c *VMConstruct) phaseFailed(phase Phase, err error) error {
+	return c.revertAfterFailure(c.collectDiagnostics(phase, err))
+}
+
+func (c *VMConstruct) collectDiagnostics(phase Phase, err error) error {
+	if c.Diagnostics == nil {
 		return err
 	}
-	c.messenger.ExecuteSetupScriptSucceeded()
-	c.messenger.WinRMDisconnectedForReboot()
 
-	c.messenger.RebootHasStarted()
-	time.Sleep(c.RebootWaitTime)
-	err = c.rebootWaiter.WaitForRebootFinished()
-	if err != nil {
+	c.messenger.CollectDiagnosticsStarted()
+	bundle, collectErr := c.Diagnostics.Collect(phase, err)
+	if collectErr != nil {
+		c.messenger.CollectDiagnosticsFailed(collectErr)
 		return err
 	}
-	c.messenger.RebootHasFinished()
+	c.messenger.CollectDiagnosticsSucceeded()
 
-	c.messenger.ExecutePostRebootScriptStarted()
-	err = c.scriptExecutor.ExecutePostRebootScript(24 * time.Hour)
-	if err != nil {
-		if strings.Contains(err.Error(), "winrm connection event") {
-			c.messenger.ExecutePostRebootWarning(err.Error())
-		} else {
-			return fmt.Errorf("failure in post-reboot script: %s", err)
+	return fmt.Errorf("%s\nGuest logs and a console screenshot were saved to %s", err, bundle)
+}
+
+// deadlineExceeded reports whether the overall Timeout has passed. A zero Timeout never passes.
+func (c *VMConstruct) deadlineExceeded() bool {
+	return !c.deadline.IsZero() && !time.Now().Before(c.deadline)
+}
+
+// untilDeadline shortens a wait so that it ends by the overall deadline. A zero wait is
+// unbounded, so it becomes the time left until the deadline.
+func (c *VMConstruct) untilDeadline(wait time.Duration) time.Duration {
+	if c.deadline.IsZero() {
+		return wait
+	}
+
+	left := time.Until(c.deadline)
+	if left <= 0 {
+		left = time.Nanosecond
+	}
+	if wait == 0 || left < wait {
+		return left
+	}
+	return wait
+}
+
+func winRMNeededAfter(phases []constructPhase, index int) bool {
+	for _, phase := range phases[index+1:] {
+		if phase.needsWinRM {
+			return true
 		}
 	}
+	return false
+}
 
-	c.messenger.ExecutePostRebootScriptSucceeded()
+func (c *VMConstruct) recordPhase(phase Phase, stembuildVersion string) error {
+	if c.Checkpoints == nil {
+		return nil
+	}
 
-	err = c.isPoweredOff(time.Minute)
+	err := c.Checkpoints.Save(c.vmInventoryPath, Checkpoint{Phase: phase, Version: stembuildVersion, UpdatedAt: time.Now()})
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to record construct checkpoint: %s", err)
 	}
-	c.messenger.ShutdownCompleted()
-
 	return nil
 }
 
+func (c *VMConstruct) guestFilesExist(paths []string) (bool, error) {
+	if len(paths) == 0 {
+		return true, nil
+	}
+
+	var testPaths []string
+	for _, path := range paths {
+		testPaths = append(testPaths, fmt.Sprintf("(Test-Path '%s')", path))
+	}
+	rawCommand := fmt.Sprintf("if (%s) { exit 0 } else { exit 1 }", strings.Join(testPaths, " -and "))
+
+	pid, err := c.guestManager.StartProgramInGuest(c.ctx, powershell, "-EncodedCommand "+EncodePowershellCommand([]byte(rawCommand)))
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm checkpoint on guest: %s", err)
+	}
+
+	exitCode, err := c.guestManager.ExitCodeForProgramInGuest(c.ctx, pid)
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm checkpoint on guest: %s", err)
+	}
+
+	return exitCode == 0, nil
+}
+
 func (c *VMConstruct) createProvisionDirectory() error {
 	c.messenger.CreateProvisionDirStarted()
-	err := c.Client.MakeDirectory(c.vmInventoryPath, provisionDir, c.vmUsername, c.vmPassword)
+	err := c.makeDirectory(provisionDir)
 	if err != nil {
 		return err
 	}
@@ -240,26 +779,50 @@ func (c *VMConstruct) createProvisionDirectory() error {
 }
 
 func (c *VMConstruct) uploadArtifacts() error {
+	lgpo, err := c.assets.LGPOFile()
+	if err != nil {
+		return err
+	}
 	c.messenger.UploadFileStarted("LGPO")
-	err := c.Client.UploadArtifact(c.vmInventoryPath, "./LGPO.zip", lgpoDest, c.vmUsername, c.vmPassword)
+	err = c.upload(lgpo, lgpoDest, c.uploadProgress())
 	if err != nil {
 		return err
 	}
 	c.messenger.UploadFileSucceeded()
 
+	stemcellAutomation, cleanup, err := c.assets.AutomationBundleFile()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	c.messenger.UploadFileStarted("stemcell preparation artifacts")
-	err = c.Client.UploadArtifact(c.vmInventoryPath, fmt.Sprintf("./%s", stemcellAutomationName), stemcellAutomationDest, c.vmUsername, c.vmPassword)
+	err = c.upload(stemcellAutomation, stemcellAutomationDest, c.uploadProgress())
 	if err != nil {
 		return err
 	}
 	c.messenger.UploadFileSucceeded()
 
+	if c.HardeningGPODir != "" {
+		return c.uploadHardeningGPO()
+	}
 	return nil
 }
 
+// lgpoSource describes where LGPO.zip is uploaded from, for the plan of a dry run
+func (c *VMConstruct) lgpoSource() string {
+	lgpo, err := c.assets.LGPOFile()
+	if err != nil {
+		return "LGPO.zip (" + err.Error() + ")"
+	}
+	return lgpo
+}
+
 func (c *VMConstruct) extractArchive() error {
 	err := c.remoteManager.ExtractArchive(stemcellAutomationDest, provisionDir)
-	return err
+	if err != nil || c.HardeningGPODir == "" {
+		return err
+	}
+	return c.remoteManager.ExtractArchive(hardeningGPODest, hardeningGPODir)
 }
 
 func (c *VMConstruct) logOutUsers() error {
@@ -277,23 +840,61 @@ func (c *VMConstruct) logOutUsers() error {
 }
 
 type ScriptExecutor struct {
-	remoteManager RemoteManager
+	remoteManager  RemoteManager
+	sysprepOptions SysprepOptions
+}
+
+// SysprepOptions are passed through PostReboot.ps1 to sysprep
+type SysprepOptions struct {
+	Organization       string
+	Owner              string
+	SkipRandomPassword bool
+	// HardeningProfile is also passed to Setup.ps1, which applies the registry settings of the profile
+	HardeningProfile string
 }
 
-func NewScriptExecutor(remoteManager RemoteManager) *ScriptExecutor {
+func NewScriptExecutor(remoteManager RemoteManager, sysprepOptions SysprepOptions) *ScriptExecutor {
 	return &ScriptExecutor{
 		remoteManager,
+		sysprepOptions,
 	}
 }
 
 func (e *ScriptExecutor) ExecuteSetupScript(stembuildVersion string) error {
 	versionArg := " -Version " + stembuildVersion
-	_, err := e.remoteManager.ExecuteCommand("powershell.exe " + stemcellAutomationSetupScript + versionArg)
+	_, err := e.remoteManager.ExecuteCommand("powershell.exe " + stemcellAutomationSetupScript + versionArg + e.hardeningProfileArg())
 	return err
 }
 
 func (e *ScriptExecutor) ExecutePostRebootScript(timeout time.Duration) error {
-	_, err := e.remoteManager.ExecuteCommandWithTimeout("powershell.exe "+stemcellAutomationPostRebootScript, timeout)
+	return e.executeSysprepScript(stemcellAutomationPostRebootScript, timeout)
+}
+
+// ExecutePostRebootScriptWithoutSysprep runs the post-reboot script up to, but not including, sysprep
+func (e *ScriptExecutor) ExecutePostRebootScriptWithoutSysprep(timeout time.Duration) error {
+	_, err := e.remoteManager.ExecuteCommandWithTimeout("powershell.exe "+stemcellAutomationPostRebootScript+" -SkipSysprep", timeout)
+	return err
+}
+
+// ExecuteSysprepScript runs only the sysprep step of the post-reboot script
+func (e *ScriptExecutor) ExecuteSysprepScript(timeout time.Duration) error {
+	return e.executeSysprepScript(stemcellAutomationSysprepScript, timeout)
+}
+
+func (e *ScriptExecutor) executeSysprepScript(script string, timeout time.Duration) error {
+	command := "powershell.exe " + script
+	if e.sysprepOptions.Organization != "" {
+		command += " -Organization " + PowershellStringArgument(e.sysprepOptions.Organization)
+	}
+	if e.sysprepOptions.Owner != "" {
+		command += " -Owner " + PowershellStringArgument(e.sysprepOptions.Owner)
+	}
+	if e.sysprepOptions.SkipRandomPassword {
+		command += " -SkipRandomPassword"
+	}
+	command += e.hardeningProfileArg()
+
+	_, err := e.remoteManager.ExecuteCommandWithTimeout(command, timeout)
 
 	if err != nil && strings.Contains(err.Error(), PowershellExecutionErrorMessage) {
 		return err
@@ -307,8 +908,29 @@ func (e *ScriptExecutor) ExecutePostRebootScript(timeout time.Duration) error {
 
 }
 
+func (e *ScriptExecutor) hardeningProfileArg() string {
+	if e.sysprepOptions.HardeningProfile == "" {
+		return ""
+	}
+	return " -HardeningProfile " + PowershellStringArgument(e.sysprepOptions.HardeningProfile)
+}
+
+// PowershellStringArgument returns a PowerShell expression that evaluates to value.
+// The command line passes through cmd.exe before PowerShell parses it, so quoting alone
+// cannot protect characters such as &, %, ^ and ". The value is therefore carried as
+// base64, which contains nothing either shell interprets.
+func PowershellStringArgument(value string) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(value))
+	return fmt.Sprintf("([Text.Encoding]::UTF8.GetString([Convert]::FromBase64String('%s')))", encoded)
+}
+
 func (c *VMConstruct) isPoweredOff(duration time.Duration) error {
-	err := c.poller.Poll(duration, func() (bool, error) {
+	timeout := c.untilDeadline(c.ShutdownTimeout)
+	err := c.poller.PollWithOptions(c.ctx, poller.Options{
+		Interval: duration,
+		Jitter:   0.1,
+		Timeout:  timeout,
+	}, func() (bool, error) {
 		isPoweredOff, err := c.Client.IsPoweredOff(c.vmInventoryPath)
 
 		if err != nil {
@@ -319,6 +941,11 @@ func (c *VMConstruct) isPoweredOff(duration time.Duration) error {
 
 		return isPoweredOff, nil
 	})
+
+	var timeoutErr *poller.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return fmt.Errorf("VM did not power off within %s", timeout)
+	}
 	return err
 }
 
@@ -1,12 +1,18 @@
 package construct_test
 
 import (
+	"archive/zip"
 	"context"
 	"errors"
 	"fmt"
+	"github.com/cloudfoundry-incubator/stembuild/poller"
 	"github.com/cloudfoundry-incubator/stembuild/poller/pollerfakes"
 	"github.com/cloudfoundry-incubator/stembuild/remotemanager"
 	"github.com/onsi/gomega/gbytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	. "github.com/cloudfoundry-incubator/stembuild/construct"
@@ -29,6 +35,8 @@ var _ = Describe("construct_helpers", func() {
 		fakeVMConnectionValidator *constructfakes.FakeVMConnectionValidator
 		fakeRebootWaiter          *constructfakes.FakeRebootWaiterI
 		fakeScriptExecutor        *constructfakes.FakeScriptExecutorI
+		fakeAssets                *constructfakes.FakeAssets
+		assetsCleanedUp           bool
 	)
 	const rawLogoffCommand = `&{If([string]::IsNullOrEmpty($(Get-WmiObject win32_computersystem).username)) {Write-Host "No users logged in." } Else {Write-Host "Logging out user."; $(Get-WmiObject win32_operatingsystem).Win32Shutdown(0) 1> $null}}`
 	BeforeEach(func() {
@@ -42,6 +50,11 @@ var _ = Describe("construct_helpers", func() {
 		fakeVMConnectionValidator = &constructfakes.FakeVMConnectionValidator{}
 		fakeRebootWaiter = &constructfakes.FakeRebootWaiterI{}
 		fakeScriptExecutor = &constructfakes.FakeScriptExecutorI{}
+		fakeAssets = &constructfakes.FakeAssets{}
+		fakeAssets.LGPOFileReturns("./LGPO.zip", nil)
+		assetsCleanedUp = false
+		fakeAssets.AutomationBundleFileReturns("./StemcellAutomation.zip", func() { assetsCleanedUp = true }, nil)
+		fakeAssets.AutomationBundleSourceReturns("./StemcellAutomation.zip")
 
 		vmConstruct = NewVMConstruct(
 			context.TODO(),
@@ -58,6 +71,7 @@ var _ = Describe("construct_helpers", func() {
 			fakeVersionGetter,
 			fakeRebootWaiter,
 			fakeScriptExecutor,
+			fakeAssets,
 		)
 		vmConstruct.RebootWaitTime = 0
 
@@ -75,7 +89,7 @@ var _ = Describe("construct_helpers", func() {
 	Describe("ScriptExecutor", func() {
 		It("executes setup script with correct arguments", func() {
 
-			e := NewScriptExecutor(fakeRemoteManager)
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{})
 			version := "11.11.11"
 			err := e.ExecuteSetupScript(version)
 			executeCommandCallArg := fakeRemoteManager.ExecuteCommandArgsForCall(0)
@@ -87,7 +101,7 @@ var _ = Describe("construct_helpers", func() {
 		})
 
 		It("executes post-reboot script with correct arguments", func() {
-			e := NewScriptExecutor(fakeRemoteManager)
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{})
 			superLongTimeout := 24 * time.Hour
 			err := e.ExecutePostRebootScript(superLongTimeout)
 			executeCommandCallArg, timeout := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(0)
@@ -96,10 +110,29 @@ var _ = Describe("construct_helpers", func() {
 			Expect(executeCommandCallArg).To(ContainSubstring("powershell"))
 			Expect(executeCommandCallArg).To(ContainSubstring("PostReboot.ps1"))
 			Expect(timeout).To(Equal(superLongTimeout))
+			Expect(executeCommandCallArg).NotTo(ContainSubstring("-Organization"))
+			Expect(executeCommandCallArg).NotTo(ContainSubstring("-Owner"))
+			Expect(executeCommandCallArg).NotTo(ContainSubstring("-SkipRandomPassword"))
+		})
+
+		It("passes sysprep options to the post-reboot script", func() {
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{
+				Organization:       "Acme & Sons",
+				Owner:              `O'Brien "the builder"`,
+				SkipRandomPassword: true,
+			})
+			err := e.ExecutePostRebootScript(time.Hour)
+			executeCommandCallArg, _ := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executeCommandCallArg).To(Equal("powershell.exe C:\\provision\\PostReboot.ps1" +
+				" -Organization " + PowershellStringArgument("Acme & Sons") +
+				" -Owner " + PowershellStringArgument(`O'Brien "the builder"`) +
+				" -SkipRandomPassword"))
 		})
 
 		It("returns an error when there is a powershell script execution error", func() {
-			e := NewScriptExecutor(fakeRemoteManager)
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{})
 			superLongTimeout := 24 * time.Hour
 			powershellErrorPrefix := errors.New(remotemanager.PowershellExecutionErrorMessage)
 			powershellErr := fmt.Errorf("%s: %s", powershellErrorPrefix, "a command failed to run")
@@ -111,7 +144,7 @@ var _ = Describe("construct_helpers", func() {
 		})
 
 		It("wraps a non-powershell execution error", func() {
-			e := NewScriptExecutor(fakeRemoteManager)
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{})
 			superLongTimeout := 24 * time.Hour
 			winRMError := errors.New("some EOF thing")
 
@@ -123,6 +156,48 @@ var _ = Describe("construct_helpers", func() {
 			Expect(err.Error()).To(ContainSubstring("winrm connection event"))
 		})
 
+		It("can run the post-reboot script without sysprep", func() {
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{Owner: "owner"})
+			err := e.ExecutePostRebootScriptWithoutSysprep(time.Hour)
+			executeCommandCallArg, timeout := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executeCommandCallArg).To(Equal("powershell.exe C:\\provision\\PostReboot.ps1 -SkipSysprep"))
+			Expect(timeout).To(Equal(time.Hour))
+		})
+
+		It("passes sysprep options to the sysprep script", func() {
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{Owner: "owner", SkipRandomPassword: true})
+			err := e.ExecuteSysprepScript(time.Hour)
+			executeCommandCallArg, _ := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executeCommandCallArg).To(Equal("powershell.exe C:\\provision\\Sysprep.ps1" +
+				" -Owner " + PowershellStringArgument("owner") +
+				" -SkipRandomPassword"))
+		})
+
+		It("passes the hardening profile to the setup, post-reboot and sysprep scripts", func() {
+			e := NewScriptExecutor(fakeRemoteManager, SysprepOptions{HardeningProfile: "minimal"})
+			Expect(e.ExecuteSetupScript("2019.1")).To(Succeed())
+			Expect(e.ExecutePostRebootScript(time.Hour)).To(Succeed())
+			Expect(e.ExecuteSysprepScript(time.Hour)).To(Succeed())
+
+			Expect(fakeRemoteManager.ExecuteCommandArgsForCall(0)).To(Equal("powershell.exe C:\\provision\\Setup.ps1 -Version 2019.1 -HardeningProfile " + PowershellStringArgument("minimal")))
+			postReboot, _ := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(0)
+			Expect(postReboot).To(Equal("powershell.exe C:\\provision\\PostReboot.ps1 -HardeningProfile " + PowershellStringArgument("minimal")))
+			sysprep, _ := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(1)
+			Expect(sysprep).To(Equal("powershell.exe C:\\provision\\Sysprep.ps1 -HardeningProfile " + PowershellStringArgument("minimal")))
+		})
+
+	})
+
+	Describe("PowershellStringArgument", func() {
+		It("encodes the value so that neither cmd.exe nor PowerShell interpret it", func() {
+			argument := PowershellStringArgument(`50% "off" & 'more'`)
+
+			Expect(argument).To(Equal("([Text.Encoding]::UTF8.GetString([Convert]::FromBase64String('NTAlICJvZmYiICYgJ21vcmUn')))"))
+		})
 	})
 
 	Describe("PrepareVM", func() {
@@ -139,12 +214,13 @@ var _ = Describe("construct_helpers", func() {
 			It("fails when the provision dir cannot be created", func() {
 				mkDirError := errors.New("failed to create dir")
 				fakeVcenterClient.MakeDirectoryReturns(mkDirError)
+				fakeRemoteManager.ExecuteCommandReturns(1, errors.New("WinRM unavailable"))
 
 				err := vmConstruct.PrepareVM()
 
 				Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(1))
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("failed to create dir"))
+				Expect(err.Error()).To(Equal("creating C:\\provision\\ failed over vSphere guest operations: failed to create dir, and over WinRM: WinRM unavailable"))
 				Expect(fakeMessenger.CreateProvisionDirStartedCallCount()).To(Equal(1))
 				Expect(fakeMessenger.CreateProvisionDirSucceededCallCount()).To(Equal(0))
 			})
@@ -209,13 +285,11 @@ var _ = Describe("construct_helpers", func() {
 
 					err := vmConstruct.PrepareVM()
 					Expect(err).ToNot(HaveOccurred())
-					vmPath, artifact, dest, user, pass := fakeVcenterClient.UploadArtifactArgsForCall(0)
+					_, artifact, dest, progress := fakeGuestManager.UploadFileInGuestArgsForCall(0)
 					Expect(artifact).To(Equal("./LGPO.zip"))
-					Expect(vmPath).To(Equal("fakeVmPath"))
 					Expect(dest).To(Equal("C:\\provision\\LGPO.zip"))
-					Expect(user).To(Equal("fakeUser"))
-					Expect(pass).To(Equal("fakePass"))
-					Expect(fakeVcenterClient.UploadArtifactCallCount()).To(Equal(2))
+					Expect(progress).NotTo(BeNil())
+					Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(2))
 					Expect(fakeMessenger.UploadArtifactsStartedCallCount()).To(Equal(1))
 					Expect(fakeMessenger.UploadArtifactsSucceededCallCount()).To(Equal(1))
 
@@ -228,49 +302,154 @@ var _ = Describe("construct_helpers", func() {
 					Expect(fakeMessenger.UploadFileSucceededCallCount()).To(Equal(2))
 				})
 
+				It("reports the progress of each upload with its throughput and ETA", func() {
+					fakeGuestManager.UploadFileInGuestStub = func(_ context.Context, _, _ string, progress func(int64, int64)) error {
+						progress(0, 2048)
+						progress(1024, 2048)
+						progress(2048, 2048)
+						return nil
+					}
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).ToNot(HaveOccurred())
+
+					// the first report of each upload, then the report once it is complete
+					Expect(fakeMessenger.UploadFileProgressCallCount()).To(Equal(4))
+					sent, total, _, _ := fakeMessenger.UploadFileProgressArgsForCall(0)
+					Expect(sent).To(Equal(int64(0)))
+					Expect(total).To(Equal(int64(2048)))
+					sent, total, _, eta := fakeMessenger.UploadFileProgressArgsForCall(1)
+					Expect(sent).To(Equal(int64(2048)))
+					Expect(total).To(Equal(int64(2048)))
+					Expect(eta).To(BeZero())
+				})
+
+				It("removes the automation bundle file once it has been uploaded", func() {
+					fakeGuestManager.UploadFileInGuestStub = func(context.Context, string, string, func(int64, int64)) error {
+						Expect(assetsCleanedUp).To(BeFalse())
+						return nil
+					}
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(assetsCleanedUp).To(BeTrue())
+				})
 			})
 
 			Context("Fails to upload one or more artifacts", func() {
 				It("fails when it cannot upload LGPO", func() {
 
 					uploadError := errors.New("failed to upload LGPO")
-					fakeVcenterClient.UploadArtifactReturns(uploadError)
+					fakeGuestManager.UploadFileInGuestReturns(uploadError)
+					fakeRemoteManager.UploadArtifactReturns(errors.New("WinRM unavailable"))
 
 					err := vmConstruct.PrepareVM()
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(Equal("failed to upload LGPO"))
+					Expect(err.Error()).To(Equal("uploading ./LGPO.zip failed over vSphere guest operations: failed to upload LGPO, and over WinRM: WinRM unavailable"))
 
-					vmPath, artifact, _, _, _ := fakeVcenterClient.UploadArtifactArgsForCall(0)
+					_, artifact, _, _ := fakeGuestManager.UploadFileInGuestArgsForCall(0)
 					Expect(artifact).To(Equal("./LGPO.zip"))
-					Expect(vmPath).To(Equal("fakeVmPath"))
-					Expect(fakeVcenterClient.UploadArtifactCallCount()).To(Equal(1))
+					Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(1))
 					Expect(fakeMessenger.UploadArtifactsStartedCallCount()).To(Equal(1))
 					Expect(fakeMessenger.UploadArtifactsSucceededCallCount()).To(Equal(0))
 				})
 
+				It("fails before uploading anything when LGPO cannot be found", func() {
+					fakeAssets.LGPOFileReturns("", errors.New("could not find LGPO.zip"))
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).To(MatchError("could not find LGPO.zip"))
+					Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(0))
+				})
+
 				It("fails when it cannot upload Stemcell Automation scripts", func() {
 
 					uploadError := errors.New("failed to upload stemcell automation")
-					fakeVcenterClient.UploadArtifactReturnsOnCall(0, nil)
-					fakeVcenterClient.UploadArtifactReturnsOnCall(1, uploadError)
+					fakeGuestManager.UploadFileInGuestReturnsOnCall(0, nil)
+					fakeGuestManager.UploadFileInGuestReturnsOnCall(1, uploadError)
+					fakeRemoteManager.UploadArtifactReturns(errors.New("WinRM unavailable"))
 
 					err := vmConstruct.PrepareVM()
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(Equal("failed to upload stemcell automation"))
+					Expect(err.Error()).To(Equal("uploading ./StemcellAutomation.zip failed over vSphere guest operations: failed to upload stemcell automation, and over WinRM: WinRM unavailable"))
 
-					vmPath, artifact, _, _, _ := fakeVcenterClient.UploadArtifactArgsForCall(0)
+					_, artifact, _, _ := fakeGuestManager.UploadFileInGuestArgsForCall(0)
 					Expect(artifact).To(Equal("./LGPO.zip"))
-					Expect(vmPath).To(Equal("fakeVmPath"))
-					vmPath, artifact, _, _, _ = fakeVcenterClient.UploadArtifactArgsForCall(1)
+					_, artifact, _, _ = fakeGuestManager.UploadFileInGuestArgsForCall(1)
 					Expect(artifact).To(Equal("./StemcellAutomation.zip"))
-					Expect(vmPath).To(Equal("fakeVmPath"))
-					Expect(fakeVcenterClient.UploadArtifactCallCount()).To(Equal(2))
+					Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(2))
 					Expect(fakeMessenger.UploadArtifactsStartedCallCount()).To(Equal(1))
 					Expect(fakeMessenger.UploadArtifactsSucceededCallCount()).To(Equal(0))
 				})
 			})
 		})
 
+		Describe("artifact transport", func() {
+			It("falls back to WinRM when guest operations fail, enabling WinRM first", func() {
+				fakeGuestManager.UploadFileInGuestReturns(errors.New("file transfer URL blocked"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeRemoteManager.UploadArtifactCallCount()).To(Equal(2))
+				source, destination, _ := fakeRemoteManager.UploadArtifactArgsForCall(0)
+				Expect(source).To(Equal("./LGPO.zip"))
+				Expect(destination).To(Equal("C:\\provision\\LGPO.zip"))
+
+				transport, fallback, transportErr := fakeMessenger.ArtifactTransportFailedArgsForCall(0)
+				Expect(transport).To(Equal("vSphere guest operations"))
+				Expect(fallback).To(Equal("WinRM"))
+				Expect(transportErr).To(MatchError("file transfer URL blocked"))
+
+				// the enable WinRM phase does not enable it again
+				Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(1))
+				Expect(fakeMessenger.EnableWinRMSucceededCallCount()).To(Equal(1))
+			})
+
+			It("does not fall back to WinRM in guest-ops-only mode", func() {
+				vmConstruct.GuestOpsOnly = true
+				fakeGuestManager.UploadFileInGuestReturns(errors.New("file transfer URL blocked"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError(ContainSubstring("file transfer URL blocked")))
+
+				Expect(fakeRemoteManager.UploadArtifactCallCount()).To(Equal(0))
+				Expect(fakeMessenger.ArtifactTransportFailedCallCount()).To(Equal(0))
+				Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(0))
+			})
+
+			Context("over WinRM", func() {
+				BeforeEach(func() {
+					vmConstruct.Transport = TransportWinRM
+				})
+
+				It("creates directories and uploads files over WinRM", func() {
+					err := vmConstruct.PrepareVM()
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+					Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(0))
+					Expect(fakeRemoteManager.ExecuteCommandArgsForCall(0)).To(ContainSubstring("New-Item -ItemType Directory -Force -Path 'C:\\provision\\'"))
+					Expect(fakeRemoteManager.UploadArtifactCallCount()).To(Equal(2))
+					Expect(fakeMessenger.ArtifactTransportFailedCallCount()).To(Equal(0))
+					Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(1))
+				})
+
+				It("falls back to guest operations when WinRM fails", func() {
+					fakeWinRMEnabler.EnableReturnsOnCall(0, errors.New("WinRM blocked"))
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(1))
+					transport, fallback, _ := fakeMessenger.ArtifactTransportFailedArgsForCall(0)
+					Expect(transport).To(Equal("WinRM"))
+					Expect(fallback).To(Equal("vSphere guest operations"))
+					Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(2))
+				})
+			})
+		})
+
 		Describe("logs out users", func() {
 			It("returns success when active user is logged out", func() {
 				fakeRemoteManager.ExecuteCommandReturnsOnCall(0, 0, nil)
@@ -359,12 +538,36 @@ var _ = Describe("construct_helpers", func() {
 				Expect(fakeMessenger.ExecuteSetupScriptSucceededCallCount()).To(Equal(1))
 			})
 
+			It("records the boot time of the VM before the setup script reboots it", func() {
+				var calls []string
+				fakeRebootWaiter.RecordBootTimeCalls(func() error {
+					calls = append(calls, "recordBootTime")
+					return nil
+				})
+				fakeScriptExecutor.ExecuteSetupScriptCalls(func(string) error {
+					calls = append(calls, "executeSetupScript")
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(calls).To(Equal([]string{"recordBootTime", "executeSetupScript"}))
+			})
+
+			It("does not run the setup script when the boot time cannot be recorded", func() {
+				fakeRebootWaiter.RecordBootTimeReturns(errors.New("unable to read the boot time of the guest: exit code 1"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("unable to read the boot time of the guest: exit code 1"))
+				Expect(fakeScriptExecutor.ExecuteSetupScriptCallCount()).To(Equal(0))
+			})
+
 		})
 		Describe("can check if vm is rebooting", func() {
 			It("waits for reboot finished after the setup script has been executed", func() {
 				var calls []string
 
-				fakeRebootWaiter.WaitForRebootFinishedCalls(func() error {
+				fakeRebootWaiter.WaitForRebootFinishedCalls(func(context.Context, time.Duration) error {
 					calls = append(calls, "waitForRebootFinishedCall")
 					return nil
 				})
@@ -401,7 +604,7 @@ var _ = Describe("construct_helpers", func() {
 			It("checks that the reboot has completed before the post reboot script is executed", func() {
 				var calls []string
 
-				fakeRebootWaiter.WaitForRebootFinishedCalls(func() error {
+				fakeRebootWaiter.WaitForRebootFinishedCalls(func(context.Context, time.Duration) error {
 					calls = append(calls, "waitForRebootFinishedCall")
 					return nil
 				})
@@ -478,7 +681,7 @@ var _ = Describe("construct_helpers", func() {
 
 		Describe("can check that the VM is powered off", func() {
 			It("runs every minute and returns successfully if polling succeeds", func() {
-				fakePoller.PollReturns(nil)
+				fakePoller.PollWithOptionsReturns(nil)
 
 				fakeVcenterClient.IsPoweredOffReturnsOnCall(0, false, nil)
 				fakeVcenterClient.IsPoweredOffReturnsOnCall(1, true, nil)
@@ -488,10 +691,11 @@ var _ = Describe("construct_helpers", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(fakeMessenger.ShutdownCompletedCallCount()).To(Equal(1))
 
-				Expect(fakePoller.PollCallCount()).To(Equal(1))
-				pollDuration, pollFunc := fakePoller.PollArgsForCall(0)
+				Expect(fakePoller.PollWithOptionsCallCount()).To(Equal(1))
+				_, pollOptions, pollFunc := fakePoller.PollWithOptionsArgsForCall(0)
 
-				Expect(pollDuration).To(Equal(1 * time.Minute))
+				Expect(pollOptions.Interval).To(Equal(1 * time.Minute))
+				Expect(pollOptions.Jitter).To(Equal(0.1))
 
 				Expect(fakeVcenterClient.IsPoweredOffCallCount()).To(Equal(0))
 				Expect(fakeMessenger.WaitingForShutdownCallCount()).To(Equal(0))
@@ -515,7 +719,7 @@ var _ = Describe("construct_helpers", func() {
 
 			It("returns failure when it cannot determine VM power state", func() {
 				error := "cannot determine VM state"
-				fakePoller.PollReturnsOnCall(0, errors.New(error))
+				fakePoller.PollWithOptionsReturnsOnCall(0, errors.New(error))
 
 				err := vmConstruct.PrepareVM()
 				Expect(err).To(HaveOccurred())
@@ -524,5 +728,1300 @@ var _ = Describe("construct_helpers", func() {
 				Expect(fakeMessenger.ShutdownCompletedCallCount()).To(Equal(0))
 			})
 		})
+		Describe("hooks", func() {
+			BeforeEach(func() {
+				vmConstruct.Hooks = Hooks{
+					HookPreSetup:  {"/hooks/pre-setup/10-certs.ps1", "/hooks/pre-setup/20-agent.ps1"},
+					HookPostSetup: {"/hooks/post-setup/tweaks.ps1"},
+				}
+			})
+
+			It("uploads the hooks to the provision directory", func() {
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(3))
+				_, dir, _, _ := fakeVcenterClient.MakeDirectoryArgsForCall(1)
+				Expect(dir).To(Equal("C:\\provision\\hooks\\pre-setup"))
+				_, dir, _, _ = fakeVcenterClient.MakeDirectoryArgsForCall(2)
+				Expect(dir).To(Equal("C:\\provision\\hooks\\post-setup"))
+
+				Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(5))
+				_, source, destination, _ := fakeGuestManager.UploadFileInGuestArgsForCall(3)
+				Expect(source).To(Equal("/hooks/pre-setup/20-agent.ps1"))
+				Expect(destination).To(Equal("C:\\provision\\hooks\\pre-setup\\20-agent.ps1"))
+				Expect(fakeMessenger.UploadHooksSucceededCallCount()).To(Equal(1))
+			})
+
+			It("runs each hook in order at its hook point", func() {
+				var calls []string
+				fakeRemoteManager.ExecuteCommandWithTimeoutCalls(func(command string, _ time.Duration) (int, error) {
+					calls = append(calls, command)
+					return 0, nil
+				})
+				fakeScriptExecutor.ExecuteSetupScriptCalls(func(string) error {
+					calls = append(calls, "setup")
+					return nil
+				})
+				fakeRebootWaiter.WaitForRebootFinishedCalls(func(context.Context, time.Duration) error {
+					calls = append(calls, "reboot")
+					return nil
+				})
+				fakeScriptExecutor.ExecutePostRebootScriptCalls(func(time.Duration) error {
+					calls = append(calls, "post-reboot")
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(calls).To(Equal([]string{
+					`powershell.exe -NoProfile -ExecutionPolicy Bypass -File "C:\provision\hooks\pre-setup\10-certs.ps1"`,
+					`powershell.exe -NoProfile -ExecutionPolicy Bypass -File "C:\provision\hooks\pre-setup\20-agent.ps1"`,
+					"setup",
+					"reboot",
+					`powershell.exe -NoProfile -ExecutionPolicy Bypass -File "C:\provision\hooks\post-setup\tweaks.ps1"`,
+					"post-reboot",
+				}))
+				_, timeout := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(0)
+				Expect(timeout).To(Equal(30 * time.Minute))
+
+				Expect(fakeMessenger.RunHookStartedCallCount()).To(Equal(3))
+				point, name := fakeMessenger.RunHookStartedArgsForCall(2)
+				Expect(point).To(Equal("post-setup"))
+				Expect(name).To(Equal("tweaks.ps1"))
+				Expect(fakeMessenger.RunHookSucceededCallCount()).To(Equal(3))
+				Expect(fakeScriptExecutor.ExecuteSysprepScriptCallCount()).To(Equal(0))
+			})
+
+			It("fails construct when a hook exits non-zero", func() {
+				fakeRemoteManager.ExecuteCommandWithTimeoutReturns(3, nil)
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("pre-setup hook 10-certs.ps1 failed: exit code 3"))
+				Expect(fakeRemoteManager.ExecuteCommandWithTimeoutCallCount()).To(Equal(1))
+				Expect(fakeScriptExecutor.ExecuteSetupScriptCallCount()).To(Equal(0))
+			})
+
+			Context("with pre-sysprep hooks", func() {
+				BeforeEach(func() {
+					vmConstruct.Hooks[HookPreSysprep] = []string{"/hooks/pre-sysprep/seal.ps1"}
+				})
+
+				It("runs the hooks between the post-reboot script and sysprep", func() {
+					var calls []string
+					fakeScriptExecutor.ExecutePostRebootScriptWithoutSysprepCalls(func(time.Duration) error {
+						calls = append(calls, "post-reboot")
+						return nil
+					})
+					fakeRemoteManager.ExecuteCommandWithTimeoutCalls(func(command string, _ time.Duration) (int, error) {
+						calls = append(calls, command)
+						return 0, nil
+					})
+					fakeScriptExecutor.ExecuteSysprepScriptCalls(func(time.Duration) error {
+						calls = append(calls, "sysprep")
+						return nil
+					})
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(calls[len(calls)-3:]).To(Equal([]string{
+						"post-reboot",
+						`powershell.exe -NoProfile -ExecutionPolicy Bypass -File "C:\provision\hooks\pre-sysprep\seal.ps1"`,
+						"sysprep",
+					}))
+					Expect(fakeScriptExecutor.ExecutePostRebootScriptCallCount()).To(Equal(0))
+					Expect(fakeMessenger.ExecuteSysprepScriptSucceededCallCount()).To(Equal(1))
+				})
+
+				It("treats losing the connection during sysprep as a warning", func() {
+					fakeScriptExecutor.ExecuteSysprepScriptReturns(errors.New("winrm connection event: EOF"))
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakeMessenger.ExecutePostRebootWarningArgsForCall(0)).To(Equal("winrm connection event: EOF"))
+				})
+
+				It("records a checkpoint for every hook phase", func() {
+					fakeCheckpointStore := &constructfakes.FakeCheckpointStore{}
+					vmConstruct.Checkpoints = fakeCheckpointStore
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+
+					var phases []Phase
+					for i := 0; i < fakeCheckpointStore.SaveCallCount(); i++ {
+						_, checkpoint := fakeCheckpointStore.SaveArgsForCall(i)
+						phases = append(phases, checkpoint.Phase)
+					}
+					Expect(phases).To(Equal([]Phase{
+						PhaseCreateProvisionDir,
+						PhaseUploadArtifacts,
+						PhaseUploadHooks,
+						PhaseEnableWinRM,
+						PhaseValidateVMConnection,
+						PhaseExtractArtifacts,
+						PhaseLogOutUsers,
+						PhasePreSetupHooks,
+						PhaseExecuteSetupScript,
+						PhaseReboot,
+						PhasePostSetupHooks,
+						PhaseExecutePostRebootScript,
+						PhasePreSysprepHooks,
+						PhaseSysprep,
+						PhaseShutdown,
+					}))
+				})
+			})
+		})
+
+		Describe("Windows Updates", func() {
+			const (
+				testInstalled   = `powershell.exe -NoProfile -Command "Import-Module BOSH.WindowsUpdates; Test-InstalledUpdates"`
+				installUpdates  = `powershell.exe -NoProfile -Command "Import-Module BOSH.WindowsUpdates; exit (Invoke-WindowsUpdatesTask)"`
+				searchInstalled = `powershell.exe -NoProfile -Command "Import-Module BOSH.WindowsUpdates; Search-InstalledUpdates"`
+				baseUpdates     = "KB4470788 | 2018-11 Servicing Stack Update\r\n"
+				cumulative      = "KB4534273 | 2020-01 Cumulative Update\r\n"
+			)
+
+			var (
+				calls         []string
+				remainingFor  int
+				installResult func() (int, error)
+				installed     []string
+			)
+
+			BeforeEach(func() {
+				vmConstruct.WindowsUpdates = true
+				calls = nil
+				remainingFor = 1
+				installResult = func() (int, error) { return 3010, errors.New("powershell encountered an issue: ") }
+				installed = []string{baseUpdates, baseUpdates + cumulative}
+				fakeRemoteManager.ExecuteCommandWithTimeoutCalls(func(command string, _ time.Duration) (int, error) {
+					calls = append(calls, command)
+					if command == testInstalled {
+						if remainingFor == 0 {
+							return 0, nil
+						}
+						remainingFor--
+						return 1, errors.New("powershell encountered an issue: There are uninstalled updates\r\nAt line:1 char:38")
+					}
+					return installResult()
+				})
+				fakeRemoteManager.ExecuteCommandOutputCalls(func(command string) (string, error) {
+					calls = append(calls, command)
+					output := installed[0]
+					if len(installed) > 1 {
+						installed = installed[1:]
+					}
+					return output, nil
+				})
+			})
+
+			It("runs Install-WindowsUpdates after the post-setup hooks, rebooting until no updates are left", func() {
+				fakeRemoteManager.ExecuteCommandCalls(func(command string) (int, error) {
+					calls = append(calls, command)
+					return 0, nil
+				})
+				fakeRebootWaiter.WaitForRebootFinishedCalls(func(context.Context, time.Duration) error {
+					calls = append(calls, "reboot")
+					return nil
+				})
+				fakeScriptExecutor.ExecutePostRebootScriptCalls(func(time.Duration) error {
+					calls = append(calls, "post-reboot")
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(calls[len(calls)-9:]).To(Equal([]string{
+					"reboot",
+					testInstalled,
+					searchInstalled,
+					installUpdates,
+					searchInstalled,
+					`shutdown /r /f /t 10 /c "stembuild Windows Updates"`,
+					"reboot",
+					testInstalled,
+					"post-reboot",
+				}))
+				_, timeout := fakeRemoteManager.ExecuteCommandWithTimeoutArgsForCall(1)
+				Expect(timeout).To(Equal(2 * time.Hour))
+
+				Expect(fakeMessenger.InstallWindowsUpdatesStartedCallCount()).To(Equal(1))
+				Expect(fakeMessenger.InstallWindowsUpdatesStartedArgsForCall(0)).To(Equal(1))
+				Expect(fakeMessenger.WindowsUpdateInstalledCallCount()).To(Equal(1))
+				kb, title := fakeMessenger.WindowsUpdateInstalledArgsForCall(0)
+				Expect(kb).To(Equal("KB4534273"))
+				Expect(title).To(Equal("2020-01 Cumulative Update"))
+				Expect(fakeRebootWaiter.RecordBootTimeCallCount()).To(Equal(2))
+				Expect(fakeMessenger.WindowsUpdatesRebootFinishedCallCount()).To(Equal(1))
+				Expect(fakeMessenger.InstallWindowsUpdatesSucceededArgsForCall(0)).To(Equal(1))
+				Expect(fakeMessenger.WindowsUpdatesIterationLimitReachedCallCount()).To(Equal(0))
+			})
+
+			It("installs nothing when no updates are available", func() {
+				remainingFor = 0
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(calls).To(Equal([]string{testInstalled}))
+				Expect(fakeMessenger.InstallWindowsUpdatesStartedCallCount()).To(Equal(0))
+				Expect(fakeMessenger.InstallWindowsUpdatesSucceededArgsForCall(0)).To(Equal(0))
+			})
+
+			It("does not reboot when the updates installed need no restart", func() {
+				installResult = func() (int, error) { return 0, nil }
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(calls).To(Equal([]string{testInstalled, searchInstalled, installUpdates, searchInstalled, testInstalled}))
+				Expect(fakeMessenger.WindowsUpdatesRebootStartedCallCount()).To(Equal(0))
+				Expect(fakeMessenger.InstallWindowsUpdatesSucceededArgsForCall(0)).To(Equal(1))
+			})
+
+			It("warns and continues once the iteration limit is reached with updates left", func() {
+				vmConstruct.WindowsUpdatesMaxIterations = 1
+				remainingFor = 2
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(calls).To(Equal([]string{testInstalled, searchInstalled, installUpdates, searchInstalled, testInstalled}))
+				Expect(fakeMessenger.WindowsUpdatesIterationLimitReachedArgsForCall(0)).To(Equal(1))
+				Expect(fakeScriptExecutor.ExecutePostRebootScriptCallCount()).To(Equal(1))
+			})
+
+			It("does not warn at the iteration limit once no updates are left", func() {
+				vmConstruct.WindowsUpdatesMaxIterations = 1
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeMessenger.WindowsUpdatesIterationLimitReachedCallCount()).To(Equal(0))
+				Expect(fakeMessenger.InstallWindowsUpdatesSucceededArgsForCall(0)).To(Equal(1))
+			})
+
+			It("fails construct when the available updates cannot be searched", func() {
+				fakeRemoteManager.ExecuteCommandWithTimeoutReturns(1, errors.New("powershell encountered an issue: Exception from HRESULT: 0x8024402C"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to search for Windows Updates: powershell encountered an issue: Exception from HRESULT: 0x8024402C"))
+				Expect(fakeMessenger.InstallWindowsUpdatesStartedCallCount()).To(Equal(0))
+			})
+
+			It("fails construct when the installed updates cannot be listed", func() {
+				fakeRemoteManager.ExecuteCommandOutputReturns("", errors.New("powershell encountered an issue: Exception from HRESULT: 0x80240024"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to list the installed Windows Updates: powershell encountered an issue: Exception from HRESULT: 0x80240024"))
+				Expect(fakeMessenger.InstallWindowsUpdatesStartedCallCount()).To(Equal(0))
+			})
+
+			It("fails construct when Install-WindowsUpdates installs none of the remaining updates", func() {
+				installResult = func() (int, error) { return 0, nil }
+				installed = []string{baseUpdates}
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError(`Install-WindowsUpdates did not install any of the remaining Windows Updates, see C:\provision\log.log on the VM`))
+				Expect(fakeScriptExecutor.ExecutePostRebootScriptCallCount()).To(Equal(0))
+			})
+
+			It("fails construct when Install-WindowsUpdates fails", func() {
+				installResult = func() (int, error) {
+					return 1, errors.New("powershell encountered an issue: Exception from HRESULT: 0x80240024")
+				}
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to install Windows Updates batch 1: powershell encountered an issue: Exception from HRESULT: 0x80240024"))
+			})
+
+			It("fails construct when Install-WindowsUpdates exits non-zero", func() {
+				installResult = func() (int, error) { return 1, nil }
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to install Windows Updates batch 1: exit code 1"))
+			})
+
+			It("fails construct when the VM does not come back from a reboot", func() {
+				fakeRebootWaiter.WaitForRebootFinishedReturnsOnCall(1, errors.New("timed out"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("timed out"))
+				Expect(fakeMessenger.WindowsUpdatesRebootFinishedCallCount()).To(Equal(0))
+			})
+		})
+
+		Describe("proxy", func() {
+			var calls []string
+
+			BeforeEach(func() {
+				vmConstruct.HTTPProxy = "proxy.example.com:3128"
+				vmConstruct.ProxyBypassList = "*.example.com"
+
+				calls = nil
+				fakeRemoteManager.ExecuteCommandCalls(func(command string) (int, error) {
+					if strings.Contains(command, "ProxySettings") {
+						calls = append(calls, command)
+					}
+					return 0, nil
+				})
+				fakeScriptExecutor.ExecuteSetupScriptCalls(func(string) error {
+					calls = append(calls, "setup")
+					return nil
+				})
+				fakeScriptExecutor.ExecutePostRebootScriptCalls(func(time.Duration) error {
+					calls = append(calls, "post-reboot")
+					return nil
+				})
+			})
+
+			It("sets the proxy before the setup script and clears it before sysprep", func() {
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(calls).To(HaveLen(4))
+				Expect(calls[0]).To(HavePrefix(`powershell.exe -NoProfile -Command "Expand-Archive -LiteralPath 'C:\provision\bosh-psmodules.zip' -DestinationPath 'C:\Program Files\WindowsPowerShell\Modules\' -Force; Import-Module BOSH.Utils; Set-ProxySettings -HTTPProxy `))
+				Expect(calls[0]).To(ContainSubstring(PowershellStringArgument("proxy.example.com:3128")))
+				Expect(calls[0]).To(ContainSubstring("-BypassList " + PowershellStringArgument("*.example.com")))
+				Expect(calls[1:]).To(Equal([]string{
+					"setup",
+					`powershell.exe -NoProfile -Command "Import-Module BOSH.Utils; Clear-ProxySettings"`,
+					"post-reboot",
+				}))
+				Expect(fakeMessenger.SetProxySucceededCallCount()).To(Equal(1))
+				Expect(fakeMessenger.ClearProxySucceededCallCount()).To(Equal(1))
+			})
+
+			It("clears the proxy after the pre-sysprep hooks", func() {
+				vmConstruct.Hooks = Hooks{HookPreSysprep: {"/hooks/pre-sysprep/seal.ps1"}}
+				fakeRemoteManager.ExecuteCommandWithTimeoutCalls(func(string, time.Duration) (int, error) {
+					calls = append(calls, "pre-sysprep-hook")
+					return 0, nil
+				})
+				fakeScriptExecutor.ExecuteSysprepScriptCalls(func(time.Duration) error {
+					calls = append(calls, "sysprep")
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(calls[len(calls)-3:]).To(Equal([]string{
+					"pre-sysprep-hook",
+					`powershell.exe -NoProfile -Command "Import-Module BOSH.Utils; Clear-ProxySettings"`,
+					"sysprep",
+				}))
+			})
+
+			It("fails before the setup script when the proxy cannot be set", func() {
+				fakeRemoteManager.ExecuteCommandCalls(func(string) (int, error) {
+					return 1, nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to set the proxy of the VM: exit code 1"))
+				Expect(fakeScriptExecutor.ExecuteSetupScriptCallCount()).To(Equal(0))
+			})
+
+			It("fails before sysprep when the proxy cannot be cleared", func() {
+				fakeRemoteManager.ExecuteCommandCalls(func(command string) (int, error) {
+					if strings.Contains(command, "Clear-ProxySettings") {
+						return 0, errors.New("connection refused")
+					}
+					return 0, nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to clear the proxy of the VM: connection refused"))
+				Expect(fakeScriptExecutor.ExecutePostRebootScriptCallCount()).To(Equal(0))
+			})
+
+			It("leaves the proxy of the VM alone when none is configured", func() {
+				vmConstruct.HTTPProxy = ""
+				vmConstruct.ProxyBypassList = ""
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(calls).To(Equal([]string{"setup", "post-reboot"}))
+				Expect(fakeMessenger.SetProxyStartedCallCount()).To(Equal(0))
+			})
+		})
+
+		Describe("hardening", func() {
+			var gpoDir string
+
+			BeforeEach(func() {
+				var err error
+				gpoDir, err = ioutil.TempDir("", "hardening-gpo")
+				Expect(err).NotTo(HaveOccurred())
+				machineDir := filepath.Join(gpoDir, "DomainSysvol", "GPO", "Machine")
+				Expect(os.MkdirAll(machineDir, 0700)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(machineDir, "registry.txt"), []byte("; policy"), 0600)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(gpoDir)).To(Succeed())
+			})
+
+			It("records the hardening profile in the VM configuration before the setup script applies it", func() {
+				vmConstruct.HardeningProfile = "minimal"
+				fakeVcenterClient.SetHardeningProfileCalls(func(string, string) error {
+					Expect(fakeScriptExecutor.ExecuteSetupScriptCallCount()).To(Equal(0))
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeVcenterClient.SetHardeningProfileCallCount()).To(Equal(1))
+				vmPath, profile := fakeVcenterClient.SetHardeningProfileArgsForCall(0)
+				Expect(vmPath).To(Equal("fakeVmPath"))
+				Expect(profile).To(Equal("minimal"))
+				Expect(fakeMessenger.RecordHardeningProfileStartedArgsForCall(0)).To(Equal("minimal"))
+				Expect(fakeMessenger.RecordHardeningProfileSucceededCallCount()).To(Equal(1))
+			})
+
+			It("fails when the hardening profile cannot be recorded", func() {
+				vmConstruct.HardeningProfile = "cis"
+				fakeVcenterClient.SetHardeningProfileReturns(errors.New("vcenter_client - hardening profile of fakeVmPath could not be recorded"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to record the hardening profile of the VM: vcenter_client - hardening profile of fakeVmPath could not be recorded"))
+				Expect(fakeScriptExecutor.ExecuteSetupScriptCallCount()).To(Equal(0))
+				Expect(fakeMessenger.ShutdownCompletedCallCount()).To(Equal(0))
+			})
+
+			It("records nothing when no hardening profile is set", func() {
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeVcenterClient.SetHardeningProfileCallCount()).To(Equal(0))
+			})
+
+			It("uploads the GPO backup with the other artifacts and extracts it next to them", func() {
+				vmConstruct.HardeningProfile = "custom"
+				vmConstruct.HardeningGPODir = gpoDir
+				var entries []string
+				fakeGuestManager.UploadFileInGuestCalls(func(_ context.Context, source, destination string, _ func(sent, total int64)) error {
+					if destination != `C:\provision\hardening-gpo.zip` {
+						return nil
+					}
+					archive, err := zip.OpenReader(source)
+					Expect(err).NotTo(HaveOccurred())
+					defer archive.Close()
+					for _, f := range archive.File {
+						entries = append(entries, f.Name)
+					}
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(3))
+				Expect(entries).To(ContainElement("DomainSysvol/GPO/Machine/registry.txt"))
+				Expect(fakeMessenger.UploadFileStartedArgsForCall(2)).To(Equal("hardening GPO backup"))
+				Expect(fakeRemoteManager.ExtractArchiveCallCount()).To(Equal(2))
+				source, destination := fakeRemoteManager.ExtractArchiveArgsForCall(1)
+				Expect(source).To(Equal(`C:\provision\hardening-gpo.zip`))
+				Expect(destination).To(Equal(`C:\provision\hardening-gpo`))
+			})
+
+			It("fails when the GPO backup cannot be uploaded", func() {
+				vmConstruct.HardeningGPODir = gpoDir
+				vmConstruct.GuestOpsOnly = true
+				fakeGuestManager.UploadFileInGuestCalls(func(_ context.Context, _, destination string, _ func(sent, total int64)) error {
+					if destination == `C:\provision\hardening-gpo.zip` {
+						return errors.New("upload failed")
+					}
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError(ContainSubstring("upload failed")))
+				Expect(fakeRemoteManager.ExtractArchiveCallCount()).To(Equal(0))
+			})
+		})
+
+		Describe("timeouts", func() {
+			It("waits as long as configured", func() {
+				vmConstruct.RebootTimeout = 20 * time.Minute
+				vmConstruct.PostRebootTimeout = 36 * time.Hour
+				vmConstruct.ShutdownPollInterval = 5 * time.Second
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, rebootTimeout := fakeRebootWaiter.WaitForRebootFinishedArgsForCall(0)
+				Expect(rebootTimeout).To(Equal(20 * time.Minute))
+				Expect(fakeScriptExecutor.ExecutePostRebootScriptArgsForCall(0)).To(Equal(36 * time.Hour))
+				_, pollOptions, _ := fakePoller.PollWithOptionsArgsForCall(0)
+				Expect(pollOptions.Interval).To(Equal(5 * time.Second))
+				Expect(pollOptions.Timeout).To(BeZero())
+			})
+
+			It("stops waiting for the VM to power off after the shutdown timeout", func() {
+				start := time.Now()
+				now := start
+				fakeClock := &pollerfakes.FakeClock{}
+				fakeClock.NowCalls(func() time.Time { return now })
+				fakeClock.AfterCalls(func(d time.Duration) <-chan time.Time {
+					now = now.Add(d)
+					c := make(chan time.Time, 1)
+					c <- now
+					return c
+				})
+				fakePoller.PollWithOptionsCalls((&poller.Poller{Clock: fakeClock}).PollWithOptions)
+				vmConstruct.ShutdownTimeout = 10 * time.Minute
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("VM did not power off within 10m0s"))
+				Expect(now.Sub(start)).To(Equal(10 * time.Minute))
+				Expect(fakeVcenterClient.IsPoweredOffCallCount()).To(BeNumerically(">=", 9))
+			})
+
+			Context("with an overall deadline", func() {
+				It("shortens waits so that they end by the deadline", func() {
+					vmConstruct.Timeout = time.Hour
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+
+					_, rebootTimeout := fakeRebootWaiter.WaitForRebootFinishedArgsForCall(0)
+					Expect(rebootTimeout).To(BeNumerically("~", time.Hour, time.Minute))
+					Expect(fakeScriptExecutor.ExecutePostRebootScriptArgsForCall(0)).To(BeNumerically("~", time.Hour, time.Minute))
+				})
+
+				It("stops before the next phase once the deadline has passed", func() {
+					vmConstruct.Timeout = 10 * time.Millisecond
+					fakeDiagnostics := &constructfakes.FakeDiagnosticsCollector{}
+					fakeDiagnostics.CollectReturns("bundle.tgz", nil)
+					vmConstruct.Diagnostics = fakeDiagnostics
+					fakeScriptExecutor.ExecuteSetupScriptCalls(func(string) error {
+						time.Sleep(20 * time.Millisecond)
+						return nil
+					})
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).To(MatchError("construct did not finish within 10ms\nGuest logs and a console screenshot were saved to bundle.tgz"))
+
+					phase, _ := fakeDiagnostics.CollectArgsForCall(0)
+					Expect(phase).To(Equal(PhaseReboot))
+					Expect(fakeRebootWaiter.WaitForRebootFinishedCallCount()).To(Equal(0))
+				})
+
+				It("reports a phase that fails after the deadline as timed out", func() {
+					vmConstruct.Timeout = 10 * time.Millisecond
+					fakeScriptExecutor.ExecuteSetupScriptCalls(func(string) error {
+						time.Sleep(20 * time.Millisecond)
+						return errors.New("connection reset")
+					})
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).To(MatchError("construct did not finish within 10ms: connection reset"))
+				})
+			})
+		})
+
+		Describe("interruption", func() {
+			var (
+				cancel          context.CancelFunc
+				fakeDiagnostics *constructfakes.FakeDiagnosticsCollector
+				fakeSnapshots   *constructfakes.FakeSnapshotManager
+			)
+
+			BeforeEach(func() {
+				var ctx context.Context
+				ctx, cancel = context.WithCancel(context.Background())
+				vmConstruct = NewVMConstruct(
+					ctx,
+					fakeRemoteManager,
+					"fakeUser",
+					"fakePass",
+					"fakeVmPath",
+					fakeVcenterClient,
+					fakeGuestManager,
+					fakeWinRMEnabler,
+					fakeVMConnectionValidator,
+					fakeMessenger,
+					fakePoller,
+					fakeVersionGetter,
+					fakeRebootWaiter,
+					fakeScriptExecutor,
+					fakeAssets,
+				)
+				vmConstruct.RebootWaitTime = 0
+
+				fakeDiagnostics = &constructfakes.FakeDiagnosticsCollector{}
+				vmConstruct.Diagnostics = fakeDiagnostics
+				fakeSnapshots = &constructfakes.FakeSnapshotManager{}
+				vmConstruct.Snapshots = fakeSnapshots
+				vmConstruct.SnapshotName = "pre-construct"
+			})
+
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("stops after the phase that was running and reports the state of the VM", func() {
+				fakeScriptExecutor.ExecuteSetupScriptCalls(func(string) error {
+					cancel()
+					return errors.New("connection closed")
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("construct was interrupted during the execute-setup-script phase: context canceled"))
+
+				Expect(fakeMessenger.ConstructInterruptedCallCount()).To(Equal(1))
+				phase, state := fakeMessenger.ConstructInterruptedArgsForCall(0)
+				Expect(phase).To(Equal(string(PhaseExecuteSetupScript)))
+				Expect(state).To(Equal("The VM was left part way through the execute-setup-script phase. Run construct with -snapshot pre-construct -revert-snapshot to start over."))
+
+				Expect(fakeRebootWaiter.WaitForRebootFinishedCallCount()).To(Equal(0))
+				Expect(fakeDiagnostics.CollectCallCount()).To(Equal(0))
+				Expect(fakeSnapshots.RevertToSnapshotCallCount()).To(Equal(0))
+			})
+
+			It("does not start the next phase once interrupted", func() {
+				fakeScriptExecutor.ExecuteSetupScriptCalls(func(string) error {
+					cancel()
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("construct was interrupted during the reboot phase: context canceled"))
+				Expect(fakeRebootWaiter.WaitForRebootFinishedCallCount()).To(Equal(0))
+			})
+
+			It("reports a VM that was not changed", func() {
+				cancel()
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(HaveOccurred())
+
+				_, state := fakeMessenger.ConstructInterruptedArgsForCall(0)
+				Expect(state).To(Equal("The VM was not changed."))
+				Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+			})
+
+			It("suggests resuming when phases are checkpointed", func() {
+				vmConstruct.Checkpoints = &constructfakes.FakeCheckpointStore{}
+				fakeGuestManager.UploadFileInGuestCalls(func(context.Context, string, string, func(int64, int64)) error {
+					cancel()
+					return errors.New("upload aborted")
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(HaveOccurred())
+
+				_, state := fakeMessenger.ConstructInterruptedArgsForCall(0)
+				Expect(state).To(ContainSubstring("Re-run construct with -resume to continue after the last completed phase."))
+			})
+
+			It("warns that sysprep may still be running", func() {
+				fakeScriptExecutor.ExecutePostRebootScriptCalls(func(time.Duration) error {
+					cancel()
+					return errors.New("connection closed")
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(HaveOccurred())
+
+				_, state := fakeMessenger.ConstructInterruptedArgsForCall(0)
+				Expect(state).To(HavePrefix("Sysprep may still be running in the VM and shut it down, so construct cannot be resumed."))
+			})
+
+			It("waits for the reboot and power off with its context", func() {
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				rebootCtx, _ := fakeRebootWaiter.WaitForRebootFinishedArgsForCall(0)
+				pollCtx, _, _ := fakePoller.PollWithOptionsArgsForCall(0)
+				Expect(rebootCtx.Done()).NotTo(BeNil())
+				Expect(pollCtx.Done()).NotTo(BeNil())
+			})
+		})
+
+		Describe("diagnostics", func() {
+			var fakeDiagnostics *constructfakes.FakeDiagnosticsCollector
+
+			BeforeEach(func() {
+				fakeDiagnostics = &constructfakes.FakeDiagnosticsCollector{}
+				vmConstruct.Diagnostics = fakeDiagnostics
+			})
+
+			It("does not collect diagnostics when construct succeeds", func() {
+				err := vmConstruct.PrepareVM()
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeDiagnostics.CollectCallCount()).To(Equal(0))
+			})
+
+			It("collects diagnostics for the failed phase and points to the bundle", func() {
+				setupErr := errors.New("failed to execute setup script")
+				fakeScriptExecutor.ExecuteSetupScriptReturns(setupErr)
+				fakeDiagnostics.CollectReturns("/tmp/stembuild-diagnostics.tgz", nil)
+
+				err := vmConstruct.PrepareVM()
+
+				Expect(err).To(MatchError("failed to execute setup script\nGuest logs and a console screenshot were saved to /tmp/stembuild-diagnostics.tgz"))
+				phase, failure := fakeDiagnostics.CollectArgsForCall(0)
+				Expect(phase).To(Equal(PhaseExecuteSetupScript))
+				Expect(failure).To(MatchError(setupErr))
+				Expect(fakeMessenger.CollectDiagnosticsStartedCallCount()).To(Equal(1))
+				Expect(fakeMessenger.CollectDiagnosticsSucceededCallCount()).To(Equal(1))
+			})
+
+			It("returns the original error when diagnostics cannot be collected", func() {
+				fakeVcenterClient.MakeDirectoryReturns(errors.New("failed to create dir"))
+				fakeRemoteManager.ExecuteCommandReturns(1, errors.New("WinRM unavailable"))
+				collectErr := errors.New("cannot write bundle")
+				fakeDiagnostics.CollectReturns("", collectErr)
+
+				err := vmConstruct.PrepareVM()
+
+				Expect(err).To(MatchError(ContainSubstring("failed to create dir")))
+				Expect(fakeMessenger.CollectDiagnosticsFailedCallCount()).To(Equal(1))
+				Expect(fakeMessenger.CollectDiagnosticsFailedArgsForCall(0)).To(MatchError(collectErr))
+			})
+		})
+
+		Describe("checkpoints", func() {
+			var fakeCheckpointStore *constructfakes.FakeCheckpointStore
+
+			BeforeEach(func() {
+				fakeCheckpointStore = &constructfakes.FakeCheckpointStore{}
+				vmConstruct.Checkpoints = fakeCheckpointStore
+				fakeVersionGetter.GetVersionReturns("2019.1")
+			})
+
+			It("records every completed phase and clears the checkpoint once construct succeeds", func() {
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeCheckpointStore.LoadCallCount()).To(Equal(0))
+				Expect(fakeCheckpointStore.SaveCallCount()).To(Equal(10))
+				var phases []Phase
+				for i := 0; i < fakeCheckpointStore.SaveCallCount(); i++ {
+					vmPath, checkpoint := fakeCheckpointStore.SaveArgsForCall(i)
+					Expect(vmPath).To(Equal("fakeVmPath"))
+					Expect(checkpoint.Version).To(Equal("2019.1"))
+					phases = append(phases, checkpoint.Phase)
+				}
+				Expect(phases).To(Equal([]Phase{
+					PhaseCreateProvisionDir,
+					PhaseUploadArtifacts,
+					PhaseEnableWinRM,
+					PhaseValidateVMConnection,
+					PhaseExtractArtifacts,
+					PhaseLogOutUsers,
+					PhaseExecuteSetupScript,
+					PhaseReboot,
+					PhaseExecutePostRebootScript,
+					PhaseShutdown,
+				}))
+
+				Expect(fakeCheckpointStore.ClearCallCount()).To(Equal(2))
+				Expect(fakeCheckpointStore.ClearArgsForCall(1)).To(Equal("fakeVmPath"))
+			})
+
+			It("does not enable WinRM in guest-ops-only mode", func() {
+				vmConstruct.GuestOpsOnly = true
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				var phases []Phase
+				for i := 0; i < fakeCheckpointStore.SaveCallCount(); i++ {
+					_, checkpoint := fakeCheckpointStore.SaveArgsForCall(i)
+					phases = append(phases, checkpoint.Phase)
+				}
+				Expect(phases).To(Equal([]Phase{
+					PhaseCreateProvisionDir,
+					PhaseUploadArtifacts,
+					PhaseValidateVMConnection,
+					PhaseExtractArtifacts,
+					PhaseLogOutUsers,
+					PhaseExecuteSetupScript,
+					PhaseReboot,
+					PhaseExecutePostRebootScript,
+					PhaseShutdown,
+				}))
+				Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(0))
+				Expect(fakeMessenger.EnableWinRMStartedCallCount()).To(Equal(0))
+			})
+
+			It("does not record a phase that failed", func() {
+				fakeScriptExecutor.ExecuteSetupScriptReturns(errors.New("setup failed"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("setup failed"))
+
+				lastSaved := fakeCheckpointStore.SaveCallCount() - 1
+				_, checkpoint := fakeCheckpointStore.SaveArgsForCall(lastSaved)
+				Expect(checkpoint.Phase).To(Equal(PhaseLogOutUsers))
+				Expect(fakeCheckpointStore.ClearCallCount()).To(Equal(1))
+			})
+
+			It("returns an error when a checkpoint cannot be recorded", func() {
+				fakeCheckpointStore.SaveReturns(errors.New("disk full"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("failed to record construct checkpoint: disk full"))
+				Expect(fakeCheckpointStore.SaveCallCount()).To(Equal(1))
+			})
+
+			Context("when resuming", func() {
+				BeforeEach(func() {
+					vmConstruct.Resume = true
+				})
+
+				It("starts from the beginning when no checkpoint exists", func() {
+					fakeCheckpointStore.LoadReturns(Checkpoint{}, false, nil)
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeMessenger.NoCheckpointFoundCallCount()).To(Equal(1))
+					Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(1))
+					Expect(fakeCheckpointStore.SaveCallCount()).To(Equal(10))
+				})
+
+				It("skips completed phases but re-establishes the WinRM connection", func() {
+					fakeCheckpointStore.LoadReturns(Checkpoint{Phase: PhaseExecuteSetupScript, Version: "2019.1"}, true, nil)
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeMessenger.ResumingFromCheckpointCallCount()).To(Equal(1))
+					Expect(fakeMessenger.ResumingFromCheckpointArgsForCall(0)).To(Equal("execute-setup-script"))
+
+					Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+					Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(0))
+					Expect(fakeRemoteManager.ExtractArchiveCallCount()).To(Equal(0))
+					Expect(fakeRemoteManager.ExecuteCommandCallCount()).To(Equal(0))
+					Expect(fakeScriptExecutor.ExecuteSetupScriptCallCount()).To(Equal(0))
+
+					Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(1))
+					Expect(fakeVMConnectionValidator.ValidateCallCount()).To(Equal(1))
+					Expect(fakeRebootWaiter.WaitForRebootFinishedCallCount()).To(Equal(1))
+					Expect(fakeScriptExecutor.ExecutePostRebootScriptCallCount()).To(Equal(1))
+					Expect(fakePoller.PollWithOptionsCallCount()).To(Equal(1))
+
+					Expect(fakeCheckpointStore.SaveCallCount()).To(Equal(3))
+					_, checkpoint := fakeCheckpointStore.SaveArgsForCall(0)
+					Expect(checkpoint.Phase).To(Equal(PhaseReboot))
+				})
+
+				It("does not reconnect WinRM when only the shutdown wait remains", func() {
+					fakeCheckpointStore.LoadReturns(Checkpoint{Phase: PhaseExecutePostRebootScript, Version: "2019.1"}, true, nil)
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(0))
+					Expect(fakeVMConnectionValidator.ValidateCallCount()).To(Equal(0))
+					Expect(fakeScriptExecutor.ExecutePostRebootScriptCallCount()).To(Equal(0))
+					Expect(fakePoller.PollWithOptionsCallCount()).To(Equal(1))
+				})
+
+				It("re-runs a phase whose results cannot be found on the guest", func() {
+					fakeCheckpointStore.LoadReturns(Checkpoint{Phase: PhaseUploadArtifacts, Version: "2019.1"}, true, nil)
+					fakeGuestManager.ExitCodeForProgramInGuestReturnsOnCall(0, 1, nil)
+					fakeGuestManager.ExitCodeForProgramInGuestReturnsOnCall(1, 0, nil)
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeGuestManager.StartProgramInGuestCallCount()).To(Equal(2))
+					_, command, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+					Expect(command).To(ContainSubstring("powershell.exe"))
+					Expect(args).To(ContainSubstring(EncodePowershellCommand([]byte(
+						"if ((Test-Path 'C:\\provision\\LGPO.zip') -and (Test-Path 'C:\\provision\\StemcellAutomation.zip')) { exit 0 } else { exit 1 }",
+					))))
+
+					Expect(fakeMessenger.CheckpointNotConfirmedCallCount()).To(Equal(1))
+					Expect(fakeMessenger.CheckpointNotConfirmedArgsForCall(0)).To(Equal("upload-artifacts"))
+					Expect(fakeMessenger.ResumingFromCheckpointArgsForCall(0)).To(Equal("create-provision-dir"))
+
+					Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+					Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(2))
+				})
+
+				It("returns an error when the checkpoint was recorded by a different stembuild version", func() {
+					fakeCheckpointStore.LoadReturns(Checkpoint{Phase: PhaseReboot, Version: "2019.0"}, true, nil)
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).To(MatchError("checkpoint for fakeVmPath was recorded by stembuild 2019.0 and cannot be resumed by stembuild 2019.1"))
+					Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+				})
+
+				It("returns an error when the checkpoint cannot be loaded", func() {
+					fakeCheckpointStore.LoadReturns(Checkpoint{}, false, errors.New("corrupt"))
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).To(MatchError("corrupt"))
+				})
+			})
+		})
+
+		Describe("snapshots", func() {
+			var fakeSnapshots *constructfakes.FakeSnapshotManager
+
+			BeforeEach(func() {
+				fakeSnapshots = &constructfakes.FakeSnapshotManager{}
+				vmConstruct.Snapshots = fakeSnapshots
+				vmConstruct.SnapshotName = "pre-construct"
+			})
+
+			It("does not touch snapshots when no snapshot name is given", func() {
+				vmConstruct.SnapshotName = ""
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSnapshots.HasSnapshotCallCount()).To(Equal(0))
+				Expect(fakeSnapshots.CreateSnapshotCallCount()).To(Equal(0))
+			})
+
+			It("takes the snapshot before the first phase and keeps it after success", func() {
+				fakeSnapshots.CreateSnapshotCalls(func(string, string) error {
+					Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+					return nil
+				})
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSnapshots.HasSnapshotArgsForCall(0)).To(Equal("pre-construct"))
+				name, _ := fakeSnapshots.CreateSnapshotArgsForCall(0)
+				Expect(name).To(Equal("pre-construct"))
+				Expect(fakeMessenger.CreateSnapshotStartedArgsForCall(0)).To(Equal("pre-construct"))
+				Expect(fakeMessenger.CreateSnapshotSucceededCallCount()).To(Equal(1))
+				Expect(fakeSnapshots.RevertToSnapshotCallCount()).To(Equal(0))
+				Expect(fakeSnapshots.RemoveSnapshotCallCount()).To(Equal(0))
+			})
+
+			It("reuses a snapshot left behind by an earlier construct", func() {
+				fakeSnapshots.HasSnapshotReturns(true, nil)
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSnapshots.CreateSnapshotCallCount()).To(Equal(0))
+				Expect(fakeMessenger.UsingExistingSnapshotArgsForCall(0)).To(Equal("pre-construct"))
+			})
+
+			It("returns an error before any phase runs when the snapshot cannot be taken", func() {
+				fakeSnapshots.CreateSnapshotReturns(errors.New("datastore full"))
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).To(MatchError("unable to create snapshot pre-construct: datastore full"))
+				Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+			})
+
+			It("deletes the snapshot after success when asked to", func() {
+				vmConstruct.DeleteSnapshot = true
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSnapshots.RemoveSnapshotArgsForCall(0)).To(Equal("pre-construct"))
+				Expect(fakeMessenger.RemoveSnapshotSucceededCallCount()).To(Equal(1))
+			})
+
+			Context("when a phase fails", func() {
+				BeforeEach(func() {
+					vmConstruct.DeleteSnapshot = true
+					fakeScriptExecutor.ExecuteSetupScriptReturns(errors.New("setup failed"))
+				})
+
+				It("reverts to the snapshot after collecting diagnostics", func() {
+					fakeDiagnostics := &constructfakes.FakeDiagnosticsCollector{}
+					fakeDiagnostics.CollectCalls(func(Phase, error) (string, error) {
+						Expect(fakeSnapshots.RevertToSnapshotCallCount()).To(Equal(0))
+						return "bundle.tgz", nil
+					})
+					vmConstruct.Diagnostics = fakeDiagnostics
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).To(MatchError("setup failed\nGuest logs and a console screenshot were saved to bundle.tgz\nThe VM was reverted to snapshot pre-construct"))
+
+					Expect(fakeSnapshots.RevertToSnapshotArgsForCall(0)).To(Equal("pre-construct"))
+					Expect(fakeMessenger.RevertSnapshotSucceededCallCount()).To(Equal(1))
+					Expect(fakeSnapshots.RemoveSnapshotCallCount()).To(Equal(0))
+				})
+
+				It("returns the original error when the revert fails", func() {
+					fakeSnapshots.RevertToSnapshotReturns(errors.New("task failed"))
+
+					err := vmConstruct.PrepareVM()
+					Expect(err).To(MatchError("setup failed"))
+					Expect(fakeMessenger.RevertSnapshotFailedArgsForCall(0)).To(MatchError("unable to revert to snapshot pre-construct: task failed"))
+				})
+			})
+		})
+	})
+
+	Describe("RevertSnapshot", func() {
+		var (
+			fakeSnapshots       *constructfakes.FakeSnapshotManager
+			fakeCheckpointStore *constructfakes.FakeCheckpointStore
+		)
+
+		BeforeEach(func() {
+			fakeSnapshots = &constructfakes.FakeSnapshotManager{}
+			fakeCheckpointStore = &constructfakes.FakeCheckpointStore{}
+			vmConstruct.Snapshots = fakeSnapshots
+			vmConstruct.Checkpoints = fakeCheckpointStore
+			vmConstruct.SnapshotName = "pre-construct"
+		})
+
+		It("reverts the VM and discards its checkpoint without running construct", func() {
+			err := vmConstruct.RevertSnapshot()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSnapshots.RevertToSnapshotArgsForCall(0)).To(Equal("pre-construct"))
+			Expect(fakeCheckpointStore.ClearArgsForCall(0)).To(Equal("fakeVmPath"))
+			Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the revert fails", func() {
+			fakeSnapshots.RevertToSnapshotReturns(errors.New("no such snapshot"))
+
+			err := vmConstruct.RevertSnapshot()
+			Expect(err).To(MatchError("unable to revert to snapshot pre-construct: no such snapshot"))
+			Expect(fakeCheckpointStore.ClearCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("dry run", func() {
+		var (
+			fakeSnapshots *constructfakes.FakeSnapshotManager
+			planned       func() []string
+		)
+
+		BeforeEach(func() {
+			fakeSnapshots = &constructfakes.FakeSnapshotManager{}
+			vmConstruct.Snapshots = fakeSnapshots
+			vmConstruct.DryRun = true
+			fakeVersionGetter.GetVersionReturns("2019.1")
+
+			planned = func() []string {
+				var operations []string
+				for i := 0; i < fakeMessenger.PlannedOperationCallCount(); i++ {
+					phase, operation := fakeMessenger.PlannedOperationArgsForCall(i)
+					operations = append(operations, phase+": "+operation)
+				}
+				return operations
+			}
+		})
+
+		It("reports every change construct would make, in order, without making any", func() {
+			err := vmConstruct.PrepareVM()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(planned()).To(Equal([]string{
+				`create-provision-dir: create directory C:\provision\`,
+				`upload-artifacts: upload ./LGPO.zip to C:\provision\LGPO.zip`,
+				`upload-artifacts: upload ./StemcellAutomation.zip to C:\provision\StemcellAutomation.zip`,
+				`enable-winrm: enable WinRM through VMware Tools guest operations`,
+				`extract-artifacts: extract C:\provision\StemcellAutomation.zip to C:\provision\`,
+				`log-out-users: log out any user logged in to the VM`,
+				`execute-setup-script: run C:\provision\Setup.ps1 -Version 2019.1, which reboots the VM`,
+				`execute-post-reboot-script: run C:\provision\PostReboot.ps1, which syspreps the VM and shuts it down`,
+			}))
+			Expect(fakeMessenger.DryRunStartedCallCount()).To(Equal(1))
+			Expect(fakeMessenger.DryRunSucceededCallCount()).To(Equal(1))
+
+			Expect(fakeVcenterClient.MakeDirectoryCallCount()).To(Equal(0))
+			Expect(fakeGuestManager.UploadFileInGuestCallCount()).To(Equal(0))
+			Expect(fakeWinRMEnabler.EnableCallCount()).To(Equal(0))
+			Expect(fakeRemoteManager.ExtractArchiveCallCount()).To(Equal(0))
+			Expect(fakeRemoteManager.ExecuteCommandCallCount()).To(Equal(0))
+			Expect(fakeScriptExecutor.ExecuteSetupScriptCallCount()).To(Equal(0))
+			Expect(fakeScriptExecutor.ExecutePostRebootScriptCallCount()).To(Equal(0))
+			Expect(fakePoller.PollWithOptionsCallCount()).To(Equal(0))
+		})
+
+		It("does not plan to enable WinRM in guest-ops-only mode", func() {
+			vmConstruct.GuestOpsOnly = true
+
+			err := vmConstruct.PrepareVM()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(planned()).To(Equal([]string{
+				`create-provision-dir: create directory C:\provision\`,
+				`upload-artifacts: upload ./LGPO.zip to C:\provision\LGPO.zip`,
+				`upload-artifacts: upload ./StemcellAutomation.zip to C:\provision\StemcellAutomation.zip`,
+				`extract-artifacts: extract C:\provision\StemcellAutomation.zip to C:\provision\`,
+				`log-out-users: log out any user logged in to the VM`,
+				`execute-setup-script: run C:\provision\Setup.ps1 -Version 2019.1, which reboots the VM`,
+				`execute-post-reboot-script: run C:\provision\PostReboot.ps1, which syspreps the VM and shuts it down`,
+			}))
+		})
+
+		It("reports the hardening GPO backup and the recording of the hardening profile", func() {
+			vmConstruct.HardeningProfile = "custom"
+			vmConstruct.HardeningGPODir = "/gpo/legacy-apps"
+
+			err := vmConstruct.PrepareVM()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(planned()).To(Equal([]string{
+				`create-provision-dir: create directory C:\provision\`,
+				`upload-artifacts: upload ./LGPO.zip to C:\provision\LGPO.zip`,
+				`upload-artifacts: upload ./StemcellAutomation.zip to C:\provision\StemcellAutomation.zip`,
+				`upload-artifacts: upload the GPO backup in /gpo/legacy-apps to C:\provision\hardening-gpo.zip`,
+				`enable-winrm: enable WinRM through VMware Tools guest operations`,
+				`extract-artifacts: extract C:\provision\StemcellAutomation.zip to C:\provision\`,
+				`extract-artifacts: extract C:\provision\hardening-gpo.zip to C:\provision\hardening-gpo`,
+				`log-out-users: log out any user logged in to the VM`,
+				`record-hardening-profile: record the custom hardening profile in the configuration of fakeVmPath`,
+				`execute-setup-script: run C:\provision\Setup.ps1 -Version 2019.1 -HardeningProfile ` + PowershellStringArgument("custom") + `, which reboots the VM`,
+				`execute-post-reboot-script: run C:\provision\PostReboot.ps1, which syspreps the VM and shuts it down`,
+			}))
+			Expect(fakeVcenterClient.SetHardeningProfileCallCount()).To(Equal(0))
+		})
+
+		It("reports the clone, snapshot and hooks around the construct phases", func() {
+			vmConstruct.CloneSource = "/dc/vm/base"
+			vmConstruct.SnapshotName = "pre-construct"
+			vmConstruct.DeleteSnapshot = true
+			vmConstruct.Hooks = Hooks{
+				HookPreSetup:   {"/hooks/pre-setup/certs.ps1"},
+				HookPreSysprep: {"/hooks/pre-sysprep/cleanup.ps1"},
+			}
+
+			err := vmConstruct.PrepareVM()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(planned()).To(Equal([]string{
+				`clone-vm: clone /dc/vm/base to fakeVmPath`,
+				`create-snapshot: create snapshot pre-construct`,
+				`create-provision-dir: create directory C:\provision\`,
+				`upload-artifacts: upload ./LGPO.zip to C:\provision\LGPO.zip`,
+				`upload-artifacts: upload ./StemcellAutomation.zip to C:\provision\StemcellAutomation.zip`,
+				`upload-hooks: create directory C:\provision\hooks\pre-setup`,
+				`upload-hooks: upload /hooks/pre-setup/certs.ps1 to C:\provision\hooks\pre-setup\certs.ps1`,
+				`upload-hooks: create directory C:\provision\hooks\pre-sysprep`,
+				`upload-hooks: upload /hooks/pre-sysprep/cleanup.ps1 to C:\provision\hooks\pre-sysprep\cleanup.ps1`,
+				`enable-winrm: enable WinRM through VMware Tools guest operations`,
+				`extract-artifacts: extract C:\provision\StemcellAutomation.zip to C:\provision\`,
+				`log-out-users: log out any user logged in to the VM`,
+				`pre-setup-hooks: run pre-setup hook C:\provision\hooks\pre-setup\certs.ps1`,
+				`execute-setup-script: run C:\provision\Setup.ps1 -Version 2019.1, which reboots the VM`,
+				`execute-post-reboot-script: run C:\provision\PostReboot.ps1 -SkipSysprep`,
+				`pre-sysprep-hooks: run pre-sysprep hook C:\provision\hooks\pre-sysprep\cleanup.ps1`,
+				`sysprep: run C:\provision\Sysprep.ps1, which syspreps the VM and shuts it down`,
+				`remove-snapshot: delete snapshot pre-construct`,
+			}))
+			Expect(fakeSnapshots.HasSnapshotCallCount()).To(Equal(0))
+			Expect(fakeSnapshots.CreateSnapshotCallCount()).To(Equal(0))
+			Expect(fakeSnapshots.RemoveSnapshotCallCount()).To(Equal(0))
+		})
+
+		It("reports the Windows Updates phase before the post-reboot script", func() {
+			vmConstruct.WindowsUpdates = true
+
+			err := vmConstruct.PrepareVM()
+			Expect(err).NotTo(HaveOccurred())
+
+			operations := planned()
+			Expect(operations[len(operations)-2]).To(Equal("windows-updates: install the available Windows Updates in up to 5 batches, rebooting the VM after each batch that needs it"))
+			Expect(fakeRemoteManager.ExecuteCommandWithTimeoutCallCount()).To(Equal(0))
+		})
+
+		It("reports setting the proxy before the setup script and clearing it before the post-reboot script", func() {
+			vmConstruct.HTTPSProxy = "proxy.example.com:3129"
+			vmConstruct.ProxyBypassList = "<local>"
+
+			err := vmConstruct.PrepareVM()
+			Expect(err).NotTo(HaveOccurred())
+
+			operations := planned()
+			Expect(operations).To(ContainElement("set-proxy: set the HTTPS proxy proxy.example.com:3129, bypassing <local> with Set-ProxySettings"))
+			Expect(operations[len(operations)-2]).To(Equal("clear-proxy: clear the proxy settings with Clear-ProxySettings, so they do not end up in the stemcell"))
+			Expect(fakeRemoteManager.ExecuteCommandCallCount()).To(Equal(0))
+		})
+
+		It("does not report a snapshot the VM already has", func() {
+			vmConstruct.SnapshotName = "pre-construct"
+			fakeSnapshots.HasSnapshotReturns(true, nil)
+
+			err := vmConstruct.PrepareVM()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSnapshots.HasSnapshotArgsForCall(0)).To(Equal("pre-construct"))
+			Expect(planned()[0]).To(HavePrefix("create-provision-dir: "))
+		})
+
+		It("returns an error when the snapshot cannot be looked up", func() {
+			vmConstruct.SnapshotName = "pre-construct"
+			fakeSnapshots.HasSnapshotReturns(false, errors.New("not logged in"))
+
+			err := vmConstruct.PrepareVM()
+			Expect(err).To(MatchError("unable to look up snapshot pre-construct: not logged in"))
+			Expect(fakeMessenger.DryRunSucceededCallCount()).To(Equal(0))
+		})
+
+		Context("with checkpoints", func() {
+			var fakeCheckpointStore *constructfakes.FakeCheckpointStore
+
+			BeforeEach(func() {
+				fakeCheckpointStore = &constructfakes.FakeCheckpointStore{}
+				vmConstruct.Checkpoints = fakeCheckpointStore
+			})
+
+			It("leaves the recorded checkpoint alone when not resuming", func() {
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeCheckpointStore.ClearCallCount()).To(Equal(0))
+				Expect(fakeCheckpointStore.SaveCallCount()).To(Equal(0))
+			})
+
+			It("only reports the phases a resume would run", func() {
+				vmConstruct.Resume = true
+				fakeCheckpointStore.LoadReturns(Checkpoint{Phase: PhaseExecuteSetupScript, Version: "2019.1"}, true, nil)
+
+				err := vmConstruct.PrepareVM()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(planned()).To(Equal([]string{
+					`enable-winrm: enable WinRM through VMware Tools guest operations`,
+					`execute-post-reboot-script: run C:\provision\PostReboot.ps1, which syspreps the VM and shuts it down`,
+				}))
+				Expect(fakeCheckpointStore.SaveCallCount()).To(Equal(0))
+				Expect(fakeCheckpointStore.ClearCallCount()).To(Equal(0))
+			})
+		})
+
+		Describe("RevertSnapshot", func() {
+			BeforeEach(func() {
+				vmConstruct.SnapshotName = "pre-construct"
+			})
+
+			It("reports the revert without reverting", func() {
+				fakeSnapshots.HasSnapshotReturns(true, nil)
+
+				err := vmConstruct.RevertSnapshot()
+				Expect(err).NotTo(HaveOccurred())
+
+				phase, operation := fakeMessenger.PlannedOperationArgsForCall(0)
+				Expect(phase).To(Equal("revert-snapshot"))
+				Expect(operation).To(Equal("revert to snapshot pre-construct"))
+				Expect(fakeSnapshots.RevertToSnapshotCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the VM has no such snapshot", func() {
+				err := vmConstruct.RevertSnapshot()
+				Expect(err).To(MatchError("unable to revert to snapshot pre-construct: the VM has no snapshot of that name"))
+			})
+		})
 	})
 })
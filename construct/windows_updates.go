@@ -0,0 +1,173 @@
+package construct
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// windowsUpdatesTest throws windowsUpdatesRemaining when updates are available that are not installed
+const windowsUpdatesTest = `powershell.exe -NoProfile -Command "Import-Module BOSH.WindowsUpdates; Test-InstalledUpdates"`
+
+const windowsUpdatesRemaining = "There are uninstalled updates"
+
+// windowsUpdatesInstall runs Install-WindowsUpdates -NoRestart as SYSTEM, since Windows does not install updates
+// over a remote session, and exits with the exit code of Install-WindowsUpdates
+const windowsUpdatesInstall = `powershell.exe -NoProfile -Command "Import-Module BOSH.WindowsUpdates; exit (Invoke-WindowsUpdatesTask)"`
+
+// windowsUpdatesRestartRequired is the exit code of Install-WindowsUpdates -NoRestart when the updates need a reboot
+const windowsUpdatesRestartRequired = 3010
+
+// windowsUpdatesSearchInstalled lists the installed updates as "KB<id> | <title>", oldest first
+const windowsUpdatesSearchInstalled = `powershell.exe -NoProfile -Command "Import-Module BOSH.WindowsUpdates; Search-InstalledUpdates"`
+
+// windowsUpdatesReboot gives the command that reboots the VM time to return before WinRM goes away
+const windowsUpdatesReboot = `shutdown /r /f /t 10 /c "stembuild Windows Updates"`
+
+type windowsUpdate struct {
+	KB    string
+	Title string
+}
+
+// installWindowsUpdates runs Install-WindowsUpdates, rebooting the VM whenever the updates it installed need it,
+// until Test-InstalledUpdates finds nothing left to install or Install-WindowsUpdates has run WindowsUpdatesMaxIterations times
+func (c *VMConstruct) installWindowsUpdates() error {
+	installed := 0
+	var known map[windowsUpdate]bool
+	for iteration := 1; iteration <= c.WindowsUpdatesMaxIterations; iteration++ {
+		if c.ctx.Err() != nil {
+			return c.ctx.Err()
+		}
+
+		remaining, err := c.windowsUpdatesRemain()
+		if err != nil {
+			return err
+		}
+		if !remaining {
+			c.messenger.InstallWindowsUpdatesSucceeded(installed)
+			return nil
+		}
+
+		if known == nil {
+			known = map[windowsUpdate]bool{}
+			_, err = c.newlyInstalledUpdates(known)
+			if err != nil {
+				return err
+			}
+		}
+
+		c.messenger.InstallWindowsUpdatesStarted(iteration)
+		restartRequired, err := c.runInstallWindowsUpdates()
+		if err != nil {
+			return fmt.Errorf("failed to install Windows Updates batch %d: %s", iteration, err)
+		}
+
+		updates, err := c.newlyInstalledUpdates(known)
+		if err != nil {
+			return err
+		}
+		for _, update := range updates {
+			c.messenger.WindowsUpdateInstalled(update.KB, update.Title)
+		}
+		installed += len(updates)
+
+		if restartRequired {
+			c.messenger.WindowsUpdatesRebootStarted()
+			err = c.rebootForWindowsUpdates()
+			if err != nil {
+				return err
+			}
+			c.messenger.WindowsUpdatesRebootFinished()
+		} else if len(updates) == 0 {
+			// Install-WindowsUpdates skips the updates that need user input and moves on from those that fail
+			return fmt.Errorf("Install-WindowsUpdates did not install any of the remaining Windows Updates, see %slog.log on the VM", provisionDir)
+		}
+	}
+
+	remaining, err := c.windowsUpdatesRemain()
+	if err != nil {
+		return err
+	}
+	if remaining {
+		c.messenger.WindowsUpdatesIterationLimitReached(c.WindowsUpdatesMaxIterations)
+	}
+	c.messenger.InstallWindowsUpdatesSucceeded(installed)
+	return nil
+}
+
+// windowsUpdatesRemain runs Test-InstalledUpdates, which fails when updates are available that are not installed
+func (c *VMConstruct) windowsUpdatesRemain() (bool, error) {
+	exitCode, err := c.remoteManager.ExecuteCommandWithTimeout(windowsUpdatesTest, c.untilDeadline(c.WindowsUpdatesTimeout))
+	if err != nil && strings.Contains(err.Error(), windowsUpdatesRemaining) {
+		return true, nil
+	}
+	if err == nil && exitCode != 0 {
+		err = fmt.Errorf("exit code %d", exitCode)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to search for Windows Updates: %s", err)
+	}
+	return false, nil
+}
+
+// runInstallWindowsUpdates runs Install-WindowsUpdates and returns whether the updates it installed need a reboot
+func (c *VMConstruct) runInstallWindowsUpdates() (bool, error) {
+	exitCode, err := c.remoteManager.ExecuteCommandWithTimeout(windowsUpdatesInstall, c.untilDeadline(c.WindowsUpdatesTimeout))
+	if exitCode == windowsUpdatesRestartRequired {
+		return true, nil
+	}
+	if err == nil && exitCode != 0 {
+		err = fmt.Errorf("exit code %d", exitCode)
+	}
+	return false, err
+}
+
+// newlyInstalledUpdates runs Search-InstalledUpdates and returns the installed updates that are not known yet,
+// adding them to known
+func (c *VMConstruct) newlyInstalledUpdates(known map[windowsUpdate]bool) ([]windowsUpdate, error) {
+	output, err := c.remoteManager.ExecuteCommandOutput(windowsUpdatesSearchInstalled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the installed Windows Updates: %s", err)
+	}
+
+	var updates []windowsUpdate
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " | ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		update := windowsUpdate{KB: fields[0], Title: fields[1]}
+		if !known[update] {
+			known[update] = true
+			updates = append(updates, update)
+		}
+	}
+	return updates, nil
+}
+
+func (c *VMConstruct) rebootForWindowsUpdates() error {
+	err := c.rebootWaiter.RecordBootTime()
+	if err != nil {
+		return err
+	}
+	exitCode, err := c.remoteManager.ExecuteCommand(windowsUpdatesReboot)
+	if err == nil && exitCode != 0 {
+		err = fmt.Errorf("exit code %d", exitCode)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reboot the VM to finish installing Windows Updates: %s", err)
+	}
+	return c.waitForReboot()
+}
+
+// waitForReboot gives the VM RebootWaitTime to go down before polling until it is back up
+func (c *VMConstruct) waitForReboot() error {
+	if c.RebootWaitTime > 0 {
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case <-time.After(c.untilDeadline(c.RebootWaitTime)):
+		}
+	}
+	return c.rebootWaiter.WaitForRebootFinished(c.ctx, c.untilDeadline(c.RebootTimeout))
+}
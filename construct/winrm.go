@@ -3,9 +3,15 @@ package construct
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+
 	"github.com/cloudfoundry-incubator/stembuild/assets"
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager"
 )
 
+// winRMListenerCert is where Enable-WinRM -Https writes the certificate of the HTTPS listener
+const winRMListenerCert = "C:\\Windows\\Temp\\stembuild-winrm-https.pem"
+
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . zipUnarchiver
 type zipUnarchiver interface {
 	Unzip(fileArchive []byte, file string) ([]byte, error)
@@ -14,11 +20,19 @@ type zipUnarchiver interface {
 type WinRMManager struct {
 	GuestManager GuestManager
 	Unarchiver   zipUnarchiver
+	// Transport, when HTTPS, has Enable provision an HTTPS listener. Unless Transport has CA certificates
+	// or is insecure, the certificate of the listener, read back through guest operations, is trusted from then on.
+	// With NTLM auth, Enable leaves Basic auth on the guest as it is.
+	Transport *remotemanager.WinRMTransport
+	// Context cancels enabling WinRM, terminating the PowerShell process in the guest
+	Context context.Context
+	// Assets serves the automation bundle that BOSH.WinRM.psm1 is read from. Without it, the bundle compiled into stembuild is used.
+	Assets Assets
 }
 
 func (w *WinRMManager) Enable() error {
 	failureString := "failed to enable WinRM: %s"
-	saZip, err := assets.Asset(stemcellAutomationName)
+	saZip, err := w.automationBundle()
 	if err != nil {
 		return fmt.Errorf(failureString, err)
 	}
@@ -33,16 +47,16 @@ func (w *WinRMManager) Enable() error {
 		return fmt.Errorf(failureString, err)
 	}
 
-	rawWinRMwtCmd := append(rawWinRM, []byte("\nEnable-WinRM\n")...)
+	rawWinRMwtCmd := append(rawWinRM, []byte("\n"+w.enableCommand()+"\n")...)
 
 	base64WinRM := EncodePowershellCommand(rawWinRMwtCmd)
 
-	pid, err := w.GuestManager.StartProgramInGuest(context.Background(), powershell, fmt.Sprintf("-EncodedCommand %s", base64WinRM))
+	pid, err := w.GuestManager.StartProgramInGuest(w.context(), powershell, fmt.Sprintf("-EncodedCommand %s", base64WinRM))
 	if err != nil {
 		return fmt.Errorf(failureString, err)
 	}
 
-	exitCode, err := w.GuestManager.ExitCodeForProgramInGuest(context.Background(), pid)
+	exitCode, err := w.GuestManager.ExitCodeForProgramInGuest(w.context(), pid)
 	if err != nil {
 		return fmt.Errorf(failureString, err)
 	}
@@ -51,5 +65,57 @@ func (w *WinRMManager) Enable() error {
 		return fmt.Errorf(failureString, fmt.Sprintf("WinRM process on guest VM exited with code %d", exitCode))
 	}
 
+	if w.https() && w.Transport.CACert == nil && !w.Transport.Insecure {
+		err = w.trustListenerCert()
+		if err != nil {
+			return fmt.Errorf(failureString, err)
+		}
+	}
+
+	return nil
+}
+
+func (w *WinRMManager) automationBundle() ([]byte, error) {
+	if w.Assets == nil {
+		return assets.Asset(stemcellAutomationName)
+	}
+	return w.Assets.AutomationBundle()
+}
+
+func (w *WinRMManager) https() bool {
+	return w.Transport != nil && w.Transport.HTTPS
+}
+
+func (w *WinRMManager) enableCommand() string {
+	command := "Enable-WinRM"
+	if w.https() {
+		command += fmt.Sprintf(" -Https -HttpsPort %d -CertificateFile '%s'", w.Transport.ListenerPort(), winRMListenerCert)
+	}
+	if w.Transport != nil && (w.Transport.Auth == remotemanager.WinRMAuthNTLM || w.Transport.Auth == remotemanager.WinRMAuthKerberos) {
+		command += " -Authentication Negotiate"
+	}
+	return command
+}
+
+// trustListenerCert pins the certificate of the HTTPS listener. It is read through guest operations,
+// which are authenticated by vCenter, so it can be trusted without a certificate authority.
+func (w *WinRMManager) trustListenerCert() error {
+	reader, _, err := w.GuestManager.DownloadFileInGuest(w.context(), winRMListenerCert)
+	if err != nil {
+		return fmt.Errorf("unable to read the certificate of the WinRM HTTPS listener: %s", err)
+	}
+
+	cert, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("unable to read the certificate of the WinRM HTTPS listener: %s", err)
+	}
+	w.Transport.CACert = cert
 	return nil
 }
+
+func (w *WinRMManager) context() context.Context {
+	if w.Context == nil {
+		return context.Background()
+	}
+	return w.Context
+}
@@ -1,11 +1,16 @@
 package construct_test
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
+	"strings"
+	"unicode/utf16"
 
 	"github.com/cloudfoundry-incubator/stembuild/assets"
 	"github.com/cloudfoundry-incubator/stembuild/construct"
 	"github.com/cloudfoundry-incubator/stembuild/construct/constructfakes"
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -33,6 +38,43 @@ var _ = Describe("WinRMManager", func() {
 	})
 
 	Describe("Enable", func() {
+		It("runs the guest program with its context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			winrmManager.Context = ctx
+			fakeZipUnarchiver.UnzipReturns([]byte("extracted byte array"), nil)
+
+			err := winrmManager.Enable()
+			Expect(err).ToNot(HaveOccurred())
+
+			startCtx, _, _ := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+			exitCtx, _ := fakeGuestManager.ExitCodeForProgramInGuestArgsForCall(0)
+			Expect(startCtx).To(Equal(ctx))
+			Expect(exitCtx).To(Equal(ctx))
+		})
+
+		It("reads BOSH.WinRM.psm1 from the automation bundle of its assets", func() {
+			fakeAssets := &constructfakes.FakeAssets{}
+			fakeAssets.AutomationBundleReturns([]byte("overriding bundle"), nil)
+			winrmManager.Assets = fakeAssets
+
+			err := winrmManager.Enable()
+			Expect(err).ToNot(HaveOccurred())
+
+			archive, _ := fakeZipUnarchiver.UnzipArgsForCall(0)
+			Expect(archive).To(Equal([]byte("overriding bundle")))
+		})
+
+		It("returns an error when its assets cannot read the automation bundle", func() {
+			fakeAssets := &constructfakes.FakeAssets{}
+			fakeAssets.AutomationBundleReturns(nil, errors.New("unable to read automation bundle"))
+			winrmManager.Assets = fakeAssets
+
+			err := winrmManager.Enable()
+			Expect(err).To(MatchError("failed to enable WinRM: unable to read automation bundle"))
+			Expect(fakeGuestManager.StartProgramInGuestCallCount()).To(Equal(0))
+		})
+
 		It("returns success when it enables WinRM on the guest VM", func() {
 			expectedPid := int64(65535)
 			fakeGuestManager.StartProgramInGuestReturns(expectedPid, nil)
@@ -128,5 +170,89 @@ var _ = Describe("WinRMManager", func() {
 
 			Expect(pid).To(Equal(expectedPid))
 		})
+
+		Context("over HTTPS", func() {
+			decodeCommand := func(args string) string {
+				encoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(args, "-EncodedCommand "))
+				Expect(err).ToNot(HaveOccurred())
+				utf16Command := make([]uint16, len(encoded)/2)
+				for i := range utf16Command {
+					utf16Command[i] = uint16(encoded[2*i]) | uint16(encoded[2*i+1])<<8
+				}
+				return string(utf16.Decode(utf16Command))
+			}
+
+			BeforeEach(func() {
+				fakeZipUnarchiver.UnzipReturnsOnCall(0, []byte("bosh-psmodules.zip extracted byte array"), nil)
+				fakeZipUnarchiver.UnzipReturnsOnCall(1, []byte("BOSH.WinRM.psm1 extracted byte array"), nil)
+				winrmManager.Transport = &remotemanager.WinRMTransport{HTTPS: true}
+			})
+
+			It("provisions an HTTPS listener and trusts its certificate", func() {
+				fakeGuestManager.DownloadFileInGuestReturns(strings.NewReader("listener certificate"), 20, nil)
+
+				err := winrmManager.Enable()
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+				Expect(decodeCommand(args)).To(HaveSuffix("\nEnable-WinRM -Https -HttpsPort 5986 -CertificateFile 'C:\\Windows\\Temp\\stembuild-winrm-https.pem'\n"))
+
+				_, path := fakeGuestManager.DownloadFileInGuestArgsForCall(0)
+				Expect(path).To(Equal("C:\\Windows\\Temp\\stembuild-winrm-https.pem"))
+				Expect(winrmManager.Transport.CACert).To(Equal([]byte("listener certificate")))
+			})
+
+			It("keeps the configured CA certificates", func() {
+				winrmManager.Transport.Port = 443
+				winrmManager.Transport.CACert = []byte("ca certificates")
+
+				err := winrmManager.Enable()
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+				Expect(decodeCommand(args)).To(ContainSubstring("-HttpsPort 443 "))
+				Expect(fakeGuestManager.DownloadFileInGuestCallCount()).To(Equal(0))
+				Expect(winrmManager.Transport.CACert).To(Equal([]byte("ca certificates")))
+			})
+
+			It("leaves Basic auth alone with NTLM", func() {
+				winrmManager.Transport.Insecure = true
+				winrmManager.Transport.Auth = remotemanager.WinRMAuthNTLM
+
+				err := winrmManager.Enable()
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+				Expect(decodeCommand(args)).To(HaveSuffix(" -Authentication Negotiate\n"))
+			})
+
+			It("leaves Basic auth alone with Kerberos", func() {
+				winrmManager.Transport.Insecure = true
+				winrmManager.Transport.Auth = remotemanager.WinRMAuthKerberos
+
+				err := winrmManager.Enable()
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+				Expect(decodeCommand(args)).To(HaveSuffix(" -Authentication Negotiate\n"))
+			})
+
+			It("does not read the certificate when verification is skipped", func() {
+				winrmManager.Transport.Insecure = true
+
+				err := winrmManager.Enable()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeGuestManager.DownloadFileInGuestCallCount()).To(Equal(0))
+			})
+
+			It("returns a failure when the listener certificate cannot be read", func() {
+				fakeGuestManager.DownloadFileInGuestReturns(nil, 0, errors.New("file not found"))
+
+				err := winrmManager.Enable()
+				Expect(err).To(MatchError("failed to enable WinRM: unable to read the certificate of the WinRM HTTPS listener: file not found"))
+				Expect(winrmManager.Transport.CACert).To(BeNil())
+			})
+		})
 	})
 })
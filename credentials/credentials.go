@@ -0,0 +1,166 @@
+package credentials
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const schemeSeparator = "://"
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Provider
+type Provider interface {
+	// Secret reads the secret at location. name describes the secret, e.g. "vCenter password".
+	Secret(name, location string) (string, error)
+}
+
+// Resolver turns a flag value such as env://VCENTER_PASSWORD into the secret it refers to.
+// Values without a known scheme:// prefix are returned as they are.
+type Resolver struct {
+	Providers map[string]Provider
+}
+
+// NewResolver returns a Resolver for the env, file, stdin and vault schemes. stdin:// secrets are
+// read from stdin, after writing a prompt to prompt.
+func NewResolver(stdin *os.File, prompt io.Writer) *Resolver {
+	return &Resolver{
+		Providers: map[string]Provider{
+			"env":   &Env{},
+			"file":  &File{},
+			"stdin": NewStdin(stdin, prompt),
+			"vault": &Vault{Client: http.DefaultClient},
+		},
+	}
+}
+
+func (r *Resolver) Resolve(name, value string) (string, error) {
+	i := strings.Index(value, schemeSeparator)
+	if i == -1 {
+		return value, nil
+	}
+
+	provider, ok := r.Providers[value[:i]]
+	if !ok {
+		return value, nil
+	}
+
+	secret, err := provider.Secret(name, value[i+len(schemeSeparator):])
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s: %s", name, err)
+	}
+	return secret, nil
+}
+
+// Env reads env://NAME from the environment variable NAME
+type Env struct{}
+
+func (*Env) Secret(_, location string) (string, error) {
+	secret, ok := os.LookupEnv(location)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", location)
+	}
+	return secret, nil
+}
+
+// File reads file:///path/to/secret from the first line of the file
+type File struct{}
+
+func (*File) Secret(_, location string) (string, error) {
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return "", err
+	}
+	line := strings.SplitN(string(contents), "\n", 2)[0]
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// Stdin reads stdin:// from a line of stdin. When stdin is a terminal, the user is prompted
+// and the line is not echoed.
+type Stdin struct {
+	file   *os.File
+	reader *bufio.Reader
+	prompt io.Writer
+}
+
+func NewStdin(file *os.File, prompt io.Writer) *Stdin {
+	return &Stdin{file: file, reader: bufio.NewReader(file), prompt: prompt}
+}
+
+func (s *Stdin) Secret(name, _ string) (string, error) {
+	fmt.Fprintf(s.prompt, "Enter %s: ", name)
+
+	restore, err := disableEcho(s.file.Fd())
+	if err == nil {
+		defer func() {
+			restore()
+			fmt.Fprintln(s.prompt)
+		}()
+	}
+
+	line, err := s.reader.ReadString('\n')
+	if err != nil && !(err == io.EOF && line != "") {
+		return "", fmt.Errorf("no input on stdin: %s", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Vault reads vault://<path>#<key> from a HashiCorp Vault KV secrets engine, using the address and token in
+// VAULT_ADDR and VAULT_TOKEN. The path is the API path below /v1, e.g. secret/data/stembuild for version 2
+// of the KV engine or secret/stembuild for version 1.
+type Vault struct {
+	Client *http.Client
+}
+
+func (v *Vault) Secret(_, location string) (string, error) {
+	i := strings.LastIndex(location, "#")
+	if i == -1 {
+		return "", fmt.Errorf("vault reference %s must name a key, e.g. secret/data/stembuild#password", location)
+	}
+	path, key := location[:i], location[i+1:]
+
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		return "", fmt.Errorf("VAULT_ADDR is not set")
+	}
+
+	request, err := http.NewRequest(http.MethodGet, strings.TrimRight(address, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+
+	response, err := v.Client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %s for %s", response.Status, path)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&body)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse vault response for %s: %s", path, err)
+	}
+
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok && data["metadata"] != nil {
+		// version 2 of the KV engine wraps the secret in metadata
+		data = nested
+	}
+
+	secret, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no key %s", path, key)
+	}
+	return secret, nil
+}
@@ -0,0 +1,206 @@
+package credentials_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry-incubator/stembuild/credentials"
+	"github.com/cloudfoundry-incubator/stembuild/credentials/credentialsfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Resolver", func() {
+	var (
+		provider *credentialsfakes.FakeProvider
+		resolver *Resolver
+	)
+
+	BeforeEach(func() {
+		provider = &credentialsfakes.FakeProvider{}
+		provider.SecretReturns("s3cret", nil)
+		resolver = &Resolver{Providers: map[string]Provider{"env": provider}}
+	})
+
+	It("reads a reference from the provider of its scheme", func() {
+		secret, err := resolver.Resolve("vCenter password", "env://VCENTER_PASSWORD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("s3cret"))
+
+		name, location := provider.SecretArgsForCall(0)
+		Expect(name).To(Equal("vCenter password"))
+		Expect(location).To(Equal("VCENTER_PASSWORD"))
+	})
+
+	It("returns values without a known scheme as they are", func() {
+		for _, value := range []string{"plain-password", "ftp://not-a-scheme", ""} {
+			secret, err := resolver.Resolve("vCenter password", value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret).To(Equal(value))
+		}
+		Expect(provider.SecretCallCount()).To(Equal(0))
+	})
+
+	It("names the secret that could not be read", func() {
+		provider.SecretReturns("", errors.New("environment variable VCENTER_PASSWORD is not set"))
+
+		_, err := resolver.Resolve("vCenter password", "env://VCENTER_PASSWORD")
+		Expect(err).To(MatchError("unable to read vCenter password: environment variable VCENTER_PASSWORD is not set"))
+	})
+})
+
+var _ = Describe("Env", func() {
+	AfterEach(func() {
+		_ = os.Unsetenv("STEMBUILD_TEST_SECRET")
+	})
+
+	It("reads the environment variable", func() {
+		Expect(os.Setenv("STEMBUILD_TEST_SECRET", "s3cret")).To(Succeed())
+
+		secret, err := (&Env{}).Secret("VM password", "STEMBUILD_TEST_SECRET")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("s3cret"))
+	})
+
+	It("returns an error when the variable is not set", func() {
+		_, err := (&Env{}).Secret("VM password", "STEMBUILD_TEST_SECRET")
+		Expect(err).To(MatchError("environment variable STEMBUILD_TEST_SECRET is not set"))
+	})
+})
+
+var _ = Describe("File", func() {
+	It("reads the file without its trailing newline", func() {
+		dir, err := ioutil.TempDir("", "credentials")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "password")
+		Expect(ioutil.WriteFile(path, []byte("s3cret\r\n"), 0600)).To(Succeed())
+
+		secret, err := (&File{}).Secret("VM password", path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("s3cret"))
+	})
+
+	It("reads only the first line of the file", func() {
+		dir, err := ioutil.TempDir("", "credentials")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "password")
+		Expect(ioutil.WriteFile(path, []byte("s3cret\r\nrotated on 2020-01-02\n"), 0600)).To(Succeed())
+
+		secret, err := (&File{}).Secret("VM password", path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("s3cret"))
+	})
+
+	It("returns an error when the file cannot be read", func() {
+		_, err := (&File{}).Secret("VM password", "/does/not/exist")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Stdin", func() {
+	It("prompts for each secret and reads it from its own line", func() {
+		r, w, err := os.Pipe()
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+		_, err = w.Write([]byte("first\nsecond"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+
+		prompt := gbytes.NewBuffer()
+		stdin := NewStdin(r, prompt)
+
+		secret, err := stdin.Secret("vCenter password", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("first"))
+		Expect(prompt).To(gbytes.Say("Enter vCenter password: "))
+
+		secret, err = stdin.Secret("VM password", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("second"))
+		Expect(prompt).To(gbytes.Say("Enter VM password: "))
+
+		_, err = stdin.Secret("VM password", "")
+		Expect(err).To(MatchError(ContainSubstring("no input on stdin")))
+	})
+})
+
+var _ = Describe("Vault", func() {
+	var (
+		server   *httptest.Server
+		response string
+		vault    *Vault
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Vault-Token") != "root-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			switch r.URL.Path {
+			case "/v1/secret/data/stembuild":
+				_, _ = w.Write([]byte(response))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		vault = &Vault{Client: server.Client()}
+		Expect(os.Setenv("VAULT_ADDR", server.URL)).To(Succeed())
+		Expect(os.Setenv("VAULT_TOKEN", "root-token")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		_ = os.Unsetenv("VAULT_ADDR")
+		_ = os.Unsetenv("VAULT_TOKEN")
+	})
+
+	It("reads a key of a version 2 KV secret", func() {
+		response = `{"data": {"data": {"password": "s3cret"}, "metadata": {"version": 1}}}`
+
+		secret, err := vault.Secret("vCenter password", "secret/data/stembuild#password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("s3cret"))
+	})
+
+	It("reads a key of a version 1 KV secret", func() {
+		response = `{"data": {"password": "s3cret"}}`
+
+		secret, err := vault.Secret("vCenter password", "secret/data/stembuild#password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(Equal("s3cret"))
+	})
+
+	It("returns an error when the secret has no such key", func() {
+		response = `{"data": {"data": {"username": "root"}, "metadata": {"version": 1}}}`
+
+		_, err := vault.Secret("vCenter password", "secret/data/stembuild#password")
+		Expect(err).To(MatchError("vault secret secret/data/stembuild has no key password"))
+	})
+
+	It("returns an error when vault refuses the request", func() {
+		Expect(os.Setenv("VAULT_TOKEN", "wrong")).To(Succeed())
+
+		_, err := vault.Secret("vCenter password", "secret/data/stembuild#password")
+		Expect(err).To(MatchError("vault returned 403 Forbidden for secret/data/stembuild"))
+	})
+
+	It("requires a key", func() {
+		_, err := vault.Secret("vCenter password", "secret/data/stembuild")
+		Expect(err).To(MatchError(ContainSubstring("must name a key")))
+	})
+
+	It("requires VAULT_ADDR", func() {
+		Expect(os.Unsetenv("VAULT_ADDR")).To(Succeed())
+
+		_, err := vault.Secret("vCenter password", "secret/data/stembuild#password")
+		Expect(err).To(MatchError("VAULT_ADDR is not set"))
+	})
+})
@@ -0,0 +1,118 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package credentialsfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/credentials"
+)
+
+type FakeProvider struct {
+	SecretStub        func(string, string) (string, error)
+	secretMutex       sync.RWMutex
+	secretArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	secretReturns struct {
+		result1 string
+		result2 error
+	}
+	secretReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeProvider) Secret(arg1 string, arg2 string) (string, error) {
+	fake.secretMutex.Lock()
+	ret, specificReturn := fake.secretReturnsOnCall[len(fake.secretArgsForCall)]
+	fake.secretArgsForCall = append(fake.secretArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SecretStub
+	fakeReturns := fake.secretReturns
+	fake.recordInvocation("Secret", []interface{}{arg1, arg2})
+	fake.secretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) SecretCallCount() int {
+	fake.secretMutex.RLock()
+	defer fake.secretMutex.RUnlock()
+	return len(fake.secretArgsForCall)
+}
+
+func (fake *FakeProvider) SecretCalls(stub func(string, string) (string, error)) {
+	fake.secretMutex.Lock()
+	defer fake.secretMutex.Unlock()
+	fake.SecretStub = stub
+}
+
+func (fake *FakeProvider) SecretArgsForCall(i int) (string, string) {
+	fake.secretMutex.RLock()
+	defer fake.secretMutex.RUnlock()
+	argsForCall := fake.secretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) SecretReturns(result1 string, result2 error) {
+	fake.secretMutex.Lock()
+	defer fake.secretMutex.Unlock()
+	fake.SecretStub = nil
+	fake.secretReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) SecretReturnsOnCall(i int, result1 string, result2 error) {
+	fake.secretMutex.Lock()
+	defer fake.secretMutex.Unlock()
+	fake.SecretStub = nil
+	if fake.secretReturnsOnCall == nil {
+		fake.secretReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.secretReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.secretMutex.RLock()
+	defer fake.secretMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ credentials.Provider = new(FakeProvider)
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package credentials
+
+import "errors"
+
+func disableEcho(fd uintptr) (func(), error) {
+	return nil, errors.New("hiding input is not supported on this platform")
+}
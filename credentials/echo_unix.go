@@ -0,0 +1,28 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package credentials
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// disableEcho stops the terminal on fd from echoing input until restore is called.
+// It fails when fd is not a terminal.
+func disableEcho(fd uintptr) (func(), error) {
+	termios, err := unix.IoctlGetTermios(int(fd), getTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	original := *termios
+	termios.Lflag &^= unix.ECHO
+	err = unix.IoctlSetTermios(int(fd), setTermios, termios)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = unix.IoctlSetTermios(int(fd), setTermios, &original)
+	}, nil
+}
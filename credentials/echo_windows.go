@@ -0,0 +1,24 @@
+package credentials
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// disableEcho stops the console on fd from echoing input until restore is called.
+// It fails when fd is not a console.
+func disableEcho(fd uintptr) (func(), error) {
+	var mode uint32
+	err := windows.GetConsoleMode(windows.Handle(fd), &mode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = windows.SetConsoleMode(windows.Handle(fd), mode&^windows.ENABLE_ECHO_INPUT)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = windows.SetConsoleMode(windows.Handle(fd), mode)
+	}, nil
+}
@@ -0,0 +1,6 @@
+package credentials
+
+import "golang.org/x/sys/unix"
+
+const getTermios = unix.TIOCGETA
+const setTermios = unix.TIOCSETA
@@ -0,0 +1,6 @@
+package credentials
+
+import "golang.org/x/sys/unix"
+
+const getTermios = unix.TCGETS
+const setTermios = unix.TCSETS
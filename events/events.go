@@ -0,0 +1,110 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	Started   Status = "started"
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+	Warning   Status = "warning"
+	Info      Status = "info"
+)
+
+// Event is a single line of the machine readable output stream. Event names match the
+// messenger callback that produced them, e.g. UploadFileStarted or PackageFailed.
+// VM names the VM an event is about when a batch constructs several at once.
+type Event struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Command         string    `json:"command"`
+	VM              string    `json:"vm,omitempty"`
+	Phase           string    `json:"phase"`
+	Event           string    `json:"event"`
+	Status          Status    `json:"status"`
+	Target          string    `json:"target,omitempty"`
+	Message         string    `json:"message,omitempty"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	// BytesSent, BytesTotal, BytesPerSecond and ETASeconds describe the progress of an upload
+	BytesSent      int64   `json:"bytes_sent,omitempty"`
+	BytesTotal     int64   `json:"bytes_total,omitempty"`
+	BytesPerSecond float64 `json:"bytes_per_second,omitempty"`
+	ETASeconds     float64 `json:"eta_seconds,omitempty"`
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Sink
+type Sink interface {
+	Emit(event Event)
+}
+
+// JSONSink writes every event as one JSON object per line. It timestamps events and
+// works out how long a phase took by pairing its started event with the matching
+// succeeded or failed one. A failure that does not name a phase is attributed to the
+// most recently started phase of that command and VM which has not finished yet.
+type JSONSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	now     func() time.Time
+	started map[string]time.Time
+	running map[string][]string
+}
+
+func NewJSONSink(out io.Writer) *JSONSink {
+	return newJSONSink(out, time.Now)
+}
+
+func newJSONSink(out io.Writer, now func() time.Time) *JSONSink {
+	return &JSONSink{
+		encoder: json.NewEncoder(out),
+		now:     now,
+		started: map[string]time.Time{},
+		running: map[string][]string{},
+	}
+}
+
+func (s *JSONSink) Emit(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.Timestamp = s.now().UTC()
+
+	command := event.Command + "/" + event.VM
+	running := s.running[command]
+	if event.Phase == "" && event.Status == Failed && len(running) > 0 {
+		event.Phase = running[len(running)-1]
+	}
+
+	key := command + "/" + event.Phase + "/" + event.Target
+	switch event.Status {
+	case Started:
+		s.started[key] = event.Timestamp
+		if event.Target == "" {
+			s.running[command] = append(running, event.Phase)
+		}
+	case Succeeded, Failed:
+		if start, ok := s.started[key]; ok {
+			event.DurationSeconds = event.Timestamp.Sub(start).Seconds()
+			delete(s.started, key)
+		}
+		if event.Target == "" {
+			s.running[command] = withoutPhase(running, event.Phase)
+		}
+	}
+
+	_ = s.encoder.Encode(event)
+}
+
+func withoutPhase(running []string, phase string) []string {
+	for i := len(running) - 1; i >= 0; i-- {
+		if running[i] == phase {
+			return append(running[:i:i], running[i+1:]...)
+		}
+	}
+	return running
+}
@@ -0,0 +1,115 @@
+package events_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/stembuild/events"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONSink", func() {
+	var (
+		out  *bytes.Buffer
+		now  time.Time
+		sink *JSONSink
+	)
+
+	lines := func() []map[string]interface{} {
+		var decoded []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			var event map[string]interface{}
+			Expect(json.Unmarshal([]byte(line), &event)).To(Succeed())
+			decoded = append(decoded, event)
+		}
+		return decoded
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		sink = NewJSONSinkWithClock(out, func() time.Time { return now })
+	})
+
+	It("writes one JSON object per event with a timestamp", func() {
+		sink.Emit(Event{Command: "construct", Phase: "reboot", Event: "RebootHasStarted", Status: Started})
+
+		events := lines()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0]).To(Equal(map[string]interface{}{
+			"timestamp": "2020-01-02T03:04:05Z",
+			"command":   "construct",
+			"phase":     "reboot",
+			"event":     "RebootHasStarted",
+			"status":    "started",
+		}))
+	})
+
+	It("adds the duration of a phase to its succeeded event", func() {
+		sink.Emit(Event{Command: "construct", Phase: "reboot", Event: "RebootHasStarted", Status: Started})
+		now = now.Add(90 * time.Second)
+		sink.Emit(Event{Command: "construct", Phase: "reboot", Event: "RebootHasFinished", Status: Succeeded})
+
+		Expect(lines()[1]["duration_seconds"]).To(Equal(90.0))
+	})
+
+	It("times each target of a phase separately", func() {
+		sink.Emit(Event{Command: "construct", Phase: "upload-artifacts", Event: "UploadArtifactsStarted", Status: Started})
+		now = now.Add(time.Second)
+		sink.Emit(Event{Command: "construct", Phase: "upload-artifacts", Event: "UploadFileStarted", Status: Started, Target: "LGPO"})
+		now = now.Add(2 * time.Second)
+		sink.Emit(Event{Command: "construct", Phase: "upload-artifacts", Event: "UploadFileSucceeded", Status: Succeeded, Target: "LGPO"})
+		sink.Emit(Event{Command: "construct", Phase: "upload-artifacts", Event: "UploadArtifactsSucceeded", Status: Succeeded})
+
+		events := lines()
+		Expect(events[2]["duration_seconds"]).To(Equal(2.0))
+		Expect(events[3]["duration_seconds"]).To(Equal(3.0))
+	})
+
+	It("attributes a failure without a phase to the phase that is still running", func() {
+		sink.Emit(Event{Command: "construct", Phase: "execute-setup-script", Event: "ExecuteSetupScriptStarted", Status: Started})
+		now = now.Add(5 * time.Second)
+		sink.Emit(Event{Command: "construct", Event: "CannotPrepareVM", Status: Failed, Error: "boom"})
+
+		failure := lines()[1]
+		Expect(failure["phase"]).To(Equal("execute-setup-script"))
+		Expect(failure["error"]).To(Equal("boom"))
+		Expect(failure["duration_seconds"]).To(Equal(5.0))
+	})
+
+	It("attributes a failure to the unfinished phase even after later phases completed", func() {
+		sink.Emit(Event{Command: "construct", Phase: "reboot", Event: "RebootHasStarted", Status: Started})
+		sink.Emit(Event{Command: "construct", Phase: "collect-diagnostics", Event: "CollectDiagnosticsStarted", Status: Started})
+		sink.Emit(Event{Command: "construct", Phase: "collect-diagnostics", Event: "CollectDiagnosticsSucceeded", Status: Succeeded})
+		sink.Emit(Event{Command: "construct", Event: "CannotPrepareVM", Status: Failed, Error: "boom"})
+
+		Expect(lines()[3]["phase"]).To(Equal("reboot"))
+	})
+
+	It("times and attributes the phases of each VM separately", func() {
+		sink.Emit(Event{Command: "construct", VM: "vm-1", Phase: "reboot", Event: "RebootHasStarted", Status: Started})
+		now = now.Add(time.Second)
+		sink.Emit(Event{Command: "construct", VM: "vm-2", Phase: "reboot", Event: "RebootHasStarted", Status: Started})
+		sink.Emit(Event{Command: "construct", VM: "vm-2", Phase: "execute-post-reboot-script", Event: "ExecutePostRebootScriptStarted", Status: Started})
+		now = now.Add(2 * time.Second)
+		sink.Emit(Event{Command: "construct", VM: "vm-1", Phase: "reboot", Event: "RebootHasFinished", Status: Succeeded})
+		sink.Emit(Event{Command: "construct", VM: "vm-1", Event: "CannotPrepareVM", Status: Failed, Error: "boom"})
+
+		events := lines()
+		Expect(events[3]["vm"]).To(Equal("vm-1"))
+		Expect(events[3]["duration_seconds"]).To(Equal(3.0))
+		Expect(events[4]["phase"]).To(Equal(""))
+	})
+
+	It("leaves the phase empty when a failure happens outside of any phase", func() {
+		sink.Emit(Event{Command: "package", Phase: "convert-vmdk", Event: "ConvertVMDKStarted", Status: Started})
+		sink.Emit(Event{Command: "package", Phase: "convert-vmdk", Event: "StemcellCreated", Status: Succeeded})
+		sink.Emit(Event{Command: "package", Event: "PackageFailed", Status: Failed, Error: "boom"})
+
+		Expect(lines()[2]["phase"]).To(Equal(""))
+		Expect(lines()[2]).NotTo(HaveKey("duration_seconds"))
+	})
+})
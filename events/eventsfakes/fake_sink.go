@@ -0,0 +1,76 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package eventsfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
+)
+
+type FakeSink struct {
+	EmitStub        func(events.Event)
+	emitMutex       sync.RWMutex
+	emitArgsForCall []struct {
+		arg1 events.Event
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSink) Emit(arg1 events.Event) {
+	fake.emitMutex.Lock()
+	fake.emitArgsForCall = append(fake.emitArgsForCall, struct {
+		arg1 events.Event
+	}{arg1})
+	stub := fake.EmitStub
+	fake.recordInvocation("Emit", []interface{}{arg1})
+	fake.emitMutex.Unlock()
+	if stub != nil {
+		fake.EmitStub(arg1)
+	}
+}
+
+func (fake *FakeSink) EmitCallCount() int {
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	return len(fake.emitArgsForCall)
+}
+
+func (fake *FakeSink) EmitCalls(stub func(events.Event)) {
+	fake.emitMutex.Lock()
+	defer fake.emitMutex.Unlock()
+	fake.EmitStub = stub
+}
+
+func (fake *FakeSink) EmitArgsForCall(i int) events.Event {
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	argsForCall := fake.emitArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSink) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSink) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ events.Sink = new(FakeSink)
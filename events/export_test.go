@@ -0,0 +1,10 @@
+package events
+
+import (
+	"io"
+	"time"
+)
+
+func NewJSONSinkWithClock(out io.Writer, now func() time.Time) *JSONSink {
+	return newJSONSink(out, now)
+}
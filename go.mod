@@ -12,6 +12,7 @@ require (
 	github.com/golang/mock v1.4.3
 	github.com/google/subcommands v1.2.0
 	github.com/google/uuid v1.1.2
+	github.com/jcmturner/gokrb5/v8 v8.4.2
 	github.com/kr/pretty v0.2.1 // indirect
 	github.com/kr/text v0.2.0 // indirect
 	github.com/masterzen/simplexml v0.0.0-20190410153822-31eea3082786 // indirect
@@ -24,9 +25,9 @@ require (
 	github.com/packer-community/winrmcp v0.0.0-20180921211025-c76d91c1e7db
 	github.com/pkg/errors v0.9.1
 	github.com/vmware/govmomi v0.23.1
-	golang.org/x/crypto v0.0.0-20200820211705-5c72a883971a // indirect
 	golang.org/x/net v0.0.0-20200904194848-62affa334b73 // indirect
 	golang.org/x/sys v0.0.0-20200909081042-eff7692f9009
 	golang.org/x/text v0.3.3 // indirect
 	golang.org/x/tools v0.0.0-20200505023115-26f46d2f7ef8 // indirect
+	gopkg.in/yaml.v2 v2.3.0
 )
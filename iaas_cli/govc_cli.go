@@ -2,8 +2,11 @@ package iaas_cli
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	_ "github.com/vmware/govmomi/govc/about"
 	"github.com/vmware/govmomi/govc/cli"
@@ -22,13 +25,49 @@ type CliRunner interface {
 }
 
 type GovcRunner struct {
+	// Context stops new govc commands from starting once it is cancelled, and stops waiting for a running one.
+	// govc cannot be cancelled itself, so an abandoned command keeps running until stembuild exits.
+	Context context.Context
 }
 
+// outputMutex serialises govc commands, since RunWithOutput swaps the process-wide os.Stdout and the VMs
+// of a batch construct run govc concurrently: a command run meanwhile would have its output captured too
+var outputMutex sync.Mutex
+
 func (r *GovcRunner) Run(args []string) int {
-	return cli.Run(args)
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+
+	return r.run(args)
+}
+
+func (r *GovcRunner) run(args []string) int {
+	if r.Context == nil {
+		return cli.Run(args)
+	}
+	if r.Context.Err() != nil {
+		fmt.Fprintf(os.Stderr, "govc %s not run: %s\n", args[0], r.Context.Err())
+		return 1
+	}
+
+	exitCode := make(chan int, 1)
+	go func() {
+		exitCode <- cli.Run(args)
+	}()
+
+	select {
+	case code := <-exitCode:
+		return code
+	case <-r.Context.Done():
+		fmt.Fprintf(os.Stderr, "stopped waiting for govc %s: %s\n", args[0], r.Context.Err())
+		return 1
+	}
 }
 
 func (r *GovcRunner) RunWithOutput(args []string) (string, int, error) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+
 	old := os.Stdout // keep backup of the real stdout
 	reader, w, _ := os.Pipe()
 	os.Stdout = w
@@ -48,7 +87,7 @@ func (r *GovcRunner) RunWithOutput(args []string) (string, int, error) {
 		}
 	}()
 
-	exitCode := r.Run(args)
+	exitCode := r.run(args)
 
 	// back to normal state
 	err := w.Close()
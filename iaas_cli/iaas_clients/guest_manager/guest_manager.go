@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/vmware/govmomi/vim25"
@@ -17,20 +18,26 @@ import (
 type ProcManager interface {
 	StartProgram(ctx context.Context, auth types.BaseGuestAuthentication, spec types.BaseGuestProgramSpec) (int64, error)
 	ListProcesses(ctx context.Context, auth types.BaseGuestAuthentication, pids []int64) ([]types.GuestProcessInfo, error)
+	TerminateProcess(ctx context.Context, auth types.BaseGuestAuthentication, pid int64) error
 	Client() *vim25.Client
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . FileManager
 type FileManager interface {
 	InitiateFileTransferFromGuest(ctx context.Context, auth types.BaseGuestAuthentication, guestFilePath string) (*types.FileTransferInformation, error)
+	InitiateFileTransferToGuest(ctx context.Context, auth types.BaseGuestAuthentication, guestFilePath string, fileAttributes types.BaseGuestFileAttributes, fileSize int64, overwrite bool) (string, error)
 	TransferURL(ctx context.Context, u string) (*url.URL, error)
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . DownloadClient
 type DownloadClient interface {
 	Download(ctx context.Context, u *url.URL, param *soap.Download) (io.ReadCloser, int64, error)
+	Upload(ctx context.Context, f io.Reader, u *url.URL, param *soap.Upload) error
 }
 
+// terminateTimeout bounds terminating a program after its context has been cancelled
+const terminateTimeout = 30 * time.Second
+
 type GuestManager struct {
 	auth           types.NamePasswordAuthentication
 	processManager ProcManager
@@ -57,10 +64,19 @@ func (g *GuestManager) StartProgramInGuest(ctx context.Context, command, args st
 	return pid, nil
 }
 
+// ExitCodeForProgramInGuest waits for the program to exit. If ctx is cancelled first, the program is
+// terminated so that it does not keep running in the guest unobserved.
 func (g *GuestManager) ExitCodeForProgramInGuest(ctx context.Context, pid int64) (int32, error) {
 	for {
+		if ctx.Err() != nil {
+			return -1, g.terminateProgram(pid, ctx.Err())
+		}
+
 		procs, err := g.processManager.ListProcesses(ctx, &g.auth, []int64{pid})
 		if err != nil {
+			if ctx.Err() != nil {
+				return -1, g.terminateProgram(pid, ctx.Err())
+			}
 			return -1, fmt.Errorf("vcenter_client - could not observe program exiting: %s", err.Error())
 		}
 
@@ -69,7 +85,10 @@ func (g *GuestManager) ExitCodeForProgramInGuest(ctx context.Context, pid int64)
 		}
 
 		if procs[0].EndTime == nil {
-			<-time.After(time.Millisecond * 250)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Millisecond * 250):
+			}
 			continue
 		}
 
@@ -77,6 +96,17 @@ func (g *GuestManager) ExitCodeForProgramInGuest(ctx context.Context, pid int64)
 	}
 }
 
+func (g *GuestManager) terminateProgram(pid int64, cause error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), terminateTimeout)
+	defer cancel()
+
+	err := g.processManager.TerminateProcess(ctx, &g.auth, pid)
+	if err != nil {
+		return fmt.Errorf("vcenter_client - stopped waiting for program %d: %s, and could not terminate it: %s", pid, cause, err)
+	}
+	return fmt.Errorf("vcenter_client - terminated program %d: %s", pid, cause)
+}
+
 func (g *GuestManager) DownloadFileInGuest(ctx context.Context, path string) (io.Reader, int64, error) {
 	info, err := g.fileManager.InitiateFileTransferFromGuest(ctx, &g.auth, path)
 	if err != nil {
@@ -97,3 +127,60 @@ func (g *GuestManager) DownloadFileInGuest(ctx context.Context, path string) (io
 
 	return f, n, nil
 }
+
+// UploadFileInGuest copies the local file source to destination on the guest, replacing any file already there.
+// If progress is not nil, it is called with the number of bytes sent so far as the file is read.
+func (g *GuestManager) UploadFileInGuest(ctx context.Context, source, destination string, progress func(sent, total int64)) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("vcenter_client - unable to upload file: %s", err.Error())
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("vcenter_client - unable to upload file: %s", err.Error())
+	}
+
+	transferURL, err := g.fileManager.InitiateFileTransferToGuest(ctx, &g.auth, destination, &types.GuestFileAttributes{}, info.Size(), true)
+	if err != nil {
+		return fmt.Errorf("vcenter_client - unable to upload file: %s", err.Error())
+	}
+
+	u, err := g.fileManager.TransferURL(ctx, transferURL)
+	if err != nil {
+		return fmt.Errorf("vcenter_client - unable to upload file: %s", err.Error())
+	}
+
+	p := soap.DefaultUpload
+	p.ContentLength = info.Size()
+
+	var src io.Reader = f
+	if progress != nil {
+		src = &progressReader{reader: f, total: info.Size(), progress: progress}
+	}
+
+	err = g.client.Upload(ctx, src, u, &p)
+	if err != nil {
+		return fmt.Errorf("vcenter_client - unable to upload file: %s", err.Error())
+	}
+
+	return nil
+}
+
+// progressReader tells progress how much of the file has been read
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
@@ -3,6 +3,11 @@ package guest_manager_test
 import (
 	"context"
 	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/guest_manager/guest_managerfakes"
@@ -10,6 +15,7 @@ import (
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/guest_manager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -73,6 +79,27 @@ var _ = Describe("GuestManager", func() {
 			Expect(err).To(MatchError("vcenter_client - could not observe program exiting: yo"))
 		})
 
+		It("terminates the program when the context is cancelled", func() {
+			procManager.ListProcessesReturns([]types.GuestProcessInfo{{}}, nil)
+			cancelledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			_, err := guestManager.ExitCodeForProgramInGuest(cancelledCtx, 1000)
+			Expect(err).To(MatchError("vcenter_client - terminated program 1000: context canceled"))
+			Expect(procManager.TerminateProcessCallCount()).To(Equal(1))
+			_, _, pid := procManager.TerminateProcessArgsForCall(0)
+			Expect(pid).To(Equal(int64(1000)))
+		})
+
+		It("reports a program that could not be terminated", func() {
+			procManager.TerminateProcessReturns(errors.New("guest unreachable"))
+			cancelledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			_, err := guestManager.ExitCodeForProgramInGuest(cancelledCtx, 1000)
+			Expect(err).To(MatchError("vcenter_client - stopped waiting for program 1000: context canceled, and could not terminate it: guest unreachable"))
+		})
+
 		It("returns an error if ListProcesses does not find pid", func() {
 			procManager.ListProcessesReturns([]types.GuestProcessInfo{}, nil)
 
@@ -118,4 +145,78 @@ var _ = Describe("GuestManager", func() {
 			Expect(client.DownloadCallCount()).To(Equal(1))
 		})
 	})
+	Describe("UploadFileInGuest", func() {
+		var source string
+
+		BeforeEach(func() {
+			f, err := ioutil.TempFile("", "guest-manager-upload")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = f.WriteString("some-content")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Close()).To(Succeed())
+			source = f.Name()
+
+			fileManager.InitiateFileTransferToGuestReturns("my.dude.edu", nil)
+			fileManager.TransferURLReturns(&url.URL{Host: "my.dude.edu"}, nil)
+		})
+
+		AfterEach(func() {
+			os.Remove(source)
+		})
+
+		It("uploads the file to the destination on the guest, overwriting it", func() {
+			err := guestManager.UploadFileInGuest(ctx, source, "C:\\provision\\file", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileManager.InitiateFileTransferToGuestCallCount()).To(Equal(1))
+			_, _, path, _, size, overwrite := fileManager.InitiateFileTransferToGuestArgsForCall(0)
+			Expect(path).To(Equal("C:\\provision\\file"))
+			Expect(size).To(Equal(int64(len("some-content"))))
+			Expect(overwrite).To(BeTrue())
+
+			_, transferURL := fileManager.TransferURLArgsForCall(0)
+			Expect(transferURL).To(Equal("my.dude.edu"))
+
+			Expect(client.UploadCallCount()).To(Equal(1))
+			_, _, u, param := client.UploadArgsForCall(0)
+			Expect(u.Host).To(Equal("my.dude.edu"))
+			Expect(param.ContentLength).To(Equal(int64(len("some-content"))))
+		})
+
+		It("reports how much of the file has been sent", func() {
+			client.UploadCalls(func(_ context.Context, f io.Reader, _ *url.URL, _ *soap.Upload) error {
+				_, err := ioutil.ReadAll(f)
+				return err
+			})
+			var sent, total int64
+
+			err := guestManager.UploadFileInGuest(ctx, source, "C:\\provision\\file", func(s, t int64) {
+				sent, total = s, t
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sent).To(Equal(int64(len("some-content"))))
+			Expect(total).To(Equal(int64(len("some-content"))))
+		})
+
+		It("returns an error if the source cannot be opened", func() {
+			err := guestManager.UploadFileInGuest(ctx, filepath.Join(os.TempDir(), "does-not-exist"), "C:\\file", nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("vcenter_client - unable to upload file: "))
+			Expect(fileManager.InitiateFileTransferToGuestCallCount()).To(Equal(0))
+		})
+
+		It("returns an error if InitiateFileTransferToGuest fails", func() {
+			fileManager.InitiateFileTransferToGuestReturns("", errors.New("couldn't initiate file transfer :("))
+
+			err := guestManager.UploadFileInGuest(ctx, source, "C:\\file", nil)
+			Expect(err).To(MatchError("vcenter_client - unable to upload file: couldn't initiate file transfer :("))
+		})
+
+		It("returns an error if Upload fails", func() {
+			client.UploadReturns(errors.New("connection reset"))
+
+			err := guestManager.UploadFileInGuest(ctx, source, "C:\\file", nil)
+			Expect(err).To(MatchError("vcenter_client - unable to upload file: connection reset"))
+		})
+	})
 })
@@ -26,6 +26,24 @@ type FakeFileManager struct {
 		result1 *types.FileTransferInformation
 		result2 error
 	}
+	InitiateFileTransferToGuestStub        func(context.Context, types.BaseGuestAuthentication, string, types.BaseGuestFileAttributes, int64, bool) (string, error)
+	initiateFileTransferToGuestMutex       sync.RWMutex
+	initiateFileTransferToGuestArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.BaseGuestAuthentication
+		arg3 string
+		arg4 types.BaseGuestFileAttributes
+		arg5 int64
+		arg6 bool
+	}
+	initiateFileTransferToGuestReturns struct {
+		result1 string
+		result2 error
+	}
+	initiateFileTransferToGuestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	TransferURLStub        func(context.Context, string) (*url.URL, error)
 	transferURLMutex       sync.RWMutex
 	transferURLArgsForCall []struct {
@@ -52,15 +70,16 @@ func (fake *FakeFileManager) InitiateFileTransferFromGuest(arg1 context.Context,
 		arg2 types.BaseGuestAuthentication
 		arg3 string
 	}{arg1, arg2, arg3})
+	stub := fake.InitiateFileTransferFromGuestStub
+	fakeReturns := fake.initiateFileTransferFromGuestReturns
 	fake.recordInvocation("InitiateFileTransferFromGuest", []interface{}{arg1, arg2, arg3})
 	fake.initiateFileTransferFromGuestMutex.Unlock()
-	if fake.InitiateFileTransferFromGuestStub != nil {
-		return fake.InitiateFileTransferFromGuestStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.initiateFileTransferFromGuestReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -109,6 +128,75 @@ func (fake *FakeFileManager) InitiateFileTransferFromGuestReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
+func (fake *FakeFileManager) InitiateFileTransferToGuest(arg1 context.Context, arg2 types.BaseGuestAuthentication, arg3 string, arg4 types.BaseGuestFileAttributes, arg5 int64, arg6 bool) (string, error) {
+	fake.initiateFileTransferToGuestMutex.Lock()
+	ret, specificReturn := fake.initiateFileTransferToGuestReturnsOnCall[len(fake.initiateFileTransferToGuestArgsForCall)]
+	fake.initiateFileTransferToGuestArgsForCall = append(fake.initiateFileTransferToGuestArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.BaseGuestAuthentication
+		arg3 string
+		arg4 types.BaseGuestFileAttributes
+		arg5 int64
+		arg6 bool
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.InitiateFileTransferToGuestStub
+	fakeReturns := fake.initiateFileTransferToGuestReturns
+	fake.recordInvocation("InitiateFileTransferToGuest", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.initiateFileTransferToGuestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFileManager) InitiateFileTransferToGuestCallCount() int {
+	fake.initiateFileTransferToGuestMutex.RLock()
+	defer fake.initiateFileTransferToGuestMutex.RUnlock()
+	return len(fake.initiateFileTransferToGuestArgsForCall)
+}
+
+func (fake *FakeFileManager) InitiateFileTransferToGuestCalls(stub func(context.Context, types.BaseGuestAuthentication, string, types.BaseGuestFileAttributes, int64, bool) (string, error)) {
+	fake.initiateFileTransferToGuestMutex.Lock()
+	defer fake.initiateFileTransferToGuestMutex.Unlock()
+	fake.InitiateFileTransferToGuestStub = stub
+}
+
+func (fake *FakeFileManager) InitiateFileTransferToGuestArgsForCall(i int) (context.Context, types.BaseGuestAuthentication, string, types.BaseGuestFileAttributes, int64, bool) {
+	fake.initiateFileTransferToGuestMutex.RLock()
+	defer fake.initiateFileTransferToGuestMutex.RUnlock()
+	argsForCall := fake.initiateFileTransferToGuestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeFileManager) InitiateFileTransferToGuestReturns(result1 string, result2 error) {
+	fake.initiateFileTransferToGuestMutex.Lock()
+	defer fake.initiateFileTransferToGuestMutex.Unlock()
+	fake.InitiateFileTransferToGuestStub = nil
+	fake.initiateFileTransferToGuestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFileManager) InitiateFileTransferToGuestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.initiateFileTransferToGuestMutex.Lock()
+	defer fake.initiateFileTransferToGuestMutex.Unlock()
+	fake.InitiateFileTransferToGuestStub = nil
+	if fake.initiateFileTransferToGuestReturnsOnCall == nil {
+		fake.initiateFileTransferToGuestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.initiateFileTransferToGuestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFileManager) TransferURL(arg1 context.Context, arg2 string) (*url.URL, error) {
 	fake.transferURLMutex.Lock()
 	ret, specificReturn := fake.transferURLReturnsOnCall[len(fake.transferURLArgsForCall)]
@@ -116,15 +204,16 @@ func (fake *FakeFileManager) TransferURL(arg1 context.Context, arg2 string) (*ur
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.TransferURLStub
+	fakeReturns := fake.transferURLReturns
 	fake.recordInvocation("TransferURL", []interface{}{arg1, arg2})
 	fake.transferURLMutex.Unlock()
-	if fake.TransferURLStub != nil {
-		return fake.TransferURLStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.transferURLReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -178,6 +267,8 @@ func (fake *FakeFileManager) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.initiateFileTransferFromGuestMutex.RLock()
 	defer fake.initiateFileTransferFromGuestMutex.RUnlock()
+	fake.initiateFileTransferToGuestMutex.RLock()
+	defer fake.initiateFileTransferToGuestMutex.RUnlock()
 	fake.transferURLMutex.RLock()
 	defer fake.transferURLMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -10,6 +10,7 @@ import (
 	"github.com/vmware/govmomi/object"
 
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/factory"
+	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/vcenter_manager"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -62,7 +63,7 @@ var _ = BeforeSuite(func() {
 
 	TestVmPath = testVmPath + fmt.Sprintf("%s", uuid.New())[0:8]
 
-	err = vCenterManager.CloneVM(CTX, vmToClone, TestVmPath)
+	err = vCenterManager.CloneVM(CTX, vmToClone, TestVmPath, vcenter_manager.CloneOptions{})
 	Expect(err).ToNot(HaveOccurred())
 
 	time.Sleep(30 * time.Second)
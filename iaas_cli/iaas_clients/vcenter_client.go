@@ -118,12 +118,11 @@ func (c *VcenterClient) ExportVM(vmInventoryPath string, destination string) err
 	return nil
 }
 
-func (c *VcenterClient) UploadArtifact(vmInventoryPath, artifact, destination, username, password string) error {
-	vmCredentials := fmt.Sprintf("%s:%s", username, password)
-	args := c.buildGovcCommand("guest.upload", "-f", "-l", vmCredentials, "-vm", vmInventoryPath, artifact, destination)
+func (c *VcenterClient) CaptureScreenshot(vmInventoryPath, destination string) error {
+	args := c.buildGovcCommand("vm.console", "-capture", destination, vmInventoryPath)
 	errCode := c.Runner.Run(args)
 	if errCode != 0 {
-		return fmt.Errorf("vcenter_client - %s could not be uploaded", artifact)
+		return fmt.Errorf("vcenter_client - console screenshot of %s could not be captured", vmInventoryPath)
 	}
 	return nil
 }
@@ -214,3 +213,55 @@ func (c *VcenterClient) IsPoweredOff(vmInventoryPath string) (bool, error) {
 
 	return false, nil
 }
+
+// hardeningProfileKey is the extraConfig key construct records the hardening profile of a VM under
+const hardeningProfileKey = "stembuild.hardening_profile"
+
+func (c *VcenterClient) SetHardeningProfile(vmInventoryPath, profile string) error {
+	args := c.buildGovcCommand("vm.change", "-vm", vmInventoryPath, "-e", fmt.Sprintf("%s=%s", hardeningProfileKey, profile))
+	errCode := c.Runner.Run(args)
+	if errCode != 0 {
+		return fmt.Errorf("vcenter_client - hardening profile of %s could not be recorded", vmInventoryPath)
+	}
+	return nil
+}
+
+type govcVMInfo struct {
+	VirtualMachines []struct {
+		Config struct {
+			ExtraConfig []struct {
+				Key   string
+				Value interface{}
+			}
+		}
+	}
+}
+
+// HardeningProfile returns the hardening profile construct recorded for the VM, or an empty string if it recorded none
+func (c *VcenterClient) HardeningProfile(vmInventoryPath string) (string, error) {
+	args := c.buildGovcCommand("vm.info", "-e", "-json", vmInventoryPath)
+	out, exitCode, err := c.Runner.RunWithOutput(args)
+	if exitCode != 0 {
+		return "", fmt.Errorf("vcenter_client - failed to get vm info, govc exit code: %d", exitCode)
+	}
+	if err != nil {
+		return "", fmt.Errorf("vcenter_client - failed to read the hardening profile of %s: %s", vmInventoryPath, err)
+	}
+
+	info := govcVMInfo{}
+	err = json.Unmarshal([]byte(out), &info)
+	if err != nil {
+		return "", fmt.Errorf("vcenter_client - received bad JSON output for %s: %s", vmInventoryPath, err)
+	}
+	if len(info.VirtualMachines) != 1 {
+		return "", fmt.Errorf("vcenter_client - couldn't get vm info for %s", vmInventoryPath)
+	}
+
+	for _, option := range info.VirtualMachines[0].Config.ExtraConfig {
+		if option.Key == hardeningProfileKey {
+			profile, _ := option.Value.(string)
+			return profile, nil
+		}
+	}
+	return "", nil
+}
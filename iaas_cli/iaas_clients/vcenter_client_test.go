@@ -293,22 +293,21 @@ ethernet-0         VirtualE1000e                 internal-network
 		})
 	})
 
-	Describe("UploadArtifact", func() {
-		It("Uploads artifact to the given vm", func() {
+	Describe("CaptureScreenshot", func() {
+		It("Captures the console of the given vm to the destination", func() {
 			runner.RunReturns(0)
-			err := vcenterClient.UploadArtifact("validVMPath", "artifact", "C:\\provision\\artifact", "user", "pass")
+			err := vcenterClient.CaptureScreenshot("validVMPath", "/tmp/console.png")
 
 			Expect(err).To(Not(HaveOccurred()))
-			expectedArgs := []string{"guest.upload", "-u", credentialUrl, "-f", "-l", "user:pass", "-vm", "validVMPath", "artifact", "C:\\provision\\artifact"}
+			expectedArgs := []string{"vm.console", "-u", credentialUrl, "-capture", "/tmp/console.png", "validVMPath"}
 			Expect(runner.RunArgsForCall(0)).To(Equal(expectedArgs))
 		})
 
-		It("Returns an error if VCenter reports a failure uploading the artifact", func() {
+		It("Returns an error if VCenter reports a failure capturing the console", func() {
 			runner.RunReturns(1)
-			err := vcenterClient.UploadArtifact("validVMPath", "artifact", "C:\\provision\\artifact", "user", "pass")
+			err := vcenterClient.CaptureScreenshot("validVMPath", "/tmp/console.png")
 
-			Expect(err).To(HaveOccurred())
-			Expect(err).To(MatchError("vcenter_client - artifact could not be uploaded"))
+			Expect(err).To(MatchError("vcenter_client - console screenshot of validVMPath could not be captured"))
 		})
 	})
 
@@ -467,4 +466,54 @@ ethernet-0         VirtualE1000e                 internal-network
 		})
 	})
 
+	Describe("SetHardeningProfile", func() {
+		It("records the profile in the extraConfig of the VM", func() {
+			expectedArgs := []string{"vm.change", "-u", credentialUrl, "-vm", "validVMPath", "-e", "stembuild.hardening_profile=minimal"}
+
+			err := vcenterClient.SetHardeningProfile("validVMPath", "minimal")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.RunArgsForCall(0)).To(Equal(expectedArgs))
+		})
+
+		It("returns an error if the profile cannot be recorded", func() {
+			runner.RunReturns(1)
+
+			err := vcenterClient.SetHardeningProfile("validVMPath", "minimal")
+
+			Expect(err).To(MatchError("vcenter_client - hardening profile of validVMPath could not be recorded"))
+		})
+	})
+
+	Describe("HardeningProfile", func() {
+		It("reads the profile from the extraConfig of the VM", func() {
+			expectedArgs := []string{"vm.info", "-u", credentialUrl, "-e", "-json", "validVMPath"}
+			runner.RunWithOutputReturns(`{"VirtualMachines":[{"Config":{"ExtraConfig":[{"Key":"svga.present","Value":"TRUE"},{"Key":"stembuild.hardening_profile","Value":"custom"}]}}]}`, 0, nil)
+
+			profile, err := vcenterClient.HardeningProfile("validVMPath")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(profile).To(Equal("custom"))
+			Expect(runner.RunWithOutputArgsForCall(0)).To(Equal(expectedArgs))
+		})
+
+		It("returns an empty profile if none was recorded", func() {
+			runner.RunWithOutputReturns(`{"VirtualMachines":[{"Config":{"ExtraConfig":[{"Key":"svga.present","Value":"TRUE"}]}}]}`, 0, nil)
+
+			profile, err := vcenterClient.HardeningProfile("validVMPath")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(profile).To(BeEmpty())
+		})
+
+		It("returns an error if govc fails or its output is not valid", func() {
+			runner.RunWithOutputReturns("", 1, nil)
+			_, err := vcenterClient.HardeningProfile("validVMPath")
+			Expect(err).To(MatchError("vcenter_client - failed to get vm info, govc exit code: 1"))
+
+			runner.RunWithOutputReturns("not json", 0, nil)
+			_, err = vcenterClient.HardeningProfile("validVMPath")
+			Expect(err).To(MatchError(ContainSubstring("vcenter_client - received bad JSON output for validVMPath")))
+		})
+	})
 })
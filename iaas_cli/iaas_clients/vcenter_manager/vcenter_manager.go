@@ -13,6 +13,7 @@ import (
 	"github.com/vmware/govmomi/vim25"
 
 	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/guest_manager"
@@ -30,6 +31,7 @@ type Finder interface {
 	ResourcePoolOrDefault(ctx context.Context, path string) (*object.ResourcePool, error)
 	SetDatacenter(dc *object.Datacenter) *find.Finder
 	FolderOrDefault(ctx context.Context, path string) (*object.Folder, error)
+	DatastoreOrDefault(ctx context.Context, path string) (*object.Datastore, error)
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . OpsManager
@@ -69,8 +71,19 @@ func (v *VCenterManager) FindVM(ctx context.Context, inventoryPath string) (*obj
 	return vm, nil
 }
 
-// CloneVM clones vm to clonePath. It currently does no network configuration (i.e. there is no IP assigned)
-func (v *VCenterManager) CloneVM(ctx context.Context, vm *object.VirtualMachine, clonePath string) error {
+// CloneOptions place a clone somewhere other than next to the VM it was cloned from.
+// Empty fields fall back to the folder of the clone path and the resource pool and datastore of the source VM.
+// With Snapshot the clone is a linked clone, which shares the disks of the source VM as they were at that snapshot
+// instead of copying them.
+type CloneOptions struct {
+	Folder       string
+	ResourcePool string
+	Datastore    string
+	Snapshot     string
+}
+
+// CloneVM clones vm to clonePath and powers the clone on. It currently does no network configuration (i.e. there is no IP assigned)
+func (v *VCenterManager) CloneVM(ctx context.Context, vm *object.VirtualMachine, clonePath string, options CloneOptions) error {
 
 	dc := strings.Split(vm.InventoryPath, "/")[0]
 
@@ -81,14 +94,23 @@ func (v *VCenterManager) CloneVM(ctx context.Context, vm *object.VirtualMachine,
 
 	v.finder.SetDatacenter(datacenter)
 
-	resourcePool, err := vm.ResourcePool(ctx)
+	var resourcePool *object.ResourcePool
+	if options.ResourcePool != "" {
+		resourcePool, err = v.finder.ResourcePoolOrDefault(ctx, options.ResourcePool)
+	} else {
+		resourcePool, err = vm.ResourcePool(ctx)
+	}
 	if err != nil {
 		return err
 	}
 
 	// use path, not filepath so the windows stembuild executable
 	//	still respects vsphere's forward slash scheme
-	folder, err := v.finder.FolderOrDefault(ctx, path.Dir(clonePath))
+	folderPath := path.Dir(clonePath)
+	if options.Folder != "" {
+		folderPath = options.Folder
+	}
+	folder, err := v.finder.FolderOrDefault(ctx, folderPath)
 	if err != nil {
 		return err
 	}
@@ -102,6 +124,23 @@ func (v *VCenterManager) CloneVM(ctx context.Context, vm *object.VirtualMachine,
 		PowerOn: true,
 	}
 
+	if options.Datastore != "" {
+		datastore, err := v.finder.DatastoreOrDefault(ctx, options.Datastore)
+		if err != nil {
+			return err
+		}
+		datastoreRef := datastore.Reference()
+		config.Location.Datastore = &datastoreRef
+	}
+
+	if options.Snapshot != "" {
+		config.Snapshot, err = vm.FindSnapshot(ctx, options.Snapshot)
+		if err != nil {
+			return err
+		}
+		config.Location.DiskMoveType = string(types.VirtualMachineRelocateDiskMoveOptionsCreateNewChildDiskBacking)
+	}
+
 	task, err := vm.Clone(ctx, folder, path.Base(clonePath), config)
 	if err != nil {
 		return err
@@ -115,6 +154,114 @@ func (v *VCenterManager) CloneVM(ctx context.Context, vm *object.VirtualMachine,
 	return nil
 }
 
+// DestroyVM powers vm off if it is on and deletes it along with its disks
+func (v *VCenterManager) DestroyVM(ctx context.Context, vm *object.VirtualMachine) error {
+	powerState, err := vm.PowerState(ctx)
+	if err != nil {
+		return err
+	}
+	if powerState == types.VirtualMachinePowerStatePoweredOn {
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			return err
+		}
+		err = task.Wait(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	task, err := vm.Destroy(ctx)
+	if err != nil {
+		return err
+	}
+	return task.Wait(ctx)
+}
+
+// WaitForIP blocks until VMware Tools on the guest reports an IPv4 address
+func (v *VCenterManager) WaitForIP(ctx context.Context, vm *object.VirtualMachine) (string, error) {
+	return vm.WaitForIP(ctx, true)
+}
+
+// GuestHeartbeatIsGreen reports whether VMware Tools on vm is sending heartbeats normally.
+// The heartbeat stops while the guest reboots, so it tells a rebooting VM apart from one that is up.
+func (v *VCenterManager) GuestHeartbeatIsGreen(ctx context.Context, vm *object.VirtualMachine) (bool, error) {
+	var o mo.VirtualMachine
+	err := vm.Properties(ctx, vm.Reference(), []string{"guestHeartbeatStatus"}, &o)
+	if err != nil {
+		return false, err
+	}
+	return o.GuestHeartbeatStatus == types.ManagedEntityStatusGreen, nil
+}
+
+// HasSnapshot reports whether vm has a snapshot called name anywhere in its snapshot tree
+func (v *VCenterManager) HasSnapshot(ctx context.Context, vm *object.VirtualMachine, name string) (bool, error) {
+	var o mo.VirtualMachine
+	err := vm.Properties(ctx, vm.Reference(), []string{"snapshot"}, &o)
+	if err != nil {
+		return false, err
+	}
+	if o.Snapshot == nil {
+		return false, nil
+	}
+	return snapshotTreeContains(o.Snapshot.RootSnapshotList, name), nil
+}
+
+func snapshotTreeContains(tree []types.VirtualMachineSnapshotTree, name string) bool {
+	for _, snapshot := range tree {
+		if snapshot.Name == name || snapshotTreeContains(snapshot.ChildSnapshotList, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSnapshot takes a disk-only snapshot of vm
+func (v *VCenterManager) CreateSnapshot(ctx context.Context, vm *object.VirtualMachine, name, description string) error {
+	task, err := vm.CreateSnapshot(ctx, name, description, false, false)
+	if err != nil {
+		return err
+	}
+	return task.Wait(ctx)
+}
+
+// RevertToSnapshot reverts vm to the named snapshot. Snapshots are taken without memory,
+// so the VM is powered back on afterwards if the revert left it powered off.
+func (v *VCenterManager) RevertToSnapshot(ctx context.Context, vm *object.VirtualMachine, name string) error {
+	task, err := vm.RevertToSnapshot(ctx, name, false)
+	if err != nil {
+		return err
+	}
+	err = task.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	powerState, err := vm.PowerState(ctx)
+	if err != nil {
+		return err
+	}
+	if powerState == types.VirtualMachinePowerStatePoweredOn {
+		return nil
+	}
+
+	task, err = vm.PowerOn(ctx)
+	if err != nil {
+		return err
+	}
+	return task.Wait(ctx)
+}
+
+// RemoveSnapshot deletes the named snapshot, consolidating its disks into the VM
+func (v *VCenterManager) RemoveSnapshot(ctx context.Context, vm *object.VirtualMachine, name string) error {
+	consolidate := true
+	task, err := vm.RemoveSnapshot(ctx, name, false, &consolidate)
+	if err != nil {
+		return err
+	}
+	return task.Wait(ctx)
+}
+
 func (v *VCenterManager) OperationsManager(ctx context.Context, vm *object.VirtualMachine) *guest.OperationsManager {
 	return guest.NewOperationsManager(v.vimClient, vm.Reference())
 }
@@ -25,6 +25,20 @@ type FakeFinder struct {
 		result1 *object.Datacenter
 		result2 error
 	}
+	DatastoreOrDefaultStub        func(context.Context, string) (*object.Datastore, error)
+	datastoreOrDefaultMutex       sync.RWMutex
+	datastoreOrDefaultArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	datastoreOrDefaultReturns struct {
+		result1 *object.Datastore
+		result2 error
+	}
+	datastoreOrDefaultReturnsOnCall map[int]struct {
+		result1 *object.Datastore
+		result2 error
+	}
 	FolderOrDefaultStub        func(context.Context, string) (*object.Folder, error)
 	folderOrDefaultMutex       sync.RWMutex
 	folderOrDefaultArgsForCall []struct {
@@ -89,15 +103,16 @@ func (fake *FakeFinder) DatacenterOrDefault(arg1 context.Context, arg2 string) (
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.DatacenterOrDefaultStub
+	fakeReturns := fake.datacenterOrDefaultReturns
 	fake.recordInvocation("DatacenterOrDefault", []interface{}{arg1, arg2})
 	fake.datacenterOrDefaultMutex.Unlock()
-	if fake.DatacenterOrDefaultStub != nil {
-		return fake.DatacenterOrDefaultStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.datacenterOrDefaultReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -146,6 +161,71 @@ func (fake *FakeFinder) DatacenterOrDefaultReturnsOnCall(i int, result1 *object.
 	}{result1, result2}
 }
 
+func (fake *FakeFinder) DatastoreOrDefault(arg1 context.Context, arg2 string) (*object.Datastore, error) {
+	fake.datastoreOrDefaultMutex.Lock()
+	ret, specificReturn := fake.datastoreOrDefaultReturnsOnCall[len(fake.datastoreOrDefaultArgsForCall)]
+	fake.datastoreOrDefaultArgsForCall = append(fake.datastoreOrDefaultArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DatastoreOrDefaultStub
+	fakeReturns := fake.datastoreOrDefaultReturns
+	fake.recordInvocation("DatastoreOrDefault", []interface{}{arg1, arg2})
+	fake.datastoreOrDefaultMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFinder) DatastoreOrDefaultCallCount() int {
+	fake.datastoreOrDefaultMutex.RLock()
+	defer fake.datastoreOrDefaultMutex.RUnlock()
+	return len(fake.datastoreOrDefaultArgsForCall)
+}
+
+func (fake *FakeFinder) DatastoreOrDefaultCalls(stub func(context.Context, string) (*object.Datastore, error)) {
+	fake.datastoreOrDefaultMutex.Lock()
+	defer fake.datastoreOrDefaultMutex.Unlock()
+	fake.DatastoreOrDefaultStub = stub
+}
+
+func (fake *FakeFinder) DatastoreOrDefaultArgsForCall(i int) (context.Context, string) {
+	fake.datastoreOrDefaultMutex.RLock()
+	defer fake.datastoreOrDefaultMutex.RUnlock()
+	argsForCall := fake.datastoreOrDefaultArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeFinder) DatastoreOrDefaultReturns(result1 *object.Datastore, result2 error) {
+	fake.datastoreOrDefaultMutex.Lock()
+	defer fake.datastoreOrDefaultMutex.Unlock()
+	fake.DatastoreOrDefaultStub = nil
+	fake.datastoreOrDefaultReturns = struct {
+		result1 *object.Datastore
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFinder) DatastoreOrDefaultReturnsOnCall(i int, result1 *object.Datastore, result2 error) {
+	fake.datastoreOrDefaultMutex.Lock()
+	defer fake.datastoreOrDefaultMutex.Unlock()
+	fake.DatastoreOrDefaultStub = nil
+	if fake.datastoreOrDefaultReturnsOnCall == nil {
+		fake.datastoreOrDefaultReturnsOnCall = make(map[int]struct {
+			result1 *object.Datastore
+			result2 error
+		})
+	}
+	fake.datastoreOrDefaultReturnsOnCall[i] = struct {
+		result1 *object.Datastore
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFinder) FolderOrDefault(arg1 context.Context, arg2 string) (*object.Folder, error) {
 	fake.folderOrDefaultMutex.Lock()
 	ret, specificReturn := fake.folderOrDefaultReturnsOnCall[len(fake.folderOrDefaultArgsForCall)]
@@ -153,15 +233,16 @@ func (fake *FakeFinder) FolderOrDefault(arg1 context.Context, arg2 string) (*obj
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.FolderOrDefaultStub
+	fakeReturns := fake.folderOrDefaultReturns
 	fake.recordInvocation("FolderOrDefault", []interface{}{arg1, arg2})
 	fake.folderOrDefaultMutex.Unlock()
-	if fake.FolderOrDefaultStub != nil {
-		return fake.FolderOrDefaultStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.folderOrDefaultReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -217,15 +298,16 @@ func (fake *FakeFinder) ResourcePoolOrDefault(arg1 context.Context, arg2 string)
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.ResourcePoolOrDefaultStub
+	fakeReturns := fake.resourcePoolOrDefaultReturns
 	fake.recordInvocation("ResourcePoolOrDefault", []interface{}{arg1, arg2})
 	fake.resourcePoolOrDefaultMutex.Unlock()
-	if fake.ResourcePoolOrDefaultStub != nil {
-		return fake.ResourcePoolOrDefaultStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.resourcePoolOrDefaultReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -280,15 +362,16 @@ func (fake *FakeFinder) SetDatacenter(arg1 *object.Datacenter) *find.Finder {
 	fake.setDatacenterArgsForCall = append(fake.setDatacenterArgsForCall, struct {
 		arg1 *object.Datacenter
 	}{arg1})
+	stub := fake.SetDatacenterStub
+	fakeReturns := fake.setDatacenterReturns
 	fake.recordInvocation("SetDatacenter", []interface{}{arg1})
 	fake.setDatacenterMutex.Unlock()
-	if fake.SetDatacenterStub != nil {
-		return fake.SetDatacenterStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.setDatacenterReturns
 	return fakeReturns.result1
 }
 
@@ -341,15 +424,16 @@ func (fake *FakeFinder) VirtualMachine(arg1 context.Context, arg2 string) (*obje
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.VirtualMachineStub
+	fakeReturns := fake.virtualMachineReturns
 	fake.recordInvocation("VirtualMachine", []interface{}{arg1, arg2})
 	fake.virtualMachineMutex.Unlock()
-	if fake.VirtualMachineStub != nil {
-		return fake.VirtualMachineStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.virtualMachineReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -403,6 +487,8 @@ func (fake *FakeFinder) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.datacenterOrDefaultMutex.RLock()
 	defer fake.datacenterOrDefaultMutex.RUnlock()
+	fake.datastoreOrDefaultMutex.RLock()
+	defer fake.datastoreOrDefaultMutex.RUnlock()
 	fake.folderOrDefaultMutex.RLock()
 	defer fake.folderOrDefaultMutex.RUnlock()
 	fake.resourcePoolOrDefaultMutex.RLock()
@@ -101,7 +101,7 @@ var _ = Describe("stembuild construct", func() {
 		session := helpers.Stembuild(stembuildExecutable, "construct", "-vm-ip", conf.TargetIP, "-vm-username", conf.VMUsername, "-vm-password", conf.VMPassword, "-vcenter-url", conf.VCenterURL, "-vcenter-username", conf.VCenterUsername, "-vcenter-password", conf.VCenterPassword, "-vm-inventory-path", conf.VMInventoryPath, "-vcenter-ca-certs", conf.VCenterCACert)
 
 		Eventually(session, constructOutputTimeout).Should(Exit(1))
-		Eventually(session.Err).Should(Say(`Could not find LGPO.zip in the current directory`))
+		Eventually(session.Err).Should(Say(`could not find LGPO.zip in the working directory or next to stembuild`))
 	})
 
 	It("does not exit when the target VM has not powered off", func() {
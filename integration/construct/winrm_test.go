@@ -25,7 +25,7 @@ var _ = Describe("WinRM Remote Manager", func() {
 
 	Context("ExtractArchive", func() {
 		BeforeEach(func() {
-			err := rm.UploadArtifact(filepath.Join("assets", "StemcellAutomation.zip"), "C:\\provision\\StemcellAutomation.zip")
+			err := rm.UploadArtifact(filepath.Join("assets", "StemcellAutomation.zip"), "C:\\provision\\StemcellAutomation.zip", nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
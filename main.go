@@ -4,16 +4,17 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path"
 	"strings"
 
-	"github.com/cloudfoundry-incubator/stembuild/assets"
 	. "github.com/cloudfoundry-incubator/stembuild/commandparser"
 	vmconstruct_factory "github.com/cloudfoundry-incubator/stembuild/construct/factory"
+	"github.com/cloudfoundry-incubator/stembuild/credentials"
+	"github.com/cloudfoundry-incubator/stembuild/events"
 	vcenter_client_factory "github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/factory"
 	packager_factory "github.com/cloudfoundry-incubator/stembuild/package_stemcell/factory"
+	verify_factory "github.com/cloudfoundry-incubator/stembuild/verify/factory"
 	"github.com/cloudfoundry-incubator/stembuild/version"
 	. "github.com/google/subcommands"
 )
@@ -26,23 +27,20 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Warning: The following environment variable is set and might override flags provided to stembuild: %s\n", env_name)
 		}
 	}
-	data, err := assets.Asset("StemcellAutomation.zip")
-	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, "StemcellAutomation not found")
-		os.Exit(1)
-	}
-	s := "./StemcellAutomation.zip"
-	err = ioutil.WriteFile(s, data, 0644)
-	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, "Unable to write StemcellAutomation.zip")
-		os.Exit(1)
-	}
 
 	var gf GlobalFlags
-	packageCmd := NewPackageCommand(version.NewVersionGetter(), &packager_factory.PackagerFactory{}, &PackageMessenger{os.Stderr})
+	packagerFactory := &packager_factory.PackagerFactory{}
+	packageCmd := NewPackageCommand(version.NewVersionGetter(), packagerFactory, &PackageMessenger{os.Stderr})
 	packageCmd.GlobalFlags = &gf
-	constructCmd := NewConstructCmd(context.Background(), &vmconstruct_factory.VMConstructFactory{}, &vcenter_client_factory.ManagerFactory{}, &ConstructValidator{}, &ConstructCmdMessenger{OutputChannel: os.Stderr})
+	vmConstructFactory := &vmconstruct_factory.VMConstructFactory{}
+	constructCmd := NewConstructCmd(context.Background(), vmConstructFactory, &vcenter_client_factory.ManagerFactory{}, &ConstructValidator{}, &ConstructCmdMessenger{OutputChannel: os.Stderr})
 	constructCmd.GlobalFlags = &gf
+	verifyCmd := NewVerifyCmd(context.Background(), &verify_factory.VMVerifierFactory{}, &vcenter_client_factory.ManagerFactory{}, &VerifyCmdMessenger{OutputChannel: os.Stderr})
+	verifyCmd.GlobalFlags = &gf
+	credentialResolver := credentials.NewResolver(os.Stdin, os.Stderr)
+	packageCmd.Credentials = credentialResolver
+	constructCmd.Credentials = credentialResolver
+	verifyCmd.Credentials = credentialResolver
 
 	var commands = make([]Command, 0)
 
@@ -51,6 +49,7 @@ func main() {
 	fs.BoolVar(&gf.Color, "color", false, "Colorize debug output")
 	fs.BoolVar(&gf.ShowVersion, "version", false, "Show Stembuild version")
 	fs.BoolVar(&gf.ShowVersion, "v", false, "Stembuild version (shorthand)")
+	fs.StringVar(&gf.Output, "output", TextOutput, "Output format: 'text' or 'json' (one JSON event per line on stdout)")
 
 	commander := NewCommander(fs, path.Base(os.Args[0]))
 
@@ -60,9 +59,11 @@ func main() {
 
 	commander.Register(packageCmd, "")
 	commander.Register(constructCmd, "")
+	commander.Register(verifyCmd, "")
 
 	commands = append(commands, packageCmd)
 	commands = append(commands, constructCmd)
+	commands = append(commands, verifyCmd)
 
 	// Override the default usage text of Google's Subcommand with our own
 	fs.Usage = func() { sh.Explain(commander.Error) }
@@ -70,12 +71,22 @@ func main() {
 	_ = fs.Parse(os.Args[1:])
 	if gf.ShowVersion {
 		_, _ = fmt.Fprintf(os.Stdout, "%s version %s, Windows Stemcell Building Tool\n\n", path.Base(os.Args[0]), version.Version)
-		_ = os.Remove(s)
 		os.Exit(0)
 	}
 
+	switch gf.Output {
+	case TextOutput:
+	case JSONOutput:
+		sink := events.NewJSONSink(os.Stdout)
+		packagerFactory.Events = sink
+		packageCmd.Events = sink
+		constructCmd.Events = sink
+		verifyCmd.Events = sink
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "Unknown output format '%s', expected 'text' or 'json'\n", gf.Output)
+		os.Exit(1)
+	}
+
 	ctx := context.Background()
-	i := int(commander.Execute(ctx))
-	_ = os.Remove(s)
-	os.Exit(i)
+	os.Exit(int(commander.Execute(ctx)))
 }
@@ -391,6 +391,15 @@ Describe "Invoke-Sysprep" {
                 Assert-MockCalled Enable-LocalSecurityPolicy -ParameterFilter { $PolicySource -eq $ExpectedPath } -Times 1 -Scope It -ModuleName BOSH.Sysprep
             }
 
+            It "applies the given policy instead of the embedded one if -PolicySource is set" {
+                Mock Get-OSVersion { "windows2019" } -ModuleName Bosh.Sysprep
+
+                { Invoke-Sysprep -Iaas "aws" -PolicySource "C:\provision\hardening-gpo" } | Should -Not -Throw
+
+                Assert-MockCalled Enable-LocalSecurityPolicy -ParameterFilter { $PolicySource -eq "C:\provision\hardening-gpo" } -Times 1 -Scope It -ModuleName BOSH.Sysprep
+                Assert-MockCalled Enable-LocalSecurityPolicy -Times 1 -Scope It -ModuleName BOSH.Sysprep
+            }
+
             It "skips local policy update if -SkipLGPO is set" {
                 Mock Get-OSVersion { "windows2012R2" } -ModuleName Bosh.Sysprep
 
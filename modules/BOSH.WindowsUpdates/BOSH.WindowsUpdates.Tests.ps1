@@ -211,5 +211,41 @@ Describe "Upgrade-PSVersion" {
     }
 }
 
+Describe "Invoke-WindowsUpdatesTask" {
+    BeforeEach {
+        Mock Write-Log { } -ModuleName BOSH.WindowsUpdates
+        Mock New-ScheduledTaskAction { } -ModuleName BOSH.WindowsUpdates
+        Mock New-ScheduledTaskPrincipal { } -ModuleName BOSH.WindowsUpdates
+        Mock Register-ScheduledTask { } -ModuleName BOSH.WindowsUpdates
+        Mock Start-ScheduledTask { } -ModuleName BOSH.WindowsUpdates
+        Mock Start-Sleep { } -ModuleName BOSH.WindowsUpdates
+        Mock Get-ScheduledTask { @{ State = "Ready" } } -ModuleName BOSH.WindowsUpdates
+        Mock Get-ScheduledTaskInfo { @{ LastTaskResult = 3010 } } -ModuleName BOSH.WindowsUpdates
+        Mock Unregister-ScheduledTask { } -ModuleName BOSH.WindowsUpdates
+    }
+
+    It "runs Install-WindowsUpdates -NoRestart as SYSTEM and returns the exit code of the task" {
+        Invoke-WindowsUpdatesTask | Should Be 3010
+
+        Assert-MockCalled New-ScheduledTaskAction -Times 1 -Scope It -ParameterFilter { $Argument -like "*Install-WindowsUpdates -NoRestart*" } -ModuleName BOSH.WindowsUpdates
+        Assert-MockCalled New-ScheduledTaskPrincipal -Times 1 -Scope It -ParameterFilter { $UserId -eq "SYSTEM" } -ModuleName BOSH.WindowsUpdates
+        Assert-MockCalled Register-ScheduledTask -Times 1 -Scope It -ParameterFilter { ($TaskName -eq "InstallWindowsUpdates") -and ($Trigger -eq $null) } -ModuleName BOSH.WindowsUpdates
+        Assert-MockCalled Start-ScheduledTask -Times 1 -Scope It -ParameterFilter { $TaskName -eq "InstallWindowsUpdates" } -ModuleName BOSH.WindowsUpdates
+        Assert-MockCalled Unregister-ScheduledTask -Times 2 -Scope It -ParameterFilter { $TaskName -eq "InstallWindowsUpdates" } -ModuleName BOSH.WindowsUpdates
+    }
+
+    It "waits for the task to finish" {
+        Mock Start-ScheduledTask { $script:polls = 0 } -ModuleName BOSH.WindowsUpdates
+        Mock Get-ScheduledTask {
+            $script:polls++
+            if ($script:polls -le 2) { @{ State = "Running" } } else { @{ State = "Ready" } }
+        } -ModuleName BOSH.WindowsUpdates
+
+        Invoke-WindowsUpdatesTask | Should Be 3010
+
+        Assert-MockCalled Start-Sleep -Times 3 -Scope It -ModuleName BOSH.WindowsUpdates
+    }
+}
+
 Remove-Module -Name BOSH.WindowsUpdates -ErrorAction Ignore
 Remove-Module -Name BOSH.Utils -ErrorAction Ignore
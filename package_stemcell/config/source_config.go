@@ -9,6 +9,7 @@ type SourceConfig struct {
 	Password        string
 	VmInventoryPath string
 	CaCertFile      string
+	DryRun          bool
 }
 
 type Source int
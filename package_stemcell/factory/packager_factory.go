@@ -11,23 +11,31 @@ import (
 	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients"
 
 	"github.com/cloudfoundry-incubator/stembuild/colorlogger"
+	"github.com/cloudfoundry-incubator/stembuild/events"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/config"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/package_parameters"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/packagers"
 )
 
-type PackagerFactory struct{}
+type PackagerFactory struct {
+	Events events.Sink
+}
 
 func (f *PackagerFactory) Packager(sourceConfig config.SourceConfig, outputConfig config.OutputConfig, logLevel int, color bool) (commandparser.Packager, error) {
 	source, err := sourceConfig.GetSource()
 	if err != nil {
 		return nil, err
 	}
+	var messenger packagers.PackageMessenger = packagers.NewMessenger(os.Stdout, os.Stderr)
+	if f.Events != nil {
+		messenger = packagers.NewJSONMessenger(f.Events)
+	}
+
 	switch source {
 	case config.VCENTER:
 		runner := &iaas_cli.GovcRunner{}
 		client := iaas_clients.NewVcenterClient(sourceConfig.Username, sourceConfig.Password, sourceConfig.URL, sourceConfig.CaCertFile, runner)
-		v := packagers.VCenterPackager{SourceConfig: sourceConfig, OutputConfig: outputConfig, Client: client}
+		v := packagers.VCenterPackager{SourceConfig: sourceConfig, OutputConfig: outputConfig, Client: client, Messenger: messenger}
 		return v, nil
 	case config.VMDK:
 		options := package_parameters.VmdkPackageParameters{}
@@ -36,6 +44,8 @@ func (f *PackagerFactory) Packager(sourceConfig config.SourceConfig, outputConfi
 			Stop:         make(chan struct{}),
 			Debugf:       logger.Debugf,
 			BuildOptions: options,
+			Messenger:    messenger,
+			DryRun:       sourceConfig.DryRun,
 		}
 
 		vmdkPackager.BuildOptions.VMDKFile = sourceConfig.Vmdk
@@ -1,6 +1,7 @@
 package factory_test
 
 import (
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/config"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/factory"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/packagers"
@@ -83,6 +84,20 @@ var _ = Describe("Factory", func() {
 			})
 		})
 
+		Context("When an event sink is set", func() {
+			It("reports packaging progress as events", func() {
+				packagerFactory.Events = &eventsfakes.FakeSink{}
+				sourceConfig := config.SourceConfig{
+					Vmdk: "path/to/a/vmdk",
+				}
+
+				actualPackager, err := packagerFactory.Packager(sourceConfig, outputConfig, 0, false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(actualPackager.(packagers.VmdkPackager).Messenger).To(BeAssignableToTypeOf(&packagers.JSONMessenger{}))
+			})
+		})
+
 		Context("When no configuration has been provided", func() {
 			It("returns an error", func() {
 				sourceConfig := config.SourceConfig{}
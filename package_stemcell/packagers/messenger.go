@@ -0,0 +1,124 @@
+package packagers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
+)
+
+const phaseConvertVMDK = "convert-vmdk"
+const phaseDryRun = "dry-run"
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . PackageMessenger
+type PackageMessenger interface {
+	ConvertVMDKStarted()
+	StemcellCreated(stemcellPath string)
+	// VMDKConversionStarted and VMDKStemcellCreated report the conversion of a VMDK file, which keeps its own text output
+	VMDKConversionStarted()
+	VMDKStemcellCreated(stemcellPath string)
+	InterruptReceived(signal string)
+	SecondInterruptReceived(signal string)
+	ConvertVMDKFailed(err error)
+	DryRunStarted()
+	PlannedOperation(operation string)
+	DryRunSucceeded()
+}
+
+type Messenger struct {
+	out    io.Writer
+	errOut io.Writer
+}
+
+func NewMessenger(out, errOut io.Writer) *Messenger {
+	return &Messenger{out, errOut}
+}
+
+func (m *Messenger) ConvertVMDKStarted() {
+	fmt.Fprintln(m.out, "Converting VMDK into stemcell")
+}
+
+func (m *Messenger) StemcellCreated(stemcellPath string) {
+	fmt.Fprintf(m.out, "Stemcell successfully created: %s\n", stemcellPath)
+}
+
+// VMDKConversionStarted writes nothing, as converting a VMDK file has only ever reported the stemcell it created
+func (m *Messenger) VMDKConversionStarted() {
+}
+
+func (m *Messenger) VMDKStemcellCreated(stemcellPath string) {
+	fmt.Fprintf(m.out, "created stemcell: %s", stemcellPath)
+}
+
+func (m *Messenger) InterruptReceived(signal string) {
+	fmt.Fprintf(m.errOut, "received (%s) signal cleaning up\n", signal)
+}
+
+func (m *Messenger) SecondInterruptReceived(signal string) {
+	fmt.Fprintf(m.errOut, "received second (%s) signal - exiting now\n", signal)
+}
+
+func (m *Messenger) ConvertVMDKFailed(err error) {
+	fmt.Fprintf(m.errOut, "Error: %s\n", err)
+}
+
+func (m *Messenger) DryRunStarted() {
+	fmt.Fprintln(m.out, "Dry run: no changes will be made. Package would, in order:")
+}
+
+func (m *Messenger) PlannedOperation(operation string) {
+	fmt.Fprintf(m.out, "  %s\n", operation)
+}
+
+func (m *Messenger) DryRunSucceeded() {
+	fmt.Fprintln(m.out, "Dry run complete, no changes were made.")
+}
+
+// JSONMessenger reports packaging progress as events instead of human readable text.
+type JSONMessenger struct {
+	sink events.Sink
+}
+
+func NewJSONMessenger(sink events.Sink) *JSONMessenger {
+	return &JSONMessenger{sink}
+}
+
+func (m *JSONMessenger) ConvertVMDKStarted() {
+	m.sink.Emit(events.Event{Command: "package", Phase: phaseConvertVMDK, Event: "ConvertVMDKStarted", Status: events.Started})
+}
+
+func (m *JSONMessenger) StemcellCreated(stemcellPath string) {
+	m.sink.Emit(events.Event{Command: "package", Phase: phaseConvertVMDK, Event: "StemcellCreated", Status: events.Succeeded, Target: stemcellPath})
+}
+
+func (m *JSONMessenger) VMDKConversionStarted() {
+	m.ConvertVMDKStarted()
+}
+
+func (m *JSONMessenger) VMDKStemcellCreated(stemcellPath string) {
+	m.StemcellCreated(stemcellPath)
+}
+
+func (m *JSONMessenger) InterruptReceived(signal string) {
+	m.sink.Emit(events.Event{Command: "package", Event: "InterruptReceived", Status: events.Warning, Message: signal})
+}
+
+func (m *JSONMessenger) SecondInterruptReceived(signal string) {
+	m.sink.Emit(events.Event{Command: "package", Event: "SecondInterruptReceived", Status: events.Warning, Message: signal})
+}
+
+func (m *JSONMessenger) ConvertVMDKFailed(err error) {
+	m.sink.Emit(events.Event{Command: "package", Phase: phaseConvertVMDK, Event: "ConvertVMDKFailed", Status: events.Failed, Error: err.Error()})
+}
+
+func (m *JSONMessenger) DryRunStarted() {
+	m.sink.Emit(events.Event{Command: "package", Phase: phaseDryRun, Event: "DryRunStarted", Status: events.Started})
+}
+
+func (m *JSONMessenger) PlannedOperation(operation string) {
+	m.sink.Emit(events.Event{Command: "package", Phase: phaseDryRun, Event: "PlannedOperation", Status: events.Info, Message: operation})
+}
+
+func (m *JSONMessenger) DryRunSucceeded() {
+	m.sink.Emit(events.Event{Command: "package", Phase: phaseDryRun, Event: "DryRunSucceeded", Status: events.Succeeded})
+}
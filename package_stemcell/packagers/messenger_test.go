@@ -0,0 +1,113 @@
+package packagers_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
+	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/packagers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Messenger", func() {
+	var (
+		out    *gbytes.Buffer
+		errOut *gbytes.Buffer
+		m      *packagers.Messenger
+	)
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		errOut = gbytes.NewBuffer()
+		m = packagers.NewMessenger(out, errOut)
+	})
+
+	It("writes progress to the output writer", func() {
+		m.ConvertVMDKStarted()
+		m.StemcellCreated("/output/bosh-stemcell.tgz")
+
+		Expect(out).To(gbytes.Say("Converting VMDK into stemcell\n"))
+		Expect(out).To(gbytes.Say("Stemcell successfully created: /output/bosh-stemcell.tgz\n"))
+	})
+
+	It("reports only the stemcell created from a VMDK file", func() {
+		m.VMDKConversionStarted()
+		m.VMDKStemcellCreated("/output/bosh-stemcell.tgz")
+
+		Expect(string(out.Contents())).To(Equal("created stemcell: /output/bosh-stemcell.tgz"))
+	})
+
+	It("writes interrupts and failures to the error writer", func() {
+		m.InterruptReceived("interrupt")
+		m.SecondInterruptReceived("interrupt")
+		m.ConvertVMDKFailed(errors.New("ovftool failed"))
+
+		Expect(errOut).To(gbytes.Say(`received \(interrupt\) signal cleaning up\n`))
+		Expect(errOut).To(gbytes.Say(`received second \(interrupt\) signal - exiting now\n`))
+		Expect(errOut).To(gbytes.Say("Error: ovftool failed\n"))
+		Expect(out.Contents()).To(BeEmpty())
+	})
+
+	It("writes the planned changes of a dry run to the output writer", func() {
+		m.DryRunStarted()
+		m.PlannedOperation("eject cdrom-3000 from /dc/vm/stemcell")
+		m.DryRunSucceeded()
+
+		Expect(out).To(gbytes.Say("Dry run: no changes will be made. Package would, in order:\n"))
+		Expect(out).To(gbytes.Say("  eject cdrom-3000 from /dc/vm/stemcell\n"))
+		Expect(out).To(gbytes.Say("Dry run complete, no changes were made.\n"))
+	})
+})
+
+var _ = Describe("JSONMessenger", func() {
+	var (
+		sink *eventsfakes.FakeSink
+		m    *packagers.JSONMessenger
+	)
+
+	BeforeEach(func() {
+		sink = &eventsfakes.FakeSink{}
+		m = packagers.NewJSONMessenger(sink)
+	})
+
+	It("emits the conversion as a convert-vmdk phase", func() {
+		m.ConvertVMDKStarted()
+		m.StemcellCreated("/output/bosh-stemcell.tgz")
+
+		started := sink.EmitArgsForCall(0)
+		Expect(started).To(Equal(events.Event{Command: "package", Phase: "convert-vmdk", Event: "ConvertVMDKStarted", Status: events.Started}))
+
+		created := sink.EmitArgsForCall(1)
+		Expect(created.Status).To(Equal(events.Succeeded))
+		Expect(created.Target).To(Equal("/output/bosh-stemcell.tgz"))
+	})
+
+	It("emits the conversion of a VMDK file as a convert-vmdk phase", func() {
+		m.VMDKConversionStarted()
+		m.VMDKStemcellCreated("/output/bosh-stemcell.tgz")
+
+		Expect(sink.EmitArgsForCall(0).Event).To(Equal("ConvertVMDKStarted"))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "package", Phase: "convert-vmdk", Event: "StemcellCreated", Status: events.Succeeded, Target: "/output/bosh-stemcell.tgz"}))
+	})
+
+	It("emits a failed event with the error", func() {
+		m.ConvertVMDKFailed(errors.New("ovftool failed"))
+
+		failed := sink.EmitArgsForCall(0)
+		Expect(failed.Phase).To(Equal("convert-vmdk"))
+		Expect(failed.Status).To(Equal(events.Failed))
+		Expect(failed.Error).To(Equal("ovftool failed"))
+	})
+
+	It("emits planned changes as info events of the dry-run phase", func() {
+		m.DryRunStarted()
+		m.PlannedOperation("eject cdrom-3000 from /dc/vm/stemcell")
+		m.DryRunSucceeded()
+
+		Expect(sink.EmitArgsForCall(0)).To(Equal(events.Event{Command: "package", Phase: "dry-run", Event: "DryRunStarted", Status: events.Started}))
+		Expect(sink.EmitArgsForCall(1)).To(Equal(events.Event{Command: "package", Phase: "dry-run", Event: "PlannedOperation", Status: events.Info, Message: "eject cdrom-3000 from /dc/vm/stemcell"}))
+		Expect(sink.EmitArgsForCall(2)).To(Equal(events.Event{Command: "package", Phase: "dry-run", Event: "DryRunSucceeded", Status: events.Succeeded}))
+	})
+})
@@ -28,7 +28,9 @@ func WriteManifest(manifestContents, manifestPath string) error {
 	return nil
 }
 
-func CreateManifest(osVersion, version, sha1sum string) string {
+// CreateManifest gives the contents of stemcell.MF. The hardening profile construct applied to the VM is only
+// included when it is known.
+func CreateManifest(osVersion, version, sha1sum, hardeningProfile string) string {
 	const format = `---
 name: bosh-vsphere-esxi-windows%[1]s-go_agent
 version: '%[2]s'
@@ -42,7 +44,11 @@ stemcell_formats:
 - vsphere-ovf
 - vsphere-ova
 `
-	return fmt.Sprintf(format, osVersion, version, sha1sum)
+	manifest := fmt.Sprintf(format, osVersion, version, sha1sum)
+	if hardeningProfile != "" {
+		manifest += fmt.Sprintf("hardening_profile: %s\n", hardeningProfile)
+	}
+	return manifest
 
 }
 
@@ -95,9 +95,14 @@ stemcell_formats:
 - vsphere-ovf
 - vsphere-ova
 `
-			result := CreateManifest("1", "version", "sha1sum")
+			result := CreateManifest("1", "version", "sha1sum", "")
 			Expect(result).To(Equal(expectedManifest))
 		})
+
+		It("records the hardening profile when it is known", func() {
+			result := CreateManifest("1", "version", "sha1sum", "minimal")
+			Expect(result).To(HaveSuffix("- vsphere-ova\nhardening_profile: minimal\n"))
+		})
 	})
 
 	Context("StemcellFileName", func() {
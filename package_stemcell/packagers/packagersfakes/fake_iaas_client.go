@@ -43,6 +43,19 @@ type FakeIaasClient struct {
 	findVMReturnsOnCall map[int]struct {
 		result1 error
 	}
+	HardeningProfileStub        func(string) (string, error)
+	hardeningProfileMutex       sync.RWMutex
+	hardeningProfileArgsForCall []struct {
+		arg1 string
+	}
+	hardeningProfileReturns struct {
+		result1 string
+		result2 error
+	}
+	hardeningProfileReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	ListDevicesStub        func(string) ([]string, error)
 	listDevicesMutex       sync.RWMutex
 	listDevicesArgsForCall []struct {
@@ -99,15 +112,16 @@ func (fake *FakeIaasClient) EjectCDRom(arg1 string, arg2 string) error {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.EjectCDRomStub
+	fakeReturns := fake.ejectCDRomReturns
 	fake.recordInvocation("EjectCDRom", []interface{}{arg1, arg2})
 	fake.ejectCDRomMutex.Unlock()
-	if fake.EjectCDRomStub != nil {
-		return fake.EjectCDRomStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.ejectCDRomReturns
 	return fakeReturns.result1
 }
 
@@ -160,15 +174,16 @@ func (fake *FakeIaasClient) ExportVM(arg1 string, arg2 string) error {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.ExportVMStub
+	fakeReturns := fake.exportVMReturns
 	fake.recordInvocation("ExportVM", []interface{}{arg1, arg2})
 	fake.exportVMMutex.Unlock()
-	if fake.ExportVMStub != nil {
-		return fake.ExportVMStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.exportVMReturns
 	return fakeReturns.result1
 }
 
@@ -220,15 +235,16 @@ func (fake *FakeIaasClient) FindVM(arg1 string) error {
 	fake.findVMArgsForCall = append(fake.findVMArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.FindVMStub
+	fakeReturns := fake.findVMReturns
 	fake.recordInvocation("FindVM", []interface{}{arg1})
 	fake.findVMMutex.Unlock()
-	if fake.FindVMStub != nil {
-		return fake.FindVMStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.findVMReturns
 	return fakeReturns.result1
 }
 
@@ -274,21 +290,86 @@ func (fake *FakeIaasClient) FindVMReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeIaasClient) HardeningProfile(arg1 string) (string, error) {
+	fake.hardeningProfileMutex.Lock()
+	ret, specificReturn := fake.hardeningProfileReturnsOnCall[len(fake.hardeningProfileArgsForCall)]
+	fake.hardeningProfileArgsForCall = append(fake.hardeningProfileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.HardeningProfileStub
+	fakeReturns := fake.hardeningProfileReturns
+	fake.recordInvocation("HardeningProfile", []interface{}{arg1})
+	fake.hardeningProfileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeIaasClient) HardeningProfileCallCount() int {
+	fake.hardeningProfileMutex.RLock()
+	defer fake.hardeningProfileMutex.RUnlock()
+	return len(fake.hardeningProfileArgsForCall)
+}
+
+func (fake *FakeIaasClient) HardeningProfileCalls(stub func(string) (string, error)) {
+	fake.hardeningProfileMutex.Lock()
+	defer fake.hardeningProfileMutex.Unlock()
+	fake.HardeningProfileStub = stub
+}
+
+func (fake *FakeIaasClient) HardeningProfileArgsForCall(i int) string {
+	fake.hardeningProfileMutex.RLock()
+	defer fake.hardeningProfileMutex.RUnlock()
+	argsForCall := fake.hardeningProfileArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeIaasClient) HardeningProfileReturns(result1 string, result2 error) {
+	fake.hardeningProfileMutex.Lock()
+	defer fake.hardeningProfileMutex.Unlock()
+	fake.HardeningProfileStub = nil
+	fake.hardeningProfileReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeIaasClient) HardeningProfileReturnsOnCall(i int, result1 string, result2 error) {
+	fake.hardeningProfileMutex.Lock()
+	defer fake.hardeningProfileMutex.Unlock()
+	fake.HardeningProfileStub = nil
+	if fake.hardeningProfileReturnsOnCall == nil {
+		fake.hardeningProfileReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.hardeningProfileReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeIaasClient) ListDevices(arg1 string) ([]string, error) {
 	fake.listDevicesMutex.Lock()
 	ret, specificReturn := fake.listDevicesReturnsOnCall[len(fake.listDevicesArgsForCall)]
 	fake.listDevicesArgsForCall = append(fake.listDevicesArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.ListDevicesStub
+	fakeReturns := fake.listDevicesReturns
 	fake.recordInvocation("ListDevices", []interface{}{arg1})
 	fake.listDevicesMutex.Unlock()
-	if fake.ListDevicesStub != nil {
-		return fake.ListDevicesStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.listDevicesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -344,15 +425,16 @@ func (fake *FakeIaasClient) RemoveDevice(arg1 string, arg2 string) error {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.RemoveDeviceStub
+	fakeReturns := fake.removeDeviceReturns
 	fake.recordInvocation("RemoveDevice", []interface{}{arg1, arg2})
 	fake.removeDeviceMutex.Unlock()
-	if fake.RemoveDeviceStub != nil {
-		return fake.RemoveDeviceStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.removeDeviceReturns
 	return fakeReturns.result1
 }
 
@@ -403,15 +485,16 @@ func (fake *FakeIaasClient) ValidateCredentials() error {
 	ret, specificReturn := fake.validateCredentialsReturnsOnCall[len(fake.validateCredentialsArgsForCall)]
 	fake.validateCredentialsArgsForCall = append(fake.validateCredentialsArgsForCall, struct {
 	}{})
+	stub := fake.ValidateCredentialsStub
+	fakeReturns := fake.validateCredentialsReturns
 	fake.recordInvocation("ValidateCredentials", []interface{}{})
 	fake.validateCredentialsMutex.Unlock()
-	if fake.ValidateCredentialsStub != nil {
-		return fake.ValidateCredentialsStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.validateCredentialsReturns
 	return fakeReturns.result1
 }
 
@@ -455,15 +538,16 @@ func (fake *FakeIaasClient) ValidateUrl() error {
 	ret, specificReturn := fake.validateUrlReturnsOnCall[len(fake.validateUrlArgsForCall)]
 	fake.validateUrlArgsForCall = append(fake.validateUrlArgsForCall, struct {
 	}{})
+	stub := fake.ValidateUrlStub
+	fakeReturns := fake.validateUrlReturns
 	fake.recordInvocation("ValidateUrl", []interface{}{})
 	fake.validateUrlMutex.Unlock()
-	if fake.ValidateUrlStub != nil {
-		return fake.ValidateUrlStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.validateUrlReturns
 	return fakeReturns.result1
 }
 
@@ -511,6 +595,8 @@ func (fake *FakeIaasClient) Invocations() map[string][][]interface{} {
 	defer fake.exportVMMutex.RUnlock()
 	fake.findVMMutex.RLock()
 	defer fake.findVMMutex.RUnlock()
+	fake.hardeningProfileMutex.RLock()
+	defer fake.hardeningProfileMutex.RUnlock()
 	fake.listDevicesMutex.RLock()
 	defer fake.listDevicesMutex.RUnlock()
 	fake.removeDeviceMutex.RLock()
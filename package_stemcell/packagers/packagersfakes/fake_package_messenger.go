@@ -0,0 +1,391 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package packagersfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/packagers"
+)
+
+type FakePackageMessenger struct {
+	ConvertVMDKFailedStub        func(error)
+	convertVMDKFailedMutex       sync.RWMutex
+	convertVMDKFailedArgsForCall []struct {
+		arg1 error
+	}
+	ConvertVMDKStartedStub        func()
+	convertVMDKStartedMutex       sync.RWMutex
+	convertVMDKStartedArgsForCall []struct {
+	}
+	DryRunStartedStub        func()
+	dryRunStartedMutex       sync.RWMutex
+	dryRunStartedArgsForCall []struct {
+	}
+	DryRunSucceededStub        func()
+	dryRunSucceededMutex       sync.RWMutex
+	dryRunSucceededArgsForCall []struct {
+	}
+	InterruptReceivedStub        func(string)
+	interruptReceivedMutex       sync.RWMutex
+	interruptReceivedArgsForCall []struct {
+		arg1 string
+	}
+	PlannedOperationStub        func(string)
+	plannedOperationMutex       sync.RWMutex
+	plannedOperationArgsForCall []struct {
+		arg1 string
+	}
+	SecondInterruptReceivedStub        func(string)
+	secondInterruptReceivedMutex       sync.RWMutex
+	secondInterruptReceivedArgsForCall []struct {
+		arg1 string
+	}
+	StemcellCreatedStub        func(string)
+	stemcellCreatedMutex       sync.RWMutex
+	stemcellCreatedArgsForCall []struct {
+		arg1 string
+	}
+	VMDKConversionStartedStub        func()
+	vMDKConversionStartedMutex       sync.RWMutex
+	vMDKConversionStartedArgsForCall []struct {
+	}
+	VMDKStemcellCreatedStub        func(string)
+	vMDKStemcellCreatedMutex       sync.RWMutex
+	vMDKStemcellCreatedArgsForCall []struct {
+		arg1 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePackageMessenger) ConvertVMDKFailed(arg1 error) {
+	fake.convertVMDKFailedMutex.Lock()
+	fake.convertVMDKFailedArgsForCall = append(fake.convertVMDKFailedArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.ConvertVMDKFailedStub
+	fake.recordInvocation("ConvertVMDKFailed", []interface{}{arg1})
+	fake.convertVMDKFailedMutex.Unlock()
+	if stub != nil {
+		fake.ConvertVMDKFailedStub(arg1)
+	}
+}
+
+func (fake *FakePackageMessenger) ConvertVMDKFailedCallCount() int {
+	fake.convertVMDKFailedMutex.RLock()
+	defer fake.convertVMDKFailedMutex.RUnlock()
+	return len(fake.convertVMDKFailedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) ConvertVMDKFailedCalls(stub func(error)) {
+	fake.convertVMDKFailedMutex.Lock()
+	defer fake.convertVMDKFailedMutex.Unlock()
+	fake.ConvertVMDKFailedStub = stub
+}
+
+func (fake *FakePackageMessenger) ConvertVMDKFailedArgsForCall(i int) error {
+	fake.convertVMDKFailedMutex.RLock()
+	defer fake.convertVMDKFailedMutex.RUnlock()
+	argsForCall := fake.convertVMDKFailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePackageMessenger) ConvertVMDKStarted() {
+	fake.convertVMDKStartedMutex.Lock()
+	fake.convertVMDKStartedArgsForCall = append(fake.convertVMDKStartedArgsForCall, struct {
+	}{})
+	stub := fake.ConvertVMDKStartedStub
+	fake.recordInvocation("ConvertVMDKStarted", []interface{}{})
+	fake.convertVMDKStartedMutex.Unlock()
+	if stub != nil {
+		fake.ConvertVMDKStartedStub()
+	}
+}
+
+func (fake *FakePackageMessenger) ConvertVMDKStartedCallCount() int {
+	fake.convertVMDKStartedMutex.RLock()
+	defer fake.convertVMDKStartedMutex.RUnlock()
+	return len(fake.convertVMDKStartedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) ConvertVMDKStartedCalls(stub func()) {
+	fake.convertVMDKStartedMutex.Lock()
+	defer fake.convertVMDKStartedMutex.Unlock()
+	fake.ConvertVMDKStartedStub = stub
+}
+
+func (fake *FakePackageMessenger) DryRunStarted() {
+	fake.dryRunStartedMutex.Lock()
+	fake.dryRunStartedArgsForCall = append(fake.dryRunStartedArgsForCall, struct {
+	}{})
+	stub := fake.DryRunStartedStub
+	fake.recordInvocation("DryRunStarted", []interface{}{})
+	fake.dryRunStartedMutex.Unlock()
+	if stub != nil {
+		fake.DryRunStartedStub()
+	}
+}
+
+func (fake *FakePackageMessenger) DryRunStartedCallCount() int {
+	fake.dryRunStartedMutex.RLock()
+	defer fake.dryRunStartedMutex.RUnlock()
+	return len(fake.dryRunStartedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) DryRunStartedCalls(stub func()) {
+	fake.dryRunStartedMutex.Lock()
+	defer fake.dryRunStartedMutex.Unlock()
+	fake.DryRunStartedStub = stub
+}
+
+func (fake *FakePackageMessenger) DryRunSucceeded() {
+	fake.dryRunSucceededMutex.Lock()
+	fake.dryRunSucceededArgsForCall = append(fake.dryRunSucceededArgsForCall, struct {
+	}{})
+	stub := fake.DryRunSucceededStub
+	fake.recordInvocation("DryRunSucceeded", []interface{}{})
+	fake.dryRunSucceededMutex.Unlock()
+	if stub != nil {
+		fake.DryRunSucceededStub()
+	}
+}
+
+func (fake *FakePackageMessenger) DryRunSucceededCallCount() int {
+	fake.dryRunSucceededMutex.RLock()
+	defer fake.dryRunSucceededMutex.RUnlock()
+	return len(fake.dryRunSucceededArgsForCall)
+}
+
+func (fake *FakePackageMessenger) DryRunSucceededCalls(stub func()) {
+	fake.dryRunSucceededMutex.Lock()
+	defer fake.dryRunSucceededMutex.Unlock()
+	fake.DryRunSucceededStub = stub
+}
+
+func (fake *FakePackageMessenger) InterruptReceived(arg1 string) {
+	fake.interruptReceivedMutex.Lock()
+	fake.interruptReceivedArgsForCall = append(fake.interruptReceivedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.InterruptReceivedStub
+	fake.recordInvocation("InterruptReceived", []interface{}{arg1})
+	fake.interruptReceivedMutex.Unlock()
+	if stub != nil {
+		fake.InterruptReceivedStub(arg1)
+	}
+}
+
+func (fake *FakePackageMessenger) InterruptReceivedCallCount() int {
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	return len(fake.interruptReceivedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) InterruptReceivedCalls(stub func(string)) {
+	fake.interruptReceivedMutex.Lock()
+	defer fake.interruptReceivedMutex.Unlock()
+	fake.InterruptReceivedStub = stub
+}
+
+func (fake *FakePackageMessenger) InterruptReceivedArgsForCall(i int) string {
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	argsForCall := fake.interruptReceivedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePackageMessenger) PlannedOperation(arg1 string) {
+	fake.plannedOperationMutex.Lock()
+	fake.plannedOperationArgsForCall = append(fake.plannedOperationArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.PlannedOperationStub
+	fake.recordInvocation("PlannedOperation", []interface{}{arg1})
+	fake.plannedOperationMutex.Unlock()
+	if stub != nil {
+		fake.PlannedOperationStub(arg1)
+	}
+}
+
+func (fake *FakePackageMessenger) PlannedOperationCallCount() int {
+	fake.plannedOperationMutex.RLock()
+	defer fake.plannedOperationMutex.RUnlock()
+	return len(fake.plannedOperationArgsForCall)
+}
+
+func (fake *FakePackageMessenger) PlannedOperationCalls(stub func(string)) {
+	fake.plannedOperationMutex.Lock()
+	defer fake.plannedOperationMutex.Unlock()
+	fake.PlannedOperationStub = stub
+}
+
+func (fake *FakePackageMessenger) PlannedOperationArgsForCall(i int) string {
+	fake.plannedOperationMutex.RLock()
+	defer fake.plannedOperationMutex.RUnlock()
+	argsForCall := fake.plannedOperationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePackageMessenger) SecondInterruptReceived(arg1 string) {
+	fake.secondInterruptReceivedMutex.Lock()
+	fake.secondInterruptReceivedArgsForCall = append(fake.secondInterruptReceivedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SecondInterruptReceivedStub
+	fake.recordInvocation("SecondInterruptReceived", []interface{}{arg1})
+	fake.secondInterruptReceivedMutex.Unlock()
+	if stub != nil {
+		fake.SecondInterruptReceivedStub(arg1)
+	}
+}
+
+func (fake *FakePackageMessenger) SecondInterruptReceivedCallCount() int {
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	return len(fake.secondInterruptReceivedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) SecondInterruptReceivedCalls(stub func(string)) {
+	fake.secondInterruptReceivedMutex.Lock()
+	defer fake.secondInterruptReceivedMutex.Unlock()
+	fake.SecondInterruptReceivedStub = stub
+}
+
+func (fake *FakePackageMessenger) SecondInterruptReceivedArgsForCall(i int) string {
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	argsForCall := fake.secondInterruptReceivedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePackageMessenger) StemcellCreated(arg1 string) {
+	fake.stemcellCreatedMutex.Lock()
+	fake.stemcellCreatedArgsForCall = append(fake.stemcellCreatedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.StemcellCreatedStub
+	fake.recordInvocation("StemcellCreated", []interface{}{arg1})
+	fake.stemcellCreatedMutex.Unlock()
+	if stub != nil {
+		fake.StemcellCreatedStub(arg1)
+	}
+}
+
+func (fake *FakePackageMessenger) StemcellCreatedCallCount() int {
+	fake.stemcellCreatedMutex.RLock()
+	defer fake.stemcellCreatedMutex.RUnlock()
+	return len(fake.stemcellCreatedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) StemcellCreatedCalls(stub func(string)) {
+	fake.stemcellCreatedMutex.Lock()
+	defer fake.stemcellCreatedMutex.Unlock()
+	fake.StemcellCreatedStub = stub
+}
+
+func (fake *FakePackageMessenger) StemcellCreatedArgsForCall(i int) string {
+	fake.stemcellCreatedMutex.RLock()
+	defer fake.stemcellCreatedMutex.RUnlock()
+	argsForCall := fake.stemcellCreatedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePackageMessenger) VMDKConversionStarted() {
+	fake.vMDKConversionStartedMutex.Lock()
+	fake.vMDKConversionStartedArgsForCall = append(fake.vMDKConversionStartedArgsForCall, struct {
+	}{})
+	stub := fake.VMDKConversionStartedStub
+	fake.recordInvocation("VMDKConversionStarted", []interface{}{})
+	fake.vMDKConversionStartedMutex.Unlock()
+	if stub != nil {
+		fake.VMDKConversionStartedStub()
+	}
+}
+
+func (fake *FakePackageMessenger) VMDKConversionStartedCallCount() int {
+	fake.vMDKConversionStartedMutex.RLock()
+	defer fake.vMDKConversionStartedMutex.RUnlock()
+	return len(fake.vMDKConversionStartedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) VMDKConversionStartedCalls(stub func()) {
+	fake.vMDKConversionStartedMutex.Lock()
+	defer fake.vMDKConversionStartedMutex.Unlock()
+	fake.VMDKConversionStartedStub = stub
+}
+
+func (fake *FakePackageMessenger) VMDKStemcellCreated(arg1 string) {
+	fake.vMDKStemcellCreatedMutex.Lock()
+	fake.vMDKStemcellCreatedArgsForCall = append(fake.vMDKStemcellCreatedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.VMDKStemcellCreatedStub
+	fake.recordInvocation("VMDKStemcellCreated", []interface{}{arg1})
+	fake.vMDKStemcellCreatedMutex.Unlock()
+	if stub != nil {
+		fake.VMDKStemcellCreatedStub(arg1)
+	}
+}
+
+func (fake *FakePackageMessenger) VMDKStemcellCreatedCallCount() int {
+	fake.vMDKStemcellCreatedMutex.RLock()
+	defer fake.vMDKStemcellCreatedMutex.RUnlock()
+	return len(fake.vMDKStemcellCreatedArgsForCall)
+}
+
+func (fake *FakePackageMessenger) VMDKStemcellCreatedCalls(stub func(string)) {
+	fake.vMDKStemcellCreatedMutex.Lock()
+	defer fake.vMDKStemcellCreatedMutex.Unlock()
+	fake.VMDKStemcellCreatedStub = stub
+}
+
+func (fake *FakePackageMessenger) VMDKStemcellCreatedArgsForCall(i int) string {
+	fake.vMDKStemcellCreatedMutex.RLock()
+	defer fake.vMDKStemcellCreatedMutex.RUnlock()
+	argsForCall := fake.vMDKStemcellCreatedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePackageMessenger) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.convertVMDKFailedMutex.RLock()
+	defer fake.convertVMDKFailedMutex.RUnlock()
+	fake.convertVMDKStartedMutex.RLock()
+	defer fake.convertVMDKStartedMutex.RUnlock()
+	fake.dryRunStartedMutex.RLock()
+	defer fake.dryRunStartedMutex.RUnlock()
+	fake.dryRunSucceededMutex.RLock()
+	defer fake.dryRunSucceededMutex.RUnlock()
+	fake.interruptReceivedMutex.RLock()
+	defer fake.interruptReceivedMutex.RUnlock()
+	fake.plannedOperationMutex.RLock()
+	defer fake.plannedOperationMutex.RUnlock()
+	fake.secondInterruptReceivedMutex.RLock()
+	defer fake.secondInterruptReceivedMutex.RUnlock()
+	fake.stemcellCreatedMutex.RLock()
+	defer fake.stemcellCreatedMutex.RUnlock()
+	fake.vMDKConversionStartedMutex.RLock()
+	defer fake.vMDKConversionStartedMutex.RUnlock()
+	fake.vMDKStemcellCreatedMutex.RLock()
+	defer fake.vMDKStemcellCreatedMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePackageMessenger) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ packagers.PackageMessenger = new(FakePackageMessenger)
@@ -23,20 +23,34 @@ type IaasClient interface {
 	ListDevices(vmInventoryPath string) ([]string, error)
 	RemoveDevice(vmInventoryPath string, deviceName string) error
 	EjectCDRom(vmInventoryPath string, deviceName string) error
+	HardeningProfile(vmInventoryPath string) (string, error)
 }
 
 type VCenterPackager struct {
 	SourceConfig config.SourceConfig
 	OutputConfig config.OutputConfig
 	Client       IaasClient
+	Messenger    PackageMessenger
 }
 
+const removedDevicePattern = "^(floppy-|ethernet-)"
+const ejectedDevicePattern = "^(cdrom-)"
+
 func (v VCenterPackager) Package() error {
-	err := v.executeOnMatchingDevice(v.Client.RemoveDevice, "^(floppy-|ethernet-)")
+	if v.SourceConfig.DryRun {
+		return v.dryRun()
+	}
+
+	err := v.executeOnMatchingDevice(v.Client.RemoveDevice, removedDevicePattern)
 	if err != nil {
 		return err
 	}
-	err = v.executeOnMatchingDevice(v.Client.EjectCDRom, "^(cdrom-)")
+	err = v.executeOnMatchingDevice(v.Client.EjectCDRom, ejectedDevicePattern)
+	if err != nil {
+		return err
+	}
+
+	hardeningProfile, err := v.Client.HardeningProfile(v.SourceConfig.VmInventoryPath)
 	if err != nil {
 		return err
 	}
@@ -57,10 +71,10 @@ func (v VCenterPackager) Package() error {
 		return errors.New("failed to export the prepared VM")
 	}
 
-	fmt.Println("Converting VMDK into stemcell")
+	v.Messenger.ConvertVMDKStarted()
 	vmName := path.Base(v.SourceConfig.VmInventoryPath)
 	shaSum, err := TarGenerator(filepath.Join(stemcellDir, "image"), filepath.Join(workingDir, vmName))
-	manifestContents := CreateManifest(v.OutputConfig.Os, v.OutputConfig.StemcellVersion, shaSum)
+	manifestContents := CreateManifest(v.OutputConfig.Os, v.OutputConfig.StemcellVersion, shaSum, hardeningProfile)
 	err = WriteManifest(manifestContents, stemcellDir)
 
 	if err != nil {
@@ -70,7 +84,7 @@ func (v VCenterPackager) Package() error {
 	stemcellFilename := StemcellFilename(v.OutputConfig.StemcellVersion, v.OutputConfig.Os)
 	_, err = TarGenerator(filepath.Join(v.OutputConfig.OutputDir, stemcellFilename), stemcellDir)
 
-	fmt.Printf("Stemcell successfully created: %s\n", stemcellFilename)
+	v.Messenger.StemcellCreated(filepath.Join(v.OutputConfig.OutputDir, stemcellFilename))
 	return nil
 }
 
@@ -92,6 +106,31 @@ func (v VCenterPackager) executeOnMatchingDevice(action func(a, b string) error,
 	return nil
 }
 
+// dryRun reports the changes Package would make, in the order it makes them, without making any
+func (v VCenterPackager) dryRun() error {
+	deviceList, err := v.Client.ListDevices(v.SourceConfig.VmInventoryPath)
+	if err != nil {
+		return err
+	}
+
+	v.Messenger.DryRunStarted()
+	for _, deviceName := range deviceList {
+		if matched, _ := regexp.MatchString(removedDevicePattern, deviceName); matched {
+			v.Messenger.PlannedOperation(fmt.Sprintf("remove device %s from %s", deviceName, v.SourceConfig.VmInventoryPath))
+		}
+	}
+	for _, deviceName := range deviceList {
+		if matched, _ := regexp.MatchString(ejectedDevicePattern, deviceName); matched {
+			v.Messenger.PlannedOperation(fmt.Sprintf("eject %s from %s", deviceName, v.SourceConfig.VmInventoryPath))
+		}
+	}
+	v.Messenger.PlannedOperation(fmt.Sprintf("export %s to a temporary directory", v.SourceConfig.VmInventoryPath))
+	stemcellFilename := StemcellFilename(v.OutputConfig.StemcellVersion, v.OutputConfig.Os)
+	v.Messenger.PlannedOperation("create stemcell " + filepath.Join(v.OutputConfig.OutputDir, stemcellFilename))
+	v.Messenger.DryRunSucceeded()
+	return nil
+}
+
 func (v VCenterPackager) ValidateFreeSpaceForPackage(fs filesystem.FileSystem) error {
 	return nil
 }
@@ -93,6 +93,7 @@ var _ = Describe("VcenterPackager", func() {
 
 	Describe("Package", func() {
 		var packager *VCenterPackager
+		var fakeMessenger *packagersfakes.FakePackageMessenger
 
 		AfterEach(func() {
 			_ = os.RemoveAll("./valid-vm-name")
@@ -100,7 +101,8 @@ var _ = Describe("VcenterPackager", func() {
 		})
 
 		BeforeEach(func() {
-			packager = &VCenterPackager{SourceConfig: sourceConfig, OutputConfig: outputConfig, Client: fakeVcenterClient}
+			fakeMessenger = &packagersfakes.FakePackageMessenger{}
+			packager = &VCenterPackager{SourceConfig: sourceConfig, OutputConfig: outputConfig, Client: fakeVcenterClient, Messenger: fakeMessenger}
 
 			fakeVcenterClient.ExportVMStub = func(vmInventoryPath string, destination string) error {
 				vmName := path.Base(vmInventoryPath)
@@ -113,6 +115,15 @@ var _ = Describe("VcenterPackager", func() {
 			}
 		})
 
+		It("reports the conversion and the created stemcell through the messenger", func() {
+			err := packager.Package()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeMessenger.ConvertVMDKStartedCallCount()).To(Equal(1))
+			stemcellFilename := StemcellFilename(packager.OutputConfig.StemcellVersion, packager.OutputConfig.Os)
+			Expect(fakeMessenger.StemcellCreatedArgsForCall(0)).To(Equal(filepath.Join(outputDir, stemcellFilename)))
+		})
+
 		It("creates a valid stemcell in the output directory", func() {
 			err := packager.Package()
 
@@ -176,6 +187,41 @@ stemcell_formats:
 			Expect(actualStemcellManifestContent).To(Equal(expectedManifestContent))
 		})
 
+		It("records the hardening profile construct applied to the VM in the stemcell manifest", func() {
+			fakeVcenterClient.HardeningProfileReturns("custom", nil)
+
+			err := packager.Package()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeVcenterClient.HardeningProfileArgsForCall(0)).To(Equal("path/valid-vm-name"))
+			stemcellFilename := StemcellFilename(packager.OutputConfig.StemcellVersion, packager.OutputConfig.Os)
+			stemcellFile, err := os.Open(filepath.Join(outputDir, stemcellFilename))
+			Expect(err).NotTo(HaveOccurred())
+			defer stemcellFile.Close()
+			gzr, err := gzip.NewReader(stemcellFile)
+			Expect(err).NotTo(HaveOccurred())
+			tarfileReader := tar.NewReader(gzr)
+			for {
+				header, err := tarfileReader.Next()
+				Expect(err).NotTo(HaveOccurred())
+				if filepath.Base(header.Name) == "stemcell.MF" {
+					manifest, err := ioutil.ReadAll(tarfileReader)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(manifest)).To(HaveSuffix("hardening_profile: custom\n"))
+					break
+				}
+			}
+		})
+
+		It("returns an error if the hardening profile of the VM cannot be read", func() {
+			fakeVcenterClient.HardeningProfileReturns("", errors.New("vcenter_client - failed to get vm info, govc exit code: 1"))
+
+			err := packager.Package()
+
+			Expect(err).To(MatchError("vcenter_client - failed to get vm info, govc exit code: 1"))
+			Expect(fakeVcenterClient.ExportVMCallCount()).To(Equal(0))
+		})
+
 		It("removes all ethernet and floppy devices", func() {
 			fullDeviceList := []string{"video-674", "cdrom-12", "ps2-450", "ethernet-1", "floppy-8000", "floppy-9000", "video-500"}
 			expectedDeviceList := []string{"ethernet-1", "floppy-8000", "floppy-9000"}
@@ -233,5 +279,46 @@ stemcell_formats:
 			Expect(vmPath).To(Equal(sourceConfig.VmInventoryPath))
 			Expect(err.Error()).To(Equal("failed to export the prepared VM"))
 		})
+
+		Context("in a dry run", func() {
+			BeforeEach(func() {
+				packager.SourceConfig.DryRun = true
+			})
+
+			It("reports the device changes, export and stemcell without making them", func() {
+				fakeVcenterClient.ListDevicesReturns([]string{"cdrom-12", "ethernet-1", "video-500", "floppy-8000"}, nil)
+
+				err := packager.Package()
+				Expect(err).NotTo(HaveOccurred())
+
+				var planned []string
+				for i := 0; i < fakeMessenger.PlannedOperationCallCount(); i++ {
+					planned = append(planned, fakeMessenger.PlannedOperationArgsForCall(i))
+				}
+				stemcellFilename := StemcellFilename(outputConfig.StemcellVersion, outputConfig.Os)
+				Expect(planned).To(Equal([]string{
+					"remove device ethernet-1 from path/valid-vm-name",
+					"remove device floppy-8000 from path/valid-vm-name",
+					"eject cdrom-12 from path/valid-vm-name",
+					"export path/valid-vm-name to a temporary directory",
+					"create stemcell " + filepath.Join(outputDir, stemcellFilename),
+				}))
+				Expect(fakeMessenger.DryRunStartedCallCount()).To(Equal(1))
+				Expect(fakeMessenger.DryRunSucceededCallCount()).To(Equal(1))
+
+				Expect(fakeVcenterClient.RemoveDeviceCallCount()).To(Equal(0))
+				Expect(fakeVcenterClient.EjectCDRomCallCount()).To(Equal(0))
+				Expect(fakeVcenterClient.ExportVMCallCount()).To(Equal(0))
+				Expect(filepath.Join(outputDir, stemcellFilename)).NotTo(BeAnExistingFile())
+			})
+
+			It("returns an error if the devices cannot be listed", func() {
+				fakeVcenterClient.ListDevicesReturns(nil, errors.New("some client error"))
+
+				err := packager.Package()
+				Expect(err).To(MatchError("some client error"))
+				Expect(fakeMessenger.DryRunStartedCallCount()).To(Equal(0))
+			})
+		})
 	})
 })
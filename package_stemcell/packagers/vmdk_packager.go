@@ -33,6 +33,8 @@ type VmdkPackager struct {
 	Stop         chan struct{}
 	Debugf       func(format string, a ...interface{})
 	BuildOptions package_parameters.VmdkPackageParameters
+	Messenger    PackageMessenger
+	DryRun       bool
 }
 
 type CancelReadSeeker struct {
@@ -328,7 +330,7 @@ func (c *VmdkPackager) ConvertVMDK() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	manifest := CreateManifest(c.BuildOptions.OSVersion, c.BuildOptions.Version, c.Sha1sum)
+	manifest := CreateManifest(c.BuildOptions.OSVersion, c.BuildOptions.Version, c.Sha1sum, "")
 	if err := WriteManifest(manifest, c.tmpdir); err != nil {
 		return "", err
 	}
@@ -354,31 +356,39 @@ func (c *VmdkPackager) catchInterruptSignal() {
 	for sig := range ch {
 		c.Debugf("received signal: %s", sig)
 		if stopping {
-			fmt.Fprintf(os.Stderr, "received second (%s) signal - exiting now\n", sig)
+			c.Messenger.SecondInterruptReceived(sig.String())
 			c.Cleanup() // remove temp dir
 			os.Exit(1)
 		}
 		stopping = true
-		fmt.Fprintf(os.Stderr, "received (%s) signal cleaning up\n", sig)
+		c.Messenger.InterruptReceived(sig.String())
 		c.StopConfig()
 	}
 }
 
 func (c VmdkPackager) Package() error {
+	if c.DryRun {
+		c.Messenger.DryRunStarted()
+		stemcellFilename := StemcellFilename(c.BuildOptions.Version, c.BuildOptions.OSVersion)
+		c.Messenger.PlannedOperation(fmt.Sprintf("convert %s into stemcell %s", c.BuildOptions.VMDKFile, filepath.Join(c.BuildOptions.OutputDir, stemcellFilename)))
+		c.Messenger.DryRunSucceeded()
+		return nil
+	}
 
 	go c.catchInterruptSignal()
 
 	start := time.Now()
 
+	c.Messenger.VMDKConversionStarted()
 	stemcellPath, err := c.ConvertVMDK()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		c.Messenger.ConvertVMDKFailed(err)
 		c.Cleanup() // remove temp dir
 		return err
 	}
 
 	c.Debugf("created stemcell (%s) in: %s", stemcellPath, time.Since(start))
-	fmt.Printf("created stemcell: %s", stemcellPath)
+	c.Messenger.VMDKStemcellCreated(stemcellPath)
 
 	c.Cleanup()
 	return nil
@@ -14,6 +14,7 @@ import (
 	. "github.com/cloudfoundry-incubator/stembuild/filesystem/mock"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/package_parameters"
 	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/packagers"
+	"github.com/cloudfoundry-incubator/stembuild/package_stemcell/packagers/packagersfakes"
 	"github.com/cloudfoundry-incubator/stembuild/test/helpers"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -38,6 +39,7 @@ var _ = Describe("VmdkPackager", func() {
 			Stop:         make(chan struct{}),
 			Debugf:       func(format string, a ...interface{}) {},
 			BuildOptions: stembuildConfig,
+			Messenger:    &packagersfakes.FakePackageMessenger{},
 		}
 	})
 
@@ -1,18 +1,119 @@
 package poller
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
 
-type Poller struct{}
+// Options control how PollWithOptions waits between calls and when it gives up
+type Options struct {
+	// Interval is the wait before the first call and, without backoff, between calls
+	Interval time.Duration
+	// Multiplier grows the wait after every call that did not finish; below 1 the wait stays at Interval
+	Multiplier float64
+	// MaxInterval caps the wait grown by Multiplier, 0 for no cap
+	MaxInterval time.Duration
+	// Jitter varies every wait by up to this fraction of it, e.g. 0.1 for 10%, so that parallel pollers spread out
+	Jitter float64
+	// Timeout bounds the whole poll, 0 for no limit
+	Timeout time.Duration
+	// Retryable decides whether polling goes on after an error; without it every error stops the poll
+	Retryable func(err error) bool
+}
+
+// TimeoutError is returned once Options.Timeout has passed without the poll finishing
+type TimeoutError struct {
+	Timeout time.Duration
+	// LastErr is the last retryable error, if the last call returned one
+	LastErr error
+}
 
-func (p *Poller) Poll(duration time.Duration, loopFunc func() (bool, error)) error {
-	poll := true
-	for poll {
-		time.Sleep(duration)
-		out, err := loopFunc()
-		if err != nil {
+func (e *TimeoutError) Error() string {
+	if e.LastErr != nil {
+		return fmt.Sprintf("did not finish within %s, last error: %s", e.Timeout, e.LastErr)
+	}
+	return fmt.Sprintf("did not finish within %s", e.Timeout)
+}
+
+type Poller struct {
+	// Clock defaults to the system clock
+	Clock Clock
+}
+
+// Poll calls loopFunc once per duration until it returns true or an error, or ctx is cancelled
+func (p *Poller) Poll(ctx context.Context, duration time.Duration, loopFunc func() (bool, error)) error {
+	return p.PollWithOptions(ctx, Options{Interval: duration}, loopFunc)
+}
+
+// PollWithOptions waits and then calls loopFunc until it returns true, a fatal error, ctx is cancelled or
+// options.Timeout passes. The last wait is shortened so that loopFunc is called once more at the timeout.
+func (p *Poller) PollWithOptions(ctx context.Context, options Options, loopFunc func() (bool, error)) error {
+	clock := p.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	var deadline time.Time
+	if options.Timeout > 0 {
+		deadline = clock.Now().Add(options.Timeout)
+	}
+
+	interval := options.Interval
+	for {
+		wait := jitter(interval, options.Jitter)
+		if !deadline.IsZero() {
+			if remaining := deadline.Sub(clock.Now()); wait > remaining {
+				wait = remaining
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(wait):
+		}
+
+		done, err := loopFunc()
+		if err != nil && (options.Retryable == nil || !options.Retryable(err)) {
 			return err
 		}
-		poll = !out
+		if done && err == nil {
+			return nil
+		}
+
+		if !deadline.IsZero() && !clock.Now().Before(deadline) {
+			return &TimeoutError{Timeout: options.Timeout, LastErr: err}
+		}
+		interval = backoff(interval, options)
+	}
+}
+
+func backoff(interval time.Duration, options Options) time.Duration {
+	if options.Multiplier <= 1 {
+		return interval
+	}
+	next := time.Duration(float64(interval) * options.Multiplier)
+	if options.MaxInterval > 0 && next > options.MaxInterval {
+		return options.MaxInterval
 	}
-	return nil
+	return next
+}
+
+func jitter(interval time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return interval
+	}
+	return interval + time.Duration((2*rand.Float64()-1)*fraction*float64(interval))
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
 }
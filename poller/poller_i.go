@@ -1,8 +1,18 @@
 package poller
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . PollerI
 type PollerI interface {
-	Poll(duration time.Duration, loopFunc func() (bool, error)) error
+	Poll(ctx context.Context, duration time.Duration, loopFunc func() (bool, error)) error
+	PollWithOptions(ctx context.Context, options Options, loopFunc func() (bool, error)) error
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Clock
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
 }
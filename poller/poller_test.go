@@ -1,10 +1,12 @@
 package poller_test
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"github.com/cloudfoundry-incubator/stembuild/poller"
+	"github.com/cloudfoundry-incubator/stembuild/poller/pollerfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -16,7 +18,7 @@ var _ = Describe("Poller", func() {
 			callCount := 0
 			startTime := time.Now()
 			period := 500 * time.Millisecond
-			Expect(poller.Poll(period, func() (bool, error) {
+			Expect(poller.Poll(context.Background(), period, func() (bool, error) {
 				callCount++
 				Expect(startTime.Add(time.Duration(callCount) * period)).To(BeTemporally("~", time.Now(), 200*time.Millisecond))
 				return callCount == 3, nil
@@ -26,9 +28,136 @@ var _ = Describe("Poller", func() {
 		})
 		It("returns an error when polling fails", func() {
 			poller := poller.Poller{}
-			Expect(poller.Poll(0*time.Second, func() (bool, error) {
+			Expect(poller.Poll(context.Background(), 0*time.Second, func() (bool, error) {
 				return true, errors.New("polling is hard :(")
 			})).To(MatchError("polling is hard :("))
 		})
+
+		It("stops polling when the context is cancelled", func() {
+			poller := poller.Poller{}
+			ctx, cancel := context.WithCancel(context.Background())
+			callCount := 0
+			err := poller.Poll(ctx, 10*time.Millisecond, func() (bool, error) {
+				callCount++
+				cancel()
+				return false, nil
+			})
+
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(callCount).To(Equal(1))
+		})
+	})
+
+	Describe("PollWithOptions", func() {
+		var (
+			start     time.Time
+			now       time.Time
+			fakeClock *pollerfakes.FakeClock
+			p         *poller.Poller
+		)
+
+		BeforeEach(func() {
+			start = time.Now()
+			now = start
+			fakeClock = &pollerfakes.FakeClock{}
+			fakeClock.NowCalls(func() time.Time { return now })
+			fakeClock.AfterCalls(func(d time.Duration) <-chan time.Time {
+				now = now.Add(d)
+				c := make(chan time.Time, 1)
+				c <- now
+				return c
+			})
+			p = &poller.Poller{Clock: fakeClock}
+		})
+
+		waits := func() []time.Duration {
+			var waits []time.Duration
+			for i := 0; i < fakeClock.AfterCallCount(); i++ {
+				waits = append(waits, fakeClock.AfterArgsForCall(i))
+			}
+			return waits
+		}
+
+		It("grows the wait by the multiplier up to the maximum interval", func() {
+			callCount := 0
+			err := p.PollWithOptions(context.Background(), poller.Options{
+				Interval:    time.Second,
+				Multiplier:  2,
+				MaxInterval: 5 * time.Second,
+			}, func() (bool, error) {
+				callCount++
+				return callCount == 5, nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waits()).To(Equal([]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}))
+		})
+
+		It("varies every wait by up to the jitter", func() {
+			callCount := 0
+			err := p.PollWithOptions(context.Background(), poller.Options{Interval: time.Minute, Jitter: 0.2}, func() (bool, error) {
+				callCount++
+				return callCount == 20, nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			for _, wait := range waits() {
+				Expect(wait).To(BeNumerically("~", time.Minute, 12*time.Second))
+			}
+		})
+
+		It("returns a timeout error once the timeout has passed, calling the function once more at the timeout", func() {
+			err := p.PollWithOptions(context.Background(), poller.Options{Interval: 4 * time.Minute, Timeout: 10 * time.Minute}, func() (bool, error) {
+				return false, nil
+			})
+
+			Expect(err).To(MatchError("did not finish within 10m0s"))
+			Expect(err).To(BeAssignableToTypeOf(&poller.TimeoutError{}))
+			Expect(waits()).To(Equal([]time.Duration{4 * time.Minute, 4 * time.Minute, 2 * time.Minute}))
+			Expect(now.Sub(start)).To(Equal(10 * time.Minute))
+		})
+
+		It("keeps polling after retryable errors and names the last one when it times out", func() {
+			retryable := errors.New("connection refused")
+			err := p.PollWithOptions(context.Background(), poller.Options{
+				Interval:  time.Minute,
+				Timeout:   3 * time.Minute,
+				Retryable: func(err error) bool { return err == retryable },
+			}, func() (bool, error) {
+				return false, retryable
+			})
+
+			Expect(err).To(MatchError("did not finish within 3m0s, last error: connection refused"))
+			Expect(fakeClock.AfterCallCount()).To(Equal(3))
+		})
+
+		It("stops at the first error the classifier does not retry", func() {
+			callCount := 0
+			err := p.PollWithOptions(context.Background(), poller.Options{
+				Interval:  time.Minute,
+				Retryable: func(err error) bool { return err.Error() == "connection refused" },
+			}, func() (bool, error) {
+				callCount++
+				if callCount < 3 {
+					return false, errors.New("connection refused")
+				}
+				return false, errors.New("access denied")
+			})
+
+			Expect(err).To(MatchError("access denied"))
+			Expect(callCount).To(Equal(3))
+		})
+
+		It("stops polling when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			fakeClock.AfterCalls(func(time.Duration) <-chan time.Time { return nil })
+
+			err := p.PollWithOptions(ctx, poller.Options{Interval: time.Minute}, func() (bool, error) {
+				return true, nil
+			})
+
+			Expect(err).To(MatchError(context.Canceled))
+		})
 	})
 })
@@ -2,6 +2,7 @@
 package pollerfakes
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -9,11 +10,12 @@ import (
 )
 
 type FakePollerI struct {
-	PollStub        func(time.Duration, func() (bool, error)) error
+	PollStub        func(context.Context, time.Duration, func() (bool, error)) error
 	pollMutex       sync.RWMutex
 	pollArgsForCall []struct {
-		arg1 time.Duration
-		arg2 func() (bool, error)
+		arg1 context.Context
+		arg2 time.Duration
+		arg3 func() (bool, error)
 	}
 	pollReturns struct {
 		result1 error
@@ -21,26 +23,41 @@ type FakePollerI struct {
 	pollReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PollWithOptionsStub        func(context.Context, poller.Options, func() (bool, error)) error
+	pollWithOptionsMutex       sync.RWMutex
+	pollWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 poller.Options
+		arg3 func() (bool, error)
+	}
+	pollWithOptionsReturns struct {
+		result1 error
+	}
+	pollWithOptionsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakePollerI) Poll(arg1 time.Duration, arg2 func() (bool, error)) error {
+func (fake *FakePollerI) Poll(arg1 context.Context, arg2 time.Duration, arg3 func() (bool, error)) error {
 	fake.pollMutex.Lock()
 	ret, specificReturn := fake.pollReturnsOnCall[len(fake.pollArgsForCall)]
 	fake.pollArgsForCall = append(fake.pollArgsForCall, struct {
-		arg1 time.Duration
-		arg2 func() (bool, error)
-	}{arg1, arg2})
-	fake.recordInvocation("Poll", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 time.Duration
+		arg3 func() (bool, error)
+	}{arg1, arg2, arg3})
+	stub := fake.PollStub
+	fakeReturns := fake.pollReturns
+	fake.recordInvocation("Poll", []interface{}{arg1, arg2, arg3})
 	fake.pollMutex.Unlock()
-	if fake.PollStub != nil {
-		return fake.PollStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.pollReturns
 	return fakeReturns.result1
 }
 
@@ -50,17 +67,17 @@ func (fake *FakePollerI) PollCallCount() int {
 	return len(fake.pollArgsForCall)
 }
 
-func (fake *FakePollerI) PollCalls(stub func(time.Duration, func() (bool, error)) error) {
+func (fake *FakePollerI) PollCalls(stub func(context.Context, time.Duration, func() (bool, error)) error) {
 	fake.pollMutex.Lock()
 	defer fake.pollMutex.Unlock()
 	fake.PollStub = stub
 }
 
-func (fake *FakePollerI) PollArgsForCall(i int) (time.Duration, func() (bool, error)) {
+func (fake *FakePollerI) PollArgsForCall(i int) (context.Context, time.Duration, func() (bool, error)) {
 	fake.pollMutex.RLock()
 	defer fake.pollMutex.RUnlock()
 	argsForCall := fake.pollArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakePollerI) PollReturns(result1 error) {
@@ -86,11 +103,76 @@ func (fake *FakePollerI) PollReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePollerI) PollWithOptions(arg1 context.Context, arg2 poller.Options, arg3 func() (bool, error)) error {
+	fake.pollWithOptionsMutex.Lock()
+	ret, specificReturn := fake.pollWithOptionsReturnsOnCall[len(fake.pollWithOptionsArgsForCall)]
+	fake.pollWithOptionsArgsForCall = append(fake.pollWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 poller.Options
+		arg3 func() (bool, error)
+	}{arg1, arg2, arg3})
+	stub := fake.PollWithOptionsStub
+	fakeReturns := fake.pollWithOptionsReturns
+	fake.recordInvocation("PollWithOptions", []interface{}{arg1, arg2, arg3})
+	fake.pollWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePollerI) PollWithOptionsCallCount() int {
+	fake.pollWithOptionsMutex.RLock()
+	defer fake.pollWithOptionsMutex.RUnlock()
+	return len(fake.pollWithOptionsArgsForCall)
+}
+
+func (fake *FakePollerI) PollWithOptionsCalls(stub func(context.Context, poller.Options, func() (bool, error)) error) {
+	fake.pollWithOptionsMutex.Lock()
+	defer fake.pollWithOptionsMutex.Unlock()
+	fake.PollWithOptionsStub = stub
+}
+
+func (fake *FakePollerI) PollWithOptionsArgsForCall(i int) (context.Context, poller.Options, func() (bool, error)) {
+	fake.pollWithOptionsMutex.RLock()
+	defer fake.pollWithOptionsMutex.RUnlock()
+	argsForCall := fake.pollWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePollerI) PollWithOptionsReturns(result1 error) {
+	fake.pollWithOptionsMutex.Lock()
+	defer fake.pollWithOptionsMutex.Unlock()
+	fake.PollWithOptionsStub = nil
+	fake.pollWithOptionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePollerI) PollWithOptionsReturnsOnCall(i int, result1 error) {
+	fake.pollWithOptionsMutex.Lock()
+	defer fake.pollWithOptionsMutex.Unlock()
+	fake.PollWithOptionsStub = nil
+	if fake.pollWithOptionsReturnsOnCall == nil {
+		fake.pollWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pollWithOptionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePollerI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.pollMutex.RLock()
 	defer fake.pollMutex.RUnlock()
+	fake.pollWithOptionsMutex.RLock()
+	defer fake.pollWithOptionsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
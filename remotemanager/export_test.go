@@ -0,0 +1,5 @@
+package remotemanager
+
+func IsRebooting(err error) bool {
+	return isRebooting(err)
+}
@@ -0,0 +1,29 @@
+package remotemanager
+
+import "fmt"
+
+// GuestHeartbeatRebootChecker only probes the guest once VMware Tools is sending heartbeats again,
+// since the heartbeat stops while the guest reboots
+type GuestHeartbeatRebootChecker struct {
+	heartbeat     GuestHeartbeat
+	rebootChecker RebootCheckerI
+}
+
+func NewGuestHeartbeatRebootChecker(heartbeat GuestHeartbeat, rebootChecker RebootCheckerI) *GuestHeartbeatRebootChecker {
+	return &GuestHeartbeatRebootChecker{heartbeat, rebootChecker}
+}
+
+func (rc *GuestHeartbeatRebootChecker) RecordBootTime() error {
+	return rc.rebootChecker.RecordBootTime()
+}
+
+func (rc *GuestHeartbeatRebootChecker) RebootHasFinished() (bool, error) {
+	green, err := rc.heartbeat.GuestHeartbeatIsGreen()
+	if err != nil {
+		return false, fmt.Errorf("unable to read the guest heartbeat: %s", err)
+	}
+	if !green {
+		return false, nil
+	}
+	return rc.rebootChecker.RebootHasFinished()
+}
@@ -0,0 +1,57 @@
+package remotemanager_test
+
+import (
+	"errors"
+
+	. "github.com/cloudfoundry-incubator/stembuild/remotemanager"
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager/remotemanagerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GuestHeartbeatRebootChecker", func() {
+	var (
+		fakeHeartbeat     *remotemanagerfakes.FakeGuestHeartbeat
+		fakeRebootChecker *remotemanagerfakes.FakeRebootCheckerI
+		rc                *GuestHeartbeatRebootChecker
+	)
+
+	BeforeEach(func() {
+		fakeHeartbeat = &remotemanagerfakes.FakeGuestHeartbeat{}
+		fakeRebootChecker = &remotemanagerfakes.FakeRebootCheckerI{}
+		rc = NewGuestHeartbeatRebootChecker(fakeHeartbeat, fakeRebootChecker)
+	})
+
+	It("does not probe the guest while the heartbeat is not green", func() {
+		fakeHeartbeat.GuestHeartbeatIsGreenReturns(false, nil)
+
+		finished, err := rc.RebootHasFinished()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(finished).To(BeFalse())
+		Expect(fakeRebootChecker.RebootHasFinishedCallCount()).To(Equal(0))
+	})
+
+	It("probes the guest once the heartbeat is green", func() {
+		fakeHeartbeat.GuestHeartbeatIsGreenReturns(true, nil)
+		fakeRebootChecker.RebootHasFinishedReturns(true, nil)
+
+		finished, err := rc.RebootHasFinished()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(finished).To(BeTrue())
+		Expect(fakeRebootChecker.RebootHasFinishedCallCount()).To(Equal(1))
+	})
+
+	It("returns an error when the heartbeat cannot be read", func() {
+		fakeHeartbeat.GuestHeartbeatIsGreenReturns(false, errors.New("session expired"))
+
+		_, err := rc.RebootHasFinished()
+		Expect(err).To(MatchError("unable to read the guest heartbeat: session expired"))
+	})
+
+	It("records the boot time through the guest", func() {
+		fakeRebootChecker.RecordBootTimeReturns(errors.New("guest operations are not ready"))
+
+		Expect(rc.RecordBootTime()).To(MatchError("guest operations are not ready"))
+		Expect(fakeHeartbeat.GuestHeartbeatIsGreenCallCount()).To(Equal(0))
+	})
+})
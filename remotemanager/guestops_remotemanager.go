@@ -0,0 +1,154 @@
+package remotemanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const guestCmd = "C:\\Windows\\System32\\cmd.exe"
+const guestOutputDir = "C:\\Windows\\Temp\\"
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . GuestOpsClient
+type GuestOpsClient interface {
+	StartProgramInGuest(ctx context.Context, command, args string) (int64, error)
+	ExitCodeForProgramInGuest(ctx context.Context, pid int64) (int32, error)
+	DownloadFileInGuest(ctx context.Context, path string) (io.Reader, int64, error)
+	UploadFileInGuest(ctx context.Context, source, destination string, progress func(sent, total int64)) error
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . GuestHeartbeat
+type GuestHeartbeat interface {
+	GuestHeartbeatIsGreen() (bool, error)
+}
+
+// GuestOps runs commands and transfers files through vSphere guest operations, which VMware Tools
+// serves through the ESXi host, so the guest does not need to be reachable over the network
+type GuestOps struct {
+	client    GuestOpsClient
+	heartbeat GuestHeartbeat
+	// Timeout bounds every operation that is not given its own timeout
+	Timeout time.Duration
+	// Stdout and Stderr receive the output of each command once it has exited
+	Stdout io.Writer
+	Stderr io.Writer
+	// Context cancels every operation. A command that is still running when it is cancelled is terminated.
+	Context context.Context
+}
+
+func NewGuestOps(client GuestOpsClient, heartbeat GuestHeartbeat) *GuestOps {
+	return &GuestOps{client, heartbeat, WinRmTimeout, os.Stdout, os.Stderr, context.Background()}
+}
+
+func (g *GuestOps) CanReachVM() error {
+	green, err := g.heartbeat.GuestHeartbeatIsGreen()
+	if err != nil {
+		return fmt.Errorf("unable to read the guest heartbeat: %s", err)
+	}
+	if !green {
+		return fmt.Errorf("VMware Tools on the VM is not sending heartbeats. Please ensure the VM is powered on and VMware Tools is running")
+	}
+	return nil
+}
+
+func (g *GuestOps) CanLoginVM() error {
+	ctx, cancel := g.context(g.Timeout)
+	defer cancel()
+
+	pid, err := g.client.StartProgramInGuest(ctx, guestCmd, "/C exit 0")
+	if err != nil {
+		return fmt.Errorf("failed to run a program in the guest: %s", err)
+	}
+	_, err = g.client.ExitCodeForProgramInGuest(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to run a program in the guest: %s", err)
+	}
+	return nil
+}
+
+func (g *GuestOps) UploadArtifact(sourceFilePath, destinationFilePath string, progress func(sent, total int64)) error {
+	ctx, cancel := g.context(g.Timeout)
+	defer cancel()
+
+	return g.client.UploadFileInGuest(ctx, sourceFilePath, destinationFilePath, progress)
+}
+
+func (g *GuestOps) DownloadFile(path string) ([]byte, error) {
+	ctx, cancel := g.context(g.Timeout)
+	defer cancel()
+
+	reader, _, err := g.client.DownloadFileInGuest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
+
+func (g *GuestOps) ExtractArchive(source, destination string) error {
+	command := fmt.Sprintf("powershell.exe Expand-Archive %s %s -Force", source, destination)
+	_, err := g.ExecuteCommand(command)
+	return err
+}
+
+// ExecuteCommandWithTimeout runs command through cmd.exe on the guest. Guest operations do not
+// capture output, so it is redirected to files in the guest which are read back once the command exits.
+func (g *GuestOps) ExecuteCommandWithTimeout(command string, timeout time.Duration) (int, error) {
+	return g.run(command, timeout, g.Stdout)
+}
+
+// ExecuteCommandOutput runs command and returns what it writes to stdout instead of passing it on to Stdout
+func (g *GuestOps) ExecuteCommandOutput(command string) (string, error) {
+	stdout := new(bytes.Buffer)
+	_, err := g.run(command, g.Timeout, stdout)
+	return stdout.String(), err
+}
+
+func (g *GuestOps) run(command string, timeout time.Duration, stdoutWriter io.Writer) (int, error) {
+	ctx, cancel := g.context(timeout)
+	defer cancel()
+
+	output := fmt.Sprintf("%sstembuild-%d", guestOutputDir, time.Now().UnixNano())
+	stdoutFile := output + ".out"
+	stderrFile := output + ".err"
+
+	// /S makes cmd.exe strip only the outermost quotes, leaving any quotes in command intact
+	args := fmt.Sprintf(`/S /C "%s 1> "%s" 2> "%s""`, command, stdoutFile, stderrFile)
+	pid, err := g.client.StartProgramInGuest(ctx, guestCmd, args)
+	if err != nil {
+		return -1, err
+	}
+
+	exitCode, err := g.client.ExitCodeForProgramInGuest(ctx, pid)
+	if err != nil {
+		return -1, err
+	}
+
+	stdout, _ := g.DownloadFile(stdoutFile)
+	stderr, _ := g.DownloadFile(stderrFile)
+	stdoutWriter.Write(stdout)
+	g.Stderr.Write(stderr)
+
+	// The output files are only removed on a best effort basis
+	_, _ = g.client.StartProgramInGuest(ctx, guestCmd, fmt.Sprintf(`/C del /F /Q "%s" "%s"`, stdoutFile, stderrFile))
+
+	if exitCode != 0 {
+		return int(exitCode), fmt.Errorf("%s: %s", PowershellExecutionErrorMessage, stderr)
+	}
+	return 0, nil
+}
+
+func (g *GuestOps) ExecuteCommand(command string) (int, error) {
+	return g.ExecuteCommandWithTimeout(command, g.Timeout)
+}
+
+// context returns Context bounded by timeout, or not bounded any further for a zero timeout
+func (g *GuestOps) context(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(g.Context, timeout)
+	}
+	return context.WithCancel(g.Context)
+}
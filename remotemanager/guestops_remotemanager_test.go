@@ -0,0 +1,225 @@
+package remotemanager_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/stembuild/remotemanager"
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager/remotemanagerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GuestOps", func() {
+	var (
+		fakeClient    *remotemanagerfakes.FakeGuestOpsClient
+		fakeHeartbeat *remotemanagerfakes.FakeGuestHeartbeat
+		guestOps      *GuestOps
+		stdout        *bytes.Buffer
+		stderr        *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fakeClient = &remotemanagerfakes.FakeGuestOpsClient{}
+		fakeHeartbeat = &remotemanagerfakes.FakeGuestHeartbeat{}
+		guestOps = NewGuestOps(fakeClient, fakeHeartbeat)
+
+		stdout = new(bytes.Buffer)
+		stderr = new(bytes.Buffer)
+		guestOps.Stdout = stdout
+		guestOps.Stderr = stderr
+	})
+
+	Describe("ExecuteCommand", func() {
+		BeforeEach(func() {
+			fakeClient.StartProgramInGuestReturns(42, nil)
+			fakeClient.DownloadFileInGuestStub = func(_ context.Context, path string) (io.Reader, int64, error) {
+				if strings.HasSuffix(path, ".err") {
+					return strings.NewReader("some-stderr"), 11, nil
+				}
+				return strings.NewReader("some-stdout"), 11, nil
+			}
+		})
+
+		It("runs the command through cmd.exe with its output redirected to files in the guest", func() {
+			exitCode, err := guestOps.ExecuteCommand(`powershell.exe -Command "Get-Date"`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitCode).To(Equal(0))
+
+			_, command, args := fakeClient.StartProgramInGuestArgsForCall(0)
+			Expect(command).To(Equal("C:\\Windows\\System32\\cmd.exe"))
+			Expect(args).To(MatchRegexp(`^/S /C "powershell.exe -Command "Get-Date" 1> "C:\\Windows\\Temp\\stembuild-\d+\.out" 2> "C:\\Windows\\Temp\\stembuild-\d+\.err""$`))
+
+			_, pid := fakeClient.ExitCodeForProgramInGuestArgsForCall(0)
+			Expect(pid).To(Equal(int64(42)))
+		})
+
+		It("writes the output of the command once it has exited", func() {
+			_, err := guestOps.ExecuteCommand("some-command")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.DownloadFileInGuestCallCount()).To(Equal(2))
+			Expect(stdout.String()).To(Equal("some-stdout"))
+			Expect(stderr.String()).To(Equal("some-stderr"))
+		})
+
+		It("returns the stdout of the command instead of writing it", func() {
+			output, err := guestOps.ExecuteCommandOutput("some-command")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(output).To(Equal("some-stdout"))
+			Expect(stdout.String()).To(BeEmpty())
+			Expect(stderr.String()).To(Equal("some-stderr"))
+		})
+
+		It("removes the output files from the guest", func() {
+			_, err := guestOps.ExecuteCommand("some-command")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.StartProgramInGuestCallCount()).To(Equal(2))
+			_, outputPath := fakeClient.DownloadFileInGuestArgsForCall(0)
+			_, command, args := fakeClient.StartProgramInGuestArgsForCall(1)
+			Expect(command).To(Equal("C:\\Windows\\System32\\cmd.exe"))
+			Expect(args).To(HavePrefix("/C del /F /Q "))
+			Expect(args).To(ContainSubstring(outputPath))
+		})
+
+		It("returns the exit code and stderr when the command fails", func() {
+			fakeClient.ExitCodeForProgramInGuestReturns(3, nil)
+
+			exitCode, err := guestOps.ExecuteCommand("some-command")
+			Expect(exitCode).To(Equal(3))
+			Expect(err).To(MatchError(PowershellExecutionErrorMessage + ": some-stderr"))
+		})
+
+		It("returns an error when the command cannot be started", func() {
+			fakeClient.StartProgramInGuestReturns(-1, errors.New("guest operations are not ready"))
+
+			exitCode, err := guestOps.ExecuteCommand("some-command")
+			Expect(exitCode).To(Equal(-1))
+			Expect(err).To(MatchError("guest operations are not ready"))
+		})
+
+		It("returns an error when the exit of the command cannot be observed", func() {
+			fakeClient.ExitCodeForProgramInGuestReturns(-1, errors.New("could not observe program exiting"))
+
+			_, err := guestOps.ExecuteCommand("some-command")
+			Expect(err).To(MatchError("could not observe program exiting"))
+			Expect(fakeClient.DownloadFileInGuestCallCount()).To(Equal(0))
+		})
+
+		It("bounds waiting for the command by the timeout", func() {
+			fakeClient.ExitCodeForProgramInGuestStub = func(ctx context.Context, _ int64) (int32, error) {
+				<-ctx.Done()
+				return -1, ctx.Err()
+			}
+
+			_, err := guestOps.ExecuteCommandWithTimeout("some-command", 10*time.Millisecond)
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("derives the context of each operation from its context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			guestOps.Context = ctx
+			fakeClient.ExitCodeForProgramInGuestStub = func(ctx context.Context, _ int64) (int32, error) {
+				cancel()
+				<-ctx.Done()
+				return -1, ctx.Err()
+			}
+
+			_, err := guestOps.ExecuteCommand("some-command")
+			Expect(err).To(MatchError(context.Canceled))
+		})
+	})
+
+	Describe("CanReachVM", func() {
+		It("succeeds when the guest heartbeat is green", func() {
+			fakeHeartbeat.GuestHeartbeatIsGreenReturns(true, nil)
+
+			Expect(guestOps.CanReachVM()).To(Succeed())
+		})
+
+		It("returns an error when the guest heartbeat is not green", func() {
+			fakeHeartbeat.GuestHeartbeatIsGreenReturns(false, nil)
+
+			err := guestOps.CanReachVM()
+			Expect(err).To(MatchError(ContainSubstring("VMware Tools on the VM is not sending heartbeats")))
+		})
+
+		It("returns an error when the guest heartbeat cannot be read", func() {
+			fakeHeartbeat.GuestHeartbeatIsGreenReturns(false, errors.New("not authenticated"))
+
+			err := guestOps.CanReachVM()
+			Expect(err).To(MatchError("unable to read the guest heartbeat: not authenticated"))
+		})
+	})
+
+	Describe("CanLoginVM", func() {
+		It("runs a program in the guest", func() {
+			Expect(guestOps.CanLoginVM()).To(Succeed())
+
+			_, command, args := fakeClient.StartProgramInGuestArgsForCall(0)
+			Expect(command).To(Equal("C:\\Windows\\System32\\cmd.exe"))
+			Expect(args).To(Equal("/C exit 0"))
+			Expect(fakeClient.ExitCodeForProgramInGuestCallCount()).To(Equal(1))
+		})
+
+		It("returns an error when the guest credentials are rejected", func() {
+			fakeClient.StartProgramInGuestReturns(-1, errors.New("invalid credentials"))
+
+			err := guestOps.CanLoginVM()
+			Expect(err).To(MatchError("failed to run a program in the guest: invalid credentials"))
+		})
+	})
+
+	Describe("UploadArtifact", func() {
+		It("uploads the file through guest operations", func() {
+			var sent int64
+			Expect(guestOps.UploadArtifact("./file.zip", "C:\\provision\\file.zip", func(s, _ int64) { sent = s })).To(Succeed())
+
+			_, source, destination, progress := fakeClient.UploadFileInGuestArgsForCall(0)
+			Expect(source).To(Equal("./file.zip"))
+			Expect(destination).To(Equal("C:\\provision\\file.zip"))
+			progress(512, 1024)
+			Expect(sent).To(Equal(int64(512)))
+		})
+
+		It("returns an error when the upload fails", func() {
+			fakeClient.UploadFileInGuestReturns(errors.New("upload failed"))
+
+			Expect(guestOps.UploadArtifact("./file.zip", "C:\\provision\\file.zip", nil)).To(MatchError("upload failed"))
+		})
+	})
+
+	Describe("DownloadFile", func() {
+		It("returns the contents of the file", func() {
+			fakeClient.DownloadFileInGuestReturns(strings.NewReader("log contents"), 12, nil)
+
+			contents, err := guestOps.DownloadFile("C:\\Windows\\Panther\\setupact.log")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("log contents"))
+		})
+
+		It("returns an error when the download fails", func() {
+			fakeClient.DownloadFileInGuestReturns(nil, 0, errors.New("file not found"))
+
+			_, err := guestOps.DownloadFile("C:\\missing.log")
+			Expect(err).To(MatchError("file not found"))
+		})
+	})
+
+	Describe("ExtractArchive", func() {
+		It("expands the archive with PowerShell", func() {
+			fakeClient.DownloadFileInGuestReturns(strings.NewReader(""), 0, nil)
+
+			Expect(guestOps.ExtractArchive("C:\\provision\\a.zip", "C:\\provision\\")).To(Succeed())
+
+			_, _, args := fakeClient.StartProgramInGuestArgsForCall(0)
+			Expect(args).To(ContainSubstring(`powershell.exe Expand-Archive C:\provision\a.zip C:\provision\ -Force`))
+		})
+	})
+})
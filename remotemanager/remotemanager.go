@@ -1,16 +1,38 @@
 package remotemanager
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . RemoteManager
 
 const PowershellExecutionErrorMessage = "powershell encountered an issue"
 
 type RemoteManager interface {
-	UploadArtifact(source, destination string) error
+	UploadArtifact(source, destination string, progress func(sent, total int64)) error
 	ExtractArchive(source, destination string) error
 	ExecuteCommand(command string) (int, error)
 	ExecuteCommandWithTimeout(command string, timeout time.Duration) (int, error)
+	ExecuteCommandOutput(command string) (string, error)
 	CanReachVM() error
 	CanLoginVM() error
+	DownloadFile(path string) ([]byte, error)
+}
+
+// progressReader tells progress how much of the file has been read
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
 }
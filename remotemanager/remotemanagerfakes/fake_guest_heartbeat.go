@@ -0,0 +1,107 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package remotemanagerfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager"
+)
+
+type FakeGuestHeartbeat struct {
+	GuestHeartbeatIsGreenStub        func() (bool, error)
+	guestHeartbeatIsGreenMutex       sync.RWMutex
+	guestHeartbeatIsGreenArgsForCall []struct {
+	}
+	guestHeartbeatIsGreenReturns struct {
+		result1 bool
+		result2 error
+	}
+	guestHeartbeatIsGreenReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGuestHeartbeat) GuestHeartbeatIsGreen() (bool, error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	ret, specificReturn := fake.guestHeartbeatIsGreenReturnsOnCall[len(fake.guestHeartbeatIsGreenArgsForCall)]
+	fake.guestHeartbeatIsGreenArgsForCall = append(fake.guestHeartbeatIsGreenArgsForCall, struct {
+	}{})
+	stub := fake.GuestHeartbeatIsGreenStub
+	fakeReturns := fake.guestHeartbeatIsGreenReturns
+	fake.recordInvocation("GuestHeartbeatIsGreen", []interface{}{})
+	fake.guestHeartbeatIsGreenMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGuestHeartbeat) GuestHeartbeatIsGreenCallCount() int {
+	fake.guestHeartbeatIsGreenMutex.RLock()
+	defer fake.guestHeartbeatIsGreenMutex.RUnlock()
+	return len(fake.guestHeartbeatIsGreenArgsForCall)
+}
+
+func (fake *FakeGuestHeartbeat) GuestHeartbeatIsGreenCalls(stub func() (bool, error)) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = stub
+}
+
+func (fake *FakeGuestHeartbeat) GuestHeartbeatIsGreenReturns(result1 bool, result2 error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = nil
+	fake.guestHeartbeatIsGreenReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGuestHeartbeat) GuestHeartbeatIsGreenReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = nil
+	if fake.guestHeartbeatIsGreenReturnsOnCall == nil {
+		fake.guestHeartbeatIsGreenReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.guestHeartbeatIsGreenReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGuestHeartbeat) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.guestHeartbeatIsGreenMutex.RLock()
+	defer fake.guestHeartbeatIsGreenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeGuestHeartbeat) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ remotemanager.GuestHeartbeat = new(FakeGuestHeartbeat)
@@ -0,0 +1,369 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package remotemanagerfakes
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager"
+)
+
+type FakeGuestOpsClient struct {
+	DownloadFileInGuestStub        func(context.Context, string) (io.Reader, int64, error)
+	downloadFileInGuestMutex       sync.RWMutex
+	downloadFileInGuestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	downloadFileInGuestReturns struct {
+		result1 io.Reader
+		result2 int64
+		result3 error
+	}
+	downloadFileInGuestReturnsOnCall map[int]struct {
+		result1 io.Reader
+		result2 int64
+		result3 error
+	}
+	ExitCodeForProgramInGuestStub        func(context.Context, int64) (int32, error)
+	exitCodeForProgramInGuestMutex       sync.RWMutex
+	exitCodeForProgramInGuestArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+	}
+	exitCodeForProgramInGuestReturns struct {
+		result1 int32
+		result2 error
+	}
+	exitCodeForProgramInGuestReturnsOnCall map[int]struct {
+		result1 int32
+		result2 error
+	}
+	StartProgramInGuestStub        func(context.Context, string, string) (int64, error)
+	startProgramInGuestMutex       sync.RWMutex
+	startProgramInGuestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	startProgramInGuestReturns struct {
+		result1 int64
+		result2 error
+	}
+	startProgramInGuestReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	UploadFileInGuestStub        func(context.Context, string, string, func(sent int64, total int64)) error
+	uploadFileInGuestMutex       sync.RWMutex
+	uploadFileInGuestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 func(sent int64, total int64)
+	}
+	uploadFileInGuestReturns struct {
+		result1 error
+	}
+	uploadFileInGuestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGuestOpsClient) DownloadFileInGuest(arg1 context.Context, arg2 string) (io.Reader, int64, error) {
+	fake.downloadFileInGuestMutex.Lock()
+	ret, specificReturn := fake.downloadFileInGuestReturnsOnCall[len(fake.downloadFileInGuestArgsForCall)]
+	fake.downloadFileInGuestArgsForCall = append(fake.downloadFileInGuestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DownloadFileInGuestStub
+	fakeReturns := fake.downloadFileInGuestReturns
+	fake.recordInvocation("DownloadFileInGuest", []interface{}{arg1, arg2})
+	fake.downloadFileInGuestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGuestOpsClient) DownloadFileInGuestCallCount() int {
+	fake.downloadFileInGuestMutex.RLock()
+	defer fake.downloadFileInGuestMutex.RUnlock()
+	return len(fake.downloadFileInGuestArgsForCall)
+}
+
+func (fake *FakeGuestOpsClient) DownloadFileInGuestCalls(stub func(context.Context, string) (io.Reader, int64, error)) {
+	fake.downloadFileInGuestMutex.Lock()
+	defer fake.downloadFileInGuestMutex.Unlock()
+	fake.DownloadFileInGuestStub = stub
+}
+
+func (fake *FakeGuestOpsClient) DownloadFileInGuestArgsForCall(i int) (context.Context, string) {
+	fake.downloadFileInGuestMutex.RLock()
+	defer fake.downloadFileInGuestMutex.RUnlock()
+	argsForCall := fake.downloadFileInGuestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGuestOpsClient) DownloadFileInGuestReturns(result1 io.Reader, result2 int64, result3 error) {
+	fake.downloadFileInGuestMutex.Lock()
+	defer fake.downloadFileInGuestMutex.Unlock()
+	fake.DownloadFileInGuestStub = nil
+	fake.downloadFileInGuestReturns = struct {
+		result1 io.Reader
+		result2 int64
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGuestOpsClient) DownloadFileInGuestReturnsOnCall(i int, result1 io.Reader, result2 int64, result3 error) {
+	fake.downloadFileInGuestMutex.Lock()
+	defer fake.downloadFileInGuestMutex.Unlock()
+	fake.DownloadFileInGuestStub = nil
+	if fake.downloadFileInGuestReturnsOnCall == nil {
+		fake.downloadFileInGuestReturnsOnCall = make(map[int]struct {
+			result1 io.Reader
+			result2 int64
+			result3 error
+		})
+	}
+	fake.downloadFileInGuestReturnsOnCall[i] = struct {
+		result1 io.Reader
+		result2 int64
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGuestOpsClient) ExitCodeForProgramInGuest(arg1 context.Context, arg2 int64) (int32, error) {
+	fake.exitCodeForProgramInGuestMutex.Lock()
+	ret, specificReturn := fake.exitCodeForProgramInGuestReturnsOnCall[len(fake.exitCodeForProgramInGuestArgsForCall)]
+	fake.exitCodeForProgramInGuestArgsForCall = append(fake.exitCodeForProgramInGuestArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.ExitCodeForProgramInGuestStub
+	fakeReturns := fake.exitCodeForProgramInGuestReturns
+	fake.recordInvocation("ExitCodeForProgramInGuest", []interface{}{arg1, arg2})
+	fake.exitCodeForProgramInGuestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGuestOpsClient) ExitCodeForProgramInGuestCallCount() int {
+	fake.exitCodeForProgramInGuestMutex.RLock()
+	defer fake.exitCodeForProgramInGuestMutex.RUnlock()
+	return len(fake.exitCodeForProgramInGuestArgsForCall)
+}
+
+func (fake *FakeGuestOpsClient) ExitCodeForProgramInGuestCalls(stub func(context.Context, int64) (int32, error)) {
+	fake.exitCodeForProgramInGuestMutex.Lock()
+	defer fake.exitCodeForProgramInGuestMutex.Unlock()
+	fake.ExitCodeForProgramInGuestStub = stub
+}
+
+func (fake *FakeGuestOpsClient) ExitCodeForProgramInGuestArgsForCall(i int) (context.Context, int64) {
+	fake.exitCodeForProgramInGuestMutex.RLock()
+	defer fake.exitCodeForProgramInGuestMutex.RUnlock()
+	argsForCall := fake.exitCodeForProgramInGuestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGuestOpsClient) ExitCodeForProgramInGuestReturns(result1 int32, result2 error) {
+	fake.exitCodeForProgramInGuestMutex.Lock()
+	defer fake.exitCodeForProgramInGuestMutex.Unlock()
+	fake.ExitCodeForProgramInGuestStub = nil
+	fake.exitCodeForProgramInGuestReturns = struct {
+		result1 int32
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGuestOpsClient) ExitCodeForProgramInGuestReturnsOnCall(i int, result1 int32, result2 error) {
+	fake.exitCodeForProgramInGuestMutex.Lock()
+	defer fake.exitCodeForProgramInGuestMutex.Unlock()
+	fake.ExitCodeForProgramInGuestStub = nil
+	if fake.exitCodeForProgramInGuestReturnsOnCall == nil {
+		fake.exitCodeForProgramInGuestReturnsOnCall = make(map[int]struct {
+			result1 int32
+			result2 error
+		})
+	}
+	fake.exitCodeForProgramInGuestReturnsOnCall[i] = struct {
+		result1 int32
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGuestOpsClient) StartProgramInGuest(arg1 context.Context, arg2 string, arg3 string) (int64, error) {
+	fake.startProgramInGuestMutex.Lock()
+	ret, specificReturn := fake.startProgramInGuestReturnsOnCall[len(fake.startProgramInGuestArgsForCall)]
+	fake.startProgramInGuestArgsForCall = append(fake.startProgramInGuestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.StartProgramInGuestStub
+	fakeReturns := fake.startProgramInGuestReturns
+	fake.recordInvocation("StartProgramInGuest", []interface{}{arg1, arg2, arg3})
+	fake.startProgramInGuestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGuestOpsClient) StartProgramInGuestCallCount() int {
+	fake.startProgramInGuestMutex.RLock()
+	defer fake.startProgramInGuestMutex.RUnlock()
+	return len(fake.startProgramInGuestArgsForCall)
+}
+
+func (fake *FakeGuestOpsClient) StartProgramInGuestCalls(stub func(context.Context, string, string) (int64, error)) {
+	fake.startProgramInGuestMutex.Lock()
+	defer fake.startProgramInGuestMutex.Unlock()
+	fake.StartProgramInGuestStub = stub
+}
+
+func (fake *FakeGuestOpsClient) StartProgramInGuestArgsForCall(i int) (context.Context, string, string) {
+	fake.startProgramInGuestMutex.RLock()
+	defer fake.startProgramInGuestMutex.RUnlock()
+	argsForCall := fake.startProgramInGuestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGuestOpsClient) StartProgramInGuestReturns(result1 int64, result2 error) {
+	fake.startProgramInGuestMutex.Lock()
+	defer fake.startProgramInGuestMutex.Unlock()
+	fake.StartProgramInGuestStub = nil
+	fake.startProgramInGuestReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGuestOpsClient) StartProgramInGuestReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.startProgramInGuestMutex.Lock()
+	defer fake.startProgramInGuestMutex.Unlock()
+	fake.StartProgramInGuestStub = nil
+	if fake.startProgramInGuestReturnsOnCall == nil {
+		fake.startProgramInGuestReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.startProgramInGuestReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGuestOpsClient) UploadFileInGuest(arg1 context.Context, arg2 string, arg3 string, arg4 func(sent int64, total int64)) error {
+	fake.uploadFileInGuestMutex.Lock()
+	ret, specificReturn := fake.uploadFileInGuestReturnsOnCall[len(fake.uploadFileInGuestArgsForCall)]
+	fake.uploadFileInGuestArgsForCall = append(fake.uploadFileInGuestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 func(sent int64, total int64)
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UploadFileInGuestStub
+	fakeReturns := fake.uploadFileInGuestReturns
+	fake.recordInvocation("UploadFileInGuest", []interface{}{arg1, arg2, arg3, arg4})
+	fake.uploadFileInGuestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGuestOpsClient) UploadFileInGuestCallCount() int {
+	fake.uploadFileInGuestMutex.RLock()
+	defer fake.uploadFileInGuestMutex.RUnlock()
+	return len(fake.uploadFileInGuestArgsForCall)
+}
+
+func (fake *FakeGuestOpsClient) UploadFileInGuestCalls(stub func(context.Context, string, string, func(sent int64, total int64)) error) {
+	fake.uploadFileInGuestMutex.Lock()
+	defer fake.uploadFileInGuestMutex.Unlock()
+	fake.UploadFileInGuestStub = stub
+}
+
+func (fake *FakeGuestOpsClient) UploadFileInGuestArgsForCall(i int) (context.Context, string, string, func(sent int64, total int64)) {
+	fake.uploadFileInGuestMutex.RLock()
+	defer fake.uploadFileInGuestMutex.RUnlock()
+	argsForCall := fake.uploadFileInGuestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGuestOpsClient) UploadFileInGuestReturns(result1 error) {
+	fake.uploadFileInGuestMutex.Lock()
+	defer fake.uploadFileInGuestMutex.Unlock()
+	fake.UploadFileInGuestStub = nil
+	fake.uploadFileInGuestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGuestOpsClient) UploadFileInGuestReturnsOnCall(i int, result1 error) {
+	fake.uploadFileInGuestMutex.Lock()
+	defer fake.uploadFileInGuestMutex.Unlock()
+	fake.UploadFileInGuestStub = nil
+	if fake.uploadFileInGuestReturnsOnCall == nil {
+		fake.uploadFileInGuestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uploadFileInGuestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGuestOpsClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.downloadFileInGuestMutex.RLock()
+	defer fake.downloadFileInGuestMutex.RUnlock()
+	fake.exitCodeForProgramInGuestMutex.RLock()
+	defer fake.exitCodeForProgramInGuestMutex.RUnlock()
+	fake.startProgramInGuestMutex.RLock()
+	defer fake.startProgramInGuestMutex.RUnlock()
+	fake.uploadFileInGuestMutex.RLock()
+	defer fake.uploadFileInGuestMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeGuestOpsClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ remotemanager.GuestOpsClient = new(FakeGuestOpsClient)
@@ -20,6 +20,16 @@ type FakeRebootCheckerI struct {
 		result1 bool
 		result2 error
 	}
+	RecordBootTimeStub        func() error
+	recordBootTimeMutex       sync.RWMutex
+	recordBootTimeArgsForCall []struct {
+	}
+	recordBootTimeReturns struct {
+		result1 error
+	}
+	recordBootTimeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -29,15 +39,16 @@ func (fake *FakeRebootCheckerI) RebootHasFinished() (bool, error) {
 	ret, specificReturn := fake.rebootHasFinishedReturnsOnCall[len(fake.rebootHasFinishedArgsForCall)]
 	fake.rebootHasFinishedArgsForCall = append(fake.rebootHasFinishedArgsForCall, struct {
 	}{})
+	stub := fake.RebootHasFinishedStub
+	fakeReturns := fake.rebootHasFinishedReturns
 	fake.recordInvocation("RebootHasFinished", []interface{}{})
 	fake.rebootHasFinishedMutex.Unlock()
-	if fake.RebootHasFinishedStub != nil {
-		return fake.RebootHasFinishedStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.rebootHasFinishedReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -79,11 +90,66 @@ func (fake *FakeRebootCheckerI) RebootHasFinishedReturnsOnCall(i int, result1 bo
 	}{result1, result2}
 }
 
+func (fake *FakeRebootCheckerI) RecordBootTime() error {
+	fake.recordBootTimeMutex.Lock()
+	ret, specificReturn := fake.recordBootTimeReturnsOnCall[len(fake.recordBootTimeArgsForCall)]
+	fake.recordBootTimeArgsForCall = append(fake.recordBootTimeArgsForCall, struct {
+	}{})
+	stub := fake.RecordBootTimeStub
+	fakeReturns := fake.recordBootTimeReturns
+	fake.recordInvocation("RecordBootTime", []interface{}{})
+	fake.recordBootTimeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRebootCheckerI) RecordBootTimeCallCount() int {
+	fake.recordBootTimeMutex.RLock()
+	defer fake.recordBootTimeMutex.RUnlock()
+	return len(fake.recordBootTimeArgsForCall)
+}
+
+func (fake *FakeRebootCheckerI) RecordBootTimeCalls(stub func() error) {
+	fake.recordBootTimeMutex.Lock()
+	defer fake.recordBootTimeMutex.Unlock()
+	fake.RecordBootTimeStub = stub
+}
+
+func (fake *FakeRebootCheckerI) RecordBootTimeReturns(result1 error) {
+	fake.recordBootTimeMutex.Lock()
+	defer fake.recordBootTimeMutex.Unlock()
+	fake.RecordBootTimeStub = nil
+	fake.recordBootTimeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRebootCheckerI) RecordBootTimeReturnsOnCall(i int, result1 error) {
+	fake.recordBootTimeMutex.Lock()
+	defer fake.recordBootTimeMutex.Unlock()
+	fake.RecordBootTimeStub = nil
+	if fake.recordBootTimeReturnsOnCall == nil {
+		fake.recordBootTimeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordBootTimeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRebootCheckerI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.rebootHasFinishedMutex.RLock()
 	defer fake.rebootHasFinishedMutex.RUnlock()
+	fake.recordBootTimeMutex.RLock()
+	defer fake.recordBootTimeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -29,6 +29,19 @@ type FakeRemoteManager struct {
 	canReachVMReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DownloadFileStub        func(string) ([]byte, error)
+	downloadFileMutex       sync.RWMutex
+	downloadFileArgsForCall []struct {
+		arg1 string
+	}
+	downloadFileReturns struct {
+		result1 []byte
+		result2 error
+	}
+	downloadFileReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	ExecuteCommandStub        func(string) (int, error)
 	executeCommandMutex       sync.RWMutex
 	executeCommandArgsForCall []struct {
@@ -42,6 +55,19 @@ type FakeRemoteManager struct {
 		result1 int
 		result2 error
 	}
+	ExecuteCommandOutputStub        func(string) (string, error)
+	executeCommandOutputMutex       sync.RWMutex
+	executeCommandOutputArgsForCall []struct {
+		arg1 string
+	}
+	executeCommandOutputReturns struct {
+		result1 string
+		result2 error
+	}
+	executeCommandOutputReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	ExecuteCommandWithTimeoutStub        func(string, time.Duration) (int, error)
 	executeCommandWithTimeoutMutex       sync.RWMutex
 	executeCommandWithTimeoutArgsForCall []struct {
@@ -68,11 +94,12 @@ type FakeRemoteManager struct {
 	extractArchiveReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UploadArtifactStub        func(string, string) error
+	UploadArtifactStub        func(string, string, func(sent int64, total int64)) error
 	uploadArtifactMutex       sync.RWMutex
 	uploadArtifactArgsForCall []struct {
 		arg1 string
 		arg2 string
+		arg3 func(sent int64, total int64)
 	}
 	uploadArtifactReturns struct {
 		result1 error
@@ -89,15 +116,16 @@ func (fake *FakeRemoteManager) CanLoginVM() error {
 	ret, specificReturn := fake.canLoginVMReturnsOnCall[len(fake.canLoginVMArgsForCall)]
 	fake.canLoginVMArgsForCall = append(fake.canLoginVMArgsForCall, struct {
 	}{})
+	stub := fake.CanLoginVMStub
+	fakeReturns := fake.canLoginVMReturns
 	fake.recordInvocation("CanLoginVM", []interface{}{})
 	fake.canLoginVMMutex.Unlock()
-	if fake.CanLoginVMStub != nil {
-		return fake.CanLoginVMStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.canLoginVMReturns
 	return fakeReturns.result1
 }
 
@@ -141,15 +169,16 @@ func (fake *FakeRemoteManager) CanReachVM() error {
 	ret, specificReturn := fake.canReachVMReturnsOnCall[len(fake.canReachVMArgsForCall)]
 	fake.canReachVMArgsForCall = append(fake.canReachVMArgsForCall, struct {
 	}{})
+	stub := fake.CanReachVMStub
+	fakeReturns := fake.canReachVMReturns
 	fake.recordInvocation("CanReachVM", []interface{}{})
 	fake.canReachVMMutex.Unlock()
-	if fake.CanReachVMStub != nil {
-		return fake.CanReachVMStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.canReachVMReturns
 	return fakeReturns.result1
 }
 
@@ -188,21 +217,86 @@ func (fake *FakeRemoteManager) CanReachVMReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRemoteManager) DownloadFile(arg1 string) ([]byte, error) {
+	fake.downloadFileMutex.Lock()
+	ret, specificReturn := fake.downloadFileReturnsOnCall[len(fake.downloadFileArgsForCall)]
+	fake.downloadFileArgsForCall = append(fake.downloadFileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DownloadFileStub
+	fakeReturns := fake.downloadFileReturns
+	fake.recordInvocation("DownloadFile", []interface{}{arg1})
+	fake.downloadFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRemoteManager) DownloadFileCallCount() int {
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
+	return len(fake.downloadFileArgsForCall)
+}
+
+func (fake *FakeRemoteManager) DownloadFileCalls(stub func(string) ([]byte, error)) {
+	fake.downloadFileMutex.Lock()
+	defer fake.downloadFileMutex.Unlock()
+	fake.DownloadFileStub = stub
+}
+
+func (fake *FakeRemoteManager) DownloadFileArgsForCall(i int) string {
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
+	argsForCall := fake.downloadFileArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRemoteManager) DownloadFileReturns(result1 []byte, result2 error) {
+	fake.downloadFileMutex.Lock()
+	defer fake.downloadFileMutex.Unlock()
+	fake.DownloadFileStub = nil
+	fake.downloadFileReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRemoteManager) DownloadFileReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.downloadFileMutex.Lock()
+	defer fake.downloadFileMutex.Unlock()
+	fake.DownloadFileStub = nil
+	if fake.downloadFileReturnsOnCall == nil {
+		fake.downloadFileReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.downloadFileReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRemoteManager) ExecuteCommand(arg1 string) (int, error) {
 	fake.executeCommandMutex.Lock()
 	ret, specificReturn := fake.executeCommandReturnsOnCall[len(fake.executeCommandArgsForCall)]
 	fake.executeCommandArgsForCall = append(fake.executeCommandArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.ExecuteCommandStub
+	fakeReturns := fake.executeCommandReturns
 	fake.recordInvocation("ExecuteCommand", []interface{}{arg1})
 	fake.executeCommandMutex.Unlock()
-	if fake.ExecuteCommandStub != nil {
-		return fake.ExecuteCommandStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.executeCommandReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -251,6 +345,70 @@ func (fake *FakeRemoteManager) ExecuteCommandReturnsOnCall(i int, result1 int, r
 	}{result1, result2}
 }
 
+func (fake *FakeRemoteManager) ExecuteCommandOutput(arg1 string) (string, error) {
+	fake.executeCommandOutputMutex.Lock()
+	ret, specificReturn := fake.executeCommandOutputReturnsOnCall[len(fake.executeCommandOutputArgsForCall)]
+	fake.executeCommandOutputArgsForCall = append(fake.executeCommandOutputArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ExecuteCommandOutputStub
+	fakeReturns := fake.executeCommandOutputReturns
+	fake.recordInvocation("ExecuteCommandOutput", []interface{}{arg1})
+	fake.executeCommandOutputMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRemoteManager) ExecuteCommandOutputCallCount() int {
+	fake.executeCommandOutputMutex.RLock()
+	defer fake.executeCommandOutputMutex.RUnlock()
+	return len(fake.executeCommandOutputArgsForCall)
+}
+
+func (fake *FakeRemoteManager) ExecuteCommandOutputCalls(stub func(string) (string, error)) {
+	fake.executeCommandOutputMutex.Lock()
+	defer fake.executeCommandOutputMutex.Unlock()
+	fake.ExecuteCommandOutputStub = stub
+}
+
+func (fake *FakeRemoteManager) ExecuteCommandOutputArgsForCall(i int) string {
+	fake.executeCommandOutputMutex.RLock()
+	defer fake.executeCommandOutputMutex.RUnlock()
+	argsForCall := fake.executeCommandOutputArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRemoteManager) ExecuteCommandOutputReturns(result1 string, result2 error) {
+	fake.executeCommandOutputMutex.Lock()
+	defer fake.executeCommandOutputMutex.Unlock()
+	fake.ExecuteCommandOutputStub = nil
+	fake.executeCommandOutputReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRemoteManager) ExecuteCommandOutputReturnsOnCall(i int, result1 string, result2 error) {
+	fake.executeCommandOutputMutex.Lock()
+	defer fake.executeCommandOutputMutex.Unlock()
+	fake.ExecuteCommandOutputStub = nil
+	if fake.executeCommandOutputReturnsOnCall == nil {
+		fake.executeCommandOutputReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.executeCommandOutputReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRemoteManager) ExecuteCommandWithTimeout(arg1 string, arg2 time.Duration) (int, error) {
 	fake.executeCommandWithTimeoutMutex.Lock()
 	ret, specificReturn := fake.executeCommandWithTimeoutReturnsOnCall[len(fake.executeCommandWithTimeoutArgsForCall)]
@@ -258,15 +416,16 @@ func (fake *FakeRemoteManager) ExecuteCommandWithTimeout(arg1 string, arg2 time.
 		arg1 string
 		arg2 time.Duration
 	}{arg1, arg2})
+	stub := fake.ExecuteCommandWithTimeoutStub
+	fakeReturns := fake.executeCommandWithTimeoutReturns
 	fake.recordInvocation("ExecuteCommandWithTimeout", []interface{}{arg1, arg2})
 	fake.executeCommandWithTimeoutMutex.Unlock()
-	if fake.ExecuteCommandWithTimeoutStub != nil {
-		return fake.ExecuteCommandWithTimeoutStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.executeCommandWithTimeoutReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -322,15 +481,16 @@ func (fake *FakeRemoteManager) ExtractArchive(arg1 string, arg2 string) error {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.ExtractArchiveStub
+	fakeReturns := fake.extractArchiveReturns
 	fake.recordInvocation("ExtractArchive", []interface{}{arg1, arg2})
 	fake.extractArchiveMutex.Unlock()
-	if fake.ExtractArchiveStub != nil {
-		return fake.ExtractArchiveStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.extractArchiveReturns
 	return fakeReturns.result1
 }
 
@@ -376,22 +536,24 @@ func (fake *FakeRemoteManager) ExtractArchiveReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeRemoteManager) UploadArtifact(arg1 string, arg2 string) error {
+func (fake *FakeRemoteManager) UploadArtifact(arg1 string, arg2 string, arg3 func(sent int64, total int64)) error {
 	fake.uploadArtifactMutex.Lock()
 	ret, specificReturn := fake.uploadArtifactReturnsOnCall[len(fake.uploadArtifactArgsForCall)]
 	fake.uploadArtifactArgsForCall = append(fake.uploadArtifactArgsForCall, struct {
 		arg1 string
 		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("UploadArtifact", []interface{}{arg1, arg2})
+		arg3 func(sent int64, total int64)
+	}{arg1, arg2, arg3})
+	stub := fake.UploadArtifactStub
+	fakeReturns := fake.uploadArtifactReturns
+	fake.recordInvocation("UploadArtifact", []interface{}{arg1, arg2, arg3})
 	fake.uploadArtifactMutex.Unlock()
-	if fake.UploadArtifactStub != nil {
-		return fake.UploadArtifactStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.uploadArtifactReturns
 	return fakeReturns.result1
 }
 
@@ -401,17 +563,17 @@ func (fake *FakeRemoteManager) UploadArtifactCallCount() int {
 	return len(fake.uploadArtifactArgsForCall)
 }
 
-func (fake *FakeRemoteManager) UploadArtifactCalls(stub func(string, string) error) {
+func (fake *FakeRemoteManager) UploadArtifactCalls(stub func(string, string, func(sent int64, total int64)) error) {
 	fake.uploadArtifactMutex.Lock()
 	defer fake.uploadArtifactMutex.Unlock()
 	fake.UploadArtifactStub = stub
 }
 
-func (fake *FakeRemoteManager) UploadArtifactArgsForCall(i int) (string, string) {
+func (fake *FakeRemoteManager) UploadArtifactArgsForCall(i int) (string, string, func(sent int64, total int64)) {
 	fake.uploadArtifactMutex.RLock()
 	defer fake.uploadArtifactMutex.RUnlock()
 	argsForCall := fake.uploadArtifactArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeRemoteManager) UploadArtifactReturns(result1 error) {
@@ -444,8 +606,12 @@ func (fake *FakeRemoteManager) Invocations() map[string][][]interface{} {
 	defer fake.canLoginVMMutex.RUnlock()
 	fake.canReachVMMutex.RLock()
 	defer fake.canReachVMMutex.RUnlock()
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
 	fake.executeCommandMutex.RLock()
 	defer fake.executeCommandMutex.RUnlock()
+	fake.executeCommandOutputMutex.RLock()
+	defer fake.executeCommandOutputMutex.RUnlock()
 	fake.executeCommandWithTimeoutMutex.RLock()
 	defer fake.executeCommandWithTimeoutMutex.RUnlock()
 	fake.extractArchiveMutex.RLock()
@@ -1,22 +1,32 @@
 package remotemanager
 
 import (
-	"github.com/masterzen/winrm"
+	"net"
 	"time"
+
+	"github.com/masterzen/winrm"
 )
 
 type WinRMClientFactory struct {
 	host     string
 	username string
 	password string
+	// ConnectTimeout bounds establishing the connection of every client the factory builds
+	ConnectTimeout time.Duration
+	// Transport selects the port and protocol of every client the factory builds
+	Transport *WinRMTransport
 }
 
 func NewWinRmClientFactory(host, username, password string) *WinRMClientFactory {
-	return &WinRMClientFactory{host: host, username: username, password: password}
+	return &WinRMClientFactory{host: host, username: username, password: password, ConnectTimeout: WinRmConnectTimeout, Transport: &WinRMTransport{}}
 }
 
 func (f *WinRMClientFactory) Build(timeout time.Duration) (WinRMClient, error) {
-	endpoint := winrm.NewEndpoint(f.host, WinRmPort, false, true, nil, nil, nil, timeout)
-	client, err := winrm.NewClient(endpoint, f.username, f.password)
+	t := f.Transport
+	endpoint := winrm.NewEndpoint(f.host, t.ListenerPort(), t.HTTPS, t.Insecure, t.CACert, nil, nil, timeout)
+	params := *winrm.DefaultParameters
+	params.Dial = (&net.Dialer{Timeout: f.ConnectTimeout, KeepAlive: 30 * time.Second}).Dial
+	params.TransportDecorator = t.transportDecorator(f.username, params.Dial)
+	client, err := winrm.NewClientWithParameters(endpoint, f.username, f.password, &params)
 	return client, err
 }
@@ -0,0 +1,171 @@
+package remotemanager
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/masterzen/winrm"
+	"github.com/masterzen/winrm/soap"
+)
+
+const DefaultKrb5Config = "/etc/krb5.conf"
+
+// WinRMKerberos holds where stembuild gets the Kerberos ticket of the guest user from
+type WinRMKerberos struct {
+	// Realm of the guest user, needed with Keytab
+	Realm string
+	// Keytab holds the keys of the guest user, who logs in as the username stembuild is given
+	Keytab string
+	// CCache is a credential cache holding a ticket granting ticket, used instead of Keytab.
+	// The guest user is the principal of the cache.
+	CCache string
+	// Config locates the KDCs of the realm, DefaultKrb5Config by default
+	Config string
+	// SPN is the service principal of WinRM on the guest, HTTP/<host> by default
+	SPN string
+
+	mutex  sync.Mutex
+	client *krbclient.Client
+}
+
+// login returns the Kerberos client shared by every WinRM client, logging in to the KDC the first time
+func (k *WinRMKerberos) login(username string) (*krbclient.Client, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if k.client != nil {
+		return k.client, nil
+	}
+
+	configPath := k.Config
+	if configPath == "" {
+		configPath = DefaultKrb5Config
+	}
+	config, err := krbconfig.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load Kerberos configuration %s: %s", configPath, err)
+	}
+
+	var client *krbclient.Client
+	if k.CCache != "" {
+		ccache, err := credentials.LoadCCache(k.CCache)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load Kerberos credential cache %s: %s", k.CCache, err)
+		}
+		client, err = krbclient.NewFromCCache(ccache, config, krbclient.DisablePAFXFAST(true))
+		if err != nil {
+			return nil, fmt.Errorf("unable to use Kerberos credential cache %s: %s", k.CCache, err)
+		}
+	} else {
+		kt, err := keytab.Load(k.Keytab)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load Kerberos keytab %s: %s", k.Keytab, err)
+		}
+		client = krbclient.NewWithKeytab(principalName(username), k.Realm, kt, config, krbclient.DisablePAFXFAST(true))
+	}
+
+	err = client.Login()
+	if err != nil {
+		return nil, fmt.Errorf("unable to log in to Kerberos: %s", err)
+	}
+	k.client = client
+	return client, nil
+}
+
+// principalName strips the domain of a user given as DOMAIN\user or user@domain, the forms guest operations take
+func principalName(username string) string {
+	if i := strings.LastIndex(username, "\\"); i >= 0 {
+		username = username[i+1:]
+	}
+	if i := strings.Index(username, "@"); i >= 0 {
+		username = username[:i]
+	}
+	return username
+}
+
+// kerberosTransporter authenticates every WinRM request with SPNEGO, using a Kerberos service ticket
+type kerberosTransporter struct {
+	kerberos *WinRMKerberos
+	username string
+	dial     func(network, addr string) (net.Conn, error)
+
+	url       string
+	spn       string
+	transport http.RoundTripper
+}
+
+func (t *kerberosTransporter) Transport(endpoint *winrm.Endpoint) error {
+	if t.kerberos == nil {
+		return errors.New("kerberos WinRM authentication needs a keytab or a credential cache")
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: endpoint.Insecure,
+			ServerName:         endpoint.TLSServerName,
+		},
+		Dial:                  t.dial,
+		ResponseHeaderTimeout: endpoint.Timeout,
+	}
+	if len(endpoint.CACert) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(endpoint.CACert) {
+			return errors.New("unable to read WinRM CA certificates")
+		}
+		transport.TLSClientConfig.RootCAs = certPool
+	}
+
+	scheme := "http"
+	if endpoint.HTTPS {
+		scheme = "https"
+	}
+	t.url = fmt.Sprintf("%s://%s/wsman", scheme, net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port)))
+	t.spn = t.kerberos.SPN
+	if t.spn == "" {
+		t.spn = "HTTP/" + endpoint.Host
+	}
+	t.transport = transport
+	return nil
+}
+
+func (t *kerberosTransporter) Post(_ *winrm.Client, request *soap.SoapMessage) (string, error) {
+	client, err := t.kerberos.login(t.username)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", t.url, strings.NewReader(request.String()))
+	if err != nil {
+		return "", fmt.Errorf("impossible to create http request %s", err)
+	}
+	req.Header.Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	resp, err := spnego.NewClient(client, &http.Client{Transport: t.transport}, t.spn).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unknown error %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error while reading request body %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("http error %d: %s", resp.StatusCode, body)
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "application/soap+xml") {
+		return "", fmt.Errorf("http response error: %d - invalid content type", resp.StatusCode)
+	}
+	return string(body), nil
+}
@@ -1,30 +1,52 @@
 package remotemanager
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/cloudfoundry-incubator/stembuild/poller"
+	"strings"
 	"time"
 )
 
-var tryCheckReboot = `shutdown /r /f /t 60 /c "stembuild reboot test"`
-var abortReboot = `shutdown /a`
+const bootTimeCommand = `powershell.exe -NoProfile -Command "(Get-CimInstance Win32_OperatingSystem).LastBootUpTime.ToUniversalTime().ToString('o')"`
+
+// Windows derives the boot time from its clock, so it shifts a little whenever the clock is adjusted
+const bootTimeTolerance = 10 * time.Second
 
 type RebootWaiter struct {
 	poller        poller.PollerI
 	rebootChecker RebootCheckerI
+	PollInterval  time.Duration
 }
 
 func NewRebootWaiter(poller poller.PollerI, rebootChecker RebootCheckerI) *RebootWaiter {
 	return &RebootWaiter{
 		poller,
 		rebootChecker,
+		10 * time.Second,
 	}
 }
 
-func (rw *RebootWaiter) WaitForRebootFinished() error {
-	err := rw.poller.Poll(10*time.Second, rw.rebootChecker.RebootHasFinished)
+// RecordBootTime records the boot time of the guest, which has to be done before anything reboots it
+func (rw *RebootWaiter) RecordBootTime() error {
+	return rw.rebootChecker.RecordBootTime()
+}
+
+// WaitForRebootFinished polls until the guest has finished rebooting or ctx is cancelled.
+// A zero timeout waits as long as it takes.
+func (rw *RebootWaiter) WaitForRebootFinished(ctx context.Context, timeout time.Duration) error {
+	err := rw.poller.PollWithOptions(ctx, poller.Options{
+		Interval:  rw.PollInterval,
+		Jitter:    0.1,
+		Timeout:   timeout,
+		Retryable: isRebooting,
+	}, rw.rebootChecker.RebootHasFinished)
 
+	var timeoutErr *poller.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return fmt.Errorf("error polling for reboot: reboot %s", err)
+	}
 	if err != nil {
 		return fmt.Errorf("error polling for reboot: %s", err)
 	}
@@ -33,45 +55,84 @@ func (rw *RebootWaiter) WaitForRebootFinished() error {
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . RebootCheckerI
 type RebootCheckerI interface {
+	RecordBootTime() error
 	RebootHasFinished() (bool, error)
 }
 
+// RebootChecker detects a reboot from the boot time of the guest, which only moves on once the guest has booted again
 type RebootChecker struct {
 	remoteManager RemoteManager
+	bootTime      time.Time
+	// resumed is set when the boot time was first read while waiting for the reboot
+	resumed bool
 }
 
 func NewRebootChecker(winrmRemoteManager RemoteManager) *RebootChecker {
-	return &RebootChecker{winrmRemoteManager}
+	return &RebootChecker{remoteManager: winrmRemoteManager}
 }
 
-// Inspired by Hashicorp Packer waitForRestart
-func (rc *RebootChecker) RebootHasFinished() (bool, error) {
+// RecordBootTime records the boot time of the guest before it reboots.
+func (rc *RebootChecker) RecordBootTime() error {
+	contents, err := rc.readBootTime()
+	if err != nil {
+		return fmt.Errorf("unable to read the boot time of the guest: %s", err)
+	}
+	bootTime, err := parseBootTime(contents)
+	if err != nil {
+		return err
+	}
+	rc.bootTime = bootTime
+	rc.resumed = false
+	return nil
+}
 
-	exitCode, err := rc.remoteManager.ExecuteCommand(tryCheckReboot)
+// RebootHasFinished reports whether the guest has booted since RecordBootTime and runs commands again.
+// Without a recorded boot time, such as when construct resumes at the reboot, whether the guest rebooted
+// before construct stopped is unknown. The first boot time read is recorded instead, and the reboot has
+// finished once the guest runs commands again a poll later, having stayed up or booted again since.
+func (rc *RebootChecker) RebootHasFinished() (bool, error) {
+	contents, err := rc.readBootTime()
+	if err != nil {
+		return false, rebootingError{err}
+	}
+	bootTime, err := parseBootTime(contents)
 	if err != nil {
+		return false, err
+	}
+	if rc.bootTime.IsZero() {
+		rc.bootTime = bootTime
+		rc.resumed = true
 		return false, nil
 	}
-	if exitCode == 0 {
-		var abortExitCode int
-		var abortErr error
-		for i := 0; i < 5; i++ {
-			abortExitCode, abortErr = rc.remoteManager.ExecuteCommand(abortReboot)
-
-			if abortErr == nil {
-				break
-			}
-		}
-
-		if abortErr != nil {
-			return false, fmt.Errorf("unable to abort reboot: %s", abortErr)
-		}
-
-		if abortExitCode == 0 {
-			return true, nil
-		} else {
-			return false, errors.New("unable to abort reboot.")
-		}
+	if rc.resumed {
+		// the same boot time, give or take the tolerance, or a later one
+		return !bootTime.Before(rc.bootTime.Add(-bootTimeTolerance)), nil
+	}
+	return bootTime.Sub(rc.bootTime) > bootTimeTolerance, nil
+}
 
+// rebootingError is returned while the guest cannot run commands, which is expected while it reboots
+type rebootingError struct {
+	err error
+}
+
+func (e rebootingError) Error() string {
+	return fmt.Sprintf("the guest cannot run commands yet: %s", e.err)
+}
+
+func isRebooting(err error) bool {
+	var rebooting rebootingError
+	return errors.As(err, &rebooting)
+}
+
+func (rc *RebootChecker) readBootTime() (string, error) {
+	return rc.remoteManager.ExecuteCommandOutput(bootTimeCommand)
+}
+
+func parseBootTime(contents string) (time.Time, error) {
+	bootTime, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(contents))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse the boot time of the guest: %s", err)
 	}
-	return false, err
+	return bootTime, nil
 }
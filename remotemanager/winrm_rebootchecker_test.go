@@ -1,6 +1,8 @@
 package remotemanager_test
 
 import (
+	"context"
+	"github.com/cloudfoundry-incubator/stembuild/poller"
 	"github.com/cloudfoundry-incubator/stembuild/poller/pollerfakes"
 	. "github.com/cloudfoundry-incubator/stembuild/remotemanager"
 	"github.com/cloudfoundry-incubator/stembuild/remotemanager/remotemanagerfakes"
@@ -11,9 +13,7 @@ import (
 	"time"
 )
 
-const expectedTryCheckRebootCommand = "shutdown /r /f /t 60 /c \"stembuild reboot test\""
-
-const expectedAbortRebootCommand = "shutdown /a"
+const expectedBootTimeCommand = `powershell.exe -NoProfile -Command "(Get-CimInstance Win32_OperatingSystem).LastBootUpTime.ToUniversalTime().ToString('o')"`
 
 var _ = Describe("WinRM RebootChecker", func() {
 
@@ -30,122 +30,213 @@ var _ = Describe("WinRM RebootChecker", func() {
 		rc = NewRebootChecker(fakeRemoteManager)
 	})
 	Describe("WaitForRebootFinished", func() {
+		var (
+			start     time.Time
+			now       time.Time
+			fakeClock *pollerfakes.FakeClock
+		)
+
+		BeforeEach(func() {
+			start = time.Now()
+			now = start
+			fakeClock = &pollerfakes.FakeClock{}
+			fakeClock.NowCalls(func() time.Time { return now })
+			fakeClock.AfterCalls(func(d time.Duration) <-chan time.Time {
+				now = now.Add(d)
+				c := make(chan time.Time, 1)
+				c <- now
+				return c
+			})
+			fakePoller.PollWithOptionsCalls((&poller.Poller{Clock: fakeClock}).PollWithOptions)
+		})
+
 		It("calls the hasFinished func using the Poller", func() {
-			numberOfPollCalls := 8
-			fakePoller.PollStub = func(duration time.Duration, pollFunc func() (bool, error)) error {
-				for call := 0; call < numberOfPollCalls; call++ {
-					pollFunc()
-				}
-				return nil
-			}
+			rc := &remotemanagerfakes.FakeRebootCheckerI{}
+			rc.RebootHasFinishedReturnsOnCall(7, true, nil)
+			waiter := NewRebootWaiter(fakePoller, rc)
+
+			err := waiter.WaitForRebootFinished(context.Background(), 0)
 
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakePoller.PollWithOptionsCallCount()).To(Equal(1))
+			Expect(rc.RebootHasFinishedCallCount()).To(Equal(8))
+		})
+
+		It("returns error if a reboot cannot finish successfully", func() {
 			rc := &remotemanagerfakes.FakeRebootCheckerI{}
-			rc.RebootHasFinishedReturns(false, nil)
+			rc.RebootHasFinishedReturns(false, errors.New("unable to parse the boot time of the guest"))
+			waiter := NewRebootWaiter(fakePoller, rc)
+
+			err := waiter.WaitForRebootFinished(context.Background(), 0)
+			Expect(err).To(MatchError("error polling for reboot: unable to parse the boot time of the guest"))
+			Expect(rc.RebootHasFinishedCallCount()).To(Equal(1))
+		})
+
+		It("polls at the configured interval with jitter", func() {
 			waiter := NewRebootWaiter(fakePoller, rc)
+			waiter.PollInterval = 30 * time.Second
 
-			_ = waiter.WaitForRebootFinished()
+			_ = waiter.WaitForRebootFinished(context.Background(), 0)
 
-			Expect(fakePoller.PollCallCount()).To(Equal(1))
-			Expect(rc.RebootHasFinishedCallCount()).To(Equal(numberOfPollCalls))
+			_, options, _ := fakePoller.PollWithOptionsArgsForCall(0)
+			Expect(options.Interval).To(Equal(30 * time.Second))
+			Expect(options.Jitter).To(Equal(0.1))
+			Expect(fakeClock.AfterArgsForCall(0)).To(BeNumerically("~", 30*time.Second, 3*time.Second))
 		})
 
-		It("returns nil if a reboot has finished successfully", func() {
-			fakePoller.PollStub = func(duration time.Duration, pollFunc func() (bool, error)) error {
-				pollFunc()
-				return nil
-			}
+		It("polls until the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			waiter := NewRebootWaiter(fakePoller, rc)
 
-			rc := &remotemanagerfakes.FakeRebootCheckerI{}
-			rc.RebootHasFinishedReturns(false, nil)
+			_ = waiter.WaitForRebootFinished(ctx, 0)
+
+			pollCtx, _, _ := fakePoller.PollWithOptionsArgsForCall(0)
+			Expect(pollCtx).To(Equal(ctx))
+		})
+
+		It("keeps polling while the guest cannot run commands", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(0, "2020-01-02T03:04:05Z", nil)
+			Expect(rc.RecordBootTime()).To(Succeed())
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(1, "", errors.New("connection refused"))
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(2, "", errors.New("connection refused"))
+			fakeRemoteManager.ExecuteCommandOutputReturns("2020-01-02T03:34:05Z", nil)
 			waiter := NewRebootWaiter(fakePoller, rc)
 
-			err := waiter.WaitForRebootFinished()
+			err := waiter.WaitForRebootFinished(context.Background(), 0)
+
 			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeRemoteManager.ExecuteCommandOutputCallCount()).To(Equal(4))
 		})
 
-		It("returns error if a reboot cannot finish successfully", func() {
-			errorMessage := "unable to abort reboot."
-			fakePoller.PollReturns(errors.New(errorMessage))
-
+		It("returns an error naming the last problem once the timeout has passed", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturns("", errors.New("connection refused"))
 			waiter := NewRebootWaiter(fakePoller, rc)
 
-			err := waiter.WaitForRebootFinished()
-			Expect(err.Error()).To(ContainSubstring(errorMessage))
+			err := waiter.WaitForRebootFinished(context.Background(), 5*time.Minute)
+
+			Expect(err).To(MatchError("error polling for reboot: reboot did not finish within 5m0s, last error: the guest cannot run commands yet: connection refused"))
+			Expect(now.Sub(start)).To(Equal(5 * time.Minute))
+		})
+	})
+
+	Describe("RecordBootTime", func() {
+		It("reads the boot time of the guest", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturns("2020-01-02T03:04:05.1234567Z\r\n", nil)
+
+			err := rc.RecordBootTime()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeRemoteManager.ExecuteCommandOutputArgsForCall(0)).To(Equal(expectedBootTimeCommand))
+			Expect(fakeRemoteManager.ExecuteCommandCallCount()).To(Equal(0))
+			Expect(fakeRemoteManager.DownloadFileCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the boot time cannot be read", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturns("", errors.New("powershell encountered an issue: access denied"))
+
+			err := rc.RecordBootTime()
+
+			Expect(err).To(MatchError("unable to read the boot time of the guest: powershell encountered an issue: access denied"))
+		})
+
+		It("returns an error when the boot time cannot be parsed", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturns("yesterday", nil)
+
+			err := rc.RecordBootTime()
+
+			Expect(err).To(MatchError(ContainSubstring("unable to parse the boot time of the guest")))
 		})
 	})
 
 	Describe("RebootHasFinished", func() {
-		It("returns false when reboot is in progress", func() {
-			someNonzeroExitCode := 1
-			fakeRemoteManager.ExecuteCommandReturns(someNonzeroExitCode, nil)
+		BeforeEach(func() {
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(0, "2020-01-02T03:04:05.1234567Z\r\n", nil)
+			Expect(rc.RecordBootTime()).To(Succeed())
+		})
+
+		It("returns false with a retryable error while the guest cannot run commands", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturns("", errors.New("connection refused"))
 
 			hasFinished, err := rc.RebootHasFinished()
 
-			Expect(err).NotTo(HaveOccurred())
+			Expect(err).To(MatchError("the guest cannot run commands yet: connection refused"))
+			Expect(IsRebooting(err)).To(BeTrue())
 			Expect(hasFinished).To(BeFalse())
 		})
 
-		It("returns false when it could not issue test-reboot command", func() {
-			fakeRemoteManager.ExecuteCommandReturns(0, errors.New(""))
+		It("returns false while the guest has not booted again", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(1, "2020-01-02T03:04:06.2345678Z", nil)
 
 			hasFinished, err := rc.RebootHasFinished()
 
+			Expect(err).NotTo(HaveOccurred())
 			Expect(hasFinished).To(BeFalse())
-			Expect(err).ToNot(HaveOccurred())
 		})
 
-		Context("after a reboot has been successfully scheduled", func() {
+		It("returns true once the guest has booted again", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(1, "2020-01-02T03:34:05.1234567Z", nil)
 
-			BeforeEach(func() {
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(0, 0, nil)
-			})
+			hasFinished, err := rc.RebootHasFinished()
 
-			It("aborts reboot when test-reboot succeeds", func() {
-				_, err := rc.RebootHasFinished()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasFinished).To(BeTrue())
+			Expect(fakeRemoteManager.ExecuteCommandOutputArgsForCall(1)).To(Equal(expectedBootTimeCommand))
+		})
 
-				Expect(err).NotTo(HaveOccurred())
-				Expect(fakeRemoteManager.ExecuteCommandArgsForCall(1)).
-					To(Equal(expectedAbortRebootCommand))
-			})
+		It("returns an error when the boot time cannot be parsed", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(1, "yesterday", nil)
 
-			It("returns an error when abort command could not be issued", func() {
-				ErrorExitCode := 0
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(1, ErrorExitCode, errors.New("unable to issue abort command"))
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(2, ErrorExitCode, errors.New("unable to issue abort command"))
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(3, ErrorExitCode, errors.New("unable to issue abort command"))
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(4, ErrorExitCode, errors.New("unable to issue abort command"))
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(5, ErrorExitCode, errors.New("unable to issue abort command"))
+			_, err := rc.RebootHasFinished()
 
-				hasFinished, err := rc.RebootHasFinished()
+			Expect(err).To(MatchError(ContainSubstring("unable to parse the boot time of the guest")))
+			Expect(IsRebooting(err)).To(BeFalse())
+		})
+	})
 
-				Expect(fakeRemoteManager.ExecuteCommandCallCount()).To(Equal(6))
+	Describe("RebootHasFinished without a recorded boot time", func() {
+		It("records the boot time it reads first instead of accepting it", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturns("2020-01-02T03:04:05Z", nil)
 
-				Expect(hasFinished).To(BeFalse())
-				Expect(err).To(MatchError(ContainSubstring("unable to issue abort command")))
-			})
+			hasFinished, err := rc.RebootHasFinished()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasFinished).To(BeFalse())
+		})
 
-			It("returns an error when abort command failed", func() {
-				nonZeroExitCode := 1
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(1, nonZeroExitCode, nil)
+		It("returns true once the guest has stayed up for a poll", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturns("2020-01-02T03:04:05Z", nil)
 
-				hasFinished, err := rc.RebootHasFinished()
+			_, _ = rc.RebootHasFinished()
+			hasFinished, err := rc.RebootHasFinished()
 
-				Expect(hasFinished).To(BeFalse())
-				Expect(err).To(HaveOccurred())
-			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasFinished).To(BeTrue())
+		})
 
-			It("returns true when reboot has finished and when abort succeeds", func() {
-				fakeRemoteManager.ExecuteCommandReturnsOnCall(1, 0, nil)
+		It("returns true once the guest has booted again", func() {
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(0, "2020-01-02T03:04:05Z", nil)
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(1, "", errors.New("connection refused"))
+			fakeRemoteManager.ExecuteCommandOutputReturnsOnCall(2, "2020-01-02T03:34:05Z", nil)
 
-				hasFinished, err := rc.RebootHasFinished()
+			hasFinished, _ := rc.RebootHasFinished()
+			Expect(hasFinished).To(BeFalse())
+			_, err := rc.RebootHasFinished()
+			Expect(IsRebooting(err)).To(BeTrue())
+			hasFinished, err = rc.RebootHasFinished()
 
-				Expect(err).NotTo(HaveOccurred())
-				Expect(hasFinished).To(Equal(true))
-				Expect(fakeRemoteManager.ExecuteCommandCallCount()).
-					To(BeNumerically(">=", 1))
-				Expect(fakeRemoteManager.ExecuteCommandArgsForCall(0)).
-					To(Equal(expectedTryCheckRebootCommand))
-			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasFinished).To(BeTrue())
 		})
 	})
+
+	It("records the boot time through its reboot checker", func() {
+		rc := &remotemanagerfakes.FakeRebootCheckerI{}
+		rc.RecordBootTimeReturns(errors.New("connection refused"))
+		waiter := NewRebootWaiter(fakePoller, rc)
+
+		err := waiter.RecordBootTime()
+
+		Expect(err).To(MatchError("connection refused"))
+	})
 })
@@ -2,10 +2,15 @@ package remotemanager
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/masterzen/winrm"
@@ -15,12 +20,26 @@ import (
 
 const WinRmPort = 5985
 const WinRmTimeout = 120 * time.Second
+const WinRmConnectTimeout = 60 * time.Second
 
 type WinRM struct {
 	host          string
 	username      string
 	password      string
 	clientFactory WinRMClientFactoryI
+	// Timeout bounds every WinRM operation that is not given its own timeout
+	Timeout time.Duration
+	// ConnectTimeout bounds establishing the TCP connection to the WinRM port
+	ConnectTimeout time.Duration
+	// Transport selects the port and protocol of the WinRM connection.
+	// It should be shared with the client factory so both reach the same listener.
+	Transport *WinRMTransport
+	// Context stops waiting for commands and uploads once it is cancelled. WinRM cannot cancel a
+	// running command, so it is left to finish in the guest.
+	Context context.Context
+	// Stdout and Stderr receive the output of each command as it runs
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . WinRMClient
@@ -34,12 +53,13 @@ type WinRMClientFactoryI interface {
 	Build(timeout time.Duration) (WinRMClient, error)
 }
 
-func NewWinRM(host string, username string, password string, clientFactory WinRMClientFactoryI) RemoteManager {
-	return &WinRM{host, username, password, clientFactory}
+func NewWinRM(host string, username string, password string, clientFactory WinRMClientFactoryI) *WinRM {
+	return &WinRM{host, username, password, clientFactory, WinRmTimeout, WinRmConnectTimeout, &WinRMTransport{}, context.Background(), os.Stdout, os.Stderr}
 }
 
 func (w *WinRM) CanReachVM() error {
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", w.host, WinRmPort), time.Duration(time.Second*60))
+	dialer := &net.Dialer{Timeout: w.ConnectTimeout}
+	conn, err := dialer.DialContext(w.Context, "tcp", w.Transport.address(w.host))
 	if err != nil {
 		return fmt.Errorf("host %s is unreachable. Please ensure WinRM is enabled and the IP is correct: %s", w.host, err)
 	}
@@ -48,7 +68,7 @@ func (w *WinRM) CanReachVM() error {
 }
 
 func (w *WinRM) CanLoginVM() error {
-	winrmClient, err := w.clientFactory.Build(WinRmTimeout)
+	winrmClient, err := w.clientFactory.Build(w.Timeout)
 
 	if err != nil {
 		return fmt.Errorf("failed to create winrm client: %s", err)
@@ -63,13 +83,17 @@ func (w *WinRM) CanLoginVM() error {
 	return nil
 }
 
-func (w *WinRM) UploadArtifact(sourceFilePath, destinationFilePath string) error {
-	client, err := winrmcp.New(w.host, &winrmcp.Config{
+// UploadArtifact copies the local file or directory sourceFilePath to destinationFilePath on the guest.
+// If progress is not nil, it is called with the number of bytes sent so far as a file is read.
+func (w *WinRM) UploadArtifact(sourceFilePath, destinationFilePath string, progress func(sent, total int64)) error {
+	client, err := winrmcp.New(w.Transport.address(w.host), &winrmcp.Config{
 		Auth:                  winrmcp.Auth{User: w.username, Password: w.password},
-		Https:                 false,
-		Insecure:              true,
-		ConnectTimeout:        WinRmTimeout,
-		OperationTimeout:      WinRmTimeout,
+		Https:                 w.Transport.HTTPS,
+		Insecure:              w.Transport.Insecure,
+		CACertBytes:           w.Transport.CACert,
+		TransportDecorator:    w.Transport.transportDecorator(w.username, (&net.Dialer{Timeout: w.ConnectTimeout, KeepAlive: 30 * time.Second}).Dial),
+		ConnectTimeout:        w.ConnectTimeout,
+		OperationTimeout:      w.Timeout,
 		MaxOperationsPerShell: 15,
 	})
 
@@ -80,17 +104,53 @@ func (w *WinRM) UploadArtifact(sourceFilePath, destinationFilePath string) error
 	// We override Stderr because WinRM Copy output a lot of XML status messages to StdErr
 	// even though they are not errors. In addition, these status messages are difficult to read
 	// and add little customer value. WinRM does not have an output override for Copy yet
-	reader, tmpStdOut, _ := os.Pipe()
-	oldStdErr := os.Stderr
-	os.Stderr = tmpStdOut
-
-	defer func() {
-		os.Stderr = oldStdErr
-		_ = tmpStdOut.Close()
-		_ = reader.Close()
-	}()
+	defer silenceStderr()()
+
+	f, err := os.Open(sourceFilePath)
+	if err != nil {
+		return fmt.Errorf("Couldn't read file %s: %v", sourceFilePath, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("Couldn't stat file %s: %v", sourceFilePath, err)
+	}
+
+	return w.untilCancelled(func() error {
+		if info.IsDir() || progress == nil {
+			return client.Copy(sourceFilePath, destinationFilePath)
+		}
+		return client.Write(destinationFilePath, &progressReader{reader: f, total: info.Size(), progress: progress})
+	})
+}
+
+// DownloadFile reads a file from the guest by writing it base64 encoded to stdout,
+// so it only suits small files such as logs
+func (w *WinRM) DownloadFile(path string) ([]byte, error) {
+	client, err := w.clientFactory.Build(w.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	escapedPath := strings.Replace(path, "'", "''", -1)
+	command := fmt.Sprintf(`powershell.exe -NoProfile -Command "[Convert]::ToBase64String([IO.File]::ReadAllBytes('%s'))"`, escapedPath)
 
-	return client.Copy(sourceFilePath, destinationFilePath)
+	outBuffer := new(bytes.Buffer)
+	errBuffer := new(bytes.Buffer)
+	exitCode, err := client.Run(command, outBuffer, errBuffer)
+	if err != nil {
+		return nil, err
+	}
+	if exitCode != 0 {
+		return nil, fmt.Errorf("unable to read %s from guest: %s", path, errBuffer.String())
+	}
+
+	encoded := strings.Join(strings.Fields(outBuffer.String()), "")
+	contents, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s from guest: %s", path, err)
+	}
+	return contents, nil
 }
 
 func (w *WinRM) ExtractArchive(source, destination string) error {
@@ -100,12 +160,28 @@ func (w *WinRM) ExtractArchive(source, destination string) error {
 }
 
 func (w *WinRM) ExecuteCommandWithTimeout(command string, timeout time.Duration) (int, error) {
+	return w.run(command, timeout, w.Stdout)
+}
+
+// ExecuteCommandOutput runs command and returns what it writes to stdout instead of passing it on to Stdout
+func (w *WinRM) ExecuteCommandOutput(command string) (string, error) {
+	stdout := new(bytes.Buffer)
+	_, err := w.run(command, w.Timeout, stdout)
+	return stdout.String(), err
+}
+
+func (w *WinRM) run(command string, timeout time.Duration, stdout io.Writer) (int, error) {
 	client, err := w.clientFactory.Build(timeout)
 	if err != nil {
 		return -1, err
 	}
 	errBuffer := new(bytes.Buffer)
-	exitCode, err := client.Run(command, os.Stdout, io.MultiWriter(errBuffer, os.Stderr))
+	exitCode := -1
+	err = w.untilCancelled(func() error {
+		var runErr error
+		exitCode, runErr = client.Run(command, stdout, io.MultiWriter(errBuffer, w.Stderr))
+		return runErr
+	})
 	if err == nil && exitCode != 0 {
 		err = fmt.Errorf("%s: %s", PowershellExecutionErrorMessage, errBuffer.String())
 	}
@@ -114,7 +190,67 @@ func (w *WinRM) ExecuteCommandWithTimeout(command string, timeout time.Duration)
 
 func (w *WinRM) ExecuteCommand(command string) (int, error) {
 	//fmt.Printf("Executing %s\n", command)
-	exitCode, err := w.ExecuteCommandWithTimeout(command, WinRmTimeout)
+	exitCode, err := w.ExecuteCommandWithTimeout(command, w.Timeout)
 	//fmt.Printf("Exectued. exit code %d\n", exitCode)
 	return exitCode, err
 }
+
+// untilCancelled runs operation, but returns as soon as Context is cancelled instead of waiting for it
+func (w *WinRM) untilCancelled(operation func() error) error {
+	err := w.Context.Err()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- operation()
+	}()
+
+	select {
+	case err = <-done:
+		return err
+	case <-w.Context.Done():
+		return w.Context.Err()
+	}
+}
+
+var silenced struct {
+	sync.Mutex
+	count  int
+	stderr *os.File
+	writer *os.File
+}
+
+// silenceStderr discards what is written to os.Stderr until the returned function is called.
+// Uploads to several VMs can overlap, so os.Stderr is only restored once the last of them has finished.
+func silenceStderr() func() {
+	silenced.Lock()
+	defer silenced.Unlock()
+
+	if silenced.count == 0 {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			return func() {}
+		}
+		go func() {
+			_, _ = io.Copy(ioutil.Discard, reader)
+			_ = reader.Close()
+		}()
+		silenced.stderr = os.Stderr
+		silenced.writer = writer
+		os.Stderr = writer
+	}
+	silenced.count++
+
+	return func() {
+		silenced.Lock()
+		defer silenced.Unlock()
+
+		silenced.count--
+		if silenced.count == 0 {
+			os.Stderr = silenced.stderr
+			_ = silenced.writer.Close()
+		}
+	}
+}
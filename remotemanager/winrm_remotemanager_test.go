@@ -2,6 +2,7 @@ package remotemanager_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/cloudfoundry-incubator/stembuild/remotemanager"
@@ -10,10 +11,12 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/ghttp"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 ////go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . FakeShell
@@ -67,6 +70,83 @@ var _ = Describe("WinRM RemoteManager", func() {
 
 				Expect(err).NotTo(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
+				Expect(fakeClientFactory.BuildArgsForCall(0)).To(Equal(remotemanager.WinRmTimeout))
+			})
+
+			It("uses the configured timeout", func() {
+				remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+				remoteManager.Timeout = 5 * time.Minute
+				_, err := remoteManager.ExecuteCommand("foobar")
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeClientFactory.BuildArgsForCall(0)).To(Equal(5 * time.Minute))
+			})
+
+			It("writes the output of the command to its Stdout and Stderr", func() {
+				fakeClient.RunStub = func(_ string, stdout io.Writer, stderr io.Writer) (int, error) {
+					_, _ = stdout.Write([]byte("some-stdout"))
+					_, _ = stderr.Write([]byte("some-stderr"))
+					return 0, nil
+				}
+				stdout := new(bytes.Buffer)
+				stderr := new(bytes.Buffer)
+				remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+				remoteManager.Stdout = stdout
+				remoteManager.Stderr = stderr
+
+				_, err := remoteManager.ExecuteCommand("foobar")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stdout.String()).To(Equal("some-stdout"))
+				Expect(stderr.String()).To(Equal("some-stderr"))
+			})
+
+			It("returns the stdout of the command instead of writing it to its Stdout", func() {
+				fakeClient.RunStub = func(_ string, stdout io.Writer, stderr io.Writer) (int, error) {
+					_, _ = stdout.Write([]byte("some-stdout"))
+					return 0, nil
+				}
+				stdout := new(bytes.Buffer)
+				remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+				remoteManager.Stdout = stdout
+
+				output, err := remoteManager.ExecuteCommandOutput("foobar")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(Equal("some-stdout"))
+				Expect(stdout.String()).To(BeEmpty())
+			})
+
+			It("stops waiting for the command once its context is cancelled", func() {
+				running := make(chan struct{})
+				finish := make(chan struct{})
+				defer close(finish)
+				fakeClient.RunStub = func(string, io.Writer, io.Writer) (int, error) {
+					close(running)
+					<-finish
+					return 0, nil
+				}
+
+				ctx, cancel := context.WithCancel(context.Background())
+				remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+				remoteManager.Context = ctx
+				go func() {
+					<-running
+					cancel()
+				}()
+
+				exitCode, err := remoteManager.ExecuteCommand("foobar")
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(exitCode).To(Equal(-1))
+			})
+
+			It("does not start a command once its context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+				remoteManager.Context = ctx
+
+				_, err := remoteManager.ExecuteCommand("foobar")
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(fakeClient.RunCallCount()).To(Equal(0))
 			})
 
 		})
@@ -120,6 +200,55 @@ var _ = Describe("WinRM RemoteManager", func() {
 		})
 
 	})
+	Describe("DownloadFile", func() {
+		var (
+			fakeClientFactory *remotemanagerfakes.FakeWinRMClientFactoryI
+			fakeClient        *remotemanagerfakes.FakeWinRMClient
+		)
+
+		BeforeEach(func() {
+			fakeClient = &remotemanagerfakes.FakeWinRMClient{}
+			fakeClientFactory = &remotemanagerfakes.FakeWinRMClientFactoryI{}
+			fakeClientFactory.BuildReturns(fakeClient, nil)
+		})
+
+		It("returns the decoded contents of the file", func() {
+			fakeClient.RunStub = func(command string, stdout io.Writer, stderr io.Writer) (int, error) {
+				_, err := stdout.Write([]byte("c29tZSBsb2cg\r\nY29udGVudHM=\r\n"))
+				return 0, err
+			}
+
+			remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+			contents, err := remoteManager.DownloadFile(`C:\it's\log.log`)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some log contents"))
+			command, _, _ := fakeClient.RunArgsForCall(0)
+			Expect(command).To(ContainSubstring(`ReadAllBytes('C:\it''s\log.log')`))
+		})
+
+		It("returns an error when the file cannot be read", func() {
+			fakeClient.RunStub = func(command string, stdout io.Writer, stderr io.Writer) (int, error) {
+				_, err := stderr.Write([]byte("file not found"))
+				return 1, err
+			}
+
+			remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+			_, err := remoteManager.DownloadFile(`C:\missing.log`)
+
+			Expect(err).To(MatchError(`unable to read C:\missing.log from guest: file not found`))
+		})
+
+		It("returns an error when the client cannot be built", func() {
+			fakeClientFactory.BuildReturns(nil, errors.New("no client"))
+
+			remoteManager := remotemanager.NewWinRM("foo", "bar", "baz", fakeClientFactory)
+			_, err := remoteManager.DownloadFile(`C:\log.log`)
+
+			Expect(err).To(MatchError("no client"))
+		})
+	})
+
 	Describe("CanLoginVM", func() {
 		var (
 			testServer  *Server
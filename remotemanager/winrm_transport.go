@@ -0,0 +1,80 @@
+package remotemanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+
+	"github.com/masterzen/winrm"
+)
+
+const WinRmHttpsPort = 5986
+
+// WinRMAuth is how stembuild authenticates to the WinRM service of the guest
+type WinRMAuth string
+
+const (
+	WinRMAuthBasic WinRMAuth = "basic"
+	// WinRMAuthNTLM negotiates NTLM, so the guest does not need Basic auth enabled
+	WinRMAuthNTLM WinRMAuth = "ntlm"
+	// WinRMAuthKerberos negotiates Kerberos with a ticket of the guest user, so the password is not used
+	WinRMAuthKerberos WinRMAuth = "kerberos"
+)
+
+// WinRMTransport selects how stembuild reaches the WinRM service of the guest.
+// The zero value is plain HTTP on port 5985.
+type WinRMTransport struct {
+	// Port defaults to 5985, or 5986 over HTTPS
+	Port  int
+	HTTPS bool
+	// CACert holds the PEM encoded certificates the certificate of the HTTPS listener is verified against.
+	// Without it, the certificate is verified against the certificate authorities of the host running stembuild.
+	CACert []byte
+	// Insecure skips verifying the certificate of the HTTPS listener
+	Insecure bool
+	// Auth defaults to Basic
+	Auth WinRMAuth
+	// Kerberos is where the ticket of Kerberos auth comes from
+	Kerberos *WinRMKerberos
+}
+
+// LoadCACert reads the PEM encoded certificates the HTTPS listener is verified against from path
+func (t *WinRMTransport) LoadCACert(path string) error {
+	caCert, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read WinRM CA certificates: %s", err)
+	}
+	t.CACert = caCert
+	return nil
+}
+
+// ListenerPort is the port of the WinRM listener, Port or the default port of the protocol
+func (t *WinRMTransport) ListenerPort() int {
+	if t.Port != 0 {
+		return t.Port
+	}
+	if t.HTTPS {
+		return WinRmHttpsPort
+	}
+	return WinRmPort
+}
+
+func (t *WinRMTransport) address(host string) string {
+	return net.JoinHostPort(host, strconv.Itoa(t.ListenerPort()))
+}
+
+// transportDecorator returns the transport that authenticates every request of username, or nil for the default Basic auth transport
+func (t *WinRMTransport) transportDecorator(username string, dial func(network, addr string) (net.Conn, error)) func() winrm.Transporter {
+	switch t.Auth {
+	case WinRMAuthNTLM:
+		return func() winrm.Transporter {
+			return winrm.NewClientNTLMWithDial(dial)
+		}
+	case WinRMAuthKerberos:
+		return func() winrm.Transporter {
+			return &kerberosTransporter{kerberos: t.Kerberos, username: username, dial: dial}
+		}
+	}
+	return nil
+}
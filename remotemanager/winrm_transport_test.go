@@ -0,0 +1,177 @@
+package remotemanager_test
+
+import (
+	"bytes"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/remotemanager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+const shellResponse = `
+<s:Envelope xmlns:s="https://www.w3.org/2003/05/soap-envelope"
+            xmlns:w="https://schemas.dmtf.org/wbem/wsman/1/wsman.xsd">
+  <s:Header>
+    <w:ShellId>153600</w:ShellId>
+  </s:Header>
+  <s:Body/>
+</s:Envelope>
+`
+
+var _ = Describe("WinRMTransport", func() {
+	It("defaults to port 5985 over HTTP and 5986 over HTTPS", func() {
+		Expect((&remotemanager.WinRMTransport{}).ListenerPort()).To(Equal(5985))
+		Expect((&remotemanager.WinRMTransport{HTTPS: true}).ListenerPort()).To(Equal(5986))
+		Expect((&remotemanager.WinRMTransport{HTTPS: true, Port: 443}).ListenerPort()).To(Equal(443))
+	})
+
+	It("loads CA certificates from a file", func() {
+		dir, err := ioutil.TempDir("", "winrm-transport")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		caFile := filepath.Join(dir, "ca.pem")
+		Expect(ioutil.WriteFile(caFile, []byte("ca certificates"), 0600)).To(Succeed())
+
+		transport := &remotemanager.WinRMTransport{HTTPS: true}
+		Expect(transport.LoadCACert(caFile)).To(Succeed())
+		Expect(transport.CACert).To(Equal([]byte("ca certificates")))
+
+		Expect(transport.LoadCACert(filepath.Join(dir, "missing.pem"))).To(MatchError(ContainSubstring("unable to read WinRM CA certificates")))
+	})
+
+	Context("over HTTPS", func() {
+		var (
+			server *Server
+			host   string
+			port   int
+		)
+
+		BeforeEach(func() {
+			server = NewTLSServer()
+			server.HTTPTestServer.Config.ErrorLog = log.New(&bytes.Buffer{}, "", 0)
+			server.RouteToHandler("POST", "/wsman", RespondWith(http.StatusOK, shellResponse, http.Header{"Content-Type": {"application/soap+xml;charset=UTF-8"}}))
+
+			serverURL, err := url.Parse(server.URL())
+			Expect(err).NotTo(HaveOccurred())
+			host = serverURL.Hostname()
+			port, err = strconv.Atoi(serverURL.Port())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		newWinRM := func(transport *remotemanager.WinRMTransport) *remotemanager.WinRM {
+			clientFactory := remotemanager.NewWinRmClientFactory(host, "some-user", "some-pass")
+			clientFactory.Transport = transport
+			winRM := remotemanager.NewWinRM(host, "some-user", "some-pass", clientFactory)
+			winRM.Transport = transport
+			winRM.Timeout = 10 * time.Second
+			return winRM
+		}
+
+		It("connects when the listener certificate is verified against the CA certificates", func() {
+			caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.HTTPTestServer.Certificate().Raw})
+			winRM := newWinRM(&remotemanager.WinRMTransport{Port: port, HTTPS: true, CACert: caCert})
+
+			Expect(winRM.CanReachVM()).To(Succeed())
+			Expect(winRM.CanLoginVM()).To(Succeed())
+		})
+
+		It("refuses a listener certificate it cannot verify", func() {
+			winRM := newWinRM(&remotemanager.WinRMTransport{Port: port, HTTPS: true})
+
+			Expect(winRM.CanLoginVM()).To(MatchError(ContainSubstring("certificate")))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("negotiates NTLM instead of sending Basic credentials", func() {
+			respondWithShell := RespondWith(http.StatusOK, shellResponse, http.Header{"Content-Type": {"application/soap+xml;charset=UTF-8"}})
+			server.RouteToHandler("POST", "/wsman", func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasPrefix(r.Header.Get("Authorization"), "Negotiate ") {
+					w.Header().Set("WWW-Authenticate", "Negotiate")
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				respondWithShell(w, r)
+			})
+			winRM := newWinRM(&remotemanager.WinRMTransport{Port: port, HTTPS: true, Insecure: true, Auth: remotemanager.WinRMAuthNTLM})
+
+			Expect(winRM.CanLoginVM()).To(Succeed())
+			Expect(server.ReceivedRequests()).NotTo(BeEmpty())
+			for _, request := range server.ReceivedRequests() {
+				Expect(request.Header.Get("Authorization")).NotTo(HavePrefix("Basic "))
+			}
+		})
+
+		Context("with Kerberos", func() {
+			var dir string
+
+			BeforeEach(func() {
+				var err error
+				dir, err = ioutil.TempDir("", "winrm-kerberos")
+				Expect(err).NotTo(HaveOccurred())
+				krb5Conf := "[libdefaults]\n  default_realm = EXAMPLE.COM\n[realms]\n  EXAMPLE.COM = {\n    kdc = 127.0.0.1:88\n  }\n"
+				Expect(ioutil.WriteFile(filepath.Join(dir, "krb5.conf"), []byte(krb5Conf), 0600)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(dir)
+			})
+
+			It("does not send a request without a ticket", func() {
+				winRM := newWinRM(&remotemanager.WinRMTransport{Port: port, HTTPS: true, Insecure: true, Auth: remotemanager.WinRMAuthKerberos, Kerberos: &remotemanager.WinRMKerberos{
+					Realm:  "EXAMPLE.COM",
+					Keytab: filepath.Join(dir, "missing.keytab"),
+					Config: filepath.Join(dir, "krb5.conf"),
+				}})
+
+				Expect(winRM.CanLoginVM()).To(MatchError(ContainSubstring("unable to load Kerberos keytab")))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+
+			It("fails when the Kerberos configuration cannot be loaded", func() {
+				winRM := newWinRM(&remotemanager.WinRMTransport{Port: port, HTTPS: true, Insecure: true, Auth: remotemanager.WinRMAuthKerberos, Kerberos: &remotemanager.WinRMKerberos{
+					CCache: filepath.Join(dir, "krb5cc"),
+					Config: filepath.Join(dir, "missing.conf"),
+				}})
+
+				Expect(winRM.CanLoginVM()).To(MatchError(ContainSubstring("unable to load Kerberos configuration")))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+
+		It("skips verification when insecure", func() {
+			winRM := newWinRM(&remotemanager.WinRMTransport{Port: port, HTTPS: true, Insecure: true})
+
+			Expect(winRM.CanLoginVM()).To(Succeed())
+		})
+	})
+
+	It("checks reachability on the port of the listener", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer listener.Close()
+		port := listener.Addr().(*net.TCPAddr).Port
+
+		winRM := remotemanager.NewWinRM("127.0.0.1", "some-user", "some-pass", nil)
+		winRM.Transport = &remotemanager.WinRMTransport{Port: port, HTTPS: true}
+		Expect(winRM.CanReachVM()).To(Succeed())
+
+		Expect(listener.Close()).To(Succeed())
+		Expect(winRM.CanReachVM()).To(MatchError(ContainSubstring("is unreachable")))
+	})
+})
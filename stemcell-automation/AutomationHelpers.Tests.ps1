@@ -38,6 +38,14 @@ Describe "Setup" {
         $provisionerCalls.IndexOf("ProvisionVM") | Should -Be $lastIndex
     }
 
+    It "provisions the VM with the cis hardening profile unless another is given" {
+        Setup -Version "123"
+        Setup -Version "123" -HardeningProfile "minimal"
+
+        Assert-MockCalled -CommandName ProvisionVM -Times 1 -ParameterFilter { $HardeningProfile -eq "cis" }
+        Assert-MockCalled -CommandName ProvisionVM -Times 1 -ParameterFilter { $HardeningProfile -eq "minimal" }
+    }
+
 }
 
 Describe "PostReboot" {
@@ -72,6 +80,32 @@ Describe "PostReboot" {
         $lastIndex = $postRebootCalls.Count - 1
         $postRebootCalls.IndexOf("SysprepVM") | Should -Be $lastIndex
     }
+
+    It "syspreps with the given hardening profile" {
+        PostReboot -HardeningProfile "none"
+        Assert-MockCalled -CommandName SysprepVM -ParameterFilter { $HardeningProfile -eq "none" }
+    }
+
+    It "leaves sysprep to the Sysprep script when asked to" {
+        Mock Write-Log { }
+        PostReboot -SkipSysprep
+        Assert-MockCalled -CommandName CleanUpVM
+        Assert-MockCalled -CommandName SysprepVM -Times 0
+    }
+}
+
+Describe "Sysprep" {
+    It "syspreps with the given options" {
+        Mock SysprepVM { }
+        Mock RunQuickerDism { }
+        Sysprep -Organization "org" -Owner "owner" -SkipRandomPassword -HardeningProfile "custom"
+        Assert-MockCalled -CommandName SysprepVM -ParameterFilter {
+            $Organization -eq "org" -and
+                    $Owner -eq "owner" -and
+                    $SkipRandomPassword -eq $true -and
+                    $HardeningProfile -eq "custom"
+        }
+    }
 }
 
 Describe "CopyPSModules" {
@@ -301,6 +335,35 @@ Describe "SysprepVM" {
 
         Assert-MockCalled Invoke-Sysprep -Times 1 -Scope It -ParameterFilter { $IaaS -eq "vsphere" -and $NewPassword -eq $null }
     }
+
+    It "applies the embedded CIS policy for the cis hardening profile" {
+
+        { SysprepVM -HardeningProfile "cis" } | Should -Not -Throw
+
+        Assert-MockCalled Invoke-Sysprep -Times 1 -Scope It -ParameterFilter { -not $SkipLGPO -and -not $PolicySource }
+    }
+
+    It "skips the local policy for the minimal and none hardening profiles" {
+
+        { SysprepVM -HardeningProfile "minimal" } | Should -Not -Throw
+        { SysprepVM -HardeningProfile "none" } | Should -Not -Throw
+
+        Assert-MockCalled Invoke-Sysprep -Times 2 -Scope It -ParameterFilter { $SkipLGPO -eq $true }
+    }
+
+    It "applies the uploaded GPO backup for the custom hardening profile" {
+
+        { SysprepVM -HardeningProfile "custom" } | Should -Not -Throw
+
+        Assert-MockCalled Invoke-Sysprep -Times 1 -Scope It -ParameterFilter { $PolicySource -eq "C:\provision\hardening-gpo" -and -not $SkipLGPO }
+    }
+
+    It "fails for an unknown hardening profile" {
+
+        { SysprepVM -HardeningProfile "strict" } | Should -Throw "Unknown hardening profile 'strict', expected cis, minimal, none or custom"
+
+        Assert-MockCalled Invoke-Sysprep -Times 0 -Scope It
+    }
 }
 
 Describe "GenerateRandomPassword" {
@@ -667,6 +730,68 @@ Describe "Install-SecurityPoliciesAndRegistries" {
 
     BeforeEach {
         function Set-InternetExplorerRegistries{ }
+        function Disable-RC4 { }
+        function Disable-TLS1 { }
+        function Disable-TLS11 { }
+        function Enable-TLS12 { }
+        function Disable-3DES { }
+        function Disable-DCOM { }
+        Mock Disable-RC4 { }
+        Mock Disable-TLS1 { }
+        Mock Disable-TLS11 { }
+        Mock Enable-TLS12 { }
+        Mock Disable-3DES { }
+        Mock Disable-DCOM { }
+    }
+
+    It "disables weak ciphers and protocols and DCOM for the cis and custom hardening profiles" {
+        Mock Set-InternetExplorerRegistries { }
+        Mock Write-Log { }
+        Mock Get-OSVersionString { "10.0.17763.0" }
+
+        { Install-SecurityPoliciesAndRegistries } | Should -Not -Throw
+        { Install-SecurityPoliciesAndRegistries -HardeningProfile "custom" } | Should -Not -Throw
+
+        foreach ($command in "Disable-RC4", "Disable-TLS1", "Disable-TLS11", "Enable-TLS12", "Disable-3DES", "Disable-DCOM") {
+            Assert-MockCalled $command -Times 2 -Scope It
+        }
+        Assert-MockCalled Set-InternetExplorerRegistries -Times 2 -Scope It
+    }
+
+    It "only disables RC4 and 3DES and enables TLS 1.2 for the minimal hardening profile" {
+        Mock Set-InternetExplorerRegistries { }
+        Mock Write-Log { }
+        Mock Get-OSVersionString { "10.0.17763.0" }
+
+        { Install-SecurityPoliciesAndRegistries -HardeningProfile "minimal" } | Should -Not -Throw
+
+        Assert-MockCalled Disable-RC4 -Times 1 -Scope It
+        Assert-MockCalled Disable-3DES -Times 1 -Scope It
+        Assert-MockCalled Enable-TLS12 -Times 1 -Scope It
+        Assert-MockCalled Disable-TLS1 -Times 0 -Scope It
+        Assert-MockCalled Disable-TLS11 -Times 0 -Scope It
+        Assert-MockCalled Disable-DCOM -Times 0 -Scope It
+        Assert-MockCalled Set-InternetExplorerRegistries -Times 0 -Scope It
+    }
+
+    It "changes no registry settings for the none hardening profile" {
+        Mock Set-InternetExplorerRegistries { }
+        Mock Write-Log { }
+
+        { Install-SecurityPoliciesAndRegistries -HardeningProfile "none" } | Should -Not -Throw
+
+        foreach ($command in "Disable-RC4", "Disable-TLS1", "Disable-TLS11", "Enable-TLS12", "Disable-3DES", "Disable-DCOM", "Set-InternetExplorerRegistries") {
+            Assert-MockCalled $command -Times 0 -Scope It
+        }
+        Assert-MockCalled Write-Log -Times 1 -Scope It -ParameterFilter { $Message -eq "Did not run Set-InternetExplorerRegistries because the none hardening profile does not include it" }
+    }
+
+    It "fails gracefully for an unknown hardening profile" {
+        Mock Write-Log { }
+
+        { Install-SecurityPoliciesAndRegistries -HardeningProfile "strict" } | Should -Throw "Unknown hardening profile 'strict', expected cis, minimal, none or custom"
+
+        Assert-MockCalled Write-Log -Times 1 -Scope It -ParameterFilter { $Message -eq "Failed to apply the registry settings of the strict hardening profile. See 'c:\provision\log.log' for more info." }
     }
 
     It "executes the Set-InternetExplorerRegistries powershell cmdlet if the os verison is 2019" {
@@ -952,3 +1077,24 @@ Describe "Create-VersionFile" {
     }
 
 }
+
+Describe "Create-HardeningProfileFile" {
+    It "records the hardening profile" {
+        Mock New-Item { }
+        Mock Write-Log { }
+
+        { Create-HardeningProfileFile -HardeningProfile "minimal" } | Should -Not -Throw
+
+        Assert-MockCalled New-Item -Times 1 -Scope It -ParameterFilter { $Path -eq "C:\var\vcap\bosh\etc\hardening_profile" -and $Value -eq "minimal" }
+        Assert-MockCalled Write-Log -Times 1 -Scope It -ParameterFilter { $Message -eq "Successfully recorded the minimal hardening profile" }
+    }
+
+    It "fails gracefully when the file cannot be created" {
+        Mock New-Item { throw "Something went wrong trying to record the hardening profile" }
+        Mock Write-Log { }
+
+        { Create-HardeningProfileFile -HardeningProfile "cis" } | Should -Throw "Something went wrong trying to record the hardening profile"
+
+        Assert-MockCalled Write-Log -Times 1 -Scope It -ParameterFilter { $Message -eq "Failed to execute Create-HardeningProfileFile command" }
+    }
+}
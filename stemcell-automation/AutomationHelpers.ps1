@@ -19,12 +19,13 @@ function Write-Log
 function Setup()
 {
     param(
-        [String]$Version
+        [String]$Version,
+        [String]$HardeningProfile = "cis"
     )
 
     Validate-OSVersion
     Check-Dependencies
-    ProvisionVM -Version $Version
+    ProvisionVM -Version $Version -HardeningProfile $HardeningProfile
 }
 
 function PostReboot
@@ -32,14 +33,31 @@ function PostReboot
     param(
         [string]$Organization = "",
         [string]$Owner = "",
-        [switch]$SkipRandomPassword
+        [switch]$SkipRandomPassword,
+        [switch]$SkipSysprep,
+        [string]$HardeningProfile = "cis"
     )
     RunQuickerDism -IgnoreErrors $True
     InstallCFCell
     RunQuickerDism -IgnoreErrors $True
     CleanUpVM
     RunQuickerDism -IgnoreErrors $True
-    SysprepVM -Organization $Organization -Owner $Owner -SkipRandomPassword $SkipRandomPassword
+    if ($SkipSysprep) {
+        Write-Log "Skipping sysprep, it will be run separately."
+        return
+    }
+    Sysprep -Organization $Organization -Owner $Owner -SkipRandomPassword:$SkipRandomPassword -HardeningProfile $HardeningProfile
+}
+
+function Sysprep
+{
+    param(
+        [string]$Organization = "",
+        [string]$Owner = "",
+        [switch]$SkipRandomPassword,
+        [string]$HardeningProfile = "cis"
+    )
+    SysprepVM -Organization $Organization -Owner $Owner -SkipRandomPassword $SkipRandomPassword -HardeningProfile $HardeningProfile
     RunQuickerDism
 }
 
@@ -252,16 +270,18 @@ function SysprepVM
     param (
         [string]$Organization = "",
         [string]$Owner = "",
-        [bool]$SkipRandomPassword = $false
+        [bool]$SkipRandomPassword = $false,
+        [string]$HardeningProfile = "cis"
     )
 
     try
     {
+        $policyArgs = Get-HardeningPolicyArgs -HardeningProfile $HardeningProfile
         if ($SkipRandomPassword) {
-            Invoke-Sysprep -IaaS "vsphere" -Organization $Organization -Owner $Owner
+            Invoke-Sysprep -IaaS "vsphere" -Organization $Organization -Owner $Owner @policyArgs
         }else {
             $randomPassword = GenerateRandomPassword
-            Invoke-Sysprep -IaaS "vsphere" -NewPassword $randomPassword -Organization $Organization -Owner $Owner
+            Invoke-Sysprep -IaaS "vsphere" -NewPassword $randomPassword -Organization $Organization -Owner $Owner @policyArgs
         }
         Write-Log "Successfully invoked Sysprep."
     }
@@ -273,6 +293,24 @@ function SysprepVM
     }
 }
 
+# The custom hardening profile applies the GPO backup that construct extracts to C:\provision\hardening-gpo
+# in place of the embedded CIS GPO
+function Get-HardeningPolicyArgs
+{
+    param (
+        [string]$HardeningProfile = "cis"
+    )
+
+    switch ($HardeningProfile)
+    {
+        "cis" { return @{ } }
+        "minimal" { return @{ SkipLGPO = $true } }
+        "none" { return @{ SkipLGPO = $true } }
+        "custom" { return @{ PolicySource = "C:\provision\hardening-gpo" } }
+        Default { throw "Unknown hardening profile '$HardeningProfile', expected cis, minimal, none or custom" }
+    }
+}
+
 function Check-Dependencies
 {
     try
@@ -419,6 +457,44 @@ SeAssignPrimaryTokenPrivilege=*S-1-5-19,*S-1-5-20,*S-1-5-80-3847866527-469524349
 
 function Install-SecurityPoliciesAndRegistries
 {
+    param (
+        [string]$HardeningProfile = "cis"
+    )
+
+    try
+    {
+        switch ($HardeningProfile)
+        {
+            {($_ -eq "cis") -or ($_ -eq "custom")} {
+                Disable-RC4 | Out-Null
+                Disable-TLS1 | Out-Null
+                Disable-TLS11 | Out-Null
+                Enable-TLS12 | Out-Null
+                Disable-3DES | Out-Null
+                Disable-DCOM
+            }
+            "minimal" {
+                Disable-RC4 | Out-Null
+                Enable-TLS12 | Out-Null
+                Disable-3DES | Out-Null
+            }
+            "none" { }
+            Default { throw "Unknown hardening profile '$HardeningProfile', expected cis, minimal, none or custom" }
+        }
+        Write-Log "Applied the registry settings of the $HardeningProfile hardening profile"
+    }
+    catch [Exception]
+    {
+        Write-Log $_.Exception.Message
+        Write-Log "Failed to apply the registry settings of the $HardeningProfile hardening profile. See 'c:\provision\log.log' for more info."
+        throw $_.Exception
+    }
+
+    if (($HardeningProfile -ne "cis") -and ($HardeningProfile -ne "custom")) {
+        Write-Log "Did not run Set-InternetExplorerRegistries because the $HardeningProfile hardening profile does not include it"
+        return
+    }
+
     try
     {
         $osVersion2019Regex = "10\.0\.17763\..+"
@@ -475,3 +551,22 @@ function Create-VersionFile
     }
 
 }
+
+function Create-HardeningProfileFile
+{
+    param(
+        [string]$HardeningProfile = "cis"
+    )
+
+    try
+    {
+        New-Item -Path "C:\var\vcap\bosh\etc\hardening_profile" -ItemType 'file' -Value $HardeningProfile -Force | Out-Null
+        Write-Log "Successfully recorded the $HardeningProfile hardening profile"
+    }
+    catch [Exception]
+    {
+        Write-Log $_.Exception.Message
+        Write-Log "Failed to execute Create-HardeningProfileFile command"
+        throw $_.Exception
+    }
+}
@@ -1,7 +1,9 @@
 param(
     [string]$Organization = "",
     [string]$Owner = "",
-    [switch]$SkipRandomPassword
+    [switch]$SkipRandomPassword,
+    [switch]$SkipSysprep,
+    [string]$HardeningProfile = "cis"
 )
 
 $postRebootExceptionExitCode = 2
@@ -11,7 +13,7 @@ Push-Location $PSScriptRoot
 . ./AutomationHelpers.ps1
 
 try {
-    PostReboot -Organization $Organization -Owner $Owner -SkipRandomPassword $SkipRandomPassword
+    PostReboot -Organization $Organization -Owner $Owner -SkipRandomPassword:$SkipRandomPassword -SkipSysprep:$SkipSysprep -HardeningProfile $HardeningProfile
 } catch [Exception] {
     Write-Log "Failed to prepare the VM. See 'c:\provisions\log.log' for more info."
     Exit $postRebootExceptionExitCode
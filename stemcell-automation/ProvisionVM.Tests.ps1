@@ -14,6 +14,7 @@ Describe "ProvisionVM" {
         Mock Extract-LGPO { $provisionerCalls.Add("Extract-LGPO") }
         Mock Install-WUCerts { $provisionerCalls.Add("Install-WUCerts") }
         Mock Create-VersionFile { $provisionerCalls.Add("Create-VersionFile") }
+        Mock Create-HardeningProfileFile { $provisionerCalls.Add("Create-HardeningProfileFile") }
 
         if (!(Get-Command "Restart-Computer" -errorAction SilentlyContinue))
         {
@@ -102,6 +103,16 @@ Describe "ProvisionVM" {
         Assert-MockCalled -CommandName Create-VersionFile
     }
 
+    It "applies and records the cis hardening profile unless another is given" {
+        ProvisionVM
+        ProvisionVM -HardeningProfile "none"
+
+        Assert-MockCalled -CommandName Install-SecurityPoliciesAndRegistries -Times 1 -ParameterFilter { $HardeningProfile -eq "cis" }
+        Assert-MockCalled -CommandName Install-SecurityPoliciesAndRegistries -Times 1 -ParameterFilter { $HardeningProfile -eq "none" }
+        Assert-MockCalled -CommandName Create-HardeningProfileFile -Times 1 -ParameterFilter { $HardeningProfile -eq "cis" }
+        Assert-MockCalled -CommandName Create-HardeningProfileFile -Times 1 -ParameterFilter { $HardeningProfile -eq "none" }
+    }
+
     It "restarts as the last command" {
         ProvisionVM
 
@@ -2,7 +2,8 @@
 
 function ProvisionVM() {
     param (
-        [string]$Version
+        [string]$Version,
+        [string]$HardeningProfile = "cis"
     )
 
     RunQuickerDism -IgnoreErrors $True
@@ -11,7 +12,7 @@ function ProvisionVM() {
     InstallBoshAgent
     InstallOpenSSH
     Extract-LGPO
-    Install-SecurityPoliciesAndRegistries
+    Install-SecurityPoliciesAndRegistries -HardeningProfile $HardeningProfile
     Enable-SSHD
     InstallCFFeatures
 
@@ -25,6 +26,7 @@ function ProvisionVM() {
         Write-Warning "Failed to retrieve updated root certificates from the public Windows Update Server. This should not impact the successful execution of stembuild construct. If your root certificates are out of date, Diego cells running on VMs built from this stemcell may not be able to make outbound network connections."
     }
     Create-VersionFile -Version $Version
+    Create-HardeningProfileFile -HardeningProfile $HardeningProfile
     RunQuickerDism -IgnoreErrors $True
     Restart-Computer
 }
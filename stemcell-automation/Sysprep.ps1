@@ -0,0 +1,21 @@
+param(
+    [string]$Organization = "",
+    [string]$Owner = "",
+    [switch]$SkipRandomPassword,
+    [string]$HardeningProfile = "cis"
+)
+
+$sysprepExceptionExitCode = 2
+
+Push-Location $PSScriptRoot
+
+. ./AutomationHelpers.ps1
+
+try {
+    Sysprep -Organization $Organization -Owner $Owner -SkipRandomPassword:$SkipRandomPassword -HardeningProfile $HardeningProfile
+} catch [Exception] {
+    Write-Log "Failed to sysprep the VM. See 'c:\provision\log.log' for more info."
+    Exit $sysprepExceptionExitCode
+}
+
+Pop-Location
@@ -0,0 +1,79 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const powershell = "C:\\Windows\\System32\\WindowsPowerShell\\V1.0\\powershell.exe"
+
+// checksResult is where the check script records the result of every check in the guest
+const checksResult = "C:\\Windows\\Temp\\stembuild-verify.json"
+
+// check is a PowerShell script block that returns a description of what it found if the check passes, and throws if it fails
+type check struct {
+	name   string
+	script string
+}
+
+var checks = []check{
+	{
+		name:   "bosh-agent",
+		script: `$service = Get-Service -Name bosh-agent -ErrorAction Stop; "the bosh-agent service is installed and $($service.Status)"`,
+	},
+	{
+		name: "sysprep-generalize",
+		script: `$state = (Get-ItemProperty -Path 'HKLM:\SYSTEM\Setup\Status\SysprepStatus' -ErrorAction Stop).GeneralizationState
+if ($state -ne 7) { throw "sysprep GeneralizationState is $state, expected 7" }
+"sysprep GeneralizationState is 7"`,
+	},
+	{
+		name: "openssh",
+		script: `if (-not (Test-Path "$env:ProgramFiles\OpenSSH\sshd.exe")) { throw "$env:ProgramFiles\OpenSSH\sshd.exe does not exist" }
+$service = Get-Service -Name sshd -ErrorAction Stop
+"sshd.exe is installed and the sshd service is $($service.Status)"`,
+	},
+	{
+		name: "stemcell-version",
+		script: `$version = Get-Content -Path 'C:\var\vcap\bosh\etc\stemcell_version' -ErrorAction Stop
+if (-not $version) { throw 'C:\var\vcap\bosh\etc\stemcell_version is empty' }
+"stemcell version $version"`,
+	},
+	{
+		name: "lgpo",
+		script: `if (-not (Test-Path "$env:WINDIR\LGPO.exe")) { throw "$env:WINDIR\LGPO.exe does not exist" }
+$profile = 'cis'
+if (Test-Path 'C:\var\vcap\bosh\etc\hardening_profile') { $profile = (Get-Content -Path 'C:\var\vcap\bosh\etc\hardening_profile').Trim() }
+if ($profile -eq 'minimal' -or $profile -eq 'none') { "LGPO.exe is installed and the $profile hardening profile applies no machine policy" } else {
+if (-not (Test-Path "$env:WINDIR\System32\GroupPolicy\Machine\Registry.pol")) { throw "no machine policy has been applied for the $profile hardening profile" }
+"LGPO.exe is installed and the machine policy of the $profile hardening profile has been applied" }`,
+	},
+}
+
+// checkScript runs every check, recording each result in checksResult, so that a failed check does not stop the others
+func checkScript() string {
+	script := &strings.Builder{}
+	script.WriteString("$results = @()\n")
+	for _, c := range checks {
+		fmt.Fprintf(script, "try { $detail = & { %s }; $passed = $true } catch { $detail = $_.Exception.Message; $passed = $false }\n", c.script)
+		fmt.Fprintf(script, "$results += @{ Name = '%s'; Passed = $passed; Detail = \"$detail\" }\n", c.name)
+	}
+	fmt.Fprintf(script, "ConvertTo-Json -InputObject @($results) | Set-Content -Encoding UTF8 -Path '%s'\n", checksResult)
+	return script.String()
+}
+
+func parseCheckResults(contents []byte) ([]CheckResult, error) {
+	// Set-Content -Encoding UTF8 starts the file with a byte order mark
+	contents = bytes.TrimPrefix(contents, []byte("\xef\xbb\xbf"))
+	var results []CheckResult
+	err := json.Unmarshal(contents, &results)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid: %s", checksResult, err)
+	}
+	if len(results) != len(checks) {
+		return nil, fmt.Errorf("%s has %d results, expected %d", checksResult, len(results), len(checks))
+	}
+	return results, nil
+}
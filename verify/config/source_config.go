@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+)
+
+// DefaultTimeout is how long verify waits for the clone to boot, and then for the checks to run
+const DefaultTimeout = 30 * time.Minute
+
+type SourceConfig struct {
+	VCenterUrl      string
+	VCenterUsername string
+	VCenterPassword string
+	CaCertFile      string
+	VmInventoryPath string
+	GuestVMUsername string
+	GuestVMPassword string
+	CloneTo         string
+	KeepClone       bool
+	Timeout         time.Duration
+}
+
+// ClonePath is the inventory path of the linked clone the checks run in, [clone-to] or the VM's own path with a -verify suffix
+func (c SourceConfig) ClonePath() string {
+	if c.CloneTo != "" {
+		return c.CloneTo
+	}
+	return c.VmInventoryPath + "-verify"
+}
@@ -0,0 +1,22 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry-incubator/stembuild/verify/config"
+)
+
+var _ = Describe("SourceConfig", func() {
+	Describe("ClonePath", func() {
+		It("is the clone-to path when one is given", func() {
+			c := config.SourceConfig{VmInventoryPath: "/dc/vm/windows2019", CloneTo: "/dc/vm/checks/windows2019"}
+			Expect(c.ClonePath()).To(Equal("/dc/vm/checks/windows2019"))
+		})
+
+		It("is next to the VM otherwise", func() {
+			c := config.SourceConfig{VmInventoryPath: "/dc/vm/windows2019"}
+			Expect(c.ClonePath()).To(Equal("/dc/vm/windows2019-verify"))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package verify
+
+func CheckNames() []string {
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func CheckScript() string {
+	return checkScript()
+}
@@ -0,0 +1,119 @@
+package verify_factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/vcenter_manager"
+	p "github.com/cloudfoundry-incubator/stembuild/poller"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	"github.com/cloudfoundry-incubator/stembuild/verify/config"
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+)
+
+// cleanUpTimeout bounds destroying the clone and removing its snapshot, which happens even once verify has been interrupted
+const cleanUpTimeout = 5 * time.Minute
+
+type VMVerifierFactory struct {
+}
+
+func (f *VMVerifierFactory) VMVerifier(ctx context.Context, config config.SourceConfig, vCenterManager commandparser.VCenterManager, output commandparser.VMOutput) (commandparser.VMVerifier, error) {
+	if config.Timeout < 0 {
+		return nil, fmt.Errorf("timeout must not be negative, got %s", config.Timeout)
+	}
+
+	var messenger verify.VerifyMessenger = verify.NewMessenger(output.Stdout)
+	if output.Events != nil {
+		messenger = verify.NewJSONMessenger(output.Events)
+	}
+
+	err := vCenterManager.Login(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot complete login due to an incorrect vCenter user name or password")
+	}
+
+	vm, err := vCenterManager.FindVM(ctx, config.VmInventoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &linkedClone{
+		ctx:            ctx,
+		vCenterManager: vCenterManager,
+		source:         vm,
+		sourcePath:     config.VmInventoryPath,
+		clonePath:      config.ClonePath(),
+		username:       config.GuestVMUsername,
+		password:       config.GuestVMPassword,
+	}
+
+	verifier := verify.NewVerifier(ctx, clone, config.VmInventoryPath, config.ClonePath(), messenger, &p.Poller{})
+	verifier.Timeout = config.Timeout
+	verifier.KeepClone = config.KeepClone
+
+	return verifier, nil
+}
+
+// linkedClone binds making, reaching and destroying a linked clone of the VM being verified to the vCenter manager
+type linkedClone struct {
+	ctx            context.Context
+	vCenterManager commandparser.VCenterManager
+	source         *object.VirtualMachine
+	sourcePath     string
+	clonePath      string
+	username       string
+	password       string
+	clone          *object.VirtualMachine
+}
+
+// CreateSnapshot refuses to take the snapshot if one is left over from an interrupted verify,
+// since a second snapshot of the same name could not be told apart from it
+func (c *linkedClone) CreateSnapshot() error {
+	exists, err := c.vCenterManager.HasSnapshot(c.ctx, c.source, verify.SnapshotName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%s already has a snapshot called %s, left by an earlier verify. Destroy its clone, if any, and remove the snapshot first", c.sourcePath, verify.SnapshotName)
+	}
+	return c.vCenterManager.CreateSnapshot(c.ctx, c.source, verify.SnapshotName, "Base of the linked clone stembuild verify runs its checks in")
+}
+
+func (c *linkedClone) Clone() error {
+	_, err := c.vCenterManager.FindVM(c.ctx, c.clonePath)
+	if err == nil {
+		return fmt.Errorf("%s already exists, choose another clone path", c.clonePath)
+	}
+
+	err = c.vCenterManager.CloneVM(c.ctx, c.source, c.clonePath, vcenter_manager.CloneOptions{Snapshot: verify.SnapshotName})
+	if err != nil {
+		return err
+	}
+
+	c.clone, err = c.vCenterManager.FindVM(c.ctx, c.clonePath)
+	return err
+}
+
+func (c *linkedClone) GuestManager() (verify.GuestManager, error) {
+	opsManager := c.vCenterManager.OperationsManager(c.ctx, c.clone)
+	return c.vCenterManager.GuestManager(c.ctx, opsManager, c.username, c.password)
+}
+
+func (c *linkedClone) GuestHeartbeatIsGreen() (bool, error) {
+	return c.vCenterManager.GuestHeartbeatIsGreen(c.ctx, c.clone)
+}
+
+func (c *linkedClone) Destroy() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanUpTimeout)
+	defer cancel()
+	return c.vCenterManager.DestroyVM(ctx, c.clone)
+}
+
+func (c *linkedClone) RemoveSnapshot() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanUpTimeout)
+	defer cancel()
+	return c.vCenterManager.RemoveSnapshot(ctx, c.source, verify.SnapshotName)
+}
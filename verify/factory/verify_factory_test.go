@@ -0,0 +1,154 @@
+package verify_factory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/commandparser"
+	"github.com/cloudfoundry-incubator/stembuild/commandparser/commandparserfakes"
+	"github.com/cloudfoundry-incubator/stembuild/iaas_cli/iaas_clients/vcenter_manager"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	"github.com/cloudfoundry-incubator/stembuild/verify/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/object"
+)
+
+var _ = Describe("Factory", func() {
+	var (
+		factory            *VMVerifierFactory
+		fakeVCenterManager *commandparserfakes.FakeVCenterManager
+		output             commandparser.VMOutput
+		sourceConfig       config.SourceConfig
+	)
+
+	BeforeEach(func() {
+		factory = &VMVerifierFactory{}
+		fakeVCenterManager = &commandparserfakes.FakeVCenterManager{}
+		output = commandparser.VMOutput{Stdout: new(bytes.Buffer)}
+		sourceConfig = config.SourceConfig{
+			GuestVMUsername: "vmUser",
+			GuestVMPassword: "vmPwd",
+			VmInventoryPath: "/dc/vm/windows2019",
+			Timeout:         time.Hour,
+			KeepClone:       true,
+		}
+	})
+
+	It("returns a Verifier configured from the source config", func() {
+		vmVerifier, err := factory.VMVerifier(context.Background(), sourceConfig, fakeVCenterManager, output)
+		Expect(err).ToNot(HaveOccurred())
+
+		verifier := vmVerifier.(*verify.Verifier)
+		Expect(verifier.Timeout).To(Equal(time.Hour))
+		Expect(verifier.KeepClone).To(BeTrue())
+		_, inventoryPath := fakeVCenterManager.FindVMArgsForCall(0)
+		Expect(inventoryPath).To(Equal("/dc/vm/windows2019"))
+	})
+
+	It("returns a login error when login incorrect to VCenter", func() {
+		fakeVCenterManager.LoginReturns(errors.New("could not log in"))
+
+		vmVerifier, err := factory.VMVerifier(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+		Expect(vmVerifier).To(BeNil())
+		Expect(err).To(MatchError(ContainSubstring("Cannot complete login due to an incorrect vCenter user name or password")))
+		Expect(err).To(MatchError(ContainSubstring("could not log in")))
+	})
+
+	It("returns an error for a negative timeout without logging in", func() {
+		sourceConfig.Timeout = -time.Minute
+
+		_, err := factory.VMVerifier(context.Background(), sourceConfig, fakeVCenterManager, output)
+
+		Expect(err).To(MatchError("timeout must not be negative, got -1m0s"))
+		Expect(fakeVCenterManager.LoginCallCount()).To(Equal(0))
+	})
+
+	Describe("linkedClone", func() {
+		var (
+			source *object.VirtualMachine
+			clone  *linkedClone
+		)
+
+		BeforeEach(func() {
+			source = &object.VirtualMachine{}
+			clone = &linkedClone{
+				ctx:            context.Background(),
+				vCenterManager: fakeVCenterManager,
+				source:         source,
+				sourcePath:     "/dc/vm/windows2019",
+				clonePath:      "/dc/vm/windows2019-verify",
+				username:       "vmUser",
+				password:       "vmPwd",
+			}
+		})
+
+		It("takes the stembuild-verify snapshot of the VM", func() {
+			err := clone.CreateSnapshot()
+			Expect(err).ToNot(HaveOccurred())
+
+			_, vm, name, _ := fakeVCenterManager.CreateSnapshotArgsForCall(0)
+			Expect(vm).To(BeIdenticalTo(source))
+			Expect(name).To(Equal("stembuild-verify"))
+		})
+
+		It("refuses to take a snapshot left over from an earlier verify", func() {
+			fakeVCenterManager.HasSnapshotReturns(true, nil)
+
+			err := clone.CreateSnapshot()
+			Expect(err).To(MatchError(ContainSubstring("/dc/vm/windows2019 already has a snapshot called stembuild-verify")))
+			Expect(fakeVCenterManager.CreateSnapshotCallCount()).To(Equal(0))
+		})
+
+		It("makes a linked clone from the snapshot", func() {
+			cloneVM := &object.VirtualMachine{}
+			fakeVCenterManager.FindVMReturnsOnCall(0, nil, errors.New("vm not found"))
+			fakeVCenterManager.FindVMReturnsOnCall(1, cloneVM, nil)
+
+			err := clone.Clone()
+			Expect(err).ToNot(HaveOccurred())
+
+			_, vm, clonePath, options := fakeVCenterManager.CloneVMArgsForCall(0)
+			Expect(vm).To(BeIdenticalTo(source))
+			Expect(clonePath).To(Equal("/dc/vm/windows2019-verify"))
+			Expect(options).To(Equal(vcenter_manager.CloneOptions{Snapshot: "stembuild-verify"}))
+
+			_, err = clone.GuestManager()
+			Expect(err).ToNot(HaveOccurred())
+			_, _, username, password := fakeVCenterManager.GuestManagerArgsForCall(0)
+			Expect(username).To(Equal("vmUser"))
+			Expect(password).To(Equal("vmPwd"))
+
+			err = clone.Destroy()
+			Expect(err).ToNot(HaveOccurred())
+			_, destroyed := fakeVCenterManager.DestroyVMArgsForCall(0)
+			Expect(destroyed).To(BeIdenticalTo(cloneVM))
+		})
+
+		It("refuses to clone over an existing VM", func() {
+			err := clone.Clone()
+
+			Expect(err).To(MatchError("/dc/vm/windows2019-verify already exists, choose another clone path"))
+			Expect(fakeVCenterManager.CloneVMCallCount()).To(Equal(0))
+		})
+
+		It("removes the snapshot even once verify has been interrupted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			clone.ctx = ctx
+			fakeVCenterManager.RemoveSnapshotCalls(func(ctx context.Context, _ *object.VirtualMachine, _ string) error {
+				return ctx.Err()
+			})
+
+			err := clone.RemoveSnapshot()
+			Expect(err).ToNot(HaveOccurred())
+
+			_, vm, name := fakeVCenterManager.RemoveSnapshotArgsForCall(0)
+			Expect(vm).To(BeIdenticalTo(source))
+			Expect(name).To(Equal("stembuild-verify"))
+		})
+	})
+})
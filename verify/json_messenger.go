@@ -0,0 +1,85 @@
+package verify
+
+import (
+	"github.com/cloudfoundry-incubator/stembuild/events"
+)
+
+const (
+	phaseCreateSnapshot = "create-snapshot"
+	phaseCloneVM        = "clone-vm"
+	phaseWaitForGuest   = "wait-for-guest"
+	phaseRunChecks      = "run-checks"
+	phaseCleanUp        = "clean-up"
+)
+
+// JSONMessenger reports verify progress and the result of every check as events instead of human readable text.
+type JSONMessenger struct {
+	sink events.Sink
+}
+
+func NewJSONMessenger(sink events.Sink) *JSONMessenger {
+	return &JSONMessenger{sink: sink}
+}
+
+func (m *JSONMessenger) emit(phase, event string, status events.Status) {
+	m.sink.Emit(events.Event{Command: "verify", Phase: phase, Event: event, Status: status})
+}
+
+func (m *JSONMessenger) CreateSnapshotStarted(name string) {
+	m.sink.Emit(events.Event{Command: "verify", Phase: phaseCreateSnapshot, Event: "CreateSnapshotStarted", Status: events.Started, Message: name})
+}
+
+func (m *JSONMessenger) CreateSnapshotSucceeded() {
+	m.emit(phaseCreateSnapshot, "CreateSnapshotSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) CloneVMStarted(clonePath string) {
+	m.sink.Emit(events.Event{Command: "verify", Phase: phaseCloneVM, Event: "CloneVMStarted", Status: events.Started, Message: clonePath})
+}
+
+func (m *JSONMessenger) CloneVMSucceeded() {
+	m.emit(phaseCloneVM, "CloneVMSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) WaitForGuestStarted() {
+	m.emit(phaseWaitForGuest, "WaitForGuestStarted", events.Started)
+}
+
+func (m *JSONMessenger) WaitForGuestSucceeded() {
+	m.emit(phaseWaitForGuest, "WaitForGuestSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) RunChecksStarted() {
+	m.emit(phaseRunChecks, "RunChecksStarted", events.Started)
+}
+
+func (m *JSONMessenger) RunChecksSucceeded() {
+	m.emit(phaseRunChecks, "RunChecksSucceeded", events.Succeeded)
+}
+
+// CheckFinished emits a CheckPassed or CheckFailed event targeting the check, so that the run-checks phase
+// itself only fails if the checks could not be run
+func (m *JSONMessenger) CheckFinished(result CheckResult) {
+	event := events.Event{Command: "verify", Phase: phaseRunChecks, Event: "CheckPassed", Status: events.Succeeded, Target: result.Name, Message: result.Detail}
+	if !result.Passed {
+		event.Event = "CheckFailed"
+		event.Status = events.Failed
+	}
+	m.sink.Emit(event)
+}
+
+func (m *JSONMessenger) CleanUpStarted() {
+	m.emit(phaseCleanUp, "CleanUpStarted", events.Started)
+}
+
+func (m *JSONMessenger) CleanUpSucceeded() {
+	m.emit(phaseCleanUp, "CleanUpSucceeded", events.Succeeded)
+}
+
+func (m *JSONMessenger) CleanUpFailed(err error) {
+	m.sink.Emit(events.Event{Command: "verify", Phase: phaseCleanUp, Event: "CleanUpFailed", Status: events.Failed, Error: err.Error()})
+}
+
+func (m *JSONMessenger) CloneKept(clonePath, snapshot string) {
+	m.sink.Emit(events.Event{Command: "verify", Phase: phaseCleanUp, Event: "CloneKept", Status: events.Warning, Target: clonePath, Message: snapshot})
+}
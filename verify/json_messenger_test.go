@@ -0,0 +1,74 @@
+package verify_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/stembuild/events"
+	"github.com/cloudfoundry-incubator/stembuild/events/eventsfakes"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONMessenger", func() {
+	var (
+		sink      *eventsfakes.FakeSink
+		messenger *verify.JSONMessenger
+	)
+
+	BeforeEach(func() {
+		sink = &eventsfakes.FakeSink{}
+		messenger = verify.NewJSONMessenger(sink)
+	})
+
+	It("emits started and succeeded events for each phase", func() {
+		messenger.CloneVMStarted("/dc/vm/windows2019-verify")
+		messenger.CloneVMSucceeded()
+
+		started := sink.EmitArgsForCall(0)
+		Expect(started.Command).To(Equal("verify"))
+		Expect(started.Phase).To(Equal("clone-vm"))
+		Expect(started.Event).To(Equal("CloneVMStarted"))
+		Expect(started.Status).To(Equal(events.Started))
+		Expect(started.Message).To(Equal("/dc/vm/windows2019-verify"))
+		succeeded := sink.EmitArgsForCall(1)
+		Expect(succeeded.Phase).To(Equal("clone-vm"))
+		Expect(succeeded.Status).To(Equal(events.Succeeded))
+	})
+
+	It("emits an event targeting each check with what it found", func() {
+		messenger.CheckFinished(verify.CheckResult{Name: "bosh-agent", Passed: true, Detail: "the bosh-agent service is installed and Stopped"})
+		messenger.CheckFinished(verify.CheckResult{Name: "lgpo", Detail: "no machine policy has been applied"})
+
+		passed := sink.EmitArgsForCall(0)
+		Expect(passed.Phase).To(Equal("run-checks"))
+		Expect(passed.Event).To(Equal("CheckPassed"))
+		Expect(passed.Status).To(Equal(events.Succeeded))
+		Expect(passed.Target).To(Equal("bosh-agent"))
+		Expect(passed.Message).To(Equal("the bosh-agent service is installed and Stopped"))
+		failed := sink.EmitArgsForCall(1)
+		Expect(failed.Event).To(Equal("CheckFailed"))
+		Expect(failed.Status).To(Equal(events.Failed))
+		Expect(failed.Target).To(Equal("lgpo"))
+		Expect(failed.Message).To(Equal("no machine policy has been applied"))
+	})
+
+	It("emits a failed clean-up event with the error", func() {
+		messenger.CleanUpFailed(errors.New("the clone is locked"))
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Phase).To(Equal("clean-up"))
+		Expect(event.Event).To(Equal("CleanUpFailed"))
+		Expect(event.Error).To(Equal("the clone is locked"))
+	})
+
+	It("emits a warning naming the clone and snapshot that were kept", func() {
+		messenger.CloneKept("/dc/vm/windows2019-verify", "stembuild-verify")
+
+		event := sink.EmitArgsForCall(0)
+		Expect(event.Event).To(Equal("CloneKept"))
+		Expect(event.Status).To(Equal(events.Warning))
+		Expect(event.Target).To(Equal("/dc/vm/windows2019-verify"))
+		Expect(event.Message).To(Equal("stembuild-verify"))
+	})
+})
@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"fmt"
+	"io"
+)
+
+type Messenger struct {
+	out io.Writer
+}
+
+func NewMessenger(out io.Writer) *Messenger {
+	return &Messenger{out: out}
+}
+
+func (m *Messenger) CreateSnapshotStarted(name string) {
+	m.out.Write([]byte(fmt.Sprintf("\nTaking snapshot %s of the VM...", name)))
+}
+
+func (m *Messenger) CreateSnapshotSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) CloneVMStarted(clonePath string) {
+	m.out.Write([]byte(fmt.Sprintf("\nMaking a linked clone of the VM at %s...", clonePath)))
+}
+
+func (m *Messenger) CloneVMSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) WaitForGuestStarted() {
+	m.out.Write([]byte("\nWaiting for the clone to boot..."))
+}
+
+func (m *Messenger) WaitForGuestSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) RunChecksStarted() {
+	m.out.Write([]byte("\nRunning the checks on the clone..."))
+}
+
+func (m *Messenger) RunChecksSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) CheckFinished(result CheckResult) {
+	status := "PASS"
+	if !result.Passed {
+		status = "FAIL"
+	}
+	m.out.Write([]byte(fmt.Sprintf("  [%s] %s: %s\n", status, result.Name, result.Detail)))
+}
+
+func (m *Messenger) CleanUpStarted() {
+	m.out.Write([]byte("\nDestroying the clone and removing its snapshot..."))
+}
+
+func (m *Messenger) CleanUpSucceeded() {
+	m.out.Write([]byte("succeeded.\n"))
+}
+
+func (m *Messenger) CleanUpFailed(err error) {
+	m.out.Write([]byte(fmt.Sprintf("failed: %s. Remove them from vCenter before verifying the VM again\n", err)))
+}
+
+func (m *Messenger) CloneKept(clonePath, snapshot string) {
+	m.out.Write([]byte(fmt.Sprintf("\nThe clone %s has been kept. Destroy it, and then remove snapshot %s of the VM, before verifying the VM again\n", clonePath, snapshot)))
+}
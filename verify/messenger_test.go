@@ -0,0 +1,56 @@
+package verify_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Messenger", func() {
+	var (
+		buf       *Buffer
+		messenger *verify.Messenger
+	)
+
+	BeforeEach(func() {
+		buf = NewBuffer()
+		messenger = verify.NewMessenger(buf)
+	})
+
+	It("reports the clone being made and booted", func() {
+		messenger.CreateSnapshotStarted("stembuild-verify")
+		messenger.CreateSnapshotSucceeded()
+		messenger.CloneVMStarted("/dc/vm/windows2019-verify")
+		messenger.CloneVMSucceeded()
+		messenger.WaitForGuestStarted()
+		messenger.WaitForGuestSucceeded()
+
+		Expect(buf).To(Say(`Taking snapshot stembuild-verify of the VM\.\.\.succeeded\.`))
+		Expect(buf).To(Say(`Making a linked clone of the VM at /dc/vm/windows2019-verify\.\.\.succeeded\.`))
+		Expect(buf).To(Say(`Waiting for the clone to boot\.\.\.succeeded\.`))
+	})
+
+	It("marks each check as passed or failed", func() {
+		messenger.CheckFinished(verify.CheckResult{Name: "bosh-agent", Passed: true, Detail: "the bosh-agent service is installed and Stopped"})
+		messenger.CheckFinished(verify.CheckResult{Name: "openssh", Detail: "Cannot find any service with service name 'sshd'."})
+
+		Expect(buf).To(Say(`\[PASS\] bosh-agent: the bosh-agent service is installed and Stopped`))
+		Expect(buf).To(Say(`\[FAIL\] openssh: Cannot find any service with service name 'sshd'\.`))
+	})
+
+	It("explains what is left behind if cleaning up fails", func() {
+		messenger.CleanUpStarted()
+		messenger.CleanUpFailed(errors.New("unable to destroy /dc/vm/windows2019-verify: the clone is locked"))
+
+		Expect(buf).To(Say(`Destroying the clone and removing its snapshot\.\.\.failed: unable to destroy /dc/vm/windows2019-verify: the clone is locked\. Remove them from vCenter before verifying the VM again`))
+	})
+
+	It("explains how to remove a clone that was kept", func() {
+		messenger.CloneKept("/dc/vm/windows2019-verify", "stembuild-verify")
+
+		Expect(buf).To(Say(`The clone /dc/vm/windows2019-verify has been kept\. Destroy it, and then remove snapshot stembuild-verify of the VM, before verifying the VM again`))
+	})
+})
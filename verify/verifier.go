@@ -0,0 +1,229 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/construct"
+	"github.com/cloudfoundry-incubator/stembuild/poller"
+)
+
+// SnapshotName is the snapshot of the constructed VM that the linked clone is made from
+const SnapshotName = "stembuild-verify"
+
+// CheckResult is the outcome of one check of the constructed VM, with what was found in Detail
+type CheckResult struct {
+	Name   string
+	Passed bool
+	Detail string
+}
+
+// Report lists the result of every check of the VM at VmInventoryPath
+type Report struct {
+	VmInventoryPath string
+	Results         []CheckResult
+}
+
+// Passed reports whether the report has results and every one of them passed
+func (r Report) Passed() bool {
+	return len(r.Results) > 0 && len(r.Failed()) == 0
+}
+
+// Failed gives the names of the checks that failed
+func (r Report) Failed() []string {
+	var failed []string
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result.Name)
+		}
+	}
+	return failed
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . LinkedClone
+type LinkedClone interface {
+	CreateSnapshot() error
+	Clone() error
+	GuestManager() (GuestManager, error)
+	GuestHeartbeatIsGreen() (bool, error)
+	Destroy() error
+	RemoveSnapshot() error
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . GuestManager
+type GuestManager interface {
+	StartProgramInGuest(ctx context.Context, command, args string) (int64, error)
+	ExitCodeForProgramInGuest(ctx context.Context, pid int64) (int32, error)
+	DownloadFileInGuest(ctx context.Context, path string) (io.Reader, int64, error)
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . VerifyMessenger
+type VerifyMessenger interface {
+	CreateSnapshotStarted(name string)
+	CreateSnapshotSucceeded()
+	CloneVMStarted(clonePath string)
+	CloneVMSucceeded()
+	WaitForGuestStarted()
+	WaitForGuestSucceeded()
+	RunChecksStarted()
+	RunChecksSucceeded()
+	CheckFinished(result CheckResult)
+	CleanUpStarted()
+	CleanUpSucceeded()
+	CleanUpFailed(err error)
+	CloneKept(clonePath, snapshot string)
+}
+
+// Verifier checks a constructed VM without changing it: the VM is powered off after sysprep, so the checks
+// run in a linked clone booted from a snapshot of it, which is destroyed along with the snapshot afterwards.
+type Verifier struct {
+	ctx             context.Context
+	clone           LinkedClone
+	vmInventoryPath string
+	clonePath       string
+	messenger       VerifyMessenger
+	poller          poller.PollerI
+	// Timeout bounds the wait for the clone to boot, and then the wait for the checks to run, 0 for no limit
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// KeepClone leaves the clone and its snapshot in place, to look into a failed check
+	KeepClone bool
+}
+
+func NewVerifier(ctx context.Context, clone LinkedClone, vmInventoryPath, clonePath string, messenger VerifyMessenger, poller poller.PollerI) *Verifier {
+	return &Verifier{
+		ctx:             ctx,
+		clone:           clone,
+		vmInventoryPath: vmInventoryPath,
+		clonePath:       clonePath,
+		messenger:       messenger,
+		poller:          poller,
+		PollInterval:    10 * time.Second,
+	}
+}
+
+// Verify boots a linked clone of the VM and runs every check in it. A check that fails is reported,
+// not returned as an error: an error means the checks could not be run, or the clone not cleaned up.
+func (v *Verifier) Verify() (report Report, err error) {
+	report = Report{VmInventoryPath: v.vmInventoryPath}
+
+	v.messenger.CreateSnapshotStarted(SnapshotName)
+	err = v.clone.CreateSnapshot()
+	if err != nil {
+		return report, fmt.Errorf("unable to snapshot %s: %s", v.vmInventoryPath, err)
+	}
+	v.messenger.CreateSnapshotSucceeded()
+
+	cloned := false
+	defer func() {
+		err = v.cleanUp(cloned, err)
+	}()
+
+	v.messenger.CloneVMStarted(v.clonePath)
+	err = v.clone.Clone()
+	if err != nil {
+		return report, fmt.Errorf("unable to make a linked clone of %s at %s: %s", v.vmInventoryPath, v.clonePath, err)
+	}
+	cloned = true
+	v.messenger.CloneVMSucceeded()
+
+	guestManager, err := v.clone.GuestManager()
+	if err != nil {
+		return report, fmt.Errorf("unable to use guest operations on %s: %s", v.clonePath, err)
+	}
+
+	v.messenger.WaitForGuestStarted()
+	err = v.poller.PollWithOptions(v.ctx, poller.Options{Interval: v.PollInterval, Timeout: v.Timeout}, v.clone.GuestHeartbeatIsGreen)
+	if err != nil {
+		return report, fmt.Errorf("%s did not boot: %s", v.clonePath, err)
+	}
+	v.messenger.WaitForGuestSucceeded()
+
+	v.messenger.RunChecksStarted()
+	var results []CheckResult
+	// Guest operations fail until the clone has finished the first boot after sysprep, even once VMware Tools is up
+	retryable := func(error) bool { return v.ctx.Err() == nil }
+	err = v.poller.PollWithOptions(v.ctx, poller.Options{Interval: v.PollInterval, Timeout: v.Timeout, Retryable: retryable}, func() (bool, error) {
+		var err error
+		results, err = v.runChecks(guestManager)
+		return err == nil, err
+	})
+	if err != nil {
+		return report, fmt.Errorf("unable to run the checks on %s: %s", v.clonePath, err)
+	}
+	v.messenger.RunChecksSucceeded()
+
+	for _, result := range results {
+		v.messenger.CheckFinished(result)
+	}
+	report.Results = results
+	return report, nil
+}
+
+func (v *Verifier) runChecks(guestManager GuestManager) ([]CheckResult, error) {
+	pid, err := guestManager.StartProgramInGuest(v.ctx, powershell, "-EncodedCommand "+construct.EncodePowershellCommand([]byte(checkScript())))
+	if err != nil {
+		return nil, err
+	}
+	exitCode, err := guestManager.ExitCodeForProgramInGuest(v.ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	if exitCode != 0 {
+		return nil, fmt.Errorf("the checks exited with code %d", exitCode)
+	}
+
+	reader, _, err := guestManager.DownloadFileInGuest(v.ctx, checksResult)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", checksResult, err)
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", checksResult, err)
+	}
+	return parseCheckResults(contents)
+}
+
+// cleanUp destroys the clone, if it was made, and then the snapshot it was made from, unless KeepClone is set.
+// A linked clone needs its snapshot, so the snapshot is kept if the clone cannot be destroyed.
+// An error cleaning up is returned unless verify has already failed with verifyErr.
+func (v *Verifier) cleanUp(cloned bool, verifyErr error) error {
+	if v.KeepClone && cloned {
+		v.messenger.CloneKept(v.clonePath, SnapshotName)
+		return verifyErr
+	}
+
+	v.messenger.CleanUpStarted()
+	var failures []string
+	if cloned {
+		err := v.clone.Destroy()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("unable to destroy %s: %s", v.clonePath, err))
+		}
+	}
+	if len(failures) == 0 {
+		err := v.clone.RemoveSnapshot()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("unable to remove snapshot %s of %s: %s", SnapshotName, v.vmInventoryPath, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		err := errors.New(strings.Join(failures, "; "))
+		v.messenger.CleanUpFailed(err)
+		if verifyErr != nil {
+			return verifyErr
+		}
+		return err
+	}
+	v.messenger.CleanUpSucceeded()
+	return verifyErr
+}
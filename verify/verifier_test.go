@@ -0,0 +1,237 @@
+package verify_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/stembuild/poller"
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+	"github.com/cloudfoundry-incubator/stembuild/verify/verifyfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verifier", func() {
+	var (
+		fakeClone        *verifyfakes.FakeLinkedClone
+		fakeGuestManager *verifyfakes.FakeGuestManager
+		fakeMessenger    *verifyfakes.FakeVerifyMessenger
+		verifier         *verify.Verifier
+		results          []verify.CheckResult
+	)
+
+	resultsFile := func(results []verify.CheckResult) io.Reader {
+		contents, err := json.Marshal(results)
+		Expect(err).NotTo(HaveOccurred())
+		return strings.NewReader("\xef\xbb\xbf" + string(contents))
+	}
+
+	BeforeEach(func() {
+		fakeClone = &verifyfakes.FakeLinkedClone{}
+		fakeGuestManager = &verifyfakes.FakeGuestManager{}
+		fakeMessenger = &verifyfakes.FakeVerifyMessenger{}
+
+		fakeClone.GuestManagerReturns(fakeGuestManager, nil)
+		fakeClone.GuestHeartbeatIsGreenReturns(true, nil)
+
+		results = nil
+		for _, name := range verify.CheckNames() {
+			results = append(results, verify.CheckResult{Name: name, Passed: true, Detail: name + " is fine"})
+		}
+		fakeGuestManager.DownloadFileInGuestCalls(func(context.Context, string) (io.Reader, int64, error) {
+			return resultsFile(results), 0, nil
+		})
+
+		verifier = verify.NewVerifier(context.Background(), fakeClone, "/dc/vm/windows2019", "/dc/vm/windows2019-verify", fakeMessenger, &poller.Poller{})
+		verifier.PollInterval = time.Millisecond
+		verifier.Timeout = time.Second
+	})
+
+	It("runs the checks in a linked clone and reports every result", func() {
+		report, err := verifier.Verify()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(report.VmInventoryPath).To(Equal("/dc/vm/windows2019"))
+		Expect(report.Results).To(Equal(results))
+		Expect(report.Passed()).To(BeTrue())
+		Expect(fakeMessenger.CheckFinishedCallCount()).To(Equal(len(results)))
+		Expect(fakeMessenger.CheckFinishedArgsForCall(0)).To(Equal(results[0]))
+
+		Expect(fakeClone.CreateSnapshotCallCount()).To(Equal(1))
+		Expect(fakeClone.CloneCallCount()).To(Equal(1))
+		Expect(fakeMessenger.CloneVMStartedArgsForCall(0)).To(Equal("/dc/vm/windows2019-verify"))
+
+		_, command, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+		Expect(command).To(HaveSuffix("powershell.exe"))
+		Expect(args).To(HavePrefix("-EncodedCommand "))
+		_, path := fakeGuestManager.DownloadFileInGuestArgsForCall(0)
+		Expect(path).To(Equal("C:\\Windows\\Temp\\stembuild-verify.json"))
+	})
+
+	It("runs every check in a single script", func() {
+		_, err := verifier.Verify()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, args := fakeGuestManager.StartProgramInGuestArgsForCall(0)
+		encoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(args, "-EncodedCommand "))
+		Expect(err).NotTo(HaveOccurred())
+		// the script is encoded as UTF-16LE
+		script := strings.Replace(string(encoded), "\x00", "", -1)
+		Expect(script).To(Equal(verify.CheckScript()))
+		for _, name := range verify.CheckNames() {
+			Expect(script).To(ContainSubstring("Name = '%s'", name))
+		}
+	})
+
+	It("destroys the clone and then removes the snapshot", func() {
+		var cleanedUp []string
+		fakeClone.DestroyCalls(func() error {
+			cleanedUp = append(cleanedUp, "clone")
+			return nil
+		})
+		fakeClone.RemoveSnapshotCalls(func() error {
+			cleanedUp = append(cleanedUp, "snapshot")
+			return nil
+		})
+
+		_, err := verifier.Verify()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cleanedUp).To(Equal([]string{"clone", "snapshot"}))
+		Expect(fakeMessenger.CleanUpSucceededCallCount()).To(Equal(1))
+	})
+
+	It("reports failed checks without returning an error", func() {
+		results[1].Passed = false
+		results[3].Passed = false
+
+		report, err := verifier.Verify()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(report.Passed()).To(BeFalse())
+		Expect(report.Failed()).To(Equal([]string{results[1].Name, results[3].Name}))
+	})
+
+	It("keeps the clone and its snapshot with KeepClone", func() {
+		verifier.KeepClone = true
+
+		_, err := verifier.Verify()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClone.DestroyCallCount()).To(Equal(0))
+		Expect(fakeClone.RemoveSnapshotCallCount()).To(Equal(0))
+		clonePath, snapshot := fakeMessenger.CloneKeptArgsForCall(0)
+		Expect(clonePath).To(Equal("/dc/vm/windows2019-verify"))
+		Expect(snapshot).To(Equal(verify.SnapshotName))
+	})
+
+	It("waits for the clone to boot before running the checks", func() {
+		heartbeats := 0
+		fakeClone.GuestHeartbeatIsGreenCalls(func() (bool, error) {
+			heartbeats++
+			Expect(fakeGuestManager.StartProgramInGuestCallCount()).To(Equal(0))
+			return heartbeats == 3, nil
+		})
+
+		_, err := verifier.Verify()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(heartbeats).To(Equal(3))
+		Expect(fakeMessenger.WaitForGuestSucceededCallCount()).To(Equal(1))
+	})
+
+	It("retries the checks until guest operations work on the clone", func() {
+		fakeGuestManager.StartProgramInGuestReturnsOnCall(0, 0, errors.New("guest operations agent could not be contacted"))
+		fakeGuestManager.StartProgramInGuestReturnsOnCall(1, 0, errors.New("failed to authenticate"))
+
+		report, err := verifier.Verify()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeGuestManager.StartProgramInGuestCallCount()).To(Equal(3))
+		Expect(report.Passed()).To(BeTrue())
+	})
+
+	It("fails and cleans up if the clone does not boot in time", func() {
+		fakeClone.GuestHeartbeatIsGreenReturns(false, nil)
+		verifier.Timeout = 10 * time.Millisecond
+
+		_, err := verifier.Verify()
+		Expect(err).To(MatchError(ContainSubstring("/dc/vm/windows2019-verify did not boot: did not finish within 10ms")))
+
+		Expect(fakeGuestManager.StartProgramInGuestCallCount()).To(Equal(0))
+		Expect(fakeClone.DestroyCallCount()).To(Equal(1))
+		Expect(fakeClone.RemoveSnapshotCallCount()).To(Equal(1))
+	})
+
+	It("fails if the check script exits non-zero", func() {
+		fakeGuestManager.ExitCodeForProgramInGuestReturns(1, nil)
+		verifier.Timeout = 10 * time.Millisecond
+
+		_, err := verifier.Verify()
+		Expect(err).To(MatchError(ContainSubstring("unable to run the checks on /dc/vm/windows2019-verify")))
+		Expect(err).To(MatchError(ContainSubstring("the checks exited with code 1")))
+	})
+
+	It("fails if the results are missing a check", func() {
+		results = results[1:]
+		verifier.Timeout = 10 * time.Millisecond
+
+		_, err := verifier.Verify()
+		Expect(err).To(MatchError(ContainSubstring("C:\\Windows\\Temp\\stembuild-verify.json has 4 results, expected 5")))
+	})
+
+	It("fails without cleaning up if the snapshot cannot be taken", func() {
+		fakeClone.CreateSnapshotReturns(errors.New("snapshot already exists"))
+
+		_, err := verifier.Verify()
+		Expect(err).To(MatchError("unable to snapshot /dc/vm/windows2019: snapshot already exists"))
+
+		Expect(fakeClone.CloneCallCount()).To(Equal(0))
+		Expect(fakeClone.RemoveSnapshotCallCount()).To(Equal(0))
+	})
+
+	It("removes the snapshot if the clone cannot be made", func() {
+		fakeClone.CloneReturns(errors.New("insufficient disk space"))
+
+		_, err := verifier.Verify()
+		Expect(err).To(MatchError("unable to make a linked clone of /dc/vm/windows2019 at /dc/vm/windows2019-verify: insufficient disk space"))
+
+		Expect(fakeClone.DestroyCallCount()).To(Equal(0))
+		Expect(fakeClone.RemoveSnapshotCallCount()).To(Equal(1))
+	})
+
+	It("destroys the clone if no guest manager can be made for it", func() {
+		fakeClone.GuestManagerReturns(nil, errors.New("guest operations are not enabled"))
+
+		_, err := verifier.Verify()
+		Expect(err).To(MatchError("unable to use guest operations on /dc/vm/windows2019-verify: guest operations are not enabled"))
+
+		Expect(fakeClone.DestroyCallCount()).To(Equal(1))
+		Expect(fakeClone.RemoveSnapshotCallCount()).To(Equal(1))
+	})
+
+	It("keeps the snapshot and fails if the clone cannot be destroyed", func() {
+		fakeClone.DestroyReturns(errors.New("the clone is locked"))
+
+		report, err := verifier.Verify()
+		Expect(err).To(MatchError("unable to destroy /dc/vm/windows2019-verify: the clone is locked"))
+
+		Expect(report.Passed()).To(BeTrue())
+		Expect(fakeClone.RemoveSnapshotCallCount()).To(Equal(0))
+		Expect(fakeMessenger.CleanUpFailedCallCount()).To(Equal(1))
+	})
+
+	It("returns the verify error rather than a cleanup error", func() {
+		fakeClone.GuestHeartbeatIsGreenReturns(false, errors.New("vCenter went away"))
+		fakeClone.DestroyReturns(errors.New("vCenter went away"))
+
+		_, err := verifier.Verify()
+		Expect(err).To(MatchError("/dc/vm/windows2019-verify did not boot: vCenter went away"))
+		Expect(fakeMessenger.CleanUpFailedArgsForCall(0)).To(MatchError("unable to destroy /dc/vm/windows2019-verify: vCenter went away"))
+	})
+})
@@ -0,0 +1,437 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package verifyfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+)
+
+type FakeLinkedClone struct {
+	CloneStub        func() error
+	cloneMutex       sync.RWMutex
+	cloneArgsForCall []struct {
+	}
+	cloneReturns struct {
+		result1 error
+	}
+	cloneReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateSnapshotStub        func() error
+	createSnapshotMutex       sync.RWMutex
+	createSnapshotArgsForCall []struct {
+	}
+	createSnapshotReturns struct {
+		result1 error
+	}
+	createSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DestroyStub        func() error
+	destroyMutex       sync.RWMutex
+	destroyArgsForCall []struct {
+	}
+	destroyReturns struct {
+		result1 error
+	}
+	destroyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GuestHeartbeatIsGreenStub        func() (bool, error)
+	guestHeartbeatIsGreenMutex       sync.RWMutex
+	guestHeartbeatIsGreenArgsForCall []struct {
+	}
+	guestHeartbeatIsGreenReturns struct {
+		result1 bool
+		result2 error
+	}
+	guestHeartbeatIsGreenReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	GuestManagerStub        func() (verify.GuestManager, error)
+	guestManagerMutex       sync.RWMutex
+	guestManagerArgsForCall []struct {
+	}
+	guestManagerReturns struct {
+		result1 verify.GuestManager
+		result2 error
+	}
+	guestManagerReturnsOnCall map[int]struct {
+		result1 verify.GuestManager
+		result2 error
+	}
+	RemoveSnapshotStub        func() error
+	removeSnapshotMutex       sync.RWMutex
+	removeSnapshotArgsForCall []struct {
+	}
+	removeSnapshotReturns struct {
+		result1 error
+	}
+	removeSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeLinkedClone) Clone() error {
+	fake.cloneMutex.Lock()
+	ret, specificReturn := fake.cloneReturnsOnCall[len(fake.cloneArgsForCall)]
+	fake.cloneArgsForCall = append(fake.cloneArgsForCall, struct {
+	}{})
+	stub := fake.CloneStub
+	fakeReturns := fake.cloneReturns
+	fake.recordInvocation("Clone", []interface{}{})
+	fake.cloneMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLinkedClone) CloneCallCount() int {
+	fake.cloneMutex.RLock()
+	defer fake.cloneMutex.RUnlock()
+	return len(fake.cloneArgsForCall)
+}
+
+func (fake *FakeLinkedClone) CloneCalls(stub func() error) {
+	fake.cloneMutex.Lock()
+	defer fake.cloneMutex.Unlock()
+	fake.CloneStub = stub
+}
+
+func (fake *FakeLinkedClone) CloneReturns(result1 error) {
+	fake.cloneMutex.Lock()
+	defer fake.cloneMutex.Unlock()
+	fake.CloneStub = nil
+	fake.cloneReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) CloneReturnsOnCall(i int, result1 error) {
+	fake.cloneMutex.Lock()
+	defer fake.cloneMutex.Unlock()
+	fake.CloneStub = nil
+	if fake.cloneReturnsOnCall == nil {
+		fake.cloneReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cloneReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) CreateSnapshot() error {
+	fake.createSnapshotMutex.Lock()
+	ret, specificReturn := fake.createSnapshotReturnsOnCall[len(fake.createSnapshotArgsForCall)]
+	fake.createSnapshotArgsForCall = append(fake.createSnapshotArgsForCall, struct {
+	}{})
+	stub := fake.CreateSnapshotStub
+	fakeReturns := fake.createSnapshotReturns
+	fake.recordInvocation("CreateSnapshot", []interface{}{})
+	fake.createSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLinkedClone) CreateSnapshotCallCount() int {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	return len(fake.createSnapshotArgsForCall)
+}
+
+func (fake *FakeLinkedClone) CreateSnapshotCalls(stub func() error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = stub
+}
+
+func (fake *FakeLinkedClone) CreateSnapshotReturns(result1 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	fake.createSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) CreateSnapshotReturnsOnCall(i int, result1 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	if fake.createSnapshotReturnsOnCall == nil {
+		fake.createSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) Destroy() error {
+	fake.destroyMutex.Lock()
+	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
+	fake.destroyArgsForCall = append(fake.destroyArgsForCall, struct {
+	}{})
+	stub := fake.DestroyStub
+	fakeReturns := fake.destroyReturns
+	fake.recordInvocation("Destroy", []interface{}{})
+	fake.destroyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLinkedClone) DestroyCallCount() int {
+	fake.destroyMutex.RLock()
+	defer fake.destroyMutex.RUnlock()
+	return len(fake.destroyArgsForCall)
+}
+
+func (fake *FakeLinkedClone) DestroyCalls(stub func() error) {
+	fake.destroyMutex.Lock()
+	defer fake.destroyMutex.Unlock()
+	fake.DestroyStub = stub
+}
+
+func (fake *FakeLinkedClone) DestroyReturns(result1 error) {
+	fake.destroyMutex.Lock()
+	defer fake.destroyMutex.Unlock()
+	fake.DestroyStub = nil
+	fake.destroyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) DestroyReturnsOnCall(i int, result1 error) {
+	fake.destroyMutex.Lock()
+	defer fake.destroyMutex.Unlock()
+	fake.DestroyStub = nil
+	if fake.destroyReturnsOnCall == nil {
+		fake.destroyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.destroyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) GuestHeartbeatIsGreen() (bool, error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	ret, specificReturn := fake.guestHeartbeatIsGreenReturnsOnCall[len(fake.guestHeartbeatIsGreenArgsForCall)]
+	fake.guestHeartbeatIsGreenArgsForCall = append(fake.guestHeartbeatIsGreenArgsForCall, struct {
+	}{})
+	stub := fake.GuestHeartbeatIsGreenStub
+	fakeReturns := fake.guestHeartbeatIsGreenReturns
+	fake.recordInvocation("GuestHeartbeatIsGreen", []interface{}{})
+	fake.guestHeartbeatIsGreenMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLinkedClone) GuestHeartbeatIsGreenCallCount() int {
+	fake.guestHeartbeatIsGreenMutex.RLock()
+	defer fake.guestHeartbeatIsGreenMutex.RUnlock()
+	return len(fake.guestHeartbeatIsGreenArgsForCall)
+}
+
+func (fake *FakeLinkedClone) GuestHeartbeatIsGreenCalls(stub func() (bool, error)) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = stub
+}
+
+func (fake *FakeLinkedClone) GuestHeartbeatIsGreenReturns(result1 bool, result2 error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = nil
+	fake.guestHeartbeatIsGreenReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLinkedClone) GuestHeartbeatIsGreenReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.guestHeartbeatIsGreenMutex.Lock()
+	defer fake.guestHeartbeatIsGreenMutex.Unlock()
+	fake.GuestHeartbeatIsGreenStub = nil
+	if fake.guestHeartbeatIsGreenReturnsOnCall == nil {
+		fake.guestHeartbeatIsGreenReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.guestHeartbeatIsGreenReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLinkedClone) GuestManager() (verify.GuestManager, error) {
+	fake.guestManagerMutex.Lock()
+	ret, specificReturn := fake.guestManagerReturnsOnCall[len(fake.guestManagerArgsForCall)]
+	fake.guestManagerArgsForCall = append(fake.guestManagerArgsForCall, struct {
+	}{})
+	stub := fake.GuestManagerStub
+	fakeReturns := fake.guestManagerReturns
+	fake.recordInvocation("GuestManager", []interface{}{})
+	fake.guestManagerMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLinkedClone) GuestManagerCallCount() int {
+	fake.guestManagerMutex.RLock()
+	defer fake.guestManagerMutex.RUnlock()
+	return len(fake.guestManagerArgsForCall)
+}
+
+func (fake *FakeLinkedClone) GuestManagerCalls(stub func() (verify.GuestManager, error)) {
+	fake.guestManagerMutex.Lock()
+	defer fake.guestManagerMutex.Unlock()
+	fake.GuestManagerStub = stub
+}
+
+func (fake *FakeLinkedClone) GuestManagerReturns(result1 verify.GuestManager, result2 error) {
+	fake.guestManagerMutex.Lock()
+	defer fake.guestManagerMutex.Unlock()
+	fake.GuestManagerStub = nil
+	fake.guestManagerReturns = struct {
+		result1 verify.GuestManager
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLinkedClone) GuestManagerReturnsOnCall(i int, result1 verify.GuestManager, result2 error) {
+	fake.guestManagerMutex.Lock()
+	defer fake.guestManagerMutex.Unlock()
+	fake.GuestManagerStub = nil
+	if fake.guestManagerReturnsOnCall == nil {
+		fake.guestManagerReturnsOnCall = make(map[int]struct {
+			result1 verify.GuestManager
+			result2 error
+		})
+	}
+	fake.guestManagerReturnsOnCall[i] = struct {
+		result1 verify.GuestManager
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLinkedClone) RemoveSnapshot() error {
+	fake.removeSnapshotMutex.Lock()
+	ret, specificReturn := fake.removeSnapshotReturnsOnCall[len(fake.removeSnapshotArgsForCall)]
+	fake.removeSnapshotArgsForCall = append(fake.removeSnapshotArgsForCall, struct {
+	}{})
+	stub := fake.RemoveSnapshotStub
+	fakeReturns := fake.removeSnapshotReturns
+	fake.recordInvocation("RemoveSnapshot", []interface{}{})
+	fake.removeSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLinkedClone) RemoveSnapshotCallCount() int {
+	fake.removeSnapshotMutex.RLock()
+	defer fake.removeSnapshotMutex.RUnlock()
+	return len(fake.removeSnapshotArgsForCall)
+}
+
+func (fake *FakeLinkedClone) RemoveSnapshotCalls(stub func() error) {
+	fake.removeSnapshotMutex.Lock()
+	defer fake.removeSnapshotMutex.Unlock()
+	fake.RemoveSnapshotStub = stub
+}
+
+func (fake *FakeLinkedClone) RemoveSnapshotReturns(result1 error) {
+	fake.removeSnapshotMutex.Lock()
+	defer fake.removeSnapshotMutex.Unlock()
+	fake.RemoveSnapshotStub = nil
+	fake.removeSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) RemoveSnapshotReturnsOnCall(i int, result1 error) {
+	fake.removeSnapshotMutex.Lock()
+	defer fake.removeSnapshotMutex.Unlock()
+	fake.RemoveSnapshotStub = nil
+	if fake.removeSnapshotReturnsOnCall == nil {
+		fake.removeSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLinkedClone) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.cloneMutex.RLock()
+	defer fake.cloneMutex.RUnlock()
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	fake.destroyMutex.RLock()
+	defer fake.destroyMutex.RUnlock()
+	fake.guestHeartbeatIsGreenMutex.RLock()
+	defer fake.guestHeartbeatIsGreenMutex.RUnlock()
+	fake.guestManagerMutex.RLock()
+	defer fake.guestManagerMutex.RUnlock()
+	fake.removeSnapshotMutex.RLock()
+	defer fake.removeSnapshotMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeLinkedClone) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ verify.LinkedClone = new(FakeLinkedClone)
@@ -0,0 +1,474 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package verifyfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/stembuild/verify"
+)
+
+type FakeVerifyMessenger struct {
+	CheckFinishedStub        func(verify.CheckResult)
+	checkFinishedMutex       sync.RWMutex
+	checkFinishedArgsForCall []struct {
+		arg1 verify.CheckResult
+	}
+	CleanUpFailedStub        func(error)
+	cleanUpFailedMutex       sync.RWMutex
+	cleanUpFailedArgsForCall []struct {
+		arg1 error
+	}
+	CleanUpStartedStub        func()
+	cleanUpStartedMutex       sync.RWMutex
+	cleanUpStartedArgsForCall []struct {
+	}
+	CleanUpSucceededStub        func()
+	cleanUpSucceededMutex       sync.RWMutex
+	cleanUpSucceededArgsForCall []struct {
+	}
+	CloneKeptStub        func(string, string)
+	cloneKeptMutex       sync.RWMutex
+	cloneKeptArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	CloneVMStartedStub        func(string)
+	cloneVMStartedMutex       sync.RWMutex
+	cloneVMStartedArgsForCall []struct {
+		arg1 string
+	}
+	CloneVMSucceededStub        func()
+	cloneVMSucceededMutex       sync.RWMutex
+	cloneVMSucceededArgsForCall []struct {
+	}
+	CreateSnapshotStartedStub        func(string)
+	createSnapshotStartedMutex       sync.RWMutex
+	createSnapshotStartedArgsForCall []struct {
+		arg1 string
+	}
+	CreateSnapshotSucceededStub        func()
+	createSnapshotSucceededMutex       sync.RWMutex
+	createSnapshotSucceededArgsForCall []struct {
+	}
+	RunChecksStartedStub        func()
+	runChecksStartedMutex       sync.RWMutex
+	runChecksStartedArgsForCall []struct {
+	}
+	RunChecksSucceededStub        func()
+	runChecksSucceededMutex       sync.RWMutex
+	runChecksSucceededArgsForCall []struct {
+	}
+	WaitForGuestStartedStub        func()
+	waitForGuestStartedMutex       sync.RWMutex
+	waitForGuestStartedArgsForCall []struct {
+	}
+	WaitForGuestSucceededStub        func()
+	waitForGuestSucceededMutex       sync.RWMutex
+	waitForGuestSucceededArgsForCall []struct {
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeVerifyMessenger) CheckFinished(arg1 verify.CheckResult) {
+	fake.checkFinishedMutex.Lock()
+	fake.checkFinishedArgsForCall = append(fake.checkFinishedArgsForCall, struct {
+		arg1 verify.CheckResult
+	}{arg1})
+	stub := fake.CheckFinishedStub
+	fake.recordInvocation("CheckFinished", []interface{}{arg1})
+	fake.checkFinishedMutex.Unlock()
+	if stub != nil {
+		fake.CheckFinishedStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) CheckFinishedCallCount() int {
+	fake.checkFinishedMutex.RLock()
+	defer fake.checkFinishedMutex.RUnlock()
+	return len(fake.checkFinishedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CheckFinishedCalls(stub func(verify.CheckResult)) {
+	fake.checkFinishedMutex.Lock()
+	defer fake.checkFinishedMutex.Unlock()
+	fake.CheckFinishedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CheckFinishedArgsForCall(i int) verify.CheckResult {
+	fake.checkFinishedMutex.RLock()
+	defer fake.checkFinishedMutex.RUnlock()
+	argsForCall := fake.checkFinishedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) CleanUpFailed(arg1 error) {
+	fake.cleanUpFailedMutex.Lock()
+	fake.cleanUpFailedArgsForCall = append(fake.cleanUpFailedArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.CleanUpFailedStub
+	fake.recordInvocation("CleanUpFailed", []interface{}{arg1})
+	fake.cleanUpFailedMutex.Unlock()
+	if stub != nil {
+		fake.CleanUpFailedStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) CleanUpFailedCallCount() int {
+	fake.cleanUpFailedMutex.RLock()
+	defer fake.cleanUpFailedMutex.RUnlock()
+	return len(fake.cleanUpFailedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CleanUpFailedCalls(stub func(error)) {
+	fake.cleanUpFailedMutex.Lock()
+	defer fake.cleanUpFailedMutex.Unlock()
+	fake.CleanUpFailedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CleanUpFailedArgsForCall(i int) error {
+	fake.cleanUpFailedMutex.RLock()
+	defer fake.cleanUpFailedMutex.RUnlock()
+	argsForCall := fake.cleanUpFailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) CleanUpStarted() {
+	fake.cleanUpStartedMutex.Lock()
+	fake.cleanUpStartedArgsForCall = append(fake.cleanUpStartedArgsForCall, struct {
+	}{})
+	stub := fake.CleanUpStartedStub
+	fake.recordInvocation("CleanUpStarted", []interface{}{})
+	fake.cleanUpStartedMutex.Unlock()
+	if stub != nil {
+		fake.CleanUpStartedStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) CleanUpStartedCallCount() int {
+	fake.cleanUpStartedMutex.RLock()
+	defer fake.cleanUpStartedMutex.RUnlock()
+	return len(fake.cleanUpStartedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CleanUpStartedCalls(stub func()) {
+	fake.cleanUpStartedMutex.Lock()
+	defer fake.cleanUpStartedMutex.Unlock()
+	fake.CleanUpStartedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CleanUpSucceeded() {
+	fake.cleanUpSucceededMutex.Lock()
+	fake.cleanUpSucceededArgsForCall = append(fake.cleanUpSucceededArgsForCall, struct {
+	}{})
+	stub := fake.CleanUpSucceededStub
+	fake.recordInvocation("CleanUpSucceeded", []interface{}{})
+	fake.cleanUpSucceededMutex.Unlock()
+	if stub != nil {
+		fake.CleanUpSucceededStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) CleanUpSucceededCallCount() int {
+	fake.cleanUpSucceededMutex.RLock()
+	defer fake.cleanUpSucceededMutex.RUnlock()
+	return len(fake.cleanUpSucceededArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CleanUpSucceededCalls(stub func()) {
+	fake.cleanUpSucceededMutex.Lock()
+	defer fake.cleanUpSucceededMutex.Unlock()
+	fake.CleanUpSucceededStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CloneKept(arg1 string, arg2 string) {
+	fake.cloneKeptMutex.Lock()
+	fake.cloneKeptArgsForCall = append(fake.cloneKeptArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CloneKeptStub
+	fake.recordInvocation("CloneKept", []interface{}{arg1, arg2})
+	fake.cloneKeptMutex.Unlock()
+	if stub != nil {
+		fake.CloneKeptStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeVerifyMessenger) CloneKeptCallCount() int {
+	fake.cloneKeptMutex.RLock()
+	defer fake.cloneKeptMutex.RUnlock()
+	return len(fake.cloneKeptArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CloneKeptCalls(stub func(string, string)) {
+	fake.cloneKeptMutex.Lock()
+	defer fake.cloneKeptMutex.Unlock()
+	fake.CloneKeptStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CloneKeptArgsForCall(i int) (string, string) {
+	fake.cloneKeptMutex.RLock()
+	defer fake.cloneKeptMutex.RUnlock()
+	argsForCall := fake.cloneKeptArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVerifyMessenger) CloneVMStarted(arg1 string) {
+	fake.cloneVMStartedMutex.Lock()
+	fake.cloneVMStartedArgsForCall = append(fake.cloneVMStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CloneVMStartedStub
+	fake.recordInvocation("CloneVMStarted", []interface{}{arg1})
+	fake.cloneVMStartedMutex.Unlock()
+	if stub != nil {
+		fake.CloneVMStartedStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) CloneVMStartedCallCount() int {
+	fake.cloneVMStartedMutex.RLock()
+	defer fake.cloneVMStartedMutex.RUnlock()
+	return len(fake.cloneVMStartedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CloneVMStartedCalls(stub func(string)) {
+	fake.cloneVMStartedMutex.Lock()
+	defer fake.cloneVMStartedMutex.Unlock()
+	fake.CloneVMStartedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CloneVMStartedArgsForCall(i int) string {
+	fake.cloneVMStartedMutex.RLock()
+	defer fake.cloneVMStartedMutex.RUnlock()
+	argsForCall := fake.cloneVMStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) CloneVMSucceeded() {
+	fake.cloneVMSucceededMutex.Lock()
+	fake.cloneVMSucceededArgsForCall = append(fake.cloneVMSucceededArgsForCall, struct {
+	}{})
+	stub := fake.CloneVMSucceededStub
+	fake.recordInvocation("CloneVMSucceeded", []interface{}{})
+	fake.cloneVMSucceededMutex.Unlock()
+	if stub != nil {
+		fake.CloneVMSucceededStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) CloneVMSucceededCallCount() int {
+	fake.cloneVMSucceededMutex.RLock()
+	defer fake.cloneVMSucceededMutex.RUnlock()
+	return len(fake.cloneVMSucceededArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CloneVMSucceededCalls(stub func()) {
+	fake.cloneVMSucceededMutex.Lock()
+	defer fake.cloneVMSucceededMutex.Unlock()
+	fake.CloneVMSucceededStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CreateSnapshotStarted(arg1 string) {
+	fake.createSnapshotStartedMutex.Lock()
+	fake.createSnapshotStartedArgsForCall = append(fake.createSnapshotStartedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CreateSnapshotStartedStub
+	fake.recordInvocation("CreateSnapshotStarted", []interface{}{arg1})
+	fake.createSnapshotStartedMutex.Unlock()
+	if stub != nil {
+		fake.CreateSnapshotStartedStub(arg1)
+	}
+}
+
+func (fake *FakeVerifyMessenger) CreateSnapshotStartedCallCount() int {
+	fake.createSnapshotStartedMutex.RLock()
+	defer fake.createSnapshotStartedMutex.RUnlock()
+	return len(fake.createSnapshotStartedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CreateSnapshotStartedCalls(stub func(string)) {
+	fake.createSnapshotStartedMutex.Lock()
+	defer fake.createSnapshotStartedMutex.Unlock()
+	fake.CreateSnapshotStartedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) CreateSnapshotStartedArgsForCall(i int) string {
+	fake.createSnapshotStartedMutex.RLock()
+	defer fake.createSnapshotStartedMutex.RUnlock()
+	argsForCall := fake.createSnapshotStartedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVerifyMessenger) CreateSnapshotSucceeded() {
+	fake.createSnapshotSucceededMutex.Lock()
+	fake.createSnapshotSucceededArgsForCall = append(fake.createSnapshotSucceededArgsForCall, struct {
+	}{})
+	stub := fake.CreateSnapshotSucceededStub
+	fake.recordInvocation("CreateSnapshotSucceeded", []interface{}{})
+	fake.createSnapshotSucceededMutex.Unlock()
+	if stub != nil {
+		fake.CreateSnapshotSucceededStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) CreateSnapshotSucceededCallCount() int {
+	fake.createSnapshotSucceededMutex.RLock()
+	defer fake.createSnapshotSucceededMutex.RUnlock()
+	return len(fake.createSnapshotSucceededArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) CreateSnapshotSucceededCalls(stub func()) {
+	fake.createSnapshotSucceededMutex.Lock()
+	defer fake.createSnapshotSucceededMutex.Unlock()
+	fake.CreateSnapshotSucceededStub = stub
+}
+
+func (fake *FakeVerifyMessenger) RunChecksStarted() {
+	fake.runChecksStartedMutex.Lock()
+	fake.runChecksStartedArgsForCall = append(fake.runChecksStartedArgsForCall, struct {
+	}{})
+	stub := fake.RunChecksStartedStub
+	fake.recordInvocation("RunChecksStarted", []interface{}{})
+	fake.runChecksStartedMutex.Unlock()
+	if stub != nil {
+		fake.RunChecksStartedStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) RunChecksStartedCallCount() int {
+	fake.runChecksStartedMutex.RLock()
+	defer fake.runChecksStartedMutex.RUnlock()
+	return len(fake.runChecksStartedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) RunChecksStartedCalls(stub func()) {
+	fake.runChecksStartedMutex.Lock()
+	defer fake.runChecksStartedMutex.Unlock()
+	fake.RunChecksStartedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) RunChecksSucceeded() {
+	fake.runChecksSucceededMutex.Lock()
+	fake.runChecksSucceededArgsForCall = append(fake.runChecksSucceededArgsForCall, struct {
+	}{})
+	stub := fake.RunChecksSucceededStub
+	fake.recordInvocation("RunChecksSucceeded", []interface{}{})
+	fake.runChecksSucceededMutex.Unlock()
+	if stub != nil {
+		fake.RunChecksSucceededStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) RunChecksSucceededCallCount() int {
+	fake.runChecksSucceededMutex.RLock()
+	defer fake.runChecksSucceededMutex.RUnlock()
+	return len(fake.runChecksSucceededArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) RunChecksSucceededCalls(stub func()) {
+	fake.runChecksSucceededMutex.Lock()
+	defer fake.runChecksSucceededMutex.Unlock()
+	fake.RunChecksSucceededStub = stub
+}
+
+func (fake *FakeVerifyMessenger) WaitForGuestStarted() {
+	fake.waitForGuestStartedMutex.Lock()
+	fake.waitForGuestStartedArgsForCall = append(fake.waitForGuestStartedArgsForCall, struct {
+	}{})
+	stub := fake.WaitForGuestStartedStub
+	fake.recordInvocation("WaitForGuestStarted", []interface{}{})
+	fake.waitForGuestStartedMutex.Unlock()
+	if stub != nil {
+		fake.WaitForGuestStartedStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) WaitForGuestStartedCallCount() int {
+	fake.waitForGuestStartedMutex.RLock()
+	defer fake.waitForGuestStartedMutex.RUnlock()
+	return len(fake.waitForGuestStartedArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) WaitForGuestStartedCalls(stub func()) {
+	fake.waitForGuestStartedMutex.Lock()
+	defer fake.waitForGuestStartedMutex.Unlock()
+	fake.WaitForGuestStartedStub = stub
+}
+
+func (fake *FakeVerifyMessenger) WaitForGuestSucceeded() {
+	fake.waitForGuestSucceededMutex.Lock()
+	fake.waitForGuestSucceededArgsForCall = append(fake.waitForGuestSucceededArgsForCall, struct {
+	}{})
+	stub := fake.WaitForGuestSucceededStub
+	fake.recordInvocation("WaitForGuestSucceeded", []interface{}{})
+	fake.waitForGuestSucceededMutex.Unlock()
+	if stub != nil {
+		fake.WaitForGuestSucceededStub()
+	}
+}
+
+func (fake *FakeVerifyMessenger) WaitForGuestSucceededCallCount() int {
+	fake.waitForGuestSucceededMutex.RLock()
+	defer fake.waitForGuestSucceededMutex.RUnlock()
+	return len(fake.waitForGuestSucceededArgsForCall)
+}
+
+func (fake *FakeVerifyMessenger) WaitForGuestSucceededCalls(stub func()) {
+	fake.waitForGuestSucceededMutex.Lock()
+	defer fake.waitForGuestSucceededMutex.Unlock()
+	fake.WaitForGuestSucceededStub = stub
+}
+
+func (fake *FakeVerifyMessenger) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.checkFinishedMutex.RLock()
+	defer fake.checkFinishedMutex.RUnlock()
+	fake.cleanUpFailedMutex.RLock()
+	defer fake.cleanUpFailedMutex.RUnlock()
+	fake.cleanUpStartedMutex.RLock()
+	defer fake.cleanUpStartedMutex.RUnlock()
+	fake.cleanUpSucceededMutex.RLock()
+	defer fake.cleanUpSucceededMutex.RUnlock()
+	fake.cloneKeptMutex.RLock()
+	defer fake.cloneKeptMutex.RUnlock()
+	fake.cloneVMStartedMutex.RLock()
+	defer fake.cloneVMStartedMutex.RUnlock()
+	fake.cloneVMSucceededMutex.RLock()
+	defer fake.cloneVMSucceededMutex.RUnlock()
+	fake.createSnapshotStartedMutex.RLock()
+	defer fake.createSnapshotStartedMutex.RUnlock()
+	fake.createSnapshotSucceededMutex.RLock()
+	defer fake.createSnapshotSucceededMutex.RUnlock()
+	fake.runChecksStartedMutex.RLock()
+	defer fake.runChecksStartedMutex.RUnlock()
+	fake.runChecksSucceededMutex.RLock()
+	defer fake.runChecksSucceededMutex.RUnlock()
+	fake.waitForGuestStartedMutex.RLock()
+	defer fake.waitForGuestStartedMutex.RUnlock()
+	fake.waitForGuestSucceededMutex.RLock()
+	defer fake.waitForGuestSucceededMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeVerifyMessenger) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ verify.VerifyMessenger = new(FakeVerifyMessenger)